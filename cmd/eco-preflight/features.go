@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FeatureConstructor points to the package defining a pre-flight feature and the function returning it.
+type FeatureConstructor struct {
+	// Package is the path of the package relative to the repo root. It lives in the tree of the suites of the
+	// feature, so it can use their config and internal packages.
+	Package string
+	// Constructor is the name of the function in Package returning the preflight.Feature.
+	Constructor string
+}
+
+// Features maps the feature names accepted by the -features flag to their constructors. New features should be added
+// here.
+var Features = map[string]FeatureConstructor{
+	"ptp":       {Package: "tests/cnf/ran/ptp/internal/ptppreflight", Constructor: "NewFeature"},
+	"sriov":     {Package: "tests/cnf/core/network/internal/netpreflight", Constructor: "NewSriovFeature"},
+	"metallb":   {Package: "tests/cnf/core/network/internal/netpreflight", Constructor: "NewMetalLBFeature"},
+	"nvidiagpu": {Package: "tests/hw-accel/nvidiagpu/internal/nvidiagpupreflight", Constructor: "NewFeature"},
+	"amdgpu":    {Package: "tests/hw-accel/amdgpu/internal/amdgpupreflight", Constructor: "NewFeature"},
+}
+
+// selectFeatures returns the given feature names in order without duplicates, or every feature for "all". Unknown
+// features are an error.
+func selectFeatures(names []string) ([]string, error) {
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no features selected, known features are: %s", strings.Join(featureNames(), " "))
+	}
+
+	var selected []string
+
+	found := make(map[string]bool)
+
+	for _, name := range names {
+		if name == "all" {
+			return featureNames(), nil
+		}
+
+		if _, known := Features[name]; !known {
+			return nil, fmt.Errorf("unknown feature %s, known features are: %s", name, strings.Join(featureNames(), " "))
+		}
+
+		if !found[name] {
+			found[name] = true
+			selected = append(selected, name)
+		}
+	}
+
+	return selected, nil
+}
+
+// featureNames returns the names of all features in sorted order.
+func featureNames() []string {
+	names := make([]string, 0, len(Features))
+
+	for name := range Features {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
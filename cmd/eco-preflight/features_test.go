@@ -0,0 +1,40 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectFeatures(t *testing.T) {
+	testCases := []struct {
+		name             string
+		names            []string
+		expectedFeatures []string
+		expectedError    bool
+	}{
+		{name: "in order", names: []string{"sriov", "ptp"}, expectedFeatures: []string{"sriov", "ptp"}},
+		{name: "duplicates", names: []string{"ptp", "ptp"}, expectedFeatures: []string{"ptp"}},
+		{
+			name:             "all",
+			names:            []string{"ptp", "all"},
+			expectedFeatures: []string{"amdgpu", "metallb", "nvidiagpu", "ptp", "sriov"},
+		},
+		{name: "none", expectedError: true},
+		{name: "unknown feature", names: []string{"ptp", "unknown"}, expectedError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			features, err := selectFeatures(testCase.names)
+			if testCase.expectedError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedFeatures, features)
+		})
+	}
+}
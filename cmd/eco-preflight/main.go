@@ -0,0 +1,101 @@
+/*
+Eco-preflight checks whether a cluster can run the suites of the selected features before a lab slot is spent on them.
+It connects to the cluster of inittools.APIClient, so KUBECONFIG and the general ECO_ environment variables apply, and
+prints the facts it probed, such as the topology, OCP version, installed operators, SR-IOV NICs, PTP profiles and GPU
+nodes. It then checks the cluster requirements of every selected feature and the required fields of the config of its
+suites, and prints a GO or NO-GO verdict with the reason for each one.
+
+Upon every feature getting a GO verdict the exit code is 0. If any feature gets a NO-GO verdict, or if any error
+occurs, the exit code is 1.
+
+Features are defined inside the tree of their suites, so they can load the suite config and use the packages internal
+to the suites. The tool generates a small program there for every selected feature, and one combining their reports,
+and runs them with go run using an overlay, like config-inspect, so no files are written to the repository.
+
+Usage:
+
+	eco-preflight [flags]
+
+The flags are:
+
+	-h, -help
+		Print this help message
+
+	-f, -features string
+		Space-separated list of features to check, or "all" for every known feature. Uses ECO_TEST_FEATURES if left
+		blank
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"os"
+	"os/exec"
+	"os/signal"
+	"strings"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	help     bool
+	features string
+)
+
+//nolint:gochecknoinits // This is a main package so init is fine.
+func init() {
+	const (
+		helpUsage     = "Print this help message"
+		featuresUsage = "Space-separated list of features to check. Uses ECO_TEST_FEATURES if left blank"
+
+		defaultHelp = false
+
+		shorthand = " (shorthand)"
+	)
+
+	klog.InitFlags(nil)
+	klog.EnableContextualLogging(true)
+	logf.SetLogger(logr.Discard())
+
+	_ = flag.Set("logtostderr", "true")
+
+	defaultFeatures := os.Getenv("ECO_TEST_FEATURES")
+
+	flag.BoolVar(&help, "help", defaultHelp, helpUsage)
+	flag.BoolVar(&help, "h", defaultHelp, helpUsage+shorthand)
+
+	flag.StringVar(&features, "features", defaultFeatures, featuresUsage)
+	flag.StringVar(&features, "f", defaultFeatures, featuresUsage+shorthand)
+}
+
+func main() {
+	flag.Parse()
+
+	if help {
+		flag.Usage()
+
+		return
+	}
+
+	ctx, cancel := signal.NotifyContext(context.TODO(), os.Interrupt, os.Kill)
+	defer cancel()
+
+	err := RunPreflight(ctx, strings.Fields(features))
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		cancel()
+		os.Exit(exitErr.ExitCode())
+	}
+
+	if err != nil {
+		klog.Errorf("Failed to run pre-flight checks: %v", err)
+
+		cancel()
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	modulePath = "github.com/rh-ecosystem-edge/eco-gotests"
+	// preflightDir is the directory of the program combining the reports of the features.
+	preflightDir = "tests/internal/preflight/ecopreflight"
+	// featureDir is the directory, inside the package of a feature, of the programs checking its features.
+	featureDir = "ecopreflight"
+)
+
+// preflightProgram probes the general facts of the cluster of inittools.APIClient and prints them along with the
+// reports of the features passed as arguments. It lives in the test tree so it can import the packages internal to it.
+const preflightProgram = `package main
+
+import (
+	"os"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/inittools"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/preflight"
+	"k8s.io/klog/v2"
+)
+
+func main() {
+	ready, err := preflight.Run(inittools.APIClient, os.Args[1:], os.Stdout)
+	if err != nil {
+		klog.Exitf("Failed to run pre-flight checks: %v", err)
+	}
+
+	if !ready {
+		os.Exit(1)
+	}
+}
+`
+
+// featureTemplate checks a single feature against inittools.APIClient and writes its report to the path passed as
+// argument. It lives in the tree of the suites of the feature so the constructor can use their internal packages.
+var featureTemplate = template.Must(template.New("feature").Parse(`package main
+
+import (
+	"os"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/inittools"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/preflight"
+	feature "{{ .FeaturePackage }}"
+	"k8s.io/klog/v2"
+)
+
+func main() {
+	err := preflight.RunFeature(inittools.APIClient, feature.{{ .Constructor }}(), os.Args[1])
+	if err != nil {
+		klog.Exitf("Failed to check feature: %v", err)
+	}
+}
+`))
+
+// overlay is the format of the file passed to the -overlay flag of the go command.
+type overlay struct {
+	Replace map[string]string
+}
+
+// RunPreflight checks the given features with go run, running the program of each feature to write its report and
+// then the pre-flight program to print the facts and verdicts. The programs are only provided through an overlay, so
+// they are never written to the repository.
+func RunPreflight(ctx context.Context, names []string) error {
+	features, err := selectFeatures(names)
+	if err != nil {
+		return err
+	}
+
+	rootPath, err := getModuleRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := os.MkdirTemp("", "eco-preflight-")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(tempDir)
+
+	overlayPath, err := writeOverlay(tempDir, rootPath, features)
+	if err != nil {
+		return err
+	}
+
+	var reportPaths []string
+
+	for _, feature := range features {
+		klog.V(100).Infof("Running pre-flight checks for feature %s", feature)
+
+		reportPath := filepath.Join(tempDir, feature+".json")
+
+		err = goRun(ctx, rootPath, overlayPath, featureProgramDir(feature), reportPath)
+		if err != nil {
+			return fmt.Errorf("failed to check feature %s: %w", feature, err)
+		}
+
+		reportPaths = append(reportPaths, reportPath)
+	}
+
+	return goRun(ctx, rootPath, overlayPath, preflightDir, reportPaths...)
+}
+
+// goRun runs the program in dir, relative to rootPath, with go run using the overlay.
+func goRun(ctx context.Context, rootPath, overlayPath, dir string, args ...string) error {
+	args = append([]string{"run", "-overlay", overlayPath, "./" + filepath.ToSlash(dir)}, args...)
+
+	cmd := exec.CommandContext(ctx, "go", args...)
+	cmd.Dir = rootPath
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = os.Environ()
+
+	return cmd.Run()
+}
+
+// featureProgramDir returns the directory of the program checking feature. Features may share a package, so each one
+// gets its own directory.
+func featureProgramDir(feature string) string {
+	return filepath.Join(Features[feature].Package, featureDir, feature)
+}
+
+// writeOverlay writes the pre-flight program and the program of every feature to tempDir and an overlay file placing
+// them in the test tree. It returns the path to the overlay file.
+func writeOverlay(tempDir, rootPath string, features []string) (string, error) {
+	programs := map[string][]byte{preflightDir: []byte(preflightProgram)}
+
+	for _, feature := range features {
+		var program bytes.Buffer
+
+		err := featureTemplate.Execute(&program, map[string]string{
+			"FeaturePackage": modulePath + "/" + Features[feature].Package,
+			"Constructor":    Features[feature].Constructor,
+		})
+		if err != nil {
+			return "", err
+		}
+
+		programs[featureProgramDir(feature)] = program.Bytes()
+	}
+
+	replace := make(map[string]string)
+
+	for dir, program := range programs {
+		programPath := filepath.Join(tempDir, strings.ReplaceAll(dir, string(filepath.Separator), "_")+".go")
+
+		err := os.WriteFile(programPath, program, 0644)
+		if err != nil {
+			return "", err
+		}
+
+		replace[filepath.Join(rootPath, dir, "main.go")] = programPath
+	}
+
+	overlayContent, err := json.Marshal(overlay{Replace: replace})
+	if err != nil {
+		return "", err
+	}
+
+	overlayPath := filepath.Join(tempDir, "overlay.json")
+
+	err = os.WriteFile(overlayPath, overlayContent, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return overlayPath, nil
+}
+
+// getModuleRoot returns the directory containing the go.mod of the eco-gotests module.
+func getModuleRoot(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "go", "env", "GOMOD").Output()
+	if err != nil {
+		return "", err
+	}
+
+	goMod := strings.TrimSpace(string(output))
+	if goMod == "" || goMod == os.DevNull {
+		return "", fmt.Errorf("eco-preflight must be run from inside the eco-gotests module")
+	}
+
+	return filepath.Dir(goMod), nil
+}
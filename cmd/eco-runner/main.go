@@ -0,0 +1,322 @@
+/*
+Eco-runner runs the ginkgo suites of the features selected by ECO_TEST_FEATURES. Features are resolved against the suite
+tree under the test directory and the label filter is validated before any suite runs, so typos fail fast instead of
+silently running fewer specs. Suites run one after another with their own timeout and their results are merged into a
+single junit report and a run summary in the output directory.
+
+Upon a successful run the exit code is 0. If any suite fails, times out or cannot run, or if any error occurs, the exit
+code is 1.
+
+Usage:
+
+	eco-runner [flags] [-- ginkgo flags]
+
+Arguments after the flags are passed to ginkgo for every suite.
+
+The flags are:
+
+	-h, -help
+		Print this help message
+
+	-d, -dir string
+		Directory containing the test suites. Uses "./tests" if left blank
+
+	-o, -output string
+		Directory to write the merged junit report and run summary to. Uses ECO_REPORTS_DUMP_DIR, or "/tmp/reports"
+		when it is not set, if left blank
+
+	-p, -plan
+		Print the suites that would run with their timeouts and exit without running them
+
+	-s, -specs-report string
+		Ginkgo JSON report of a dry run of the suites. When provided, suites without specs matching the label filter are
+		skipped and shards are balanced by the number of matching specs rather than the number of suites
+
+	-v int
+		Log level verbosity for klog. Use 100 for logging all messages or leave blank for none
+
+The environment variables are:
+
+	ECO_TEST_FEATURES
+		Required. Space-separated list of features to run, each being the name of a directory in the suite tree, or
+		"all" for every suite. Every feature must match a directory containing suites, otherwise the run fails before
+		any suite runs. Unlike the former test-runner script, unknown features are no longer ignored when another
+		feature matches
+
+	ECO_TEST_LABELS
+		Ginkgo label filter for the specs to run
+
+	ECO_TEST_INCLUDE_IDS, ECO_TEST_EXCLUDE_IDS
+		Space-separated lists of reportxml IDs of the specs to run and to skip. They are combined with ECO_TEST_LABELS
+
+	ECO_TEST_SHARD
+		Shard of the suites to run as index/total, for example 2/3, when the suites are split across several runners
+
+	ECO_TEST_TIMEOUT
+		Default timeout of each suite. Uses 24h if left blank
+
+	ECO_TEST_SUITE_TIMEOUTS
+		Space-separated list of name=duration timeouts overriding ECO_TEST_TIMEOUT for the suites under the directories
+		with that name, for example "ptp=6h metallb=2h". The deepest matching directory wins
+
+	ECO_TEST_VERBOSE, ECO_TEST_TRACE
+		Run ginkgo with -vv and --trace when set to true
+
+	ECO_VERBOSE_SCRIPT
+		Print the resolved suites before running them when set to true
+*/
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rh-ecosystem-edge/eco-gotests/internal/report/suitetree"
+	"k8s.io/klog/v2"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	defaultTestDir   = "./tests"
+	defaultOutputDir = "/tmp/reports"
+	defaultTimeout   = 24 * time.Hour
+)
+
+var (
+	help        bool
+	dir         string
+	output      string
+	plan        bool
+	specsReport string
+)
+
+//nolint:gochecknoinits // This is a main package so init is fine.
+func init() {
+	const (
+		helpUsage        = "Print this help message"
+		dirUsage         = "Directory containing the test suites. Uses \"./tests\" if left blank"
+		outputUsage      = "Directory to write the merged junit report and run summary to"
+		planUsage        = "Print the suites that would run with their timeouts and exit without running them"
+		specsReportUsage = "Ginkgo JSON report of a dry run of the suites, used to skip suites and balance shards"
+
+		defaultHelp        = false
+		defaultPlan        = false
+		defaultSpecsReport = ""
+
+		shorthand = " (shorthand)"
+	)
+
+	klog.InitFlags(nil)
+	klog.EnableContextualLogging(true)
+	logf.SetLogger(logr.Discard())
+
+	_ = flag.Set("logtostderr", "true")
+
+	defaultOutput := os.Getenv("ECO_REPORTS_DUMP_DIR")
+	if defaultOutput == "" {
+		defaultOutput = defaultOutputDir
+	}
+
+	flag.BoolVar(&help, "help", defaultHelp, helpUsage)
+	flag.BoolVar(&help, "h", defaultHelp, helpUsage+shorthand)
+
+	flag.StringVar(&dir, "dir", defaultTestDir, dirUsage)
+	flag.StringVar(&dir, "d", defaultTestDir, dirUsage+shorthand)
+
+	flag.StringVar(&output, "output", defaultOutput, outputUsage)
+	flag.StringVar(&output, "o", defaultOutput, outputUsage+shorthand)
+
+	flag.BoolVar(&plan, "plan", defaultPlan, planUsage)
+	flag.BoolVar(&plan, "p", defaultPlan, planUsage+shorthand)
+
+	flag.StringVar(&specsReport, "specs-report", defaultSpecsReport, specsReportUsage)
+	flag.StringVar(&specsReport, "s", defaultSpecsReport, specsReportUsage+shorthand)
+}
+
+func main() {
+	flag.Parse()
+
+	if help {
+		flag.Usage()
+
+		return
+	}
+
+	labelFilter, suites, shard, err := planFromEnv()
+	if err != nil {
+		klog.Errorf("Failed to plan the run: %v", err)
+
+		os.Exit(1)
+	}
+
+	if plan || os.Getenv("ECO_VERBOSE_SCRIPT") == "true" {
+		printPlan(labelFilter, suites, shard)
+	}
+
+	if plan {
+		return
+	}
+
+	summary, err := run(labelFilter, suites, shard)
+	if err != nil {
+		klog.Errorf("Failed to run suites: %v", err)
+
+		os.Exit(1)
+	}
+
+	PrintSummary(summary)
+
+	if !summary.Succeeded() {
+		os.Exit(1)
+	}
+}
+
+// planFromEnv returns the label filter, the suites of the current shard and the shard itself from the environment.
+func planFromEnv() (string, []PlannedSuite, Shard, error) {
+	features := splitList(os.Getenv("ECO_TEST_FEATURES"))
+	if len(features) == 0 {
+		return "", nil, Shard{}, fmt.Errorf("ECO_TEST_FEATURES environment variable is undefined")
+	}
+
+	labelFilter, err := BuildLabelFilter(os.Getenv("ECO_TEST_LABELS"),
+		splitList(os.Getenv("ECO_TEST_INCLUDE_IDS")), splitList(os.Getenv("ECO_TEST_EXCLUDE_IDS")))
+	if err != nil {
+		return "", nil, Shard{}, err
+	}
+
+	shard, err := ParseShard(os.Getenv("ECO_TEST_SHARD"))
+	if err != nil {
+		return "", nil, Shard{}, err
+	}
+
+	timeout := defaultTimeout
+
+	if timeoutText := os.Getenv("ECO_TEST_TIMEOUT"); timeoutText != "" {
+		timeout, err = time.ParseDuration(timeoutText)
+		if err != nil || timeout <= 0 {
+			return "", nil, Shard{}, fmt.Errorf("invalid ECO_TEST_TIMEOUT %q", timeoutText)
+		}
+	}
+
+	suiteTimeouts, err := ParseSuiteTimeouts(os.Getenv("ECO_TEST_SUITE_TIMEOUTS"))
+	if err != nil {
+		return "", nil, Shard{}, err
+	}
+
+	tree, err := getTree()
+	if err != nil {
+		return "", nil, Shard{}, err
+	}
+
+	resolved, err := ResolveFeatures(tree, features)
+	if err != nil {
+		return "", nil, Shard{}, err
+	}
+
+	planned, err := PlanSuites(resolved, labelFilter, timeout, suiteTimeouts)
+	if err != nil {
+		return "", nil, Shard{}, err
+	}
+
+	return labelFilter, ShardSuites(planned, shard), shard, nil
+}
+
+// getTree returns the suite tree from the specs report if provided, otherwise from the test directory. Suite paths in
+// the specs report are absolute so it must come from a dry run in the same checkout.
+func getTree() (*suitetree.SuiteTree, error) {
+	if specsReport != "" {
+		return suitetree.NewFromFile(specsReport)
+	}
+
+	return suitetree.NewFromDir(dir)
+}
+
+func printPlan(labelFilter string, suites []PlannedSuite, shard Shard) {
+	fmt.Printf("Shard %s will run %d suites with label filter %q:\n", shard, len(suites), labelFilter)
+
+	for _, suite := range suites {
+		fmt.Printf("%-8s %-6d %s\n", suite.Timeout, suite.Weight, suite.Path)
+	}
+}
+
+// run runs the suites and writes the merged junit report and the summary to the output directory.
+func run(labelFilter string, suites []PlannedSuite, shard Shard) (*Summary, error) {
+	ginkgo, err := findGinkgo()
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.MkdirAll(output, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	workDir, err := os.MkdirTemp("", "eco-runner-")
+	if err != nil {
+		return nil, err
+	}
+
+	defer os.RemoveAll(workDir)
+
+	runner := &Runner{
+		Ginkgo:      ginkgo,
+		LabelFilter: labelFilter,
+		Verbose:     os.Getenv("ECO_TEST_VERBOSE") == "true",
+		Trace:       os.Getenv("ECO_TEST_TRACE") == "true",
+		ExtraArgs:   flag.Args(),
+		WorkDir:     workDir,
+	}
+
+	summary := &Summary{Shard: shard.String(), LabelFilter: labelFilter, Start: time.Now()}
+
+	var reports []types.Report
+
+	for index, suite := range suites {
+		result, report := runner.Run(index, suite)
+		summary.Suites = append(summary.Suites, result)
+
+		if report != nil {
+			reports = append(reports, *report)
+		}
+	}
+
+	summary.End = time.Now()
+
+	err = WriteJUnit(reports, workDir, filepath.Join(output, outputFileName(junitFileName, shard)))
+	if err != nil {
+		return nil, err
+	}
+
+	err = WriteSummary(summary, filepath.Join(output, outputFileName(summaryFileName, shard)))
+	if err != nil {
+		return nil, err
+	}
+
+	return summary, nil
+}
+
+// findGinkgo returns the path to the ginkgo binary, looking in GOPATH/bin when it is not in PATH.
+func findGinkgo() (string, error) {
+	ginkgo, err := exec.LookPath("ginkgo")
+	if err == nil {
+		return ginkgo, nil
+	}
+
+	goPath := os.Getenv("GOPATH")
+	if goPath == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+
+		goPath = filepath.Join(home, "go")
+	}
+
+	return exec.LookPath(filepath.Join(goPath, "bin", "ginkgo"))
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rh-ecosystem-edge/eco-gotests/internal/report/suitetree"
+	"k8s.io/klog/v2"
+)
+
+// allFeatures is the feature selecting every suite.
+const allFeatures = "all"
+
+// Shard selects the part of the suites executed by one of several runners. Index starts from 1.
+type Shard struct {
+	Index int
+	Total int
+}
+
+// String returns the shard as index/total.
+func (shard Shard) String() string {
+	return fmt.Sprintf("%d/%d", shard.Index, shard.Total)
+}
+
+// PlannedSuite is a suite to be executed with its timeout.
+type PlannedSuite struct {
+	// Path is the absolute path of the suite directory.
+	Path    string
+	Timeout time.Duration
+	// Weight is the number of specs selected by the label filter, or 1 when the specs are unknown. It is used to
+	// balance shards.
+	Weight int
+}
+
+// ParseShard parses a shard in the index/total format. An empty value is the only shard of a single runner.
+func ParseShard(value string) (Shard, error) {
+	if value == "" {
+		return Shard{Index: 1, Total: 1}, nil
+	}
+
+	indexText, totalText, found := strings.Cut(value, "/")
+
+	index, indexErr := strconv.Atoi(indexText)
+	total, totalErr := strconv.Atoi(totalText)
+
+	if !found || indexErr != nil || totalErr != nil || index < 1 || index > total {
+		return Shard{}, fmt.Errorf("invalid shard %q, must be index/total with 1 <= index <= total", value)
+	}
+
+	return Shard{Index: index, Total: total}, nil
+}
+
+// ParseSuiteTimeouts parses space- or comma-separated name=duration pairs, where name is the name of any directory in
+// the suite tree.
+func ParseSuiteTimeouts(value string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+
+	for _, pair := range splitList(value) {
+		name, durationText, found := strings.Cut(pair, "=")
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid suite timeout %q, must be name=duration", pair)
+		}
+
+		duration, err := time.ParseDuration(durationText)
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("invalid duration in suite timeout %q", pair)
+		}
+
+		timeouts[name] = duration
+	}
+
+	return timeouts, nil
+}
+
+// BuildLabelFilter combines the label filter with the reportxml IDs to include and exclude and validates the result
+// as a ginkgo label filter. The reportxml.ID decorator labels specs with their ID so IDs are matched as labels.
+func BuildLabelFilter(labels string, includeIDs, excludeIDs []string) (string, error) {
+	var clauses []string
+
+	if strings.TrimSpace(labels) != "" {
+		_, err := types.ParseLabelFilter(labels)
+		if err != nil {
+			return "", fmt.Errorf("invalid label filter %q: %w", labels, err)
+		}
+
+		clauses = append(clauses, "("+strings.TrimSpace(labels)+")")
+	}
+
+	for _, ids := range [][]string{includeIDs, excludeIDs} {
+		for _, id := range ids {
+			if strings.ContainsAny(id, "&|!,()/:= ") {
+				return "", fmt.Errorf("invalid reportxml ID %q", id)
+			}
+		}
+	}
+
+	if len(includeIDs) > 0 {
+		clauses = append(clauses, "("+strings.Join(includeIDs, " || ")+")")
+	}
+
+	if len(excludeIDs) > 0 {
+		clauses = append(clauses, "!("+strings.Join(excludeIDs, " || ")+")")
+	}
+
+	filter := strings.Join(clauses, " && ")
+
+	_, err := types.ParseLabelFilter(filter)
+	if err != nil {
+		return "", fmt.Errorf("invalid label filter %q: %w", filter, err)
+	}
+
+	return filter, nil
+}
+
+// ResolveFeatures returns the suites of tree under the directories named after features. Every feature must match at
+// least one directory containing suites, so typos are reported rather than silently running fewer suites.
+func ResolveFeatures(tree *suitetree.SuiteTree, features []string) ([]*suitetree.SuiteTree, error) {
+	if slices.Contains(features, allFeatures) {
+		return tree.Suites(), nil
+	}
+
+	var (
+		suites  []*suitetree.SuiteTree
+		unknown []string
+	)
+
+	for _, feature := range features {
+		nodes := tree.Find(feature)
+		if len(nodes) == 0 {
+			unknown = append(unknown, feature)
+
+			continue
+		}
+
+		for _, node := range nodes {
+			klog.V(100).Infof("Feature %s matched %s", feature, node.Path)
+
+			suites = append(suites, node.Suites()...)
+		}
+	}
+
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("no suites found for features: %s", strings.Join(unknown, " "))
+	}
+
+	slices.SortFunc(suites, func(suiteA, suiteB *suitetree.SuiteTree) int {
+		return strings.Compare(suiteA.Path, suiteB.Path)
+	})
+
+	return slices.CompactFunc(suites, func(suiteA, suiteB *suitetree.SuiteTree) bool {
+		return suiteA.Path == suiteB.Path
+	}), nil
+}
+
+// PlanSuites returns the suites with their timeouts and weights. The most specific directory with a timeout in
+// suiteTimeouts sets the timeout of a suite, falling back to defaultTimeout. Suites whose specs are known and none
+// match the label filter are left out.
+func PlanSuites(suites []*suitetree.SuiteTree, labelFilter string,
+	defaultTimeout time.Duration, suiteTimeouts map[string]time.Duration) ([]PlannedSuite, error) {
+	matches, err := types.ParseLabelFilter(labelFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var planned []PlannedSuite
+
+	for _, suite := range suites {
+		weight := 1
+
+		if len(suite.Children) > 0 {
+			weight = 0
+
+			for _, spec := range suite.Children {
+				if matches(spec.SpecReport.Labels()) {
+					weight++
+				}
+			}
+
+			if weight == 0 {
+				klog.V(100).Infof("Skipping suite %s since none of its specs match the label filter", suite.Path)
+
+				continue
+			}
+		}
+
+		planned = append(planned, PlannedSuite{
+			Path:    suite.Path,
+			Timeout: suiteTimeout(suite.Path, defaultTimeout, suiteTimeouts),
+			Weight:  weight,
+		})
+	}
+
+	return planned, nil
+}
+
+// ShardSuites returns the suites executed by shard. Suites are assigned from the heaviest to the runner with the
+// lowest total weight so runners finish at about the same time. The assignment only depends on the suites, so every
+// runner computes the same one.
+func ShardSuites(suites []PlannedSuite, shard Shard) []PlannedSuite {
+	if shard.Total <= 1 {
+		return suites
+	}
+
+	ordered := slices.Clone(suites)
+	slices.SortStableFunc(ordered, func(suiteA, suiteB PlannedSuite) int {
+		if suiteA.Weight != suiteB.Weight {
+			return suiteB.Weight - suiteA.Weight
+		}
+
+		return strings.Compare(suiteA.Path, suiteB.Path)
+	})
+
+	loads := make([]int, shard.Total)
+
+	var assigned []PlannedSuite
+
+	for _, suite := range ordered {
+		lightest := 0
+
+		for index, load := range loads {
+			if load < loads[lightest] {
+				lightest = index
+			}
+		}
+
+		loads[lightest] += suite.Weight
+
+		if lightest == shard.Index-1 {
+			assigned = append(assigned, suite)
+		}
+	}
+
+	slices.SortFunc(assigned, func(suiteA, suiteB PlannedSuite) int {
+		return strings.Compare(suiteA.Path, suiteB.Path)
+	})
+
+	return assigned
+}
+
+func suiteTimeout(suitePath string, defaultTimeout time.Duration,
+	suiteTimeouts map[string]time.Duration) time.Duration {
+	timeout := defaultTimeout
+
+	for _, name := range strings.Split(filepath.ToSlash(suitePath), "/") {
+		if suiteTimeout, found := suiteTimeouts[name]; found {
+			timeout = suiteTimeout
+		}
+	}
+
+	return timeout
+}
+
+// splitList splits a list separated by spaces or commas.
+func splitList(value string) []string {
+	return strings.FieldsFunc(value, func(char rune) bool {
+		return char == ',' || char == ' ' || char == '\t' || char == '\n'
+	})
+}
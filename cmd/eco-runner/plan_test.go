@@ -0,0 +1,182 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/internal/report/suitetree"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseShard(t *testing.T) {
+	testCases := []struct {
+		name          string
+		value         string
+		expectedShard Shard
+		expectedError bool
+	}{
+		{name: "empty", value: "", expectedShard: Shard{Index: 1, Total: 1}},
+		{name: "first", value: "1/3", expectedShard: Shard{Index: 1, Total: 3}},
+		{name: "last", value: "3/3", expectedShard: Shard{Index: 3, Total: 3}},
+		{name: "index zero", value: "0/3", expectedError: true},
+		{name: "index over total", value: "4/3", expectedError: true},
+		{name: "missing total", value: "2", expectedError: true},
+		{name: "not a number", value: "a/b", expectedError: true},
+		{name: "negative", value: "-1/3", expectedError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			shard, err := ParseShard(testCase.value)
+			if testCase.expectedError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedShard, shard)
+		})
+	}
+}
+
+func TestBuildLabelFilter(t *testing.T) {
+	testCases := []struct {
+		name           string
+		labels         string
+		includeIDs     []string
+		excludeIDs     []string
+		expectedFilter string
+		expectedError  bool
+	}{
+		{name: "empty"},
+		{name: "labels only", labels: " sriov && !reboot ", expectedFilter: "(sriov && !reboot)"},
+		{name: "include ids", includeIDs: []string{"12345", "12346"}, expectedFilter: "(12345 || 12346)"},
+		{name: "exclude ids", excludeIDs: []string{"12345"}, expectedFilter: "!(12345)"},
+		{
+			name:           "labels and ids",
+			labels:         "ptp",
+			includeIDs:     []string{"12345"},
+			excludeIDs:     []string{"54321"},
+			expectedFilter: "(ptp) && (12345) && !(54321)",
+		},
+		{name: "invalid labels", labels: "ptp &&", expectedError: true},
+		{name: "invalid include id", includeIDs: []string{"123 || all"}, expectedError: true},
+		{name: "invalid exclude id", excludeIDs: []string{"!123"}, expectedError: true},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			filter, err := BuildLabelFilter(testCase.labels, testCase.includeIDs, testCase.excludeIDs)
+			if testCase.expectedError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedFilter, filter)
+		})
+	}
+}
+
+func TestResolveFeatures(t *testing.T) {
+	tree := &suitetree.SuiteTree{Path: "/"}
+	tree.Insert("/tests/cnf/ran/ptp", "", 0)
+	tree.Insert("/tests/cnf/core/network/sriov", "", 0)
+	tree.Insert("/tests/ocp/sriov", "", 0)
+
+	testCases := []struct {
+		name          string
+		features      []string
+		expectedPaths []string
+		expectedError bool
+	}{
+		{
+			name:          "all",
+			features:      []string{"all"},
+			expectedPaths: []string{"/tests/cnf/core/network/sriov", "/tests/cnf/ran/ptp", "/tests/ocp/sriov"},
+		},
+		{
+			name:          "feature in several directories",
+			features:      []string{"sriov"},
+			expectedPaths: []string{"/tests/cnf/core/network/sriov", "/tests/ocp/sriov"},
+		},
+		{
+			name:          "overlapping features",
+			features:      []string{"cnf", "ptp"},
+			expectedPaths: []string{"/tests/cnf/core/network/sriov", "/tests/cnf/ran/ptp"},
+		},
+		{
+			// Unlike the former test-runner script, which ignored unknown features, any unknown feature fails.
+			name:          "unknown feature",
+			features:      []string{"ptp", "ptpp"},
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			suites, err := ResolveFeatures(tree, testCase.features)
+			if testCase.expectedError {
+				assert.Error(t, err)
+
+				return
+			}
+
+			assert.NoError(t, err)
+
+			var paths []string
+
+			for _, suite := range suites {
+				paths = append(paths, suite.Path)
+			}
+
+			assert.Equal(t, testCase.expectedPaths, paths)
+		})
+	}
+}
+
+func TestShardSuites(t *testing.T) {
+	suites := []PlannedSuite{
+		{Path: "/a", Weight: 5},
+		{Path: "/b", Weight: 3},
+		{Path: "/c", Weight: 3},
+		{Path: "/d", Weight: 1},
+		{Path: "/e", Weight: 1},
+	}
+
+	testCases := []struct {
+		name          string
+		shard         Shard
+		expectedPaths []string
+	}{
+		{name: "single runner", shard: Shard{Index: 1, Total: 1}, expectedPaths: []string{"/a", "/b", "/c", "/d", "/e"}},
+		{name: "first of two", shard: Shard{Index: 1, Total: 2}, expectedPaths: []string{"/a", "/d", "/e"}},
+		{name: "second of two", shard: Shard{Index: 2, Total: 2}, expectedPaths: []string{"/b", "/c"}},
+		{name: "third of three", shard: Shard{Index: 3, Total: 3}, expectedPaths: []string{"/c", "/e"}},
+		{name: "more runners than suites", shard: Shard{Index: 6, Total: 6}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var paths []string
+
+			for _, suite := range ShardSuites(suites, testCase.shard) {
+				paths = append(paths, suite.Path)
+			}
+
+			assert.Equal(t, testCase.expectedPaths, paths)
+		})
+	}
+
+	// Every suite runs on exactly one shard.
+	counts := make(map[string]int)
+
+	for index := 1; index <= 3; index++ {
+		for _, suite := range ShardSuites(suites, Shard{Index: index, Total: 3}) {
+			counts[suite.Path]++
+		}
+	}
+
+	assert.Equal(t, map[string]int{"/a": 1, "/b": 1, "/c": 1, "/d": 1, "/e": 1}, counts)
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// junitFileName is the name of the merged junit report in the output directory.
+	junitFileName = "eco-runner_junit.xml"
+	// summaryFileName is the name of the run summary in the output directory.
+	summaryFileName = "eco-runner_summary.json"
+	// suiteReportFileName is the name of the ginkgo JSON report of each suite in its work directory.
+	suiteReportFileName = "report.json"
+	// suiteTimeoutReason is the special failure reason ginkgo adds to the report when the suite times out.
+	suiteTimeoutReason = "Suite Timeout Elapsed"
+)
+
+// SuiteState is the outcome of running a single suite.
+type SuiteState string
+
+const (
+	// SuiteStatePassed is for suites whose specs all passed or were skipped.
+	SuiteStatePassed SuiteState = "passed"
+	// SuiteStateFailed is for suites with at least one failed spec.
+	SuiteStateFailed SuiteState = "failed"
+	// SuiteStateTimedOut is for suites interrupted by their timeout.
+	SuiteStateTimedOut SuiteState = "timedout"
+	// SuiteStateError is for suites which did not produce a report, for example because they failed to compile.
+	SuiteStateError SuiteState = "error"
+)
+
+// SuiteResult is the result of running a single suite, as saved in the run summary.
+type SuiteResult struct {
+	Suite    string        `json:"suite"`
+	State    SuiteState    `json:"state"`
+	Timeout  time.Duration `json:"timeout"`
+	Duration time.Duration `json:"duration"`
+	Passed   int           `json:"passed"`
+	Failed   int           `json:"failed"`
+	Skipped  int           `json:"skipped"`
+	Pending  int           `json:"pending"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Summary is the summary of a whole run, saved next to the merged junit report.
+type Summary struct {
+	Shard       string        `json:"shard"`
+	LabelFilter string        `json:"labelFilter"`
+	Start       time.Time     `json:"start"`
+	End         time.Time     `json:"end"`
+	Suites      []SuiteResult `json:"suites"`
+}
+
+// Succeeded returns whether every suite of the run passed.
+func (summary *Summary) Succeeded() bool {
+	for _, result := range summary.Suites {
+		if result.State != SuiteStatePassed {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Runner runs suites one after another with ginkgo.
+type Runner struct {
+	// Ginkgo is the path to the ginkgo binary.
+	Ginkgo string
+	// LabelFilter is passed to every suite, so it should already be validated.
+	LabelFilter string
+	Verbose     bool
+	Trace       bool
+	// ExtraArgs are passed to ginkgo after the runner's own flags.
+	ExtraArgs []string
+	// WorkDir holds a directory per suite for the ginkgo reports.
+	WorkDir string
+}
+
+// Run runs the suite and returns its result along with its ginkgo report, which is nil if ginkgo did not write one.
+// Output from ginkgo is streamed to stdout and stderr.
+func (runner *Runner) Run(index int, suite PlannedSuite) (SuiteResult, *types.Report) {
+	result := SuiteResult{Suite: suite.Path, Timeout: suite.Timeout}
+	outputDir := filepath.Join(runner.WorkDir, fmt.Sprint(index))
+
+	err := os.MkdirAll(outputDir, 0755)
+	if err != nil {
+		result.State = SuiteStateError
+		result.Error = err.Error()
+
+		return result, nil
+	}
+
+	args := runner.args(suite, outputDir)
+
+	fmt.Println(runner.Ginkgo, strings.Join(args, " "))
+
+	command := exec.Command(runner.Ginkgo, args...)
+	command.Stdout = os.Stdout
+	command.Stderr = os.Stderr
+
+	start := time.Now()
+	runErr := command.Run()
+	result.Duration = time.Since(start).Round(time.Second)
+
+	report, err := readSuiteReport(filepath.Join(outputDir, suiteReportFileName))
+	if err != nil {
+		result.State = SuiteStateError
+		result.Error = errors.Join(runErr, err).Error()
+
+		return result, nil
+	}
+
+	result.State = suiteState(report)
+
+	for _, spec := range report.SpecReports {
+		if spec.LeafNodeType != types.NodeTypeIt {
+			continue
+		}
+
+		switch {
+		case spec.State == types.SpecStatePassed:
+			result.Passed++
+		case spec.State == types.SpecStateSkipped:
+			result.Skipped++
+		case spec.State == types.SpecStatePending:
+			result.Pending++
+		case spec.State.Is(types.SpecStateFailureStates):
+			result.Failed++
+		}
+	}
+
+	return result, report
+}
+
+func (runner *Runner) args(suite PlannedSuite, outputDir string) []string {
+	args := []string{
+		"--timeout=" + suite.Timeout.String(),
+		"--keep-going",
+		"--require-suite",
+		"--output-dir=" + outputDir,
+		"--json-report=" + suiteReportFileName,
+	}
+
+	if runner.Verbose {
+		args = append(args, "-vv")
+	}
+
+	if runner.Trace {
+		args = append(args, "--trace")
+	}
+
+	if runner.LabelFilter != "" {
+		args = append(args, "--label-filter="+runner.LabelFilter)
+	}
+
+	args = append(args, runner.ExtraArgs...)
+
+	return append(args, suite.Path)
+}
+
+// WriteJUnit converts the reports to junit and merges them into a single report at path.
+func WriteJUnit(reports []types.Report, workDir, path string) error {
+	var sources []string
+
+	for index, report := range reports {
+		source := filepath.Join(workDir, fmt.Sprintf("junit-%d.xml", index))
+
+		err := reporters.GenerateJUnitReport(report, source)
+		if err != nil {
+			return fmt.Errorf("failed to generate junit report for suite %s: %w", report.SuitePath, err)
+		}
+
+		sources = append(sources, source)
+	}
+
+	messages, err := reporters.MergeAndCleanupJUnitReports(sources, path)
+	for _, message := range messages {
+		klog.Warning(message)
+	}
+
+	return err
+}
+
+// WriteSummary saves the summary as indented JSON to path.
+func WriteSummary(summary *Summary, path string) error {
+	summaryBytes, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, append(summaryBytes, '\n'), 0644)
+}
+
+// PrintSummary prints one line per suite followed by the totals.
+func PrintSummary(summary *Summary) {
+	var total SuiteResult
+
+	fmt.Printf("\nRan %d suites in %s\n", len(summary.Suites), summary.End.Sub(summary.Start).Round(time.Second))
+
+	for _, result := range summary.Suites {
+		fmt.Printf("%-9s %-10s passed %-4d failed %-4d skipped %-4d pending %-4d %s\n", result.State,
+			result.Duration, result.Passed, result.Failed, result.Skipped, result.Pending, result.Suite)
+
+		total.Passed += result.Passed
+		total.Failed += result.Failed
+		total.Skipped += result.Skipped
+		total.Pending += result.Pending
+	}
+
+	fmt.Printf("Total: passed %d failed %d skipped %d pending %d\n",
+		total.Passed, total.Failed, total.Skipped, total.Pending)
+}
+
+// outputFileName returns name prefixed by the shard when there is more than one, so the outputs of runners sharing an
+// output directory do not overwrite one another.
+func outputFileName(name string, shard Shard) string {
+	if shard.Total <= 1 {
+		return name
+	}
+
+	prefix, rest, _ := strings.Cut(name, "_")
+
+	return fmt.Sprintf("%s_shard-%d-of-%d_%s", prefix, shard.Index, shard.Total, rest)
+}
+
+func suiteState(report *types.Report) SuiteState {
+	if slices.Contains(report.SpecialSuiteFailureReasons, suiteTimeoutReason) {
+		return SuiteStateTimedOut
+	}
+
+	if !report.SuiteSucceeded {
+		return SuiteStateFailed
+	}
+
+	return SuiteStatePassed
+}
+
+// readSuiteReport reads the ginkgo JSON report of a single suite. Ginkgo always writes a list of reports, which holds
+// one report when a single suite runs.
+func readSuiteReport(path string) (*types.Report, error) {
+	reportBytes, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var reports []types.Report
+
+	err = json.Unmarshal(reportBytes, &reports)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reports) != 1 {
+		return nil, fmt.Errorf("expected report %s to have 1 suite but found %d", path, len(reports))
+	}
+
+	return &reports[0], nil
+}
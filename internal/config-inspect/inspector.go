@@ -0,0 +1,151 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	configPackage = "github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	modulePath    = "github.com/rh-ecosystem-edge/eco-gotests"
+	inspectorDir  = "configinspect"
+)
+
+var inspectorTemplate = template.Must(template.New("inspector").Parse(`package main
+
+import (
+	"os"
+
+	"{{ .ConfigPackage }}"
+	suiteconfig "{{ .SuitePackage }}"
+)
+
+func main() {
+	os.Exit(config.RunInspection(suiteconfig.{{ .Constructor }}, os.Stdout, os.Stderr, os.Args[1:]...))
+}
+`))
+
+// overlay is the format of the file passed to the -overlay flag of the go command.
+type overlay struct {
+	Replace map[string]string
+}
+
+// RunInspector generates a program calling config.RunInspection with the constructor of the suite, builds it inside
+// the tree of the suite config package and runs it. The program is only provided through an overlay, so it can import
+// the internal packages of the suite without being written to the repository.
+//
+// The user config file may be shared between suites, so the keys of every other suite are passed to the program and
+// only keys no suite knows are rejected.
+func RunInspector(ctx context.Context, suite string) error {
+	suiteConfig := Suites[suite]
+
+	rootPath, err := getModuleRoot(ctx)
+	if err != nil {
+		return err
+	}
+
+	sharedKeys, err := ListSuiteKeys(ctx, rootPath, suite)
+	if err != nil {
+		return err
+	}
+
+	tempDir, err := os.MkdirTemp("", "config-inspect-")
+	if err != nil {
+		return err
+	}
+
+	defer os.RemoveAll(tempDir)
+
+	overlayPath, err := writeOverlay(tempDir, rootPath, suiteConfig)
+	if err != nil {
+		return err
+	}
+
+	packagePath := "./" + filepath.ToSlash(filepath.Join(suiteConfig.Package, inspectorDir))
+	binPath := filepath.Join(tempDir, inspectorDir)
+
+	klog.V(100).Infof("Building config inspector for %s", packagePath)
+
+	// The program is built and run separately rather than with go run, which would print the exit status of a
+	// failed inspection on top of the one of this tool.
+	build := exec.CommandContext(ctx, "go", "build", "-overlay", overlayPath, "-o", binPath, packagePath)
+	build.Dir = rootPath
+	build.Stdout = os.Stdout
+	build.Stderr = os.Stderr
+
+	err = build.Run()
+	if err != nil {
+		return fmt.Errorf("failed to build config inspector: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, binPath, sharedKeys...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	cmd.Env = append(cmd.Env, os.Environ()...)
+	// Suite configs embed the general config whose package init would otherwise need a cluster.
+	cmd.Env = append(cmd.Env, "UNIT_TEST=true")
+
+	return cmd.Run()
+}
+
+// writeOverlay renders the inspector program to tempDir and writes an overlay file placing it in the inspector
+// directory of the suite config package. It returns the path to the overlay file.
+func writeOverlay(tempDir, rootPath string, suiteConfig SuiteConfig) (string, error) {
+	var program bytes.Buffer
+
+	err := inspectorTemplate.Execute(&program, map[string]string{
+		"ConfigPackage": configPackage,
+		"SuitePackage":  modulePath + "/" + suiteConfig.Package,
+		"Constructor":   suiteConfig.Constructor,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	programPath := filepath.Join(tempDir, "main.go")
+
+	err = os.WriteFile(programPath, program.Bytes(), 0644)
+	if err != nil {
+		return "", err
+	}
+
+	overlayContent, err := json.Marshal(overlay{Replace: map[string]string{
+		filepath.Join(rootPath, suiteConfig.Package, inspectorDir, "main.go"): programPath,
+	}})
+	if err != nil {
+		return "", err
+	}
+
+	overlayPath := filepath.Join(tempDir, "overlay.json")
+
+	err = os.WriteFile(overlayPath, overlayContent, 0644)
+	if err != nil {
+		return "", err
+	}
+
+	return overlayPath, nil
+}
+
+// getModuleRoot returns the directory containing the go.mod of the eco-gotests module.
+func getModuleRoot(ctx context.Context) (string, error) {
+	output, err := exec.CommandContext(ctx, "go", "env", "GOMOD").Output()
+	if err != nil {
+		return "", err
+	}
+
+	goMod := strings.TrimSpace(string(output))
+	if goMod == "" || goMod == os.DevNull {
+		return "", fmt.Errorf("config-inspect must be run from inside the eco-gotests module")
+	}
+
+	return filepath.Dir(goMod), nil
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// leafMethods are the methods which make envconfig and yaml decode a struct as a whole, the same as the leaf check of
+// the config package.
+var leafMethods = []string{"Decode", "Set", "UnmarshalText", "UnmarshalBinary"}
+
+// ListSuiteKeys returns the sorted, dotted yaml keys known to the config of each suite other than exclude. The keys
+// are read from the type information of the suite config packages, so the suites do not need to be built and run.
+func ListSuiteKeys(ctx context.Context, rootPath, exclude string) ([]string, error) {
+	var patterns []string
+
+	for _, name := range sortedSuiteNames() {
+		if name != exclude {
+			patterns = append(patterns, "./"+Suites[name].Package)
+		}
+	}
+
+	loaded, err := packages.Load(&packages.Config{
+		Context: ctx,
+		Dir:     rootPath,
+		Mode:    packages.NeedName | packages.NeedTypes,
+	}, patterns...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load suite config packages: %w", err)
+	}
+
+	configTypes := make(map[string]types.Type)
+
+	for _, pkg := range loaded {
+		if len(pkg.Errors) > 0 {
+			return nil, fmt.Errorf("failed to load package %s: %v", pkg.PkgPath, pkg.Errors[0])
+		}
+
+		for _, name := range sortedSuiteNames() {
+			if modulePath+"/"+Suites[name].Package == pkg.PkgPath && name != exclude {
+				configType, err := constructorResult(pkg.Types, Suites[name].Constructor)
+				if err != nil {
+					return nil, fmt.Errorf("suite %s: %w", name, err)
+				}
+
+				configTypes[name] = configType
+			}
+		}
+	}
+
+	known := make(map[string]bool)
+
+	for _, configType := range configTypes {
+		addLayerKeys(known, configType, nil, make(map[types.Type]bool))
+	}
+
+	keys := make([]string, 0, len(known))
+
+	for key := range known {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys, nil
+}
+
+// constructorResult returns the type returned by the named constructor of pkg.
+func constructorResult(pkg *types.Package, constructor string) (types.Type, error) {
+	function, ok := pkg.Scope().Lookup(constructor).(*types.Func)
+	if !ok {
+		return nil, fmt.Errorf("constructor %s not found in %s", constructor, pkg.Path())
+	}
+
+	results := function.Type().(*types.Signature).Results()
+	if results.Len() != 1 {
+		return nil, fmt.Errorf("constructor %s must return a single config", constructor)
+	}
+
+	return results.At(0).Type(), nil
+}
+
+// addLayerKeys adds the keys of the leaf fields of configType to known, following the same rules as the config
+// package: embedded and inline structs are flattened and structs decoded as a whole are leaves.
+func addLayerKeys(known map[string]bool, configType types.Type, prefix []string, visited map[types.Type]bool) {
+	structType, ok := derefStruct(configType)
+	if !ok || visited[configType] {
+		return
+	}
+
+	visited[configType] = true
+	defer delete(visited, configType)
+
+	for index := 0; index < structType.NumFields(); index++ {
+		field := structType.Field(index)
+		if !field.Exported() {
+			continue
+		}
+
+		yamlTag := reflect.StructTag(structType.Tag(index)).Get("yaml")
+		if yamlTag == "-" {
+			continue
+		}
+
+		name, options, _ := strings.Cut(yamlTag, ",")
+		_, isStruct := derefStruct(field.Type())
+		isStruct = isStruct && !isLeafType(field.Type())
+
+		if isStruct && (field.Anonymous() || strings.Contains(options, "inline")) {
+			addLayerKeys(known, field.Type(), prefix, visited)
+
+			continue
+		}
+
+		if name == "" {
+			name = strings.ToLower(field.Name())
+		}
+
+		path := append(append([]string{}, prefix...), name)
+
+		if isStruct {
+			addLayerKeys(known, field.Type(), path, visited)
+
+			continue
+		}
+
+		known[strings.Join(path, ".")] = true
+	}
+}
+
+// derefStruct returns the struct behind fieldType, following pointers.
+func derefStruct(fieldType types.Type) (*types.Struct, bool) {
+	for {
+		pointer, ok := fieldType.(*types.Pointer)
+		if !ok {
+			break
+		}
+
+		fieldType = pointer.Elem()
+	}
+
+	structType, ok := fieldType.Underlying().(*types.Struct)
+
+	return structType, ok
+}
+
+// isLeafType returns true when a pointer to fieldType has one of the leaf methods.
+func isLeafType(fieldType types.Type) bool {
+	if pointer, ok := fieldType.(*types.Pointer); ok {
+		fieldType = pointer.Elem()
+	}
+
+	methods := types.NewMethodSet(types.NewPointer(fieldType))
+
+	for _, method := range leafMethods {
+		if methods.Lookup(nil, method) != nil {
+			return true
+		}
+	}
+
+	return false
+}
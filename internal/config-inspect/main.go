@@ -0,0 +1,125 @@
+/*
+Config-inspect is a tool to print and validate the configuration of a test suite. It prints every configuration field
+of the suite with its yaml key, environment variable, type, default value, effective value and the layer which set it.
+Values of secret fields are masked.
+
+The configuration is validated against the user config file from ECO_CONFIG_FILE, the profiles listed in
+ECO_CONFIG_PROFILES and the environment variables. Values which cannot be decoded, required fields which are empty and
+yaml keys which no registered suite knows are printed to stderr and cause the exit code to be 1. Since the user config
+file is shared between suites, keys of other suites are accepted.
+
+Since suite config packages are internal to their suites, the tool generates a small program inside the tree of every
+suite and builds them with an overlay, so no files are written to the repository.
+
+Usage:
+
+	config-inspect [flags]
+
+The flags are:
+
+	-h, -help
+		Print this help message
+
+	-l, -list
+		List the suites which can be inspected
+
+	-s, -suite string
+		Name of the suite whose configuration is inspected
+*/
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+
+	"github.com/go-logr/logr"
+	"k8s.io/klog/v2"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+var (
+	help  bool
+	list  bool
+	suite string
+)
+
+//nolint:gochecknoinits // This is a main package so init is fine.
+func init() {
+	const (
+		helpUsage  = "Print this help message"
+		listUsage  = "List the suites which can be inspected"
+		suiteUsage = "Name of the suite whose configuration is inspected"
+
+		defaultHelp  = false
+		defaultList  = false
+		defaultSuite = ""
+
+		shorthand = " (shorthand)"
+	)
+
+	klog.InitFlags(nil)
+	klog.EnableContextualLogging(true)
+	logf.SetLogger(logr.Discard())
+
+	_ = flag.Set("logtostderr", "true")
+
+	flag.BoolVar(&help, "help", defaultHelp, helpUsage)
+	flag.BoolVar(&help, "h", defaultHelp, helpUsage+shorthand)
+
+	flag.BoolVar(&list, "list", defaultList, listUsage)
+	flag.BoolVar(&list, "l", defaultList, listUsage+shorthand)
+
+	flag.StringVar(&suite, "suite", defaultSuite, suiteUsage)
+	flag.StringVar(&suite, "s", defaultSuite, suiteUsage+shorthand)
+}
+
+func main() {
+	flag.Parse()
+
+	if help {
+		flag.Usage()
+
+		return
+	}
+
+	if list {
+		printSuites()
+
+		return
+	}
+
+	if _, found := Suites[suite]; !found {
+		klog.Errorf("Unknown suite \"%s\", use -list to print the available suites", suite)
+
+		os.Exit(1)
+	}
+
+	ctx, cancel := signal.NotifyContext(context.TODO(), os.Interrupt, os.Kill)
+	defer cancel()
+
+	err := RunInspector(ctx, suite)
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		cancel()
+		os.Exit(exitErr.ExitCode())
+	}
+
+	if err != nil {
+		klog.Errorf("Failed to inspect config of suite %s: %v", suite, err)
+
+		cancel()
+		os.Exit(1)
+	}
+}
+
+func printSuites() {
+	for _, name := range sortedSuiteNames() {
+		fmt.Printf("%-16s %s.%s\n", name, Suites[name].Package, Suites[name].Constructor)
+	}
+}
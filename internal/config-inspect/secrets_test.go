@@ -0,0 +1,79 @@
+package main
+
+import (
+	"context"
+	"go/types"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/tools/go/packages"
+)
+
+func TestSecretFields(t *testing.T) {
+	testCases := []struct {
+		suite string
+		field string
+	}{
+		{suite: "cnf-network", field: "SwitchPass"},
+		{suite: "cnf-network", field: "BMCHostPass"},
+		{suite: "vcore", field: "Pass"},
+		{suite: "vcore", field: "PrivateKey"},
+		{suite: "rdscore", field: "HypervisorPass"},
+		{suite: "ipsec", field: "SSHPrivateKey"},
+		{suite: "o-cloud", field: "LocalRegistryAuth"},
+		{suite: "general", field: "ArtifactS3SecretAccessKey"},
+	}
+
+	rootPath, err := getModuleRoot(context.TODO())
+	assert.Nil(t, err)
+
+	for _, testCase := range testCases {
+		suite, ok := Suites[testCase.suite]
+		assert.True(t, ok, "unknown suite %s", testCase.suite)
+
+		loaded, err := packages.Load(&packages.Config{
+			Context: context.TODO(),
+			Dir:     rootPath,
+			Mode:    packages.NeedName | packages.NeedTypes,
+		}, "./"+suite.Package)
+		assert.Nil(t, err)
+
+		if !assert.Len(t, loaded, 1) || !assert.Empty(t, loaded[0].Errors) {
+			continue
+		}
+
+		configType, err := constructorResult(loaded[0].Types, suite.Constructor)
+		if !assert.Nil(t, err) {
+			continue
+		}
+
+		tag, found := findFieldTag(configType, testCase.field, make(map[types.Type]bool))
+		assert.True(t, found, "field %s not found in suite %s", testCase.field, testCase.suite)
+		assert.Equal(t, "true", tag.Get("eco_secret"), "field %s of suite %s is not masked",
+			testCase.field, testCase.suite)
+	}
+}
+
+// findFieldTag returns the tag of the first field named name in configType or any struct it contains.
+func findFieldTag(configType types.Type, name string, visited map[types.Type]bool) (reflect.StructTag, bool) {
+	structType, ok := derefStruct(configType)
+	if !ok || visited[configType] {
+		return "", false
+	}
+
+	visited[configType] = true
+
+	for index := 0; index < structType.NumFields(); index++ {
+		field := structType.Field(index)
+		if field.Name() == name {
+			return reflect.StructTag(structType.Tag(index)), true
+		}
+
+		if tag, found := findFieldTag(field.Type(), name, visited); found {
+			return tag, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,62 @@
+package main
+
+import "sort"
+
+// SuiteConfig points to the config package of a suite and the constructor returning its config.
+type SuiteConfig struct {
+	// Package is the path of the config package relative to the repo root.
+	Package string
+	// Constructor is the name of the function in Package returning a pointer to the config struct.
+	Constructor string
+}
+
+// Suites maps the suite names accepted by the -suite flag to their config packages.
+var Suites = map[string]SuiteConfig{
+	"general":        {Package: "tests/internal/config", Constructor: "NewConfig"},
+	"accel":          {Package: "tests/accel/internal/accelconfig", Constructor: "NewAccelConfig"},
+	"assisted":       {Package: "tests/assisted/internal/assistedconfig", Constructor: "NewAssistedConfig"},
+	"cnf":            {Package: "tests/cnf/internal/cnfconfig", Constructor: "NewCNFConfig"},
+	"cnf-core":       {Package: "tests/cnf/core/internal/coreconfig", Constructor: "NewCoreConfig"},
+	"cnf-network":    {Package: "tests/cnf/core/network/internal/netconfig", Constructor: "NewNetConfig"},
+	"ran":            {Package: "tests/cnf/ran/internal/ranconfig", Constructor: "NewRANConfig"},
+	"ran-deployment": {Package: "tests/cnf/ran-deployment/internal/ranconfig", Constructor: "NewRANConfig"},
+	"hwaccel":        {Package: "tests/hw-accel/internal/hwaccelconfig", Constructor: "NewHwAccelConfig"},
+	"amdgpu":         {Package: "tests/hw-accel/amdgpu/internal/amdgpuconfig", Constructor: "NewAMDConfig"},
+	"kmm":            {Package: "tests/hw-accel/kmm/internal/kmmconfig", Constructor: "NewModulesConfig"},
+	"nfd":            {Package: "tests/hw-accel/nfd/internal/nfdconfig", Constructor: "NewNfdConfig"},
+	"nvidiagpu":      {Package: "tests/hw-accel/nvidiagpu/internal/nvidiagpuconfig", Constructor: "NewNvidiaGPUConfig"},
+	"lca":            {Package: "tests/lca/internal/lcaconfig", Constructor: "NewLCAConfig"},
+	"ibi":            {Package: "tests/lca/imagebasedinstall/internal/ibiconfig", Constructor: "NewIBIConfig"},
+	"ibi-mgmt":       {Package: "tests/lca/imagebasedinstall/mgmt/internal/mgmtconfig", Constructor: "NewMGMTConfig"},
+	"ibu":            {Package: "tests/lca/imagebasedupgrade/internal/ibuconfig", Constructor: "NewIBUConfig"},
+	"ibu-cnf":        {Package: "tests/lca/imagebasedupgrade/cnf/internal/cnfconfig", Constructor: "NewCNFConfig"},
+	"ibu-mgmt":       {Package: "tests/lca/imagebasedupgrade/mgmt/internal/mgmtconfig", Constructor: "NewMGMTConfig"},
+	"ipc":            {Package: "tests/lca/ipchange/internal/ipcconfig", Constructor: "NewIPCConfig"},
+	"seedgeneration": {
+		Package:     "tests/lca/seedgeneration/internal/seedgenerationconfig",
+		Constructor: "NewSeedGenerationConfig",
+	},
+	"ocp":         {Package: "tests/ocp/internal/ocpconfig", Constructor: "NewOcpConfig"},
+	"ocp-sriov":   {Package: "tests/ocp/sriov/internal/ocpsriovconfig", Constructor: "NewSriovOcpConfig"},
+	"rhwa":        {Package: "tests/rhwa/internal/rhwaconfig", Constructor: "NewRHWAConfig"},
+	"systemtests": {Package: "tests/system-tests/internal/systemtestsconfig", Constructor: "NewSystemTestsConfig"},
+	"ipsec":       {Package: "tests/system-tests/ipsec/internal/ipsecconfig", Constructor: "NewIpsecConfig"},
+	"o-cloud":     {Package: "tests/system-tests/o-cloud/internal/ocloudconfig", Constructor: "NewOCloudConfig"},
+	"ran-du":      {Package: "tests/system-tests/ran-du/internal/randuconfig", Constructor: "NewRanDuConfig"},
+	"rdscore":     {Package: "tests/system-tests/rdscore/internal/rdscoreconfig", Constructor: "NewCoreConfig"},
+	"spk":         {Package: "tests/system-tests/spk/internal/spkconfig", Constructor: "NewSPKConfig"},
+	"vcore":       {Package: "tests/system-tests/vcore/internal/vcoreconfig", Constructor: "NewVCoreConfig"},
+}
+
+// sortedSuiteNames returns the names of all suites in sorted order.
+func sortedSuiteNames() []string {
+	names := make([]string, 0, len(Suites))
+
+	for name := range Suites {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
@@ -36,6 +36,16 @@ func CloneRepo(ctx context.Context, localPath, repo, branch string) (string, err
 	return clonedPath, nil
 }
 
+// CheckoutRevision checks out revision in the repo at the given path, leaving it in a detached HEAD state.
+func CheckoutRevision(ctx context.Context, repoPath, revision string) error {
+	klog.V(100).Infof("Checking out revision %s in %s", revision, repoPath)
+
+	cmd := exec.CommandContext(ctx, "git", "checkout", "--detach", revision)
+	cmd.Dir = repoPath
+
+	return execCommand(cmd)
+}
+
 // DryRun runs the eco-gotests tests in dry-run mode and returns the path to the JSON report file.
 func DryRun(ctx context.Context, clonedPath string) (string, error) {
 	klog.V(100).Infof("Running eco-gotests dry-run in %s", clonedPath)
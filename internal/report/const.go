@@ -3,4 +3,6 @@ package main
 const (
 	// RemoteURL is the URL of the remote repository. It should always point to the upstream eco-gotests repository.
 	RemoteURL = "https://github.com/rh-ecosystem-edge/eco-gotests.git"
+	// DefaultBranch is the branch of the remote repository cloned to check out revisions that are not branches.
+	DefaultBranch = "main"
 )
@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/internal/report/suitetree"
+)
+
+// TreeDiff contains the differences between the specs on two branches or revisions. Specs are matched by suite and
+// text, then the unmatched specs sharing a reportxml ID are considered renamed.
+type TreeDiff struct {
+	From      CacheKey
+	To        CacheKey
+	Added     []suitetree.IndexEntry
+	Removed   []suitetree.IndexEntry
+	Renamed   []SpecChange
+	Relabeled []SpecChange
+}
+
+// SpecChange is a spec that exists on both sides of a diff but was renamed or relabeled.
+type SpecChange struct {
+	From          suitetree.IndexEntry
+	To            suitetree.IndexEntry
+	AddedLabels   []string
+	RemovedLabels []string
+}
+
+// ParseDiffRange splits a range in the format from..to into its two sides, each of which is a branch or revision.
+func ParseDiffRange(diffRange string) (string, string, error) {
+	from, to, found := strings.Cut(diffRange, "..")
+	if !found || from == "" || to == "" || strings.Contains(to, "..") {
+		return "", "", fmt.Errorf("invalid diff range %q, must be in the format from..to", diffRange)
+	}
+
+	return from, to, nil
+}
+
+// DiffTrees compares the specs in the from and to trees. Suite paths and locations in the result are cleaned as in the
+// html reports.
+func DiffTrees(fromKey CacheKey, from *suitetree.SuiteTree, toKey CacheKey, to *suitetree.SuiteTree) *TreeDiff {
+	diff := &TreeDiff{From: fromKey, To: toKey}
+	toSpecs := make(map[string][]suitetree.IndexEntry)
+
+	toIndex := CleanIndex(to)
+
+	for _, entry := range toIndex {
+		toSpecs[specKey(entry)] = append(toSpecs[specKey(entry)], entry)
+	}
+
+	for _, fromEntry := range CleanIndex(from) {
+		key := specKey(fromEntry)
+
+		matches := toSpecs[key]
+		if len(matches) == 0 {
+			diff.Removed = append(diff.Removed, fromEntry)
+
+			continue
+		}
+
+		toSpecs[key] = matches[1:]
+
+		if change, changed := newSpecChange(fromEntry, matches[0]); changed {
+			diff.Relabeled = append(diff.Relabeled, change)
+		}
+	}
+
+	for _, entry := range toIndex {
+		if matches := toSpecs[specKey(entry)]; len(matches) > 0 {
+			diff.Added = append(diff.Added, matches[0])
+			toSpecs[specKey(entry)] = matches[1:]
+		}
+	}
+
+	diff.matchRenames()
+
+	return diff
+}
+
+// Empty returns true if there are no differences between the two sides.
+func (diff *TreeDiff) Empty() bool {
+	return len(diff.Added) == 0 && len(diff.Removed) == 0 && len(diff.Renamed) == 0 && len(diff.Relabeled) == 0
+}
+
+// String returns a text representation of the diff with one line per added or removed spec and one line per change
+// of a renamed or relabeled spec.
+func (diff *TreeDiff) String() string {
+	builder := &strings.Builder{}
+
+	fmt.Fprintf(builder, "Diff from %s (%s) to %s (%s)\n",
+		diff.From.Branch, shortRevision(diff.From.Revision), diff.To.Branch, shortRevision(diff.To.Revision))
+
+	if diff.Empty() {
+		builder.WriteString("No differences\n")
+
+		return builder.String()
+	}
+
+	fmt.Fprintf(builder, "Added %d:\n", len(diff.Added))
+
+	for _, entry := range diff.Added {
+		fmt.Fprintf(builder, "+ %s %s\n", formatEntry(entry), entry.Location)
+	}
+
+	fmt.Fprintf(builder, "Removed %d:\n", len(diff.Removed))
+
+	for _, entry := range diff.Removed {
+		fmt.Fprintf(builder, "- %s %s\n", formatEntry(entry), entry.Location)
+	}
+
+	fmt.Fprintf(builder, "Renamed %d:\n", len(diff.Renamed))
+
+	for _, change := range diff.Renamed {
+		fmt.Fprintf(builder, "~ %s\n  -> %s\n", formatEntry(change.From), formatEntry(change.To))
+	}
+
+	fmt.Fprintf(builder, "Relabeled %d:\n", len(diff.Relabeled))
+
+	for _, change := range diff.Relabeled {
+		fmt.Fprintf(builder, "~ %s added [%s] removed [%s]\n", formatEntry(change.To),
+			strings.Join(change.AddedLabels, " "), strings.Join(change.RemovedLabels, " "))
+	}
+
+	return builder.String()
+}
+
+// matchRenames pairs removed and added specs sharing a reportxml ID. Pairs are made in the order specs were removed,
+// so each spec is part of at most one rename.
+func (diff *TreeDiff) matchRenames() {
+	var removed []suitetree.IndexEntry
+
+	for _, fromEntry := range diff.Removed {
+		index := slices.IndexFunc(diff.Added, func(toEntry suitetree.IndexEntry) bool {
+			return slices.ContainsFunc(fromEntry.IDs, func(id string) bool {
+				return slices.Contains(toEntry.IDs, id)
+			})
+		})
+
+		if index < 0 {
+			removed = append(removed, fromEntry)
+
+			continue
+		}
+
+		change, _ := newSpecChange(fromEntry, diff.Added[index])
+		diff.Renamed = append(diff.Renamed, change)
+		diff.Added = slices.Delete(diff.Added, index, index+1)
+	}
+
+	diff.Removed = removed
+}
+
+// newSpecChange returns the change from one spec to the other and whether their labels are different.
+func newSpecChange(from, to suitetree.IndexEntry) (SpecChange, bool) {
+	change := SpecChange{From: from, To: to}
+
+	for _, label := range to.Labels {
+		if !slices.Contains(from.Labels, label) {
+			change.AddedLabels = append(change.AddedLabels, label)
+		}
+	}
+
+	for _, label := range from.Labels {
+		if !slices.Contains(to.Labels, label) {
+			change.RemovedLabels = append(change.RemovedLabels, label)
+		}
+	}
+
+	return change, len(change.AddedLabels) > 0 || len(change.RemovedLabels) > 0
+}
+
+// specKey identifies a spec across branches by its suite and text. Suite paths are only compared from the tests
+// directory, since the checkouts of each side may be in different directories.
+func specKey(entry suitetree.IndexEntry) string {
+	suite := entry.Suite
+	if index := strings.Index(suite, "/tests/"); index >= 0 {
+		suite = suite[index+1:]
+	}
+
+	return suite + "\x00" + entry.Text
+}
+
+// formatEntry formats entry as its text followed by its reportxml IDs, if any.
+func formatEntry(entry suitetree.IndexEntry) string {
+	if len(entry.IDs) == 0 {
+		return entry.Text
+	}
+
+	return fmt.Sprintf("%s [%s]", entry.Text, strings.Join(entry.IDs, " "))
+}
+
+// shortRevision returns the first 7 characters of revision, as git does for short hashes.
+func shortRevision(revision string) string {
+	if len(revision) <= 7 {
+		return revision
+	}
+
+	return revision[:7]
+}
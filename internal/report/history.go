@@ -0,0 +1,241 @@
+package main
+
+import (
+	"encoding/xml"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/internal/report/suitetree"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// junitSuffix is the suffix of the junit reports, as in GeneralConfig.GetJunitReportPath.
+	junitSuffix = "_junit.xml"
+	// testrunSuffix is the suffix of the reportxml testrun reports, as in GeneralConfig.GetReportPath.
+	testrunSuffix = "_testrun.xml"
+	// junitTimestampFormat is the format ginkgo uses for the timestamp of suites in junit reports.
+	junitTimestampFormat = "2006-01-02T15:04:05"
+)
+
+// junitTestSuites is the subset of a junit report needed for the history. Ginkgo writes a testsuites element but a
+// single testsuite is also accepted.
+type junitTestSuites struct {
+	XMLName    xml.Name         `xml:""`
+	TestSuites []junitTestSuite `xml:"testsuite"`
+	junitTestSuite
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+// testrunTestSuite is the subset of a reportxml testrun report needed for the history. It is not read with the types
+// from the reportxml package since importing it registers the ginkgo flags.
+type testrunTestSuite struct {
+	Name      string `xml:"name,attr"`
+	TestCases []struct {
+		Name    string    `xml:"name,attr"`
+		Skipped *struct{} `xml:"skipped"`
+		Failure *struct{} `xml:"failure"`
+	} `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name    string    `xml:"name,attr"`
+	Status  string    `xml:"status,attr"`
+	Skipped *struct{} `xml:"skipped"`
+	Error   *struct{} `xml:"error"`
+	Failure *struct{} `xml:"failure"`
+}
+
+// LoadHistory reads the results of past runs from the junit and reportxml testrun reports under dir. Reports in the
+// same directory are considered a single run. Since both kinds of report cover the same specs, testrun reports are
+// only read from directories without any junit reports. Reports that cannot be parsed are logged and skipped.
+func LoadHistory(dir string) ([]suitetree.RunResult, error) {
+	klog.V(100).Infof("Loading history of past runs from %s", dir)
+
+	runs := make(map[string][]string)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.Type().IsRegular() &&
+			(strings.HasSuffix(entry.Name(), junitSuffix) || strings.HasSuffix(entry.Name(), testrunSuffix)) {
+			runs[filepath.Dir(path)] = append(runs[filepath.Dir(path)], path)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var results []suitetree.RunResult
+
+	for runDir, files := range runs {
+		hasJUnit := slices.ContainsFunc(files, func(file string) bool {
+			return strings.HasSuffix(file, junitSuffix)
+		})
+
+		for _, file := range files {
+			var (
+				fileResults []suitetree.RunResult
+				err         error
+			)
+
+			switch {
+			case strings.HasSuffix(file, junitSuffix):
+				fileResults, err = loadJUnit(file)
+			case !hasJUnit:
+				fileResults, err = loadTestrun(file)
+			}
+
+			if err != nil {
+				klog.Warningf("Skipping report %s from run %s that cannot be loaded: %v", file, runDir, err)
+
+				continue
+			}
+
+			results = append(results, fileResults...)
+		}
+	}
+
+	klog.V(100).Infof("Loaded %d results from %d runs", len(results), len(runs))
+
+	return results, nil
+}
+
+// loadJUnit returns the results of the It specs in a junit report. Specs are timed from the start of their suite, which
+// ginkgo records without a time zone so it is read as UTC.
+func loadJUnit(file string) ([]suitetree.RunResult, error) {
+	fileBytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &junitTestSuites{}
+
+	err = xml.Unmarshal(fileBytes, report)
+	if err != nil {
+		return nil, err
+	}
+
+	suites := report.TestSuites
+	if report.XMLName.Local == "testsuite" {
+		suites = []junitTestSuite{report.junitTestSuite}
+	}
+
+	fileTime, err := modTime(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []suitetree.RunResult
+
+	for _, suite := range suites {
+		suiteTime, err := time.Parse(junitTimestampFormat, suite.Timestamp)
+		if err != nil {
+			suiteTime = fileTime
+		}
+
+		for _, testCase := range suite.TestCases {
+			name, isIt := strings.CutPrefix(testCase.Name, "[It] ")
+			if !isIt && strings.HasPrefix(testCase.Name, "[") {
+				continue
+			}
+
+			results = append(results, suitetree.RunResult{
+				Suite:   suite.Name,
+				Name:    name,
+				Outcome: junitOutcome(testCase),
+				Time:    suiteTime,
+			})
+		}
+	}
+
+	return results, nil
+}
+
+// loadTestrun returns the results in a reportxml testrun report. Since the report has no timestamp, specs are timed
+// from when the report was last written.
+func loadTestrun(file string) ([]suitetree.RunResult, error) {
+	fileBytes, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	suite := &testrunTestSuite{}
+
+	err = xml.Unmarshal(fileBytes, suite)
+	if err != nil {
+		return nil, err
+	}
+
+	fileTime, err := modTime(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []suitetree.RunResult
+
+	for _, testCase := range suite.TestCases {
+		outcome := suitetree.OutcomePassed
+
+		switch {
+		case testCase.Failure != nil:
+			outcome = suitetree.OutcomeFailed
+		case testCase.Skipped != nil:
+			outcome = suitetree.OutcomeSkipped
+		}
+
+		results = append(results, suitetree.RunResult{
+			Suite:   suite.Name,
+			Name:    testCase.Name,
+			Outcome: outcome,
+			Time:    fileTime,
+		})
+	}
+
+	return results, nil
+}
+
+// junitOutcome returns the outcome of testCase from its ginkgo status, falling back to its child elements for junit
+// reports not produced by ginkgo.
+func junitOutcome(testCase junitTestCase) suitetree.Outcome {
+	switch testCase.Status {
+	case "passed":
+		return suitetree.OutcomePassed
+	case "skipped", "pending":
+		return suitetree.OutcomeSkipped
+	case "":
+	default:
+		return suitetree.OutcomeFailed
+	}
+
+	switch {
+	case testCase.Failure != nil || testCase.Error != nil:
+		return suitetree.OutcomeFailed
+	case testCase.Skipped != nil:
+		return suitetree.OutcomeSkipped
+	default:
+		return suitetree.OutcomePassed
+	}
+}
+
+func modTime(file string) (time.Time, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	return info.ModTime(), nil
+}
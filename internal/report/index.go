@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/internal/report/suitetree"
+)
+
+// IndexFileName is the name of the JSON index of specs saved alongside the html report.
+const IndexFileName = "index.json"
+
+// SpecIndex is the JSON export of the specs on every branch. It allows finding which specs cover a reportxml ID, or
+// have a label, and on which branches they exist without going through the HTML reports.
+type SpecIndex struct {
+	Generated time.Time     `json:"generated"`
+	Branches  []BranchIndex `json:"branches"`
+}
+
+// BranchIndex contains the specs of a single branch. Suite paths and locations are cleaned as in the html reports.
+type BranchIndex struct {
+	Branch   string                 `json:"branch"`
+	Revision string                 `json:"revision"`
+	Specs    []suitetree.IndexEntry `json:"specs"`
+}
+
+// NewSpecIndex creates the index of the specs in treeMap. Branches are sorted lexicographically ascending by name.
+func NewSpecIndex(treeMap map[CacheKey]*suitetree.SuiteTree) SpecIndex {
+	index := SpecIndex{Generated: time.Now()}
+
+	for key, tree := range treeMap {
+		index.Branches = append(index.Branches,
+			BranchIndex{Branch: key.Branch, Revision: key.Revision, Specs: CleanIndex(tree)})
+	}
+
+	slices.SortFunc(index.Branches, func(a, b BranchIndex) int {
+		return strings.Compare(a.Branch, b.Branch)
+	})
+
+	return index
+}
+
+// CleanIndex returns the index of tree with its suite paths and locations cleaned as in the html reports.
+func CleanIndex(tree *suitetree.SuiteTree) []suitetree.IndexEntry {
+	index := tree.Index()
+
+	for i := range index {
+		index[i].Suite = CleanPath(index[i].Suite)
+		index[i].Location = CleanPath(index[i].Location)
+	}
+
+	return index
+}
+
+// SaveSpecIndex saves index as JSON at outputFileName. If outputFileName already exists, then it is truncated.
+func SaveSpecIndex(index SpecIndex, outputFileName string) error {
+	outputFile, err := os.Create(outputFileName)
+	if err != nil {
+		return err
+	}
+
+	defer outputFile.Close()
+
+	encoder := json.NewEncoder(outputFile)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(index)
+}
+
+// Labels returns every label used by the specs in tree, sorted lexicographically.
+func Labels(tree *suitetree.SuiteTree) []string {
+	var labels []string
+
+	for _, entry := range tree.Index() {
+		labels = append(labels, entry.Labels...)
+	}
+
+	slices.Sort(labels)
+
+	return slices.Compact(labels)
+}
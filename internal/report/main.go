@@ -24,9 +24,20 @@ The flags are:
 	-c, -clean
 		Delete the test suite cache and exit without running
 
+	-d, -diff string
+		Range of branches or revisions, as in main..release-4.20, to report the specs added, removed, renamed and
+		relabeled between. Cannot be used with -b
+
+	-j, -json string
+		File to output the JSON index of specs on all branches to. Will not be generated if left blank
+
 	-o, -output string
 		Directory to output static site to. Will not be generated if left blank
 
+	-r, -runs string
+		Directory of junit and reportxml testrun reports from past runs. When provided, each node of the html report is
+		annotated with the pass, fail and skip counts, flakiness and last failure of its specs
+
 	-v int
 		Log level verbosity for klog. Use 100 for logging all messages or leave blank for none
 */
@@ -44,6 +55,7 @@ import (
 	"time"
 
 	"github.com/go-logr/logr"
+	"github.com/rh-ecosystem-edge/eco-gotests/internal/report/suitetree"
 	"k8s.io/klog/v2"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -53,7 +65,10 @@ var (
 	actionURL string
 	branch    string
 	clean     bool
+	diffRange string
+	jsonFile  string
 	output    string
+	runs      string
 )
 
 //nolint:gochecknoinits // This is a main package so init is fine.
@@ -63,13 +78,19 @@ func init() {
 		actionURLUsage = "URL to the action generating this report. Only necessary with -o. Uses \"/\" if left blank"
 		branchUsage    = "Space-separated list of globs to match branches. Leave blank to use the local directory"
 		cleanUsage     = "Delete the test suite cache and exit without running"
+		diffUsage      = "Range of branches or revisions, as in main..release-4.20, to diff the specs between"
+		jsonUsage      = "File to output the JSON index of specs on all branches to. Will not be generated if left blank"
 		outputUsage    = "Directory to output static site to. Will not be generated if left blank"
+		runsUsage      = "Directory of junit and reportxml testrun reports from past runs to annotate the html report with"
 
 		defaultHelp      = false
 		defaultActionURL = "/"
 		defaultBranch    = ""
 		defaultClean     = false
+		defaultDiff      = ""
+		defaultJSON      = ""
 		defaultOutput    = ""
+		defaultRuns      = ""
 
 		shorthand = " (shorthand)"
 	)
@@ -92,8 +113,17 @@ func init() {
 	flag.BoolVar(&clean, "clean", defaultClean, cleanUsage)
 	flag.BoolVar(&clean, "c", defaultClean, cleanUsage+shorthand)
 
+	flag.StringVar(&diffRange, "diff", defaultDiff, diffUsage)
+	flag.StringVar(&diffRange, "d", defaultDiff, diffUsage+shorthand)
+
+	flag.StringVar(&jsonFile, "json", defaultJSON, jsonUsage)
+	flag.StringVar(&jsonFile, "j", defaultJSON, jsonUsage+shorthand)
+
 	flag.StringVar(&output, "output", defaultOutput, outputUsage)
 	flag.StringVar(&output, "o", defaultOutput, outputUsage+shorthand)
+
+	flag.StringVar(&runs, "runs", defaultRuns, runsUsage)
+	flag.StringVar(&runs, "r", defaultRuns, runsUsage+shorthand)
 }
 
 func main() {
@@ -116,26 +146,67 @@ func main() {
 		return
 	}
 
-	treeMap, err := getTrees(branch)
-	if err != nil {
-		klog.Errorf("Failed to get suite trees when branch=\"%s\": %v", branch, err)
+	var (
+		treeMap map[CacheKey]*suitetree.SuiteTree
+		diff    *TreeDiff
+		err     error
+	)
+
+	if diffRange != "" {
+		if branch != "" {
+			klog.Error("Only one of branch and diff may be provided")
+
+			os.Exit(1)
+		}
 
-		os.Exit(1)
+		treeMap, diff, err = getDiff(diffRange)
+		if err != nil {
+			klog.Errorf("Failed to diff suite trees when diff=\"%s\": %v", diffRange, err)
+
+			os.Exit(1)
+		}
+
+		fmt.Print(diff)
+	} else {
+		treeMap, err = getTrees(branch)
+		if err != nil {
+			klog.Errorf("Failed to get suite trees when branch=\"%s\": %v", branch, err)
+
+			os.Exit(1)
+		}
+
+		printTreeMap(treeMap)
 	}
 
-	printTreeMap(treeMap)
+	if runs != "" {
+		err := applyHistory(treeMap, runs)
+		if err != nil {
+			klog.Errorf("Failed to load history of past runs from %s: %v", runs, err)
+
+			os.Exit(1)
+		}
+	}
 
 	if output != "" {
-		err := templateTreeMap(treeMap, output)
+		err := templateTreeMap(treeMap, diff, output)
 		if err != nil {
 			klog.Errorf("Failed to template tree map and save to %s: %v", output, err)
 
 			os.Exit(1)
 		}
 	}
+
+	if jsonFile != "" {
+		err := SaveSpecIndex(NewSpecIndex(treeMap), jsonFile)
+		if err != nil {
+			klog.Errorf("Failed to save spec index to %s: %v", jsonFile, err)
+
+			os.Exit(1)
+		}
+	}
 }
 
-func getTrees(branch string) (map[CacheKey]*SuiteTree, error) {
+func getTrees(branch string) (map[CacheKey]*suitetree.SuiteTree, error) {
 	ctx, cancel := signal.NotifyContext(context.TODO(), os.Interrupt, os.Kill)
 	defer cancel()
 
@@ -144,7 +215,7 @@ func getTrees(branch string) (map[CacheKey]*SuiteTree, error) {
 		return nil, err
 	}
 
-	var treeMap map[CacheKey]*SuiteTree
+	var treeMap map[CacheKey]*suitetree.SuiteTree
 
 	if branch != "" {
 		patterns := strings.Fields(branch)
@@ -170,15 +241,111 @@ func getTrees(branch string) (map[CacheKey]*SuiteTree, error) {
 	return treeMap, nil
 }
 
-func printTreeMap(treeMap map[CacheKey]*SuiteTree) {
+// getDiff returns the trees for both sides of diffRange, trimmed and sorted as in getTrees, and the diff between them.
+func getDiff(diffRange string) (map[CacheKey]*suitetree.SuiteTree, *TreeDiff, error) {
+	from, to, err := ParseDiffRange(diffRange)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ctx, cancel := signal.NotifyContext(context.TODO(), os.Interrupt, os.Kill)
+	defer cancel()
+
+	cache, err := NewCacheContext(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	fromKey, fromTree, err := getRefTree(ctx, cache, from)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	toKey, toTree, err := getRefTree(ctx, cache, to)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = cache.Save()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	treeMap := map[CacheKey]*suitetree.SuiteTree{fromKey: fromTree.TrimRoot(), toKey: toTree.TrimRoot()}
+	for _, tree := range treeMap {
+		tree.Sort(true)
+	}
+
+	return treeMap, DiffTrees(fromKey, treeMap[fromKey], toKey, treeMap[toKey]), nil
+}
+
+// getRefTree returns the tree for ref, which is either a branch of the remote repo or a revision. Revisions are checked
+// out from a clone of the default branch, which includes the revisions of every branch.
+func getRefTree(ctx context.Context, cache *Cache, ref string) (CacheKey, *suitetree.SuiteTree, error) {
+	treeMap, err := getFromCacheOrClone(ctx, cache, []string{ref})
+	if err != nil {
+		return CacheKey{}, nil, err
+	}
+
+	if len(treeMap) > 1 {
+		return CacheKey{}, nil, fmt.Errorf("%s matches %d branches but must match only one", ref, len(treeMap))
+	}
+
+	for key, tree := range treeMap {
+		return key, tree, nil
+	}
+
+	klog.V(100).Infof("No branch matches %s, checking it out as a revision", ref)
+
+	repoPath, err := CloneRepo(ctx, os.TempDir(), RemoteURL, DefaultBranch)
+	if err != nil {
+		return CacheKey{}, nil, err
+	}
+
+	err = CheckoutRevision(ctx, repoPath, ref)
+	if err != nil {
+		return CacheKey{}, nil, fmt.Errorf("%s is neither a branch nor a revision: %w", ref, err)
+	}
+
+	tree, err := cache.GetOrCreate(repoPath)
+	if err != nil {
+		return CacheKey{}, nil, err
+	}
+
+	key, err := cache.GetKeyFromPath(repoPath)
+	if err != nil {
+		return CacheKey{}, nil, err
+	}
+
+	return key, tree, nil
+}
+
+// applyHistory loads the results of past runs from dir and applies them to every tree. Since reports do not record
+// the branch they were run on, results apply to any branch with the same specs.
+func applyHistory(treeMap map[CacheKey]*suitetree.SuiteTree, dir string) error {
+	results, err := LoadHistory(dir)
+	if err != nil {
+		return err
+	}
+
+	for key, tree := range treeMap {
+		matched := tree.ApplyHistory(results)
+
+		klog.V(100).Infof("Applied %d of %d results from past runs to branch %s", matched, len(results), key.Branch)
+	}
+
+	return nil
+}
+
+func printTreeMap(treeMap map[CacheKey]*suitetree.SuiteTree) {
 	for key, tree := range treeMap {
 		fmt.Println("---")
-		fmt.Printf("Branch %s (%s)\n", key.Branch, key.Revision[:7])
+		fmt.Printf("Branch %s (%s)\n", key.Branch, shortRevision(key.Revision))
 		fmt.Print(tree)
 	}
 }
 
-func templateTreeMap(treeMap map[CacheKey]*SuiteTree, output string) error {
+func templateTreeMap(treeMap map[CacheKey]*suitetree.SuiteTree, diff *TreeDiff, output string) error {
 	err := os.MkdirAll(output, 0755)
 	if err != nil {
 		return err
@@ -191,6 +358,8 @@ func templateTreeMap(treeMap map[CacheKey]*SuiteTree, output string) error {
 			Tree:       tree,
 			Generated:  time.Now(),
 			Branch:     key.Branch,
+			Labels:     Labels(tree),
+			HasHistory: runs != "",
 			ActionURL:  template.URL(actionURL),
 			RepoURL:    RemoteURL,
 			TimeFormat: time.RFC3339,
@@ -207,13 +376,20 @@ func templateTreeMap(treeMap map[CacheKey]*SuiteTree, output string) error {
 			Name:          key.Branch,
 			ReportFile:    outputFileName,
 			Revision:      key.Revision,
-			ShortRevision: key.Revision[:7],
+			ShortRevision: shortRevision(key.Revision),
 		}
 		branchReports = append(branchReports, branchReport)
 	}
 
+	err = SaveSpecIndex(NewSpecIndex(treeMap), filepath.Join(output, IndexFileName))
+	if err != nil {
+		return err
+	}
+
 	config := ReportTemplateConfig{
 		BranchReports: branchReports,
+		IndexFile:     IndexFileName,
+		Diff:          diff,
 		Generated:     time.Now(),
 		ActionURL:     template.URL(actionURL),
 		RepoURL:       RemoteURL,
@@ -229,7 +405,7 @@ func templateTreeMap(treeMap map[CacheKey]*SuiteTree, output string) error {
 	return nil
 }
 
-func getLocalTreeMap(cache *Cache, repoPath string) (map[CacheKey]*SuiteTree, error) {
+func getLocalTreeMap(cache *Cache, repoPath string) (map[CacheKey]*suitetree.SuiteTree, error) {
 	tree, err := cache.GetOrCreate(repoPath)
 	if err != nil {
 		klog.Errorf("Failed to get or create SuiteTree from cache: %v", err)
@@ -239,7 +415,7 @@ func getLocalTreeMap(cache *Cache, repoPath string) (map[CacheKey]*SuiteTree, er
 
 	key, err := cache.GetKeyFromPath(repoPath)
 	if IsMiss(err) {
-		treeMap := map[CacheKey]*SuiteTree{{Branch: "local", Revision: "local"}: tree}
+		treeMap := map[CacheKey]*suitetree.SuiteTree{{Branch: "local", Revision: "local"}: tree}
 
 		return treeMap, nil
 	}
@@ -248,12 +424,13 @@ func getLocalTreeMap(cache *Cache, repoPath string) (map[CacheKey]*SuiteTree, er
 		return nil, err
 	}
 
-	treeMap := map[CacheKey]*SuiteTree{key: tree}
+	treeMap := map[CacheKey]*suitetree.SuiteTree{key: tree}
 
 	return treeMap, nil
 }
 
-func getFromCacheOrClone(ctx context.Context, cache *Cache, patterns []string) (map[CacheKey]*SuiteTree, error) {
+func getFromCacheOrClone(
+	ctx context.Context, cache *Cache, patterns []string) (map[CacheKey]*suitetree.SuiteTree, error) {
 	treeMap, err := cache.GetRemotePatterns(patterns)
 	if err != nil {
 		return nil, err
@@ -0,0 +1,192 @@
+package suitetree
+
+import (
+	"slices"
+	"strings"
+	"time"
+)
+
+// Outcome is the outcome of a spec in a single run.
+type Outcome string
+
+const (
+	// OutcomePassed is for specs that passed.
+	OutcomePassed Outcome = "passed"
+	// OutcomeFailed is for specs that failed, panicked, timed out or were interrupted.
+	OutcomeFailed Outcome = "failed"
+	// OutcomeSkipped is for specs that were skipped or pending.
+	OutcomeSkipped Outcome = "skipped"
+)
+
+// RunResult is the outcome of a spec in a past run.
+type RunResult struct {
+	// Suite is the description of the suite the spec belongs to.
+	Suite string
+	// Name is the full text of the spec. It may be followed by bracketed labels, as in the junit reports from ginkgo.
+	Name    string
+	Outcome Outcome
+	// Time is when the run started.
+	Time time.Time
+}
+
+// History is the outcome of past runs of a spec or, for nodes that are not specs, of all the specs under the node.
+type History struct {
+	Passed  int
+	Failed  int
+	Skipped int
+	// Flips is the number of times a spec passed after failing or failed after passing, counting only the runs it was
+	// not skipped in.
+	Flips int
+	// Transitions is the number of pairs of consecutive runs a spec was not skipped in, so the most Flips could be.
+	Transitions int
+	// LastFailure is the start of the last run a spec failed in. It is the zero time if no spec ever failed.
+	LastFailure time.Time
+}
+
+// Runs returns the number of results included in the history.
+func (history *History) Runs() int {
+	return history.Passed + history.Failed + history.Skipped
+}
+
+// Flakiness returns how often the outcome of specs changed between consecutive runs, from 0 for specs that always
+// passed or always failed to 1 for specs alternating between the two.
+func (history *History) Flakiness() float64 {
+	if history.Transitions == 0 {
+		return 0
+	}
+
+	return float64(history.Flips) / float64(history.Transitions)
+}
+
+// add adds the counts of other to the history and keeps the latest failure of both.
+func (history *History) add(other *History) {
+	history.Passed += other.Passed
+	history.Failed += other.Failed
+	history.Skipped += other.Skipped
+	history.Flips += other.Flips
+	history.Transitions += other.Transitions
+
+	if other.LastFailure.After(history.LastFailure) {
+		history.LastFailure = other.LastFailure
+	}
+}
+
+// ApplyHistory sets the History of every spec in the tree from results, matching specs by the description of their
+// suite and their full text, then sets the History of the other nodes to the sum of their children. Nodes without any
+// matching results keep a nil History. It returns how many of the results matched a spec.
+func (tree *SuiteTree) ApplyHistory(results []RunResult) int {
+	specs := make(map[string]*SuiteTree)
+
+	for _, suite := range tree.Suites() {
+		for _, child := range suite.Children {
+			if child.SpecReport != nil {
+				specs[historyKey(suite.Description, child.SpecReport.FullText())] = child
+			}
+		}
+	}
+
+	specResults := make(map[*SuiteTree][]RunResult)
+	matched := 0
+
+	for _, result := range results {
+		spec := findResultSpec(specs, result)
+		if spec == nil {
+			continue
+		}
+
+		specResults[spec] = append(specResults[spec], result)
+		matched++
+	}
+
+	for spec, results := range specResults {
+		spec.History = newHistory(results)
+	}
+
+	tree.sumHistory()
+
+	return matched
+}
+
+// sumHistory sets the History of nodes that are not specs to the sum of their children and returns it.
+func (tree *SuiteTree) sumHistory() *History {
+	if tree.SpecReport != nil {
+		return tree.History
+	}
+
+	tree.History = nil
+
+	for _, child := range tree.Children {
+		childHistory := child.sumHistory()
+		if childHistory == nil {
+			continue
+		}
+
+		if tree.History == nil {
+			tree.History = &History{}
+		}
+
+		tree.History.add(childHistory)
+	}
+
+	return tree.History
+}
+
+// newHistory returns the history of a single spec from its results.
+func newHistory(results []RunResult) *History {
+	slices.SortStableFunc(results, func(resultA, resultB RunResult) int {
+		return resultA.Time.Compare(resultB.Time)
+	})
+
+	history := &History{}
+
+	var previous Outcome
+
+	for _, result := range results {
+		switch result.Outcome {
+		case OutcomePassed:
+			history.Passed++
+		case OutcomeFailed:
+			history.Failed++
+			history.LastFailure = result.Time
+		default:
+			history.Skipped++
+
+			continue
+		}
+
+		if previous != "" {
+			history.Transitions++
+
+			if previous != result.Outcome {
+				history.Flips++
+			}
+		}
+
+		previous = result.Outcome
+	}
+
+	return history
+}
+
+// findResultSpec returns the spec result belongs to. Since junit reports from ginkgo append the labels of the spec to
+// its name, the trailing bracketed groups are removed one at a time until a spec matches.
+func findResultSpec(specs map[string]*SuiteTree, result RunResult) *SuiteTree {
+	name := strings.TrimSpace(result.Name)
+
+	for {
+		if spec, found := specs[historyKey(result.Suite, name)]; found {
+			return spec
+		}
+
+		index := strings.LastIndex(name, " [")
+		if index < 0 || !strings.HasSuffix(name, "]") {
+			return nil
+		}
+
+		name = name[:index]
+	}
+}
+
+func historyKey(suite, text string) string {
+	return suite + "\x00" + text
+}
@@ -0,0 +1,86 @@
+package suitetree
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2/types"
+)
+
+// idLabelPrefix is the prefix of the label reportxml.ID adds alongside the bare ID, as in test_id:48452.
+const idLabelPrefix = "test_id:"
+
+// SpecInfo contains the attributes of a single spec used to index and filter specs.
+type SpecInfo struct {
+	// Text is the text of the spec prefixed by the text of its containers.
+	Text string `json:"text"`
+	// Location is the file and line of the spec, as in file.go:42.
+	Location string `json:"location"`
+	// Labels are the labels of the containers of the spec followed by its own, without duplicates.
+	Labels []string `json:"labels"`
+	// IDs are the reportxml IDs of the spec, taken from its test_id labels.
+	IDs     []string `json:"ids"`
+	Serial  bool     `json:"serial"`
+	Ordered bool     `json:"ordered"`
+	Focused bool     `json:"focused"`
+	Pending bool     `json:"pending"`
+}
+
+// IndexEntry is a single spec in the index of a tree along with the suite it belongs to.
+type IndexEntry struct {
+	// Suite is the path to the suite of the spec.
+	Suite string `json:"suite"`
+	SpecInfo
+}
+
+// SpecInfo returns the attributes of the spec the tree represents. It returns nil if the tree is not a spec.
+func (tree *SuiteTree) SpecInfo() *SpecInfo {
+	if tree.SpecReport == nil {
+		return nil
+	}
+
+	spec := tree.SpecReport
+
+	return &SpecInfo{
+		Text:     spec.FullText(),
+		Location: fmt.Sprintf("%s:%d", spec.LeafNodeLocation.FileName, spec.LeafNodeLocation.LineNumber),
+		Labels:   spec.Labels(),
+		IDs:      IDsFromLabels(spec.Labels()),
+		Serial:   spec.IsSerial,
+		Ordered:  spec.IsInOrderedContainer,
+		Focused:  tree.Focused,
+		Pending:  spec.State == types.SpecStatePending,
+	}
+}
+
+// Index returns every spec in the tree with the suite it belongs to, ordered by suite path. Specs within a suite keep
+// the order of the tree.
+func (tree *SuiteTree) Index() []IndexEntry {
+	var index []IndexEntry
+
+	for _, suite := range tree.Suites() {
+		for _, child := range suite.Children {
+			if info := child.SpecInfo(); info != nil {
+				index = append(index, IndexEntry{Suite: suite.Path, SpecInfo: *info})
+			}
+		}
+	}
+
+	return index
+}
+
+// IDsFromLabels returns the reportxml IDs found in labels, sorted and without duplicates.
+func IDsFromLabels(labels []string) []string {
+	ids := []string{}
+
+	for _, label := range labels {
+		if id, found := strings.CutPrefix(label, idLabelPrefix); found && id != "" {
+			ids = append(ids, id)
+		}
+	}
+
+	slices.Sort(ids)
+
+	return slices.Compact(ids)
+}
@@ -1,11 +1,13 @@
-package main
+package suitetree
 
 import (
 	"cmp"
 	"encoding/json"
 	"io"
+	"io/fs"
 	"os"
 	"path"
+	"path/filepath"
 	"slices"
 	"strconv"
 	"strings"
@@ -29,6 +31,12 @@ type SuiteTree struct {
 	// SpecReport should only be set when Children is empty, meaning this is a leaf node representing a single spec.
 	// It should only have It specs.
 	SpecReport *types.SpecReport
+	// Focused is true for suites with programmatically focused specs and for the specs they focus. Since ginkgo skips
+	// the other specs of such suites, focused specs are those that were not skipped.
+	Focused bool
+	// History is the outcome of past runs of the node. It is only set by [SuiteTree.ApplyHistory] and is nil for nodes
+	// without any results.
+	History *History
 }
 
 // NewFromReports creates a new SuiteTree from a list of reports. The root of the tree will be `/`.
@@ -43,6 +51,10 @@ func NewFromReports(reports []types.Report) *SuiteTree {
 	for _, report := range reports {
 		leaf := root.Insert(report.SuitePath, report.SuiteDescription, report.PreRunStats.TotalSpecs)
 		leaf.InsertSpecs(report.SpecReports)
+
+		if report.SuiteHasProgrammaticFocus {
+			leaf.markFocus()
+		}
 	}
 
 	return root
@@ -74,6 +86,48 @@ func NewFromFile(path string) (*SuiteTree, error) {
 	return NewFromReports(reports), nil
 }
 
+// NewFromDir creates a new SuiteTree from the test suites found under dir without running them, so the suites have
+// neither descriptions nor specs. Suites are the directories containing a *_suite_test.go file outside of internal
+// directories. The root of the tree will be `/`.
+func NewFromDir(dir string) (*SuiteTree, error) {
+	klog.V(100).Infof("Creating SuiteTree from suites in directory %s", dir)
+
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	root := &SuiteTree{
+		Path: "/",
+		Name: "",
+	}
+
+	err = filepath.WalkDir(absDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == "internal" || entry.Name() == "vendor" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if strings.HasSuffix(entry.Name(), "_suite_test.go") {
+			root.Insert(filepath.ToSlash(filepath.Dir(filePath)), "", 0)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return root, nil
+}
+
 // Insert adds a new suite to the tree as a leaf node and returns the node that was added. It will return nil if the
 // suitePath does not start with the tree's path.
 //
@@ -133,6 +187,53 @@ func (tree *SuiteTree) InsertSpecs(specs types.SpecReports) {
 	}
 }
 
+// IsSuite returns true if the tree is a single suite, meaning it is a leaf node or its children are all specs.
+func (tree *SuiteTree) IsSuite() bool {
+	return tree.SpecReport == nil && (len(tree.Children) == 0 || tree.Children[0].SpecReport != nil)
+}
+
+// Suites returns all the suites in the tree, ordered by path.
+func (tree *SuiteTree) Suites() []*SuiteTree {
+	if tree.Path == "/" && len(tree.Children) == 0 {
+		return nil
+	}
+
+	if tree.IsSuite() {
+		return []*SuiteTree{tree}
+	}
+
+	var suites []*SuiteTree
+
+	for _, child := range tree.Children {
+		suites = append(suites, child.Suites()...)
+	}
+
+	slices.SortFunc(suites, func(suiteA, suiteB *SuiteTree) int {
+		return strings.Compare(suiteA.Path, suiteB.Path)
+	})
+
+	return suites
+}
+
+// Find returns all the nodes of the tree, at any depth, with the given name. Specs are not included.
+func (tree *SuiteTree) Find(name string) []*SuiteTree {
+	var found []*SuiteTree
+
+	if tree.SpecReport != nil {
+		return nil
+	}
+
+	if tree.Name == name {
+		found = append(found, tree)
+	}
+
+	for _, child := range tree.Children {
+		found = append(found, child.Find(name)...)
+	}
+
+	return found
+}
+
 // Sort sorts the children of the tree first by the number of specs and then by name. If descending is true, the
 // children are sorted in descending order by number of specs, but the name is still sorted alphabetically.
 func (tree *SuiteTree) Sort(descending bool) {
@@ -200,6 +301,15 @@ func (tree *SuiteTree) stringLevel(builder *strings.Builder, level uint) {
 	}
 }
 
+// markFocus marks the suite and the specs ginkgo did not skip because of programmatic focus as focused.
+func (tree *SuiteTree) markFocus() {
+	tree.Focused = true
+
+	for _, child := range tree.Children {
+		child.Focused = child.SpecReport != nil && child.SpecReport.State != types.SpecStateSkipped
+	}
+}
+
 // findChild returns the child with the given name or nil if no child with that name exists. It only searches direct
 // children of the tree.
 func (tree *SuiteTree) findChild(name string) *SuiteTree {
@@ -4,9 +4,10 @@ import (
 	"crypto/sha256"
 	"embed"
 	"fmt"
+	"io/fs"
 )
 
-//go:embed *.go
+//go:embed *.go suitetree/*.go
 var programSourceCode embed.FS
 
 // sourceCodeSum is the SHA256 sum of this program's source code. Since a change to this program's source code may
@@ -14,26 +15,27 @@ var programSourceCode embed.FS
 // expire cache too eagerly but guarantees compatibility.
 var sourceCodeSum string = getSourceSum()
 
-// getSourceSum returns the SHA256 sum of all the .go files in this directory. If any errors are encountered, an empty
-// string is returned.
+// getSourceSum returns the SHA256 sum of all the .go files in this directory and the suitetree package, which defines
+// the cached format. If any errors are encountered, an empty string is returned.
 func getSourceSum() string {
 	summer := sha256.New()
 
-	dirEntries, err := programSourceCode.ReadDir(".")
-	if err != nil {
-		return ""
-	}
+	err := fs.WalkDir(programSourceCode, ".", func(path string, dirEntry fs.DirEntry, err error) error {
+		if err != nil || dirEntry.IsDir() {
+			return err
+		}
 
-	for _, dirEntry := range dirEntries {
-		contents, err := programSourceCode.ReadFile(dirEntry.Name())
+		contents, err := programSourceCode.ReadFile(path)
 		if err != nil {
-			return ""
+			return err
 		}
 
 		_, err = summer.Write(contents)
-		if err != nil {
-			return ""
-		}
+
+		return err
+	})
+	if err != nil {
+		return ""
 	}
 
 	return fmt.Sprintf("%x", summer.Sum(nil))
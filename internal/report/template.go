@@ -2,12 +2,15 @@ package main
 
 import (
 	_ "embed"
+	"fmt"
 	"html/template"
 	"os"
 	"path/filepath"
 	"slices"
 	"strings"
 	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/internal/report/suitetree"
 )
 
 var (
@@ -20,17 +23,23 @@ var (
 
 var (
 	funcMap = template.FuncMap{
-		"cleanPath": cleanPath,
+		"cleanPath": CleanPath,
+		"join":      strings.Join,
+		"percent":   percent,
 	}
 	treeTemplate   = template.Must(template.New("tree_template.html").Funcs(funcMap).Parse(treeTemplateFile))
-	reportTemplate = template.Must(template.New("report_template.html").Parse(reportTemplateFile))
+	reportTemplate = template.Must(template.New("report_template.html").Funcs(funcMap).Parse(reportTemplateFile))
 )
 
 // TreeTemplateConfig contains the data necessary to template a single SuiteTree into an html report.
 type TreeTemplateConfig struct {
-	Tree       *SuiteTree
-	Generated  time.Time
-	Branch     string
+	Tree      *suitetree.SuiteTree
+	Generated time.Time
+	Branch    string
+	// Labels are all the labels used by specs in Tree, offered as suggestions when filtering by label.
+	Labels []string
+	// HasHistory is true when the history of past runs was applied to Tree, so nodes without history were never run.
+	HasHistory bool
 	ActionURL  template.URL
 	RepoURL    template.URL
 	TimeFormat string
@@ -44,10 +53,14 @@ func TemplateTree(config TreeTemplateConfig, outputFileName string) error {
 // ReportTemplateConfig contains the data necessary to generate a report linking to multiple templated SuiteTrees.
 type ReportTemplateConfig struct {
 	BranchReports []BranchReportConfig
-	Generated     time.Time
-	ActionURL     template.URL
-	RepoURL       template.URL
-	TimeFormat    string
+	// IndexFile is the name of the JSON index of specs on all branches, relative to the report.
+	IndexFile string
+	// Diff is the diff between two branches or revisions. It is only included in the report when not nil.
+	Diff       *TreeDiff
+	Generated  time.Time
+	ActionURL  template.URL
+	RepoURL    template.URL
+	TimeFormat string
 }
 
 // BranchReportConfig contains the data necessary to include a single templated SuiteTree for a certain branch.
@@ -86,9 +99,14 @@ func executeTemplateAndSave(tmpl *template.Template, config any, outputFileName
 	return nil
 }
 
-// cleanPath cleans the provided path of anything preceding the eco-gotests directory. This is useful to template paths
+// percent formats the ratio as a whole percentage.
+func percent(ratio float64) string {
+	return fmt.Sprintf("%.0f%%", ratio*100)
+}
+
+// CleanPath cleans the provided path of anything preceding the eco-gotests directory. This is useful to template paths
 // to be relative to the repo root rather than / on the machine that generated the report.
-func cleanPath(path string) string {
+func CleanPath(path string) string {
 	pathElements := strings.Split(path, string(os.PathSeparator))
 	for i, element := range pathElements {
 		if element == "eco-gotests" {
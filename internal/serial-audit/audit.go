@@ -0,0 +1,628 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	goinfraPath = "github.com/rh-ecosystem-edge/eco-goinfra/pkg/"
+	ginkgoPath  = "github.com/onsi/ginkgo/v2"
+	// mutatesLabelPrefix is the prefix of the labels created by parallel.Mutates.
+	mutatesLabelPrefix = "mutates:"
+)
+
+var (
+	// watchedPackages are the eco-goinfra packages whose builders manage nodes and the objects rolling them out.
+	watchedPackages = map[string]string{
+		goinfraPath + "nodes":   "nodes",
+		goinfraPath + "mco":     "machine configs or MCPs",
+		goinfraPath + "nto":     "performance profiles",
+		goinfraPath + "machine": "machine sets",
+	}
+	// mutatingMethod matches the builder methods which change the cluster.
+	mutatingMethod = regexp.MustCompile(`^(Create|Update|Delete|Cordon|Uncordon|Drain)`)
+	// containerNodes are the ginkgo nodes holding other nodes. The F and P variants are ignored like in CI.
+	containerNodes = map[string]bool{"Describe": true, "Context": true, "When": true}
+	// subjectNodes are the ginkgo nodes which are reported as specs.
+	subjectNodes = map[string]bool{"It": true, "Specify": true, "DescribeTable": true}
+	// setupNodes are the ginkgo nodes whose code runs as part of every spec of their container.
+	setupNodes = map[string]bool{
+		"BeforeEach": true, "AfterEach": true, "JustBeforeEach": true, "JustAfterEach": true,
+		"BeforeAll": true, "AfterAll": true,
+	}
+)
+
+// Finding is a spec which touches nodes or MCPs without the Serial decorator.
+type Finding struct {
+	Location string `json:"location"`
+	Spec     string `json:"spec"`
+	Reason   string `json:"reason"`
+
+	position token.Position
+}
+
+// sourceFile is a parsed file with the import path of its package and its import aliases.
+type sourceFile struct {
+	path        string
+	packagePath string
+	ast         *ast.File
+	// imports maps the names used in the file to the packages they import.
+	imports map[string]string
+	// tainted maps the names of the variables in the file holding watched builders to what the builders manage.
+	tainted map[string]string
+}
+
+// function is a top-level function and whether it touches nodes or MCPs.
+type function struct {
+	file    *sourceFile
+	decl    *ast.FuncDecl
+	touches bool
+	reason  string
+}
+
+// auditor finds the specs touching nodes or MCPs without the Serial decorator.
+type auditor struct {
+	fileSet   *token.FileSet
+	files     []*sourceFile
+	functions map[string]*function
+	findings  []Finding
+}
+
+// container holds the state inherited by the nodes of a ginkgo container.
+type container struct {
+	text    string
+	serial  bool
+	mutates bool
+	touches bool
+	reason  string
+}
+
+// Audit parses every Go file under dir and returns the specs which touch nodes or MCPs, directly or through helpers
+// defined under dir, without being decorated with Serial or registering the resources with parallel.Mutates.
+func Audit(dir string) ([]Finding, error) {
+	modulePath, moduleRoot, err := findModule(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	audit := &auditor{fileSet: token.NewFileSet(), functions: make(map[string]*function)}
+
+	err = audit.parseDir(dir, modulePath, moduleRoot)
+	if err != nil {
+		return nil, err
+	}
+
+	audit.resolveFunctions()
+
+	for _, file := range audit.files {
+		audit.auditFile(file)
+	}
+
+	sort.Slice(audit.findings, func(i, j int) bool {
+		first, second := audit.findings[i].position, audit.findings[j].position
+		if first.Filename != second.Filename {
+			return first.Filename < second.Filename
+		}
+
+		return first.Line < second.Line
+	})
+
+	return audit.findings, nil
+}
+
+func (audit *auditor) parseDir(dir, modulePath, moduleRoot string) error {
+	return filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() {
+			if entry.Name() == "vendor" || entry.Name() == "testdata" {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		parsedFile, err := parser.ParseFile(audit.fileSet, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return err
+		}
+
+		absDir, err := filepath.Abs(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+
+		relDir, err := filepath.Rel(moduleRoot, absDir)
+		if err != nil {
+			return err
+		}
+
+		file := &sourceFile{
+			path:        path,
+			packagePath: modulePath + "/" + filepath.ToSlash(relDir),
+			ast:         parsedFile,
+			imports:     fileImports(parsedFile),
+		}
+		file.tainted = taintedNames(file)
+
+		audit.files = append(audit.files, file)
+
+		for _, decl := range parsedFile.Decls {
+			funcDecl, isFunc := decl.(*ast.FuncDecl)
+			if isFunc && funcDecl.Recv == nil && funcDecl.Body != nil {
+				audit.functions[file.packagePath+"."+funcDecl.Name.Name] = &function{file: file, decl: funcDecl}
+			}
+		}
+
+		return nil
+	})
+}
+
+// resolveFunctions marks the functions touching nodes or MCPs directly, then the functions calling them until no
+// more functions are marked.
+func (audit *auditor) resolveFunctions() {
+	for _, function := range audit.functions {
+		function.reason = audit.directTouch(function.file, function.decl.Body)
+		function.touches = function.reason != ""
+	}
+
+	for changed := true; changed; {
+		changed = false
+
+		for _, function := range audit.functions {
+			if function.touches {
+				continue
+			}
+
+			if reason := audit.helperTouch(function.file, function.decl.Body); reason != "" {
+				function.touches, function.reason = true, reason
+				changed = true
+			}
+		}
+	}
+}
+
+// touch returns why node touches nodes or MCPs, or an empty string when it does not.
+func (audit *auditor) touch(file *sourceFile, node ast.Node) string {
+	if reason := audit.directTouch(file, node); reason != "" {
+		return reason
+	}
+
+	return audit.helperTouch(file, node)
+}
+
+// directTouch returns the first mutating call on a watched builder in node.
+func (audit *auditor) directTouch(file *sourceFile, node ast.Node) string {
+	var reason string
+
+	ast.Inspect(node, func(child ast.Node) bool {
+		call, isCall := child.(*ast.CallExpr)
+		if reason != "" || !isCall {
+			return reason == ""
+		}
+
+		selector, isSelector := call.Fun.(*ast.SelectorExpr)
+		if !isSelector || !mutatingMethod.MatchString(selector.Sel.Name) {
+			return true
+		}
+
+		root := rootIdent(selector.X)
+		if root == nil {
+			return true
+		}
+
+		description, watched := watchedPackages[file.imports[root.Name]]
+		if !watched {
+			description, watched = file.tainted[root.Name]
+		}
+
+		if watched {
+			reason = fmt.Sprintf("%s.%s changes %s (%s)", root.Name, selector.Sel.Name, description, audit.position(call))
+		}
+
+		return reason == ""
+	})
+
+	return reason
+}
+
+// helperTouch returns the first call in node to a function touching nodes or MCPs.
+func (audit *auditor) helperTouch(file *sourceFile, node ast.Node) string {
+	var reason string
+
+	ast.Inspect(node, func(child ast.Node) bool {
+		call, isCall := child.(*ast.CallExpr)
+		if reason != "" || !isCall {
+			return reason == ""
+		}
+
+		key, name := file.calledFunction(call)
+		if function, found := audit.functions[key]; found && function.touches {
+			reason = fmt.Sprintf("%s (%s) -> %s", name, audit.position(call), function.reason)
+		}
+
+		return reason == ""
+	})
+
+	return reason
+}
+
+func (audit *auditor) auditFile(file *sourceFile) {
+	for _, decl := range file.ast.Decls {
+		genDecl, isGen := decl.(*ast.GenDecl)
+		if !isGen || genDecl.Tok != token.VAR {
+			continue
+		}
+
+		for _, spec := range genDecl.Specs {
+			for _, value := range spec.(*ast.ValueSpec).Values {
+				if call, isCall := value.(*ast.CallExpr); isCall {
+					audit.auditNode(file, call, container{})
+				}
+			}
+		}
+	}
+}
+
+// auditNode audits the ginkgo node called by call with the state inherited from its parent.
+func (audit *auditor) auditNode(file *sourceFile, call *ast.CallExpr, parent container) {
+	name := file.ginkgoNode(call)
+	if !containerNodes[name] && !subjectNodes[name] {
+		return
+	}
+
+	current := parent
+	current.text = strings.TrimSpace(parent.text + " " + nodeText(call))
+	current.serial = parent.serial || hasDecorator(file, call.Args, "Serial")
+	current.mutates = parent.mutates || hasMutates(file, call.Args)
+
+	body := audit.nodeBody(file, call)
+
+	if subjectNodes[name] {
+		audit.auditSpec(file, call, body, current)
+
+		return
+	}
+
+	if body == nil {
+		return
+	}
+
+	// Setup nodes apply to every spec of the container so they are checked before the nested nodes.
+	for _, stmt := range body.Body.List {
+		if setupCall := statementCall(stmt); setupCall != nil && setupNodes[file.ginkgoNode(setupCall)] {
+			if reason := audit.touch(file, setupCall); reason != "" && !current.touches {
+				current.touches, current.reason = true, reason
+			}
+		}
+	}
+
+	for _, stmt := range body.Body.List {
+		if nestedCall := statementCall(stmt); nestedCall != nil {
+			audit.auditNode(file, nestedCall, current)
+		}
+	}
+}
+
+// referencedTouch returns why the functions passed by name in args, such as the body of It("...", verifyReboot),
+// touch nodes or MCPs.
+func (audit *auditor) referencedTouch(file *sourceFile, args []ast.Expr) string {
+	for _, arg := range args {
+		key, name := file.calledFunction(&ast.CallExpr{Fun: arg})
+		if function, found := audit.functions[key]; found && function.touches {
+			return fmt.Sprintf("%s (%s) -> %s", name, audit.position(arg), function.reason)
+		}
+	}
+
+	return ""
+}
+
+func (audit *auditor) auditSpec(file *sourceFile, call *ast.CallExpr, body *ast.FuncLit, spec container) {
+	if spec.serial || spec.mutates {
+		return
+	}
+
+	reason := spec.reason
+
+	if !spec.touches {
+		reason = audit.touch(file, call)
+	}
+
+	if reason == "" && body == nil {
+		reason = audit.referencedTouch(file, call.Args)
+	}
+
+	if reason == "" {
+		return
+	}
+
+	audit.findings = append(audit.findings, Finding{
+		Location: audit.position(call),
+		Spec:     spec.text,
+		Reason:   reason,
+		position: audit.fileSet.Position(call.Pos()),
+	})
+}
+
+// nodeBody returns the function literal of a ginkgo node, which is its last function argument.
+func (audit *auditor) nodeBody(file *sourceFile, call *ast.CallExpr) *ast.FuncLit {
+	for index := len(call.Args) - 1; index >= 0; index-- {
+		if funcLit, isFuncLit := call.Args[index].(*ast.FuncLit); isFuncLit {
+			return funcLit
+		}
+	}
+
+	return nil
+}
+
+func (audit *auditor) position(node ast.Node) string {
+	position := audit.fileSet.Position(node.Pos())
+
+	return fmt.Sprintf("%s:%d", position.Filename, position.Line)
+}
+
+// ginkgoNode returns the name of the ginkgo function called by call, or an empty string if it is not a ginkgo call.
+func (file *sourceFile) ginkgoNode(call *ast.CallExpr) string {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		if file.imports["."] == ginkgoPath {
+			return fun.Name
+		}
+	case *ast.SelectorExpr:
+		if ident, isIdent := fun.X.(*ast.Ident); isIdent && file.imports[ident.Name] == ginkgoPath {
+			return fun.Sel.Name
+		}
+	}
+
+	return ""
+}
+
+// calledFunction returns the key of the function called by call and its name as written.
+func (file *sourceFile) calledFunction(call *ast.CallExpr) (string, string) {
+	switch fun := call.Fun.(type) {
+	case *ast.Ident:
+		return file.packagePath + "." + fun.Name, fun.Name
+	case *ast.SelectorExpr:
+		if ident, isIdent := fun.X.(*ast.Ident); isIdent && file.imports[ident.Name] != "" {
+			return file.imports[ident.Name] + "." + fun.Sel.Name, ident.Name + "." + fun.Sel.Name
+		}
+	}
+
+	return "", ""
+}
+
+// fileImports returns the packages imported by file keyed by the name they are used with. Dot imports are keyed by
+// ".", keeping the ginkgo one since it is the only one needed.
+func fileImports(file *ast.File) map[string]string {
+	imports := make(map[string]string)
+
+	for _, importSpec := range file.Imports {
+		path, err := strconv.Unquote(importSpec.Path.Value)
+		if err != nil {
+			continue
+		}
+
+		name := filepath.Base(path)
+		if strings.HasPrefix(name, "v") && strings.Contains(path, "/") {
+			if _, err := strconv.Atoi(name[1:]); err == nil {
+				name = filepath.Base(filepath.Dir(path))
+			}
+		}
+
+		if importSpec.Name != nil {
+			name = importSpec.Name.Name
+		}
+
+		if name == "." && imports["."] == ginkgoPath {
+			continue
+		}
+
+		imports[name] = path
+	}
+
+	return imports
+}
+
+// taintedNames returns the names of the variables and parameters in file assigned from, or typed as, watched
+// builders with what the builders manage. Names are not scoped, which is enough for the short-lived variables of specs.
+func taintedNames(file *sourceFile) map[string]string {
+	tainted := make(map[string]string)
+
+	watchedBy := func(expr ast.Expr) string {
+		root := rootIdent(expr)
+		if root == nil {
+			return ""
+		}
+
+		if description, watched := watchedPackages[file.imports[root.Name]]; watched {
+			return description
+		}
+
+		return tainted[root.Name]
+	}
+
+	taint := func(description string, names ...*ast.Ident) {
+		for _, name := range names {
+			if description != "" && name != nil && name.Name != "_" && name.Name != "err" {
+				tainted[name.Name] = description
+			}
+		}
+	}
+
+	// Taint is propagated through assignments and range loops so two passes cover the usual list-then-range code.
+	for range 2 {
+		ast.Inspect(file.ast, func(node ast.Node) bool {
+			switch typed := node.(type) {
+			case *ast.AssignStmt:
+				for _, value := range typed.Rhs {
+					taint(watchedBy(value), identifiers(typed.Lhs)...)
+				}
+			case *ast.RangeStmt:
+				taint(watchedBy(typed.X), identifiers([]ast.Expr{typed.Key, typed.Value})...)
+			case *ast.ValueSpec:
+				if typed.Type != nil {
+					taint(watchedBy(typed.Type), typed.Names...)
+				}
+
+				for _, value := range typed.Values {
+					taint(watchedBy(value), typed.Names...)
+				}
+			case *ast.Field:
+				taint(watchedBy(typed.Type), typed.Names...)
+			}
+
+			return true
+		})
+	}
+
+	return tainted
+}
+
+func identifiers(exprs []ast.Expr) []*ast.Ident {
+	var idents []*ast.Ident
+
+	for _, expr := range exprs {
+		if ident, isIdent := expr.(*ast.Ident); isIdent {
+			idents = append(idents, ident)
+		}
+	}
+
+	return idents
+}
+
+// rootIdent returns the identifier at the start of a chain of calls, selectors, indexes and pointers.
+func rootIdent(expr ast.Expr) *ast.Ident {
+	for {
+		switch typed := expr.(type) {
+		case *ast.Ident:
+			return typed
+		case *ast.SelectorExpr:
+			expr = typed.X
+		case *ast.CallExpr:
+			expr = typed.Fun
+		case *ast.IndexExpr:
+			expr = typed.X
+		case *ast.StarExpr:
+			expr = typed.X
+		case *ast.ArrayType:
+			expr = typed.Elt
+		case *ast.ParenExpr:
+			expr = typed.X
+		default:
+			return nil
+		}
+	}
+}
+
+// hasDecorator returns true if args contain the ginkgo decorator called name.
+func hasDecorator(file *sourceFile, args []ast.Expr, name string) bool {
+	for _, arg := range args {
+		switch typed := arg.(type) {
+		case *ast.Ident:
+			if typed.Name == name && file.imports["."] == ginkgoPath {
+				return true
+			}
+		case *ast.SelectorExpr:
+			if ident, isIdent := typed.X.(*ast.Ident); isIdent && typed.Sel.Name == name &&
+				file.imports[ident.Name] == ginkgoPath {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// hasMutates returns true if args register mutated resources, with parallel.Mutates or mutates labels.
+func hasMutates(file *sourceFile, args []ast.Expr) bool {
+	for _, arg := range args {
+		call, isCall := arg.(*ast.CallExpr)
+		if !isCall {
+			continue
+		}
+
+		if key, _ := file.calledFunction(call); strings.HasSuffix(key, "/tests/internal/parallel.Mutates") {
+			return true
+		}
+
+		if file.ginkgoNode(call) != "Label" {
+			continue
+		}
+
+		for _, labelArg := range call.Args {
+			literal, isLiteral := labelArg.(*ast.BasicLit)
+			if isLiteral && strings.HasPrefix(strings.Trim(literal.Value, "\"`"), mutatesLabelPrefix) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// nodeText returns the text of a ginkgo node, which is its first argument when it is a string literal.
+func nodeText(call *ast.CallExpr) string {
+	if len(call.Args) == 0 {
+		return ""
+	}
+
+	if literal, isLiteral := call.Args[0].(*ast.BasicLit); isLiteral && literal.Kind == token.STRING {
+		if text, err := strconv.Unquote(literal.Value); err == nil {
+			return text
+		}
+	}
+
+	return "<dynamic>"
+}
+
+func statementCall(stmt ast.Stmt) *ast.CallExpr {
+	exprStmt, isExpr := stmt.(*ast.ExprStmt)
+	if !isExpr {
+		return nil
+	}
+
+	call, _ := exprStmt.X.(*ast.CallExpr)
+
+	return call
+}
+
+// findModule returns the module path and root directory of the module containing dir.
+func findModule(dir string) (string, string, error) {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", "", err
+	}
+
+	for current := absDir; ; current = filepath.Dir(current) {
+		content, err := os.ReadFile(filepath.Join(current, "go.mod"))
+		if err == nil {
+			for line := range strings.SplitSeq(string(content), "\n") {
+				if modulePath, found := strings.CutPrefix(strings.TrimSpace(line), "module "); found {
+					return strings.TrimSpace(modulePath), current, nil
+				}
+			}
+
+			return "", "", fmt.Errorf("no module path in %s", filepath.Join(current, "go.mod"))
+		}
+
+		if filepath.Dir(current) == current {
+			return "", "", fmt.Errorf("no go.mod found for %s", dir)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+/*
+Serial-audit is a tool to find the specs which are not safe to run with ginkgo -p. It flags every spec which changes
+nodes, machine configs, MCPs, performance profiles or machine sets, directly or through helper functions, while
+neither the spec nor any of its containers is decorated with Serial or registers the resources it mutates with
+parallel.Mutates.
+
+The specs are found by parsing the Go files, so no cluster is needed and specs skipped at runtime are audited too.
+Changes are detected from calls to the Create, Update, Delete, Cordon, Uncordon and Drain methods of the builders of
+the watched eco-goinfra packages, including in BeforeEach, AfterEach, BeforeAll and AfterAll nodes of the containers.
+Findings are printed to stdout and cause the exit code to be 1.
+
+Usage:
+
+	serial-audit [flags]
+
+The flags are:
+
+	-h, -help
+		Print this help message
+
+	-d, -dir string
+		Directory whose specs are audited (default "tests")
+
+	-j, -json
+		Print the findings as JSON
+*/
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+
+	"k8s.io/klog/v2"
+)
+
+var (
+	help       bool
+	dir        string
+	jsonOutput bool
+)
+
+//nolint:gochecknoinits // This is a main package so init is fine.
+func init() {
+	const (
+		helpUsage = "Print this help message"
+		dirUsage  = "Directory whose specs are audited"
+		jsonUsage = "Print the findings as JSON"
+
+		defaultHelp = false
+		defaultDir  = "tests"
+		defaultJSON = false
+
+		shorthand = " (shorthand)"
+	)
+
+	klog.InitFlags(nil)
+
+	_ = flag.Set("logtostderr", "true")
+
+	flag.BoolVar(&help, "help", defaultHelp, helpUsage)
+	flag.BoolVar(&help, "h", defaultHelp, helpUsage+shorthand)
+
+	flag.StringVar(&dir, "dir", defaultDir, dirUsage)
+	flag.StringVar(&dir, "d", defaultDir, dirUsage+shorthand)
+
+	flag.BoolVar(&jsonOutput, "json", defaultJSON, jsonUsage)
+	flag.BoolVar(&jsonOutput, "j", defaultJSON, jsonUsage+shorthand)
+}
+
+func main() {
+	flag.Parse()
+
+	if help {
+		flag.Usage()
+
+		return
+	}
+
+	findings, err := Audit(dir)
+	if err != nil {
+		klog.Errorf("Failed to audit specs in %s: %v", dir, err)
+
+		os.Exit(1)
+	}
+
+	if jsonOutput {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+
+		err = encoder.Encode(findings)
+		if err != nil {
+			klog.Errorf("Failed to print findings: %v", err)
+
+			os.Exit(1)
+		}
+	} else {
+		for _, finding := range findings {
+			fmt.Printf("%s: %q is not Serial: %s\n", finding.Location, finding.Spec, finding.Reason)
+		}
+	}
+
+	if len(findings) > 0 {
+		os.Exit(1)
+	}
+}
@@ -17,9 +17,11 @@ declare -A GLOBAL_VARS
 while IFS= read -r v; do
     [[ -n "$v" ]] && GLOBAL_VARS["$v"]=1
 done < <({
-    grep -ohP 'ECO_[A-Z0-9_]+' "$REPO_ROOT/scripts/test-runner.sh" 2>/dev/null || true
+    grep -ohP 'ECO_[A-Z0-9_]+' "$REPO_ROOT"/cmd/eco-runner/*.go 2>/dev/null || true
     grep -ohP 'envconfig:"ECO_[A-Z0-9_]+"' "$TESTS_DIR/internal/config/config.go" 2>/dev/null \
         | sed 's/envconfig:"//;s/"//' || true
+    grep -ohP 'Env[A-Za-z]+ += "ECO_[A-Z0-9_]+"' "$TESTS_DIR/internal/config/layers.go" 2>/dev/null \
+        | grep -oP 'ECO_[A-Z0-9_]+' || true
 } | sort -u)
 
 readme_for_config() {
@@ -2,14 +2,11 @@ package accelconfig
 
 import (
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 
-	"github.com/kelseyhightower/envconfig"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
-	"gopkg.in/yaml.v2"
 	"k8s.io/klog/v2"
 )
 
@@ -20,7 +17,7 @@ const (
 
 // AccelConfig contains environment information related to ocp upgrade tests.
 type AccelConfig struct {
-	PullSecret           string `envconfig:"ECO_ACCEL_PULL_SECRET"`
+	PullSecret           string `envconfig:"ECO_ACCEL_PULL_SECRET" eco_secret:"true"`
 	Registry             string `envconfig:"ECO_ACCEL_REGISTRY"`
 	UpgradeTargetVersion string `envconfig:"ECO_ACCEL_UPGRADE_TARGET_IMAGE"`
 	SpokeKubeConfig      string `envconfig:"ECO_ACCEL_SPOKE_KUBECONFIG"`
@@ -43,20 +40,15 @@ func NewAccelConfig() *AccelConfig {
 	baseDir := filepath.Dir(filename)
 	configFile := filepath.Join(baseDir, PathToDefaultAccelParamsFile)
 
-	err := readFile(&accelConfig, configFile)
-	if err != nil {
-		klog.V(90).Infof("Error reading config file %s", configFile)
-
-		return nil
-	}
-
-	err = envconfig.Process("eco_accel_", &accelConfig)
+	sources, err := config.LoadLayers(&accelConfig, configFile, "eco_accel_")
 	if err != nil {
 		log.Printf("failed to instantiate AccelConfig: %v", err)
 
 		return nil
 	}
 
+	accelConfig.RecordSources(sources)
+
 	if accelConfig.SpokeKubeConfig != "" {
 		klog.V(90).Infof("Creating spoke api client from %s", accelConfig.SpokeKubeConfig)
 
@@ -70,20 +62,3 @@ func NewAccelConfig() *AccelConfig {
 
 	return &accelConfig
 }
-
-func readFile(accelConfig *AccelConfig, configFile string) error {
-	openedConfigFile, err := os.Open(configFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedConfigFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedConfigFile)
-
-	err = decoder.Decode(&accelConfig)
-
-	return err
-}
@@ -42,4 +42,5 @@ var _ = JustAfterEach(func() {
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, AccelConfig.GetReportPath(), AccelConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
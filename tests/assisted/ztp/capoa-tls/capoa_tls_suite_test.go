@@ -34,6 +34,7 @@ var _ = BeforeSuite(func() {
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, GeneralConfig.GetReportPath(), GeneralConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
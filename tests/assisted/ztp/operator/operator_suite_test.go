@@ -43,6 +43,7 @@ var _ = BeforeSuite(func() {
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, ZTPConfig.GetReportPath(), ZTPConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
@@ -7,13 +7,12 @@ import (
 	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
 	performanceprofileV2 "github.com/openshift/cluster-node-tuning-operator/pkg/apis/performanceprofile/v2"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
 )
 
 const (
 	// LabelSuite represents acc label that can be used for test cases selection.
 	LabelSuite = "accelerator"
-	// TestNamespaceName acc namespace where all test cases are performed.
-	TestNamespaceName = "accelerator-tests"
 	// Acc100DeviceID represents the device id of the acc100.
 	Acc100DeviceID = "0d5c"
 	// Acc100ResourceName represents the resource name of the acc100.
@@ -26,6 +25,10 @@ const (
 	ExpectedNumberBbdevTestsPassedForAcc100 = 27
 )
 
+// TestNamespaceName acc namespace where all test cases are performed.
+// It is suffixed per ginkgo process when running in parallel.
+var TestNamespaceName = parallel.Namespace("accelerator-tests")
+
 var (
 	// Labels represents the range of labels that can be used for test cases selection.
 	Labels = append(netparam.Labels, LabelSuite)
@@ -5,6 +5,7 @@ import (
 	"github.com/openshift-kni/k8sreporter"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netinittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
 )
 
 var (
@@ -13,7 +14,7 @@ var (
 	// LabelTapTestCases tap test cases label.
 	LabelTapTestCases = "tap"
 	// TestNamespaceName cni namespace where all test cases are performed.
-	TestNamespaceName = "cni-tests"
+	TestNamespaceName = parallel.Namespace("cni-tests")
 	// ReporterNamespacesToDump tells to the reporter from where to collect logs.
 	ReporterNamespacesToDump = map[string]string{
 		NetConfig.MultusNamesapce: NetConfig.MultusNamesapce,
@@ -76,4 +76,5 @@ var _ = JustAfterEach(func() {
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, NetConfig.GetReportPath(), NetConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
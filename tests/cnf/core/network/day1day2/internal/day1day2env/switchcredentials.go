@@ -1,4 +1,4 @@
-package juniper
+package day1day2env
 
 import . "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netinittools"
 
@@ -8,11 +8,12 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
 
 	"github.com/openshift-kni/k8sreporter"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
 )
 
 var (
 	// TestNamespaceName day1day2 namespace where all test cases are performed.
-	TestNamespaceName = "day1day2-tests"
+	TestNamespaceName = parallel.Namespace("day1day2-tests")
 
 	// Labels represents the range of labels that can be used for test cases selection.
 	Labels = append(netparam.Labels, LabelSuite)
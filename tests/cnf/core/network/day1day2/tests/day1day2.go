@@ -15,12 +15,12 @@ import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/reportxml"
 
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/day1day2/internal/day1day2env"
-	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/day1day2/internal/juniper"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/day1day2/internal/tsparams"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/cmd"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netinittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netnmstate"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/netswitch"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 )
@@ -30,7 +30,8 @@ var _ = Describe("Day1Day2", Ordered, Label(tsparams.LabelSuite), ContinueOnFail
 		workerNodeList   []*nodes.Builder
 		bondName         string
 		bondSlaves       []string
-		juniperSession   *juniper.JunosSession
+		juniperSession   *netswitch.Junos
+		switchSnapshot   *netswitch.Snapshot
 		switchInterfaces []string
 		switchLagNames   []string
 	)
@@ -60,12 +61,12 @@ var _ = Describe("Day1Day2", Ordered, Label(tsparams.LabelSuite), ContinueOnFail
 
 		By("Getting switch credentials")
 
-		switchCredentials, err := juniper.NewSwitchCredentials()
+		switchCredentials, err := day1day2env.NewSwitchCredentials()
 		Expect(err).ToNot(HaveOccurred(), "Failed to get switch credentials")
 
 		By("Opening management connection to switch")
 
-		juniperSession, err = juniper.NewSession(
+		juniperSession, err = netswitch.NewJunos(
 			switchCredentials.SwitchIP, switchCredentials.User, switchCredentials.Password)
 		Expect(err).ToNot(HaveOccurred(), "Failed to open a switch session")
 
@@ -81,9 +82,11 @@ var _ = Describe("Day1Day2", Ordered, Label(tsparams.LabelSuite), ContinueOnFail
 	})
 
 	AfterEach(func() {
-		if len(juniper.InterfaceConfigs) > 0 {
+		if switchSnapshot != nil {
 			By("Reverting initial switch interface configurations")
-			recoverSwitchConfiguration(juniperSession, switchInterfaces, switchLagNames)
+			recoverSwitchConfiguration(juniperSession, switchSnapshot, switchLagNames)
+
+			switchSnapshot = nil
 
 			By("Verifying workers are still available over the bond interface")
 
@@ -105,7 +108,9 @@ var _ = Describe("Day1Day2", Ordered, Label(tsparams.LabelSuite), ContinueOnFail
 
 	It("Day1: Validate cluster deployed via bond interface with 2 VFs enslaved and fail-over",
 		reportxml.ID("63928"), func() {
-			err := juniper.DumpInterfaceConfigs(juniperSession, switchInterfaces)
+			var err error
+
+			switchSnapshot, err = juniperSession.Snapshot(switchInterfaces...)
 			Expect(err).ToNot(HaveOccurred(), "Failed to save initial switch interfaces configs")
 
 			By("Testing Bond fail over scenario")
@@ -218,24 +223,26 @@ var _ = Describe("Day1Day2", Ordered, Label(tsparams.LabelSuite), ContinueOnFail
 	})
 })
 
-func recoverSwitchConfiguration(juniperSession *juniper.JunosSession, switchInterfaces, lagInterfaces []string) {
-	err := juniper.RestoreSwitchInterfacesConfiguration(juniperSession, switchInterfaces)
+func recoverSwitchConfiguration(
+	juniperSession netswitch.Switch, switchSnapshot *netswitch.Snapshot, lagInterfaces []string,
+) {
+	err := juniperSession.Restore(switchSnapshot)
 	Expect(err).ToNot(HaveOccurred(), "Failed to restore initial switch interfaces configurations")
 
-	err = juniper.DeleteInterfaces(juniperSession, lagInterfaces)
+	err = juniperSession.DeleteInterfaces(lagInterfaces...)
 	Expect(err).ToNot(HaveOccurred(), "Failed to delete switch LAG interfaces")
 }
 
-func waitForSwitchInterfaceUp(juniperSession *juniper.JunosSession, switchLagName string) {
+func waitForSwitchInterfaceUp(juniperSession netswitch.Switch, switchLagName string) {
 	Eventually(func() bool {
-		isBondInterfaceUp, err := juniper.IsSwitchInterfaceUp(juniperSession, switchLagName)
+		isBondInterfaceUp, err := juniperSession.IsInterfaceUp(switchLagName)
 		Expect(err).ToNot(HaveOccurred(), fmt.Sprintf("Failed to get status of switch LAG interface %s", switchLagName))
 
 		return isBondInterfaceUp
 	}, 1*time.Minute, 5*time.Second).Should(BeTrue(), "Bond interface is not Up on the switch")
 }
 
-func testBondFailOver(juniperSession *juniper.JunosSession, switchInterfaces []string) {
+func testBondFailOver(juniperSession netswitch.Switch, switchInterfaces []string) {
 	By("Verifying workers are still available over the bond interface")
 
 	err := day1day2env.CheckConnectivityBetweenMasterAndWorkers()
@@ -243,7 +250,7 @@ func testBondFailOver(juniperSession *juniper.JunosSession, switchInterfaces []s
 
 	By("Disabling one bond slave interface on the switch and check the traffic again via secondary bond interface")
 
-	err = juniper.DisableSwitchInterface(juniperSession, switchInterfaces[0])
+	err = juniperSession.SetInterfaceEnabled(switchInterfaces[0], false)
 	Expect(err).ToNot(HaveOccurred(), fmt.Sprintf("Failed to shutdown switch interface %s", switchInterfaces[0]))
 
 	err = day1day2env.CheckConnectivityBetweenMasterAndWorkers()
@@ -252,11 +259,11 @@ func testBondFailOver(juniperSession *juniper.JunosSession, switchInterfaces []s
 	By(fmt.Sprintf("Disabling secondary LAG slave interface %s, bring first LAG slave interface %s back"+
 		" and check the traffic again", switchInterfaces[1], switchInterfaces[0]))
 
-	err = juniper.EnableSwitchInterface(juniperSession, switchInterfaces[0])
+	err = juniperSession.SetInterfaceEnabled(switchInterfaces[0], true)
 	Expect(err).ToNot(HaveOccurred(),
 		fmt.Sprintf("Failed to turn on the switch interface %s", switchInterfaces[0]))
 
-	err = juniper.DisableSwitchInterface(juniperSession, switchInterfaces[1])
+	err = juniperSession.SetInterfaceEnabled(switchInterfaces[1], false)
 	Expect(err).ToNot(HaveOccurred(), fmt.Sprintf("Failed to shutdown switch interface %s", switchInterfaces[1]))
 
 	waitForSwitchInterfaceUp(juniperSession, switchInterfaces[0])
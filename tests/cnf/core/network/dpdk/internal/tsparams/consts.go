@@ -1,8 +1,12 @@
 package tsparams
 
+import "github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
+
 const (
 	// LabelSuite represents dpdk label that can be used for test cases selection.
 	LabelSuite = "dpdk"
-	// TestNamespaceName dpdk namespace where all test cases are performed.
-	TestNamespaceName = "dpdk-tests"
 )
+
+// TestNamespaceName dpdk namespace where all test cases are performed.
+// It is suffixed per ginkgo process when running in parallel.
+var TestNamespaceName = parallel.Namespace("dpdk-tests")
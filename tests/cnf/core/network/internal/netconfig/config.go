@@ -4,15 +4,13 @@ import (
 	"fmt"
 	"log"
 	"net"
-	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 
-	"github.com/kelseyhightower/envconfig"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/internal/coreconfig"
-	"gopkg.in/yaml.v2"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 )
 
 const (
@@ -31,7 +29,7 @@ type NetworkConfig struct {
 	CnfMcpLabel                    string `yaml:"cnf_mcp_label" envconfig:"ECO_CNF_CORE_NET_CNF_MCP_LABEL"`
 	MultusNamesapce                string `yaml:"multus_namespace" envconfig:"ECO_CNF_CORE_NET_MULTUS_NAMESPACE"`
 	SwitchUser                     string `envconfig:"ECO_CNF_CORE_NET_SWITCH_USER"`
-	SwitchPass                     string `envconfig:"ECO_CNF_CORE_NET_SWITCH_PASS"`
+	SwitchPass                     string `envconfig:"ECO_CNF_CORE_NET_SWITCH_PASS" eco_secret:"true"`
 	SwitchIP                       string `envconfig:"ECO_CNF_CORE_NET_SWITCH_IP"`
 	SwitchInterfaces               string `envconfig:"ECO_CNF_CORE_NET_SWITCH_INTERFACES"`
 	PrimarySwitchInterfaces        string `envconfig:"ECO_CNF_CORE_NET_PRIMARY_SWITCH_INTERFACES"`
@@ -48,7 +46,7 @@ type NetworkConfig struct {
 	NativeVLAN   string `envconfig:"ECO_CNF_CORE_NET_NATIVE_VLAN"`
 	BMCHostNames string `envconfig:"ECO_CNF_CORE_NET_BMC_HOST_NAMES"`
 	BMCHostUser  string `envconfig:"ECO_CNF_CORE_NET_BMC_HOST_USER"`
-	BMCHostPass  string `envconfig:"ECO_CNF_CORE_NET_BMC_HOST_PASS"`
+	BMCHostPass  string `envconfig:"ECO_CNF_CORE_NET_BMC_HOST_PASS" eco_secret:"true"`
 }
 
 // NewNetConfig returns instance of NetworkConfig config type.
@@ -63,19 +61,14 @@ func NewNetConfig() *NetworkConfig {
 	baseDir := filepath.Dir(filename)
 	confFile := filepath.Join(baseDir, PathToDefaultCnfCoreNetParamsFile)
 
-	err := readFile(&netConf, confFile)
+	sources, err := config.LoadLayers(&netConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&netConf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	netConf.RecordSources(sources)
 
 	return &netConf
 }
@@ -267,32 +260,3 @@ func (netConfig *NetworkConfig) GetClusterVlan() (string, error) {
 
 	return netConfig.ClusterVlan, nil
 }
-
-func readFile(netConfig *NetworkConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(&netConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(netConfig *NetworkConfig) error {
-	err := envconfig.Process("", netConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
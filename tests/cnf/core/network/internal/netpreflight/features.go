@@ -0,0 +1,82 @@
+// Package netpreflight defines the sriov and metallb features checked by the eco-preflight command.
+package netpreflight
+
+import (
+	"fmt"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nodes"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/preflight"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/requires"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/sriovoperator"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// NewSriovFeature returns the sriov feature, which needs the SR-IOV operator and a worker node with the SR-IOV
+// interfaces of ECO_CNF_CORE_NET_SRIOV_INTERFACE_LIST.
+func NewSriovFeature() preflight.Feature {
+	netConfig := netconfig.NewNetConfig()
+	if netConfig == nil {
+		return preflight.Feature{Name: "sriov"}
+	}
+
+	return preflight.Feature{
+		Name:   "sriov",
+		Config: netConfig,
+		Requirements: []requires.Requirement{
+			requires.OperatorVersion(netConfig.SriovOperatorNamespace, "sriov-network-operator", ""),
+			requires.Nodes(netConfig.WorkerLabel, 1),
+			SriovInterfaces(netConfig),
+		},
+	}
+}
+
+// NewMetalLBFeature returns the metallb feature, which needs the MetalLB operator and two worker nodes.
+func NewMetalLBFeature() preflight.Feature {
+	netConfig := netconfig.NewNetConfig()
+	if netConfig == nil {
+		return preflight.Feature{Name: "metallb"}
+	}
+
+	return preflight.Feature{
+		Name:   "metallb",
+		Config: netConfig,
+		Requirements: []requires.Requirement{
+			requires.OperatorVersion(netConfig.MlbOperatorNamespace, "metallb-operator", ""),
+			requires.Nodes(netConfig.WorkerLabel, 2),
+		},
+	}
+}
+
+// SriovInterfaces requires every interface of netConfig.GetSriovInterfaces to be an SR-IOV interface of the first
+// worker node, as discovered by sriovoperator.DiscoverInterfaceUnderTestVendorID.
+func SriovInterfaces(netConfig *netconfig.NetworkConfig) requires.Requirement {
+	return requires.New("SR-IOV interfaces", func(apiClient *clients.Settings) (bool, string) {
+		if netConfig.SriovInterfaces == "" {
+			return false, "no SR-IOV interfaces set, check ECO_CNF_CORE_NET_SRIOV_INTERFACE_LIST env var"
+		}
+
+		interfaces, err := netConfig.GetSriovInterfaces(1)
+		if err != nil {
+			return false, err.Error()
+		}
+
+		workers, err := nodes.List(apiClient, metav1.ListOptions{LabelSelector: netConfig.WorkerLabel})
+		if err != nil || len(workers) == 0 {
+			return false, fmt.Sprintf("failed to find a worker node: %v", err)
+		}
+
+		workerName := workers[0].Definition.Name
+
+		for _, sriovInterface := range interfaces {
+			_, err := sriovoperator.DiscoverInterfaceUnderTestVendorID(
+				apiClient, netConfig.SriovOperatorNamespace, sriovInterface, workerName)
+			if err != nil {
+				return false, fmt.Sprintf("SR-IOV interface %s not found on node %s: %v", sriovInterface, workerName, err)
+			}
+		}
+
+		return true, ""
+	})
+}
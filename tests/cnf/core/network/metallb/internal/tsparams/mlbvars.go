@@ -8,6 +8,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/metallb"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netinittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -41,7 +42,7 @@ var (
 		{Cr: &corev1.ServiceList{}, Namespace: &TestNamespaceName},
 	}
 	// TestNamespaceName metalLb namespace where all test cases are performed.
-	TestNamespaceName = "metallb-tests"
+	TestNamespaceName = parallel.Namespace("metallb-tests")
 	// OperatorControllerManager defaults metalLb daemonset controller name.
 	OperatorControllerManager = "metallb-operator-controller-manager"
 	// OperatorWebhook defaults metalLb webhook deployment name.
@@ -1,10 +1,14 @@
 package tsparams
 
+import "github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
+
 const (
 	// LabelSuite represents nmstate label that can be used for test cases selection.
 	LabelSuite = "nmstate"
-	// TestNamespaceName is the namespace where nmstate test cases are performed.
-	TestNamespaceName = "nmstate-tests"
 	// LabelAltnames represents nmstate altnames label that can be used for test cases selection.
 	LabelAltnames = "nmstate-altnames"
 )
+
+// TestNamespaceName is the namespace where nmstate test cases are performed.
+// It is suffixed per ginkgo process when running in parallel.
+var TestNamespaceName = parallel.Namespace("nmstate-tests")
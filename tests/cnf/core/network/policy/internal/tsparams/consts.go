@@ -1,12 +1,16 @@
 package tsparams
 
+import "github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
+
 const (
 	// LabelSuite represents policy label that can be used for test cases selection.
 	LabelSuite = "policy"
-	// TestNamespaceName policy namespace where all test cases are performed.
-	TestNamespaceName = "policy-tests"
 	// MultiNetPolNs1 policy namespace where all test cases are performed.
 	MultiNetPolNs1 = "policy-ns1"
 	// MultiNetPolNs2 policy namespace where all test cases are performed.
 	MultiNetPolNs2 = "policy-ns2"
 )
+
+// TestNamespaceName policy namespace where all test cases are performed.
+// It is suffixed per ginkgo process when running in parallel.
+var TestNamespaceName = parallel.Namespace("policy-tests")
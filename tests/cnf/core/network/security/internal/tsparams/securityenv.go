@@ -4,6 +4,7 @@ import (
 	"github.com/openshift-kni/k8sreporter"
 	mcfgv1 "github.com/openshift/api/machineconfiguration/v1"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
 )
 
 var (
@@ -17,7 +18,7 @@ var (
 	}
 
 	// TestNamespaceName security-tests namespace where all test cases are performed.
-	TestNamespaceName = "security-tests"
+	TestNamespaceName = parallel.Namespace("security-tests")
 	// ReporterCRDsToDump tells to the reporter what CRs to dump.
 	ReporterCRDsToDump = []k8sreporter.CRData{
 		{Cr: &mcfgv1.MachineConfigList{}},
@@ -1,14 +1,10 @@
 package tsparams
 
+import "github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
+
 const (
 	// LabelSuite represents sriov label that can be used for test cases selection.
 	LabelSuite = "sriov"
-	// TestNamespaceName sriov namespace where all test cases are performed.
-	TestNamespaceName = "sriov-tests"
-	// TestNamespaceName1 sriov namespace where all test cases are performed.
-	TestNamespaceName1 = "sriov-tests-1"
-	// TestNamespaceName2 sriov namespace where all test cases are performed.
-	TestNamespaceName2 = "sriov-tests-2"
 	// LabelExternallyManagedTestCases represents ExternallyManaged label that can be used for test cases selection.
 	LabelExternallyManagedTestCases = "externallymanaged"
 	// LabelParallelDrainingTestCases represents parallel draining label that can be used for test cases selection.
@@ -92,3 +88,13 @@ const (
 	// DualStackMulticastV6Port is the multicast listener port for IPv6 in dual-stack tests.
 	DualStackMulticastV6Port = 5006
 )
+
+// Namespaces are suffixed per ginkgo process when running in parallel.
+var (
+	// TestNamespaceName sriov namespace where all test cases are performed.
+	TestNamespaceName = parallel.Namespace("sriov-tests")
+	// TestNamespaceName1 sriov namespace where all test cases are performed.
+	TestNamespaceName1 = parallel.Namespace("sriov-tests-1")
+	// TestNamespaceName2 sriov namespace where all test cases are performed.
+	TestNamespaceName2 = parallel.Namespace("sriov-tests-2")
+)
@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -18,13 +19,13 @@ import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/reportxml"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/sriov"
-	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/cmd"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netinittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netnmstate"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/sriov/internal/sriovenv"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/sriov/internal/tsparams"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/cluster"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/netswitch"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/perfprofile"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/sriovoperator"
 	corev1 "k8s.io/api/core/v1"
@@ -76,7 +77,7 @@ var _ = Describe("LACP Status Relay", Ordered, Label(tsparams.LabelLACPTestCases
 		worker1NodeName              string
 		secondaryInterface0          string
 		secondaryInterface1          string
-		originalInterfaceConfigs     []string
+		originalInterfaceConfigs     *netswitch.Snapshot
 		lacpInterfaces               []string
 		lacpConfigured               bool
 		physicalInterfacesConfigured bool
@@ -159,9 +160,6 @@ var _ = Describe("LACP Status Relay", Ordered, Label(tsparams.LabelLACPTestCases
 
 		physicalInterfacesConfigured = true
 
-		By("Configure LACP block firewall filter on switch")
-		configureLACPBlockFirewallFilter(switchCredentials)
-
 		By("Creating NMState instance")
 
 		err = netnmstate.CreateNewNMStateAndWaitUntilItsRunning(7 * time.Minute)
@@ -753,12 +751,12 @@ var _ = Describe("LACP Status Relay", Ordered, Label(tsparams.LabelLACPTestCases
 	})
 })
 
-func lacpSwitchCleanup(credentials *sriovenv.SwitchCredentials, lacpInterfaces, interfaces, configs []string,
-	lacpConfigured, physicalInterfacesConfigured bool) {
+func lacpSwitchCleanup(credentials *sriovenv.SwitchCredentials, lacpInterfaces, interfaces []string,
+	configs *netswitch.Snapshot, lacpConfigured, physicalInterfacesConfigured bool) {
 	By("Restoring switch configuration to pre-test state")
 
 	// If we have saved configs, we should attempt cleanup even if flags aren't set.
-	if !lacpConfigured && !physicalInterfacesConfigured && len(configs) == 0 {
+	if !lacpConfigured && !physicalInterfacesConfigured && configs == nil {
 		By("No switch configuration was modified, skipping cleanup")
 
 		return
@@ -770,7 +768,7 @@ func lacpSwitchCleanup(credentials *sriovenv.SwitchCredentials, lacpInterfaces,
 		return
 	}
 
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	Expect(err).ToNot(HaveOccurred(), "Failed to create switch session")
 
 	defer jnpr.Close()
@@ -781,7 +779,7 @@ func lacpSwitchCleanup(credentials *sriovenv.SwitchCredentials, lacpInterfaces,
 		if len(lacpInterfaces) > 0 && len(interfaces) > 0 {
 			By(fmt.Sprintf("Disabling LACP on interfaces %v (LACP interfaces: %v)", interfaces, lacpInterfaces))
 
-			err = jnpr.DisableLACP(lacpInterfaces, interfaces)
+			err = jnpr.DeleteInterfaces(slices.Concat(lacpInterfaces, interfaces)...)
 			if err != nil {
 				By(fmt.Sprintf("Warning: Failed to disable LACP: %v (continuing with restore)", err))
 			}
@@ -789,10 +787,10 @@ func lacpSwitchCleanup(credentials *sriovenv.SwitchCredentials, lacpInterfaces,
 	}
 
 	// Restore interface configurations after removing from ae.
-	if len(configs) > 0 {
+	if configs != nil {
 		By("Restoring original interface configurations")
 		Eventually(func() error {
-			return jnpr.RestoreInterfaceConfigs(configs)
+			return jnpr.Restore(configs)
 		}, 60*time.Second, 5*time.Second).Should(Succeed(),
 			"Failed to restore interface configs after LACP cleanup")
 	} else if physicalInterfacesConfigured {
@@ -1459,22 +1457,22 @@ func removeLACPBondInterfaces(workerNodeName string) error {
 	return nil
 }
 
-func saveSwitchInterfaceConfigs(credentials *sriovenv.SwitchCredentials, interfaces []string) []string {
+func saveSwitchInterfaceConfigs(credentials *sriovenv.SwitchCredentials, interfaces []string) *netswitch.Snapshot {
 	By("Saving switch interface configurations for restoration")
 
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	Expect(err).ToNot(HaveOccurred(), "Failed to create switch session for saving configs")
 
 	defer jnpr.Close()
 
-	configs, err := jnpr.SaveInterfaceConfigs(interfaces)
+	configs, err := jnpr.Snapshot(interfaces...)
 	Expect(err).ToNot(HaveOccurred(), "Failed to save interface configs")
 
 	return configs
 }
 
 func enableLACPOnSwitchInterfaces(credentials *sriovenv.SwitchCredentials, lacpInterfaces []string) error {
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	if err != nil {
 		return err
 	}
@@ -1485,71 +1483,48 @@ func enableLACPOnSwitchInterfaces(credentials *sriovenv.SwitchCredentials, lacpI
 		return fmt.Errorf("native VLAN: %w", err)
 	}
 
-	var commands []string
-
 	for _, lacpInterface := range lacpInterfaces {
-		commands = append(commands,
-			fmt.Sprintf("set interfaces %s aggregated-ether-options lacp active", lacpInterface),
-			fmt.Sprintf("set interfaces %s aggregated-ether-options lacp periodic fast", lacpInterface),
-			fmt.Sprintf("set interfaces %s unit 0 family ethernet-switching interface-mode trunk", lacpInterface),
-			fmt.Sprintf("set interfaces %s unit 0 family ethernet-switching interface-mode trunk vlan "+
-				"members vlan%d", lacpInterface, vlan),
-			fmt.Sprintf("set interfaces %s native-vlan-id %d", lacpInterface, vlan),
-			fmt.Sprintf("set interfaces %s mtu 9216", lacpInterface),
-		)
-	}
+		err = jnpr.ConfigureLAG(netswitch.LAG{Name: lacpInterface, LACP: netswitch.LACPActive, FastRate: true})
+		if err != nil {
+			return err
+		}
 
-	err = jnpr.Config(commands)
-	if err != nil {
-		return err
+		err = jnpr.SetTrunk(lacpInterface, netswitch.Trunk{VLANs: []int{vlan}, NativeVLAN: vlan, MTU: 9216})
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
 func deletePhysicalInterfaces(credentials *sriovenv.SwitchCredentials, physicalInterfaces []string) {
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	Expect(err).ToNot(HaveOccurred(), "Failed to create switch session")
 
 	defer jnpr.Close()
 
-	var commands []string
-
 	By(fmt.Sprintf("Cleaning up any existing LACP configuration for physical interfaces: %v", physicalInterfaces))
 
-	// Get LACP interface names - these might exist from a previous test run
+	interfaces := physicalInterfaces
+
+	// Get LACP interface names - these might exist from a previous test run. Deleting them removes VLAN references
+	// that might be invalid.
 	lacpInterfaces, err := NetConfig.GetSwitchLagNames()
 	if err == nil && len(lacpInterfaces) > 0 {
-		// First, remove physical interfaces from any LACP interfaces.
-		for _, physicalInterface := range physicalInterfaces {
-			commands = append(commands, fmt.Sprintf("delete interfaces %s ether-options 802.3ad", physicalInterface))
-		}
-
-		// Delete the LACP interfaces themselves (this removes VLAN references that might be invalid)
-		for _, lacpInterface := range lacpInterfaces {
-			commands = append(commands, fmt.Sprintf("delete interfaces %s", lacpInterface))
-		}
-	}
-
-	// Then delete the physical interfaces themselves
-	for _, physicalInterface := range physicalInterfaces {
-		commands = append(commands, fmt.Sprintf("delete interfaces %s", physicalInterface))
+		interfaces = slices.Concat(lacpInterfaces, physicalInterfaces)
 	}
 
-	if len(commands) > 0 {
-		err = jnpr.Config(commands)
-		Expect(err).ToNot(HaveOccurred(), "Failed to delete physical interfaces and clean up LACP configuration")
-	}
+	err = jnpr.DeleteInterfaces(interfaces...)
+	Expect(err).ToNot(HaveOccurred(), "Failed to delete physical interfaces and clean up LACP configuration")
 }
 
 func configurePhysicalInterfacesForLACP(credentials *sriovenv.SwitchCredentials, physicalInterfaces []string) {
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	Expect(err).ToNot(HaveOccurred(), "Failed to create switch session")
 
 	defer jnpr.Close()
 
-	var commands []string
-
 	lacpInterfaces, err := NetConfig.GetSwitchLagNames()
 	Expect(err).ToNot(HaveOccurred(), "Failed to get switch LAG names")
 
@@ -1560,31 +1535,15 @@ func configurePhysicalInterfacesForLACP(credentials *sriovenv.SwitchCredentials,
 		physicalInterfaces[0], lacpInterfaces[0],
 		physicalInterfaces[1], lacpInterfaces[1]))
 
-	commands = append(commands,
-		fmt.Sprintf("set interfaces %s ether-options 802.3ad %s", physicalInterfaces[0], lacpInterfaces[0]),
-		fmt.Sprintf("set interfaces %s ether-options 802.3ad %s", physicalInterfaces[1], lacpInterfaces[1]),
-	)
-
-	err = jnpr.Config(commands)
-	Expect(err).ToNot(HaveOccurred(), "Failed to configure physical interfaces for LACP")
-}
-
-func configureLACPBlockFirewallFilter(credentials *sriovenv.SwitchCredentials) {
-	By("Configuring LACP block firewall filter on switch")
-
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
-	Expect(err).ToNot(HaveOccurred(), "Failed to create switch session")
-
-	defer jnpr.Close()
-
-	commands := []string{
-		"set firewall family ethernet-switching filter BLOCK-LACP term BLOCK from ether-type 0x8809",
-		"set firewall family ethernet-switching filter BLOCK-LACP term BLOCK then discard",
-		"set firewall family ethernet-switching filter BLOCK-LACP term ALLOW-OTHER then accept",
+	for index, lacpInterface := range lacpInterfaces[:2] {
+		err = jnpr.ConfigureLAG(netswitch.LAG{
+			Name:     lacpInterface,
+			Members:  []string{physicalInterfaces[index]},
+			LACP:     netswitch.LACPActive,
+			FastRate: true,
+		})
+		Expect(err).ToNot(HaveOccurred(), "Failed to configure physical interfaces for LACP")
 	}
-
-	err = jnpr.Config(commands)
-	Expect(err).ToNot(HaveOccurred(), "Failed to configure LACP block firewall filter")
 }
 
 func setLACPBlockFilterOnInterface(credentials *sriovenv.SwitchCredentials, enable bool) {
@@ -1601,32 +1560,21 @@ func setLACPBlockFilterOnInterface(credentials *sriovenv.SwitchCredentials, enab
 
 	Expect(err).ToNot(HaveOccurred(), "Failed to get switch LAG names")
 
-	var (
-		command           string
-		actionDescription string
-	)
-
 	firstLagInterface := lacpInterfaces[0]
 
+	actionDescription := "Removing"
 	if enable {
-		command = fmt.Sprintf("set interfaces %s unit 0 family ethernet-switching filter input BLOCK-LACP", firstLagInterface)
 		actionDescription = "Applying"
-	} else {
-		command = fmt.Sprintf("delete interfaces %s unit 0 family ethernet-switching filter input BLOCK-LACP",
-			firstLagInterface)
-		actionDescription = "Removing"
 	}
 
 	By(fmt.Sprintf("%s LACP block filter on interface %s", actionDescription, firstLagInterface))
 
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	Expect(err).ToNot(HaveOccurred(), "Failed to create switch session")
 
 	defer jnpr.Close()
 
-	commands := []string{command}
-
-	err = jnpr.Config(commands)
+	err = jnpr.SetLACPBlocked(firstLagInterface, enable)
 	Expect(err).ToNot(HaveOccurred(),
 		fmt.Sprintf("Failed to %s LACP block filter on interface", strings.ToLower(actionDescription)))
 }
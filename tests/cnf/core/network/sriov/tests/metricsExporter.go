@@ -24,6 +24,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/sriov/internal/sriovenv"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/sriov/internal/tsparams"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/netswitch"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/perfprofile"
 	"gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/types"
 	corev1 "k8s.io/api/core/v1"
@@ -553,14 +554,14 @@ func clearClientServerMacTableFromSwitch() {
 	switchCredentials, err := sriovenv.NewSwitchCredentials()
 	Expect(err).ToNot(HaveOccurred(), "Failed to get switch credentials")
 
-	jnpr, err := cmd.NewSession(switchCredentials.SwitchIP, switchCredentials.User, switchCredentials.Password)
+	jnpr, err := netswitch.NewJunos(switchCredentials.SwitchIP, switchCredentials.User, switchCredentials.Password)
 	Expect(err).ToNot(HaveOccurred(), "Failed to fetch Switch Credentials")
 
 	defer jnpr.Close()
 
-	_, err = jnpr.RunCommand(fmt.Sprintf("clear ethernet-switching table %s", tsparams.ServerMacAddress))
+	err = jnpr.ClearMACAddress(tsparams.ServerMacAddress)
 	Expect(err).ToNot(HaveOccurred(), fmt.Sprintf("Failed to clear mac table for %s", tsparams.ServerMacAddress))
 
-	_, err = jnpr.RunCommand(fmt.Sprintf("clear ethernet-switching table %s", tsparams.ClientMacAddress))
+	err = jnpr.ClearMACAddress(tsparams.ClientMacAddress)
 	Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("Failed to clear mac table for %s", tsparams.ClientMacAddress))
 }
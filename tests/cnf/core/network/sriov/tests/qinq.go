@@ -17,6 +17,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netnmstate"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/internal/netparam"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/cluster"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/netswitch"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/perfprofile"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/sriovoperator"
 	multus "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/types"
@@ -943,38 +944,23 @@ func validateDot1Encapsulation(fileOutput, dot1X string) error {
 }
 
 func enableDot1ADonSwitchInterfaces(credentials *sriovenv.SwitchCredentials, switchInterfaces []string) error {
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
-	if err != nil {
-		return err
-	}
-	defer jnpr.Close()
-
-	for _, switchInterface := range switchInterfaces {
-		commands := []string{fmt.Sprintf("set interfaces %s vlan-tagging encapsulation extended-vlan-bridge",
-			switchInterface)}
-
-		err = jnpr.Config(commands)
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return setQinQOnSwitchInterfaces(credentials, switchInterfaces, true)
 }
 
 func disableQinQOnSwitch(switchCredentials *sriovenv.SwitchCredentials, switchInterfaces []string) error {
-	jnpr, err := cmd.NewSession(switchCredentials.SwitchIP, switchCredentials.User, switchCredentials.Password)
+	return setQinQOnSwitchInterfaces(switchCredentials, switchInterfaces, false)
+}
+
+func setQinQOnSwitchInterfaces(
+	credentials *sriovenv.SwitchCredentials, switchInterfaces []string, enabled bool) error {
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	if err != nil {
 		return err
 	}
 	defer jnpr.Close()
 
 	for _, switchInterface := range switchInterfaces {
-		commands := []string{fmt.Sprintf("delete interfaces %s vlan-tagging", switchInterface)}
-		commands = append(commands, fmt.Sprintf("delete interfaces %s encapsulation extended-vlan-bridge",
-			switchInterface))
-
-		err = jnpr.Config(commands)
+		err = jnpr.SetQinQ(switchInterface, enabled)
 		if err != nil {
 			return err
 		}
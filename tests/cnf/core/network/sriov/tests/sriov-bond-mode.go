@@ -3,6 +3,7 @@ package tests
 import (
 	"fmt"
 	"net"
+	"slices"
 	"strings"
 	"time"
 
@@ -22,6 +23,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/sriov/internal/sriovenv"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/core/network/sriov/internal/tsparams"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/cluster"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/netswitch"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/sriovoperator"
 	multus "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/types"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -55,7 +57,7 @@ var (
 	bondSwitchCredentials  *sriovenv.SwitchCredentials
 	bondSwitchInterfaces   []string
 	bondSwitchLagNames     []string
-	bondSwitchSavedConfigs []string
+	bondSwitchSavedConfigs *netswitch.Snapshot
 )
 
 var _ = Describe(
@@ -171,7 +173,7 @@ var _ = Describe(
 		})
 
 		AfterAll(func() {
-			if len(bondSwitchSavedConfigs) > 0 && bondSwitchCredentials != nil {
+			if bondSwitchSavedConfigs != nil && bondSwitchCredentials != nil {
 				By("Restoring lab switch configuration after bond tests")
 
 				err = restoreBondSwitchLAG(
@@ -1094,11 +1096,11 @@ func verifyBondSlaveFailover(
 	return nil
 }
 
-func waitForSwitchInterfaceUp(jnpr *cmd.Junos, switchInterface string, timeout time.Duration) error {
+func waitForSwitchInterfaceUp(jnpr netswitch.Switch, switchInterface string, timeout time.Duration) error {
 	deadline := time.Now().Add(timeout)
 
 	for {
-		up, err := jnpr.IsSwitchInterfaceUp(switchInterface)
+		up, err := jnpr.IsInterfaceUp(switchInterface)
 		if err == nil && up {
 			return nil
 		}
@@ -1122,7 +1124,7 @@ func toggleSwitchPortsAndVerifyTraffic(clientPod *pod.Builder, serverIP string,
 		return fmt.Errorf("switch LAG not configured (need credentials, 2+ interfaces, LAG name)")
 	}
 
-	jnpr, err := cmd.NewSession(
+	jnpr, err := netswitch.NewJunos(
 		bondSwitchCredentials.SwitchIP, bondSwitchCredentials.User, bondSwitchCredentials.Password)
 	if err != nil {
 		return err
@@ -1130,10 +1132,10 @@ func toggleSwitchPortsAndVerifyTraffic(clientPod *pod.Builder, serverIP string,
 	defer jnpr.Close()
 
 	disable := func(iface string) error {
-		return jnpr.Config([]string{fmt.Sprintf("set interfaces %s disable", iface)})
+		return jnpr.SetInterfaceEnabled(iface, false)
 	}
 	enable := func(iface string) error {
-		return jnpr.Config([]string{fmt.Sprintf("delete interfaces %s disable", iface)})
+		return jnpr.SetInterfaceEnabled(iface, true)
 	}
 	restoreSwitchPortBestEffort := func(iface string) {
 		if restoreErr := enable(iface); restoreErr != nil {
@@ -1262,7 +1264,7 @@ func setupBondSwitchLAGForActiveActiveTests() error {
 	bondSwitchSavedConfigs, err = configureStaticLAGsOnSwitch(
 		bondSwitchCredentials, bondSwitchInterfaces, bondSwitchLagNames)
 	if err != nil {
-		if len(bondSwitchSavedConfigs) > 0 {
+		if bondSwitchSavedConfigs != nil {
 			if restoreErr := restoreBondSwitchLAG(
 				bondSwitchCredentials, bondSwitchInterfaces, bondSwitchLagNames, bondSwitchSavedConfigs,
 			); restoreErr != nil {
@@ -1281,7 +1283,7 @@ func setupBondSwitchLAGForActiveActiveTests() error {
 }
 
 func restoreBondSwitchLAGAfterActiveActiveTest() {
-	if len(bondSwitchSavedConfigs) == 0 || bondSwitchCredentials == nil {
+	if bondSwitchSavedConfigs == nil || bondSwitchCredentials == nil {
 		return
 	}
 
@@ -1294,31 +1296,12 @@ func restoreBondSwitchLAGAfterActiveActiveTest() {
 	bondSwitchSavedConfigs = nil
 }
 
-func bondStaticLAGCleanupCommands(physicalInterfaces, lagInterfaces []string) []string {
-	var cleanupCommands []string
-
-	for _, physicalInterface := range physicalInterfaces {
-		cleanupCommands = append(cleanupCommands,
-			fmt.Sprintf("delete interfaces %s ether-options 802.3ad", physicalInterface))
-	}
-
-	for _, lagInterface := range lagInterfaces {
-		cleanupCommands = append(cleanupCommands, fmt.Sprintf("delete interfaces %s", lagInterface))
-	}
-
-	for _, physicalInterface := range physicalInterfaces {
-		cleanupCommands = append(cleanupCommands, fmt.Sprintf("delete interfaces %s", physicalInterface))
-	}
-
-	return cleanupCommands
-}
-
 // configureStaticLAGsOnSwitch mirrors cnf-gotests configureLAGsOnSwitch: wipe the four physical
 // ports, create two static (non-LACP) 802.3ad LAGs, and trunk lab VLANs on each ae.
 func configureStaticLAGsOnSwitch(
 	credentials *sriovenv.SwitchCredentials,
 	physicalInterfaces, lagInterfaces []string,
-) ([]string, error) {
+) (*netswitch.Snapshot, error) {
 	if len(physicalInterfaces) != bondMinSwitchInterfaces {
 		return nil, fmt.Errorf("need %d switch interfaces, got %d", bondMinSwitchInterfaces, len(physicalInterfaces))
 	}
@@ -1327,23 +1310,19 @@ func configureStaticLAGsOnSwitch(
 		return nil, fmt.Errorf("need 2 switch LAG names, got %d", len(lagInterfaces))
 	}
 
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	if err != nil {
 		return nil, err
 	}
 	defer jnpr.Close()
 
-	savedConfigs, err := jnpr.SaveInterfaceConfigs(physicalInterfaces)
+	savedConfigs, err := jnpr.Snapshot(physicalInterfaces...)
 	if err != nil {
 		return nil, fmt.Errorf("save switch interface configs: %w", err)
 	}
 
-	cleanupCommands := bondStaticLAGCleanupCommands(physicalInterfaces, lagInterfaces)
-
-	if len(cleanupCommands) > 0 {
-		if err := jnpr.Config(cleanupCommands); err != nil {
-			return savedConfigs, fmt.Errorf("clean switch interfaces before LAG setup: %w", err)
-		}
+	if err := jnpr.DeleteInterfaces(slices.Concat(lagInterfaces, physicalInterfaces)...); err != nil {
+		return savedConfigs, fmt.Errorf("clean switch interfaces before LAG setup: %w", err)
 	}
 
 	vlan, err := NetConfig.GetNativeVLANID()
@@ -1358,28 +1337,21 @@ func configureStaticLAGsOnSwitch(
 		{physicalInterfaces[2], physicalInterfaces[3]},
 	}
 
-	var configureCommands []string
-
 	for idx, lagInterface := range lagInterfaces {
-		for _, physicalInterface := range lagMembers[idx] {
-			configureCommands = append(configureCommands,
-				fmt.Sprintf("set interfaces %s ether-options 802.3ad %s", physicalInterface, lagInterface))
+		err := jnpr.ConfigureLAG(netswitch.LAG{
+			Name:    lagInterface,
+			Members: lagMembers[idx][:],
+			LACP:    netswitch.LACPDisabled,
+		})
+		if err == nil {
+			err = jnpr.SetTrunk(lagInterface, netswitch.Trunk{VLANs: []int{vlan}, NativeVLAN: vlan, MTU: 9216})
 		}
 
-		configureCommands = append(configureCommands,
-			fmt.Sprintf("set interfaces %s aggregated-ether-options lacp disable", lagInterface),
-			fmt.Sprintf("set interfaces %s unit 0 family ethernet-switching interface-mode trunk", lagInterface),
-			fmt.Sprintf("set interfaces %s unit 0 family ethernet-switching interface-mode trunk vlan "+
-				"members vlan%d", lagInterface, vlan),
-			fmt.Sprintf("set interfaces %s native-vlan-id %d", lagInterface, vlan),
-			fmt.Sprintf("set interfaces %s mtu 9216", lagInterface),
-		)
-	}
-
-	if err := jnpr.Config(configureCommands); err != nil {
-		return rollbackBondSwitchLAGSetup(
-			credentials, physicalInterfaces, lagInterfaces, savedConfigs,
-			fmt.Errorf("configure static LAGs: %w", err))
+		if err != nil {
+			return rollbackBondSwitchLAGSetup(
+				credentials, physicalInterfaces, lagInterfaces, savedConfigs,
+				fmt.Errorf("configure static LAGs: %w", err))
+		}
 	}
 
 	return savedConfigs, nil
@@ -1387,9 +1359,10 @@ func configureStaticLAGsOnSwitch(
 
 func rollbackBondSwitchLAGSetup(
 	credentials *sriovenv.SwitchCredentials,
-	physicalInterfaces, lagInterfaces, savedConfigs []string,
+	physicalInterfaces, lagInterfaces []string,
+	savedConfigs *netswitch.Snapshot,
 	setupErr error,
-) ([]string, error) {
+) (*netswitch.Snapshot, error) {
 	klog.Warningf("Bond switch LAG setup failed, restoring saved interface configs: %v", setupErr)
 
 	if restoreErr := restoreBondSwitchLAG(
@@ -1403,25 +1376,26 @@ func rollbackBondSwitchLAGSetup(
 
 func restoreBondSwitchLAG(
 	credentials *sriovenv.SwitchCredentials,
-	physicalInterfaces, lagInterfaces, savedConfigs []string,
+	physicalInterfaces, lagInterfaces []string,
+	savedConfigs *netswitch.Snapshot,
 ) error {
-	if credentials == nil || len(savedConfigs) == 0 {
+	if credentials == nil || savedConfigs == nil {
 		return nil
 	}
 
-	jnpr, err := cmd.NewSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	if err != nil {
 		return err
 	}
 	defer jnpr.Close()
 
 	if len(lagInterfaces) > 0 && len(physicalInterfaces) > 0 {
-		if err := jnpr.DisableLACP(lagInterfaces, physicalInterfaces); err != nil {
+		if err := jnpr.DeleteInterfaces(slices.Concat(lagInterfaces, physicalInterfaces)...); err != nil {
 			klog.V(90).Infof("Failed to remove static LAG configuration from switch: %v", err)
 		}
 	}
 
-	return jnpr.RestoreInterfaceConfigs(savedConfigs)
+	return jnpr.Restore(savedConfigs)
 }
 
 func deleteBondNADIfExists(name string) error {
@@ -1,8 +1,6 @@
 package deployment
 
 import (
-	"fmt"
-	"path"
 	"runtime"
 	"testing"
 
@@ -12,6 +10,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran-deployment/deploymenttypes/internal/tsparams"
 	_ "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran-deployment/deploymenttypes/tests"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran-deployment/internal/raninittools"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 )
 
@@ -25,41 +24,17 @@ func TestDeployment(t *testing.T) {
 	RunSpecs(t, "RAN Deployment Types Suite", Label(tsparams.Labels...), reporterConfig)
 }
 
-var _ = JustAfterEach(func() {
-	var (
-		currentDir, currentFilename = path.Split(currentFile)
-		hubReportPath               = fmt.Sprintf("%shub_%s", currentDir, currentFilename)
-		spoke2ReportPath            = fmt.Sprintf("%sspoke2_%s", currentDir, currentFilename)
-		report                      = CurrentSpecReport()
-	)
-
-	if Spoke1APIClient != nil && Spoke1APIClient.KubeconfigPath != "" {
-		reporter.ReportIfFailed(
-			report,
-			currentFile,
-			map[string]string{},
-			tsparams.ReporterSpokeCRsToDump)
-	}
-
-	if HubAPIClient != nil && HubAPIClient.KubeconfigPath != "" {
-		reporter.ReportIfFailedOnCluster(
-			HubAPIClient.KubeconfigPath,
-			report,
-			hubReportPath,
-			map[string]string{},
-			tsparams.ReporterHubCRsToDump)
-	}
+var _ = BeforeSuite(func() {
+	clusters.SetReporter(clusters.Hub, map[string]string{}, tsparams.ReporterHubCRsToDump)
+	clusters.SetReporter(clusters.Spoke(1), map[string]string{}, tsparams.ReporterSpokeCRsToDump)
+	clusters.SetReporter(clusters.Spoke(2), map[string]string{}, tsparams.ReporterSpokeCRsToDump)
+})
 
-	if Spoke2APIClient != nil && Spoke2APIClient.KubeconfigPath != "" {
-		reporter.ReportIfFailedOnCluster(
-			Spoke2APIClient.KubeconfigPath,
-			report,
-			spoke2ReportPath,
-			map[string]string{},
-			tsparams.ReporterSpokeCRsToDump)
-	}
+var _ = JustAfterEach(func() {
+	reporter.ReportIfFailedOnClusters(CurrentSpecReport(), currentFile)
 })
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, RANConfig.GetReportPath(), RANConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
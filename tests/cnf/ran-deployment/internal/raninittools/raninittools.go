@@ -3,6 +3,7 @@ package raninittools
 import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran-deployment/internal/ranconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 )
 
 var (
@@ -21,4 +22,18 @@ func init() {
 	HubAPIClient = RANConfig.HubAPIClient
 	Spoke1APIClient = RANConfig.Spoke1APIClient
 	Spoke2APIClient = RANConfig.Spoke2APIClient
+
+	// Only clusters with a kubeconfig are registered, so the reporter skips the others. The first spoke is dumped to
+	// the suite file itself.
+	if Spoke1APIClient != nil && Spoke1APIClient.KubeconfigPath != "" {
+		clusters.RegisterClient(clusters.Spoke(1), Spoke1APIClient.KubeconfigPath, Spoke1APIClient).SetDumpPrefix("")
+	}
+
+	if HubAPIClient != nil && HubAPIClient.KubeconfigPath != "" {
+		clusters.RegisterClient(clusters.Hub, HubAPIClient.KubeconfigPath, HubAPIClient)
+	}
+
+	if Spoke2APIClient != nil && Spoke2APIClient.KubeconfigPath != "" {
+		clusters.RegisterClient(clusters.Spoke(2), Spoke2APIClient.KubeconfigPath, Spoke2APIClient)
+	}
 }
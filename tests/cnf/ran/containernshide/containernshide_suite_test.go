@@ -10,6 +10,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/containernshide/internal/tsparams"
 	_ "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/containernshide/tests"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/raninittools"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 )
 
 var _, currentFile, _, _ = runtime.Caller(0)
@@ -21,3 +22,7 @@ func TestContainerNsHide(t *testing.T) {
 	RegisterFailHandler(Fail)
 	RunSpecs(t, "Container Mount Namespace Hiding", Label(tsparams.Labels...), reporterConfig)
 }
+
+var _ = ReportAfterSuite("", func(report Report) {
+	reporter.PublishSuiteReports(report, currentFile)
+})
@@ -1,8 +1,6 @@
 package ztp
 
 import (
-	"fmt"
-	"path"
 	"runtime"
 	"strings"
 	"testing"
@@ -17,6 +15,7 @@ import (
 	_ "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/gitopsztp/tests"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/rancluster"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/raninittools"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 )
 
@@ -31,6 +30,9 @@ func TestZtp(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	clusters.SetReporter(clusters.Hub, tsparams.ReporterHubNamespacesToDump, tsparams.ReporterHubCRsToDump)
+	clusters.SetReporter(clusters.Spoke(1), tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
+
 	By("checking that the required clusters are present")
 
 	if !rancluster.AreClustersPresent([]*clients.Settings{HubAPIClient, Spoke1APIClient}) {
@@ -66,25 +68,10 @@ var _ = AfterSuite(func() {
 })
 
 var _ = JustAfterEach(func() {
-	var (
-		currentDir, currentFilename = path.Split(currentFile)
-		hubReportPath               = fmt.Sprintf("%shub_%s", currentDir, currentFilename)
-		report                      = CurrentSpecReport()
-	)
-
-	reporter.ReportIfFailed(
-		report, currentFile, tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
-
-	if HubAPIClient != nil {
-		reporter.ReportIfFailedOnCluster(
-			RANConfig.HubKubeconfig,
-			report,
-			hubReportPath,
-			tsparams.ReporterHubNamespacesToDump,
-			tsparams.ReporterHubCRsToDump)
-	}
+	reporter.ReportIfFailedOnClusters(CurrentSpecReport(), currentFile)
 })
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, RANConfig.GetReportPath(), RANConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
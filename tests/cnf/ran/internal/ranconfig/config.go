@@ -2,19 +2,17 @@ package ranconfig
 
 import (
 	"fmt"
-	"os"
 	"path/filepath"
 	"runtime"
 	"time"
 
-	"github.com/kelseyhightower/envconfig"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/bmc"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/internal/cnfconfig"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/ranparam"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/version"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/inittools"
-	"gopkg.in/yaml.v2"
 	"k8s.io/klog/v2"
 )
 
@@ -36,7 +34,12 @@ type RANConfig struct {
 	PtpStabilityDuration   time.Duration `yaml:"ptpStabilityDuration" envconfig:"ECO_CNF_RAN_PTP_STABILITY_DURATION"`
 	// PtpStabilityThreshold is the absolute offset threshold for PTP stability analysis. It is measured in
 	// nanoseconds.
-	PtpStabilityThreshold int64    `yaml:"ptpStabilityThreshold" envconfig:"ECO_CNF_RAN_PTP_STABILITY_THRESHOLD"`
+	PtpStabilityThreshold int64 `yaml:"ptpStabilityThreshold" envconfig:"ECO_CNF_RAN_PTP_STABILITY_THRESHOLD"`
+	// PtpStabilityWindow is the length of the windows over which PTP stability statistics are reported.
+	PtpStabilityWindow time.Duration `yaml:"ptpStabilityWindow" envconfig:"ECO_CNF_RAN_PTP_STABILITY_WINDOW"`
+	// PtpStabilityLimits is the G.8273.2 clock class, A, B, or C, whose limits PTP stability windows must be within.
+	// No limits are checked when it is empty.
+	PtpStabilityLimits    string   `yaml:"ptpStabilityLimits" envconfig:"ECO_CNF_RAN_PTP_STABILITY_LIMITS"`
 	StressngTestImage     string   `yaml:"stressngTestImage" envconfig:"ECO_CNF_RAN_STRESSNG_TEST_IMAGE"`
 	CnfTestImage          string   `yaml:"cnfTestImage" envconfig:"ECO_CNF_RAN_TEST_IMAGE"`
 	OcpUpgradeUpstreamURL string   `yaml:"ocpUpgradeUpstreamUrl" envconfig:"ECO_CNF_RAN_OCP_UPGRADE_UPSTREAM_URL"`
@@ -95,11 +98,11 @@ type HubConfig struct {
 	O2IMSOAuthClientID string `envconfig:"ECO_CNF_RAN_O2IMS_OAUTH_CLIENT_ID"`
 	// O2IMSOAuthClientSecret is a string used to request the access token from the OAuth endpoint using the client
 	// credentials grant type.
-	O2IMSOAuthClientSecret string `envconfig:"ECO_CNF_RAN_O2IMS_OAUTH_CLIENT_SECRET"`
+	O2IMSOAuthClientSecret string `envconfig:"ECO_CNF_RAN_O2IMS_OAUTH_CLIENT_SECRET" eco_secret:"true"`
 
 	// O2IMSToken is the token for the O-RAN suite to authenticate with the O2IMS API. It is only used when OAuth is
 	// not configured.
-	O2IMSToken string `envconfig:"ECO_CNF_RAN_O2IMS_TOKEN"`
+	O2IMSToken string `envconfig:"ECO_CNF_RAN_O2IMS_TOKEN" eco_secret:"true"`
 }
 
 // GetAppsURL returns the apps URL for the given subdomain. It should end up being in a form similar to
@@ -122,10 +125,10 @@ type Spoke1Config struct {
 	Spoke1Hostname   string `envconfig:"ECO_CNF_RAN_SPOKE1_HOSTNAME"`
 	Spoke1Kubeconfig string `envconfig:"KUBECONFIG"`
 	// Spoke1Password is the path to the admin password, saved in the O-RAN suite.
-	Spoke1Password string `envconfig:"ECO_CNF_RAN_SPOKE1_PASSWORD"`
+	Spoke1Password string `envconfig:"ECO_CNF_RAN_SPOKE1_PASSWORD" eco_secret:"true"`
 
 	BMCUsername string        `envconfig:"ECO_CNF_RAN_BMC_USERNAME"`
-	BMCPassword string        `envconfig:"ECO_CNF_RAN_BMC_PASSWORD"`
+	BMCPassword string        `envconfig:"ECO_CNF_RAN_BMC_PASSWORD" eco_secret:"true"`
 	BMCHosts    []string      `envconfig:"ECO_CNF_RAN_BMC_HOSTS"`
 	BMCTimeout  time.Duration `yaml:"bmcTimeout" envconfig:"ECO_CNF_RAN_BMC_TIMEOUT"`
 }
@@ -150,7 +153,7 @@ func NewRANConfig() *RANConfig {
 	baseDir := filepath.Dir(filename)
 	configFile := filepath.Join(baseDir, PathToDefaultCnfRanParamsFile)
 
-	err := readConfig(&ranConfig, configFile)
+	err := ranConfig.readConfig(&ranConfig, configFile)
 	if err != nil {
 		klog.V(ranparam.LogLevel).Infof("Error reading main RAN Config: %v", err)
 
@@ -169,7 +172,7 @@ func (ranconfig *RANConfig) newHubConfig(configFile string) {
 
 	ranconfig.HubConfig = new(HubConfig)
 
-	err := readConfig(ranconfig.HubConfig, configFile)
+	err := ranconfig.readConfig(ranconfig.HubConfig, configFile)
 	if err != nil {
 		klog.V(ranparam.LogLevel).Infof("Failed to instantiate HubConfig: %v", err)
 	}
@@ -236,7 +239,7 @@ func (ranconfig *RANConfig) newSpoke1Config(configFile string) {
 
 	ranconfig.Spoke1Config = new(Spoke1Config)
 
-	err := readConfig(ranconfig.Spoke1Config, configFile)
+	err := ranconfig.readConfig(ranconfig.Spoke1Config, configFile)
 	if err != nil {
 		klog.V(ranparam.LogLevel).Infof("Failed to instantiate Spoke1Config: %v", err)
 	}
@@ -291,7 +294,7 @@ func (ranconfig *RANConfig) newSpoke2Config(configFile string) {
 
 	ranconfig.Spoke2Config = new(Spoke2Config)
 
-	err := readConfig(ranconfig.Spoke2Config, configFile)
+	err := ranconfig.readConfig(ranconfig.Spoke2Config, configFile)
 	if err != nil {
 		klog.V(ranparam.LogLevel).Infof("Failed to instantiate Spoke2Config: %v", err)
 	}
@@ -318,34 +321,14 @@ func (ranconfig *RANConfig) newSpoke2Config(configFile string) {
 	klog.V(ranparam.LogLevel).Infof("Found OCP version on spoke 2: %s", ranconfig.Spoke2Config.Spoke2OCPVersion)
 }
 
-func readConfig[C any](config *C, configFile string) error {
-	err := readFile(config, configFile)
+// readConfig loads all configuration layers into target and records their sources in the general config.
+func (ranconfig *RANConfig) readConfig(target any, configFile string) error {
+	sources, err := config.LoadLayers(target, configFile, "")
 	if err != nil {
 		return err
 	}
 
-	return readEnv(config)
-}
-
-func readFile[C any](config *C, configFile string) error {
-	openedConfigFile, err := os.Open(configFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedConfigFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedConfigFile)
-
-	err = decoder.Decode(config)
-
-	return err
-}
-
-func readEnv[C any](config *C) error {
-	err := envconfig.Process("", config)
+	ranconfig.RecordSources(sources)
 
-	return err
+	return nil
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/bmc"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/ranconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/inittools"
 	"k8s.io/klog/v2"
 )
@@ -33,4 +34,14 @@ func init() {
 	HubAPIClient = RANConfig.HubAPIClient
 	Spoke2APIClient = RANConfig.Spoke2APIClient
 	BMCClient = RANConfig.Spoke1BMC
+
+	clusters.RegisterClient(clusters.Spoke(1), "", Spoke1APIClient)
+
+	if HubAPIClient != nil {
+		clusters.RegisterClient(clusters.Hub, RANConfig.HubKubeconfig, HubAPIClient)
+	}
+
+	if Spoke2APIClient != nil {
+		clusters.RegisterClient(clusters.Spoke(2), RANConfig.Spoke2Kubeconfig, Spoke2APIClient)
+	}
 }
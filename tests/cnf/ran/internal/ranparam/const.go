@@ -56,20 +56,10 @@ const (
 	RetryCount = 3
 )
 
-// Querier package constants.
+// OpenShift Monitoring constants.
 const (
-	// ThanosQuerierRouteName is the name of the Thanos querier route.
-	ThanosQuerierRouteName = "thanos-querier"
 	// OpenshiftMonitoringNamespace is the namespace for the OpenShift Monitoring.
 	OpenshiftMonitoringNamespace = "openshift-monitoring"
-	// OpenshiftMonitoringViewRole is the role for the OpenShift Monitoring.
-	OpenshiftMonitoringViewRole = "cluster-monitoring-view"
-	// QuerierServiceAccountName is the name of the querier service account that gets created by the querier
-	// package.
-	QuerierServiceAccountName = "ran-querier"
-	// QuerierCRBName is the name of the querier cluster role binding that gets created by the querier package to
-	// bind the querier service account to the cluster monitoring view role.
-	QuerierCRBName = "ran-querier-crb"
 )
 
 // Params for the alerter package. These are used for getting a token for the ACM Observability Alertmanager instance.
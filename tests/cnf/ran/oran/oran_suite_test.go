@@ -1,8 +1,6 @@
 package oran
 
 import (
-	"fmt"
-	"path"
 	"runtime"
 	"testing"
 
@@ -14,6 +12,7 @@ import (
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/raninittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/oran/internal/tsparams"
 	_ "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/oran/tests"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	subscriber "github.com/rh-ecosystem-edge/eco-gotests/tests/internal/oran-subscriber"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 )
@@ -29,6 +28,12 @@ func TestORAN(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	clusters.SetReporter(clusters.Hub, tsparams.ReporterHubNamespacesToDump, tsparams.ReporterHubCRsToDump)
+
+	if Spoke1APIClient != nil {
+		clusters.SetReporter(clusters.Spoke(1), tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
+	}
+
 	By("checking that the hub cluster is present")
 
 	isHubPresent := rancluster.AreClustersPresent([]*clients.Settings{HubAPIClient})
@@ -49,25 +54,10 @@ var _ = AfterSuite(func() {
 })
 
 var _ = JustAfterEach(func() {
-	var (
-		currentDir, currentFilename = path.Split(currentFile)
-		hubReportPath               = fmt.Sprintf("%shub_%s", currentDir, currentFilename)
-		report                      = CurrentSpecReport()
-	)
-
-	if Spoke1APIClient != nil {
-		reporter.ReportIfFailed(
-			report, currentFile, tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
-	}
-
-	reporter.ReportIfFailedOnCluster(
-		RANConfig.HubKubeconfig,
-		report,
-		hubReportPath,
-		tsparams.ReporterHubNamespacesToDump,
-		tsparams.ReporterHubCRsToDump)
+	reporter.ReportIfFailedOnClusters(CurrentSpecReport(), currentFile)
 })
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, RANConfig.GetReportPath(), RANConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
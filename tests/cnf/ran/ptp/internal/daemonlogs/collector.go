@@ -2,20 +2,14 @@ package daemonlogs
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"io"
 	"os"
-	"strings"
 	"time"
 
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
-	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/ranparam"
-	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/ptpdaemon"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/logstream"
 	"k8s.io/klog/v2"
 )
 
@@ -36,15 +30,42 @@ type CollectionResult struct {
 	Errors []error
 }
 
-// CollectDaemonLogs collects linuxptp daemon logs for a single node for the provided duration. It polls every 10
-// seconds for the full duration. Some log lines may be duplicated, but they will never be skipped. Collected lines are
-// streamed to a temporary file to keep memory usage bounded regardless of duration. The returned pointer is non-nil if
-// and only if error is nil.
+// collectOptions is a struct that contains the options for the CollectDaemonLogs function. It should not be used
+// directly since the CollectOption type is used to set the options.
+type collectOptions struct {
+	observers []func(logstream.Line)
+}
+
+// CollectOption is a function type that can be used to set the options for the CollectDaemonLogs function. It should
+// not be implemented outside of the functions provided by this package.
+type CollectOption func(*collectOptions)
+
+// WithLineObserver sets a function called with every non-empty line as it is collected, such as the ObserveLine method
+// of a stability window analyzer, so lines can be analyzed while the collection runs. It is called from the collecting
+// goroutine and should return quickly. If a nil function is provided, a log will be printed and this option will be a
+// no-op.
+func WithLineObserver(observe func(logstream.Line)) CollectOption {
+	if observe == nil {
+		klog.V(tsparams.LogLevel).Infof("Line observer cannot be nil, ignoring it")
+
+		return func(o *collectOptions) {}
+	}
+
+	return func(o *collectOptions) {
+		o.observers = append(o.observers, observe)
+	}
+}
+
+// CollectDaemonLogs collects linuxptp daemon logs for a single node for the provided duration. It follows the logs
+// with a daemon log stream, so lines are neither duplicated nor skipped, including across restarts of the daemon
+// container. Collected lines are streamed to a temporary file to keep memory usage bounded regardless of duration. The
+// returned pointer is non-nil if and only if error is nil.
 //
 // In the returned CollectionResult, the StartedAt and EndedAt times are the time the collection process started and
 // ended, not necessarily the time the first and last log lines were collected. The caller is responsible for removing
 // CollectionResult.TempFilePath when it is no longer needed.
-func CollectDaemonLogs(client *clients.Settings, nodeName string, duration time.Duration) (*CollectionResult, error) {
+func CollectDaemonLogs(
+	client *clients.Settings, nodeName string, duration time.Duration, options ...CollectOption) (*CollectionResult, error) {
 	if client == nil {
 		return nil, fmt.Errorf("cannot collect daemon logs with nil client")
 	}
@@ -57,13 +78,17 @@ func CollectDaemonLogs(client *clients.Settings, nodeName string, duration time.
 		return nil, fmt.Errorf("cannot collect daemon logs with non-positive duration: %s", duration)
 	}
 
+	var collectionOptions collectOptions
+	for _, option := range options {
+		option(&collectionOptions)
+	}
+
 	tempFile, err := os.CreateTemp("", "ptp-daemon-logs-*.log")
 	if err != nil {
 		return nil, fmt.Errorf("failed to create temp file for daemon logs: %w", err)
 	}
 
 	startTime := time.Now()
-	lastFetchTime := startTime
 
 	result := CollectionResult{
 		NodeName:     nodeName,
@@ -71,32 +96,24 @@ func CollectDaemonLogs(client *clients.Settings, nodeName string, duration time.
 		TempFilePath: tempFile.Name(),
 	}
 
-	var collectionErrors []error
-
-	pollErr := wait.PollUntilContextTimeout(
-		context.TODO(), 10*time.Second, duration, true, func(ctx context.Context) (bool, error) {
-			localFetchTime := time.Now()
-
-			linesWritten, fetchErr := appendLogsSince(tempFile, client, nodeName, lastFetchTime)
-			if fetchErr != nil {
-				klog.V(tsparams.LogLevel).Infof("Error collecting daemon logs from node %s: %v", nodeName, fetchErr)
+	ctx, cancel := context.WithTimeout(context.TODO(), duration)
+	defer cancel()
 
-				collectionErrors = append(collectionErrors, fetchErr)
-			} else {
-				lastFetchTime = localFetchTime
+	stream := NewDaemonStream(client, nodeName, startTime)
+	subscription := stream.Subscribe(streamBuffer)
+	stream.Start(ctx)
 
-				result.CollectedLineCount += linesWritten
-			}
+	linesWritten, writeErr := writeNonEmptyLines(tempFile, subscription, collectionOptions.observers...)
 
-			return false, nil
-		})
+	cancel()
+	<-stream.Done()
 
 	closeErr := tempFile.Close()
 
-	if pollErr != nil && !errors.Is(pollErr, context.DeadlineExceeded) {
+	if writeErr != nil {
 		_ = os.Remove(tempFile.Name())
 
-		return nil, fmt.Errorf("unexpected error collecting daemon logs on node %s: %w", nodeName, pollErr)
+		return nil, fmt.Errorf("failed to collect daemon logs on node %s: %w", nodeName, writeErr)
 	}
 
 	if closeErr != nil {
@@ -105,49 +122,40 @@ func CollectDaemonLogs(client *clients.Settings, nodeName string, duration time.
 		return nil, fmt.Errorf("failed to close temp file %s: %w", tempFile.Name(), closeErr)
 	}
 
-	result.EndedAt = time.Now()
-	result.Errors = collectionErrors
-
-	return &result, nil
-}
-
-// appendLogsSince fetches daemon log lines produced after lastFetchTime and appends them to dest. It returns the number
-// of non-empty lines written and any error encountered during the fetch.
-func appendLogsSince(
-	dest io.Writer, client *clients.Settings, nodeName string, lastFetchTime time.Time,
-) (int, error) {
-	daemonPod, err := ptpdaemon.GetPtpDaemonPodOnNode(client, nodeName)
-	if err != nil {
-		return 0, fmt.Errorf("failed to get PTP daemon pod on node %s: %w", nodeName, err)
+	for _, streamErr := range stream.Stats().Errors {
+		klog.V(tsparams.LogLevel).Infof("Error collecting daemon logs from node %s: %v", nodeName, streamErr)
 	}
 
-	logs, err := daemonPod.GetLogsWithOptions(&corev1.PodLogOptions{
-		SinceTime: &metav1.Time{Time: lastFetchTime},
-		Container: ranparam.PtpContainerName,
-	})
-	if err != nil {
-		return 0, fmt.Errorf("failed to get logs from node %s since %s: %w", nodeName, lastFetchTime, err)
-	}
+	result.EndedAt = time.Now()
+	result.CollectedLineCount = linesWritten
+	result.Errors = stream.Stats().Errors
 
-	return writeNonEmptyLines(dest, string(logs))
+	return &result, nil
 }
 
-// writeNonEmptyLines splits raw on newlines, writes each non-empty line (terminated by newline) to dest, and returns
-// the count of lines written.
-func writeNonEmptyLines(dest io.Writer, raw string) (int, error) {
-	lines := strings.Split(raw, "\n")
+// writeNonEmptyLines writes the text of each non-empty line received by subscription, terminated by newline, to dest
+// until the stream stops, passing each one to observers after writing it. It returns the count of lines written. If a
+// write fails, the subscription is closed.
+func writeNonEmptyLines(
+	dest io.Writer, subscription *logstream.Subscription, observers ...func(logstream.Line)) (int, error) {
 	written := 0
 
-	for _, line := range lines {
-		if line == "" {
+	for line := range subscription.Lines {
+		if line.Text == "" {
 			continue
 		}
 
-		if _, err := io.WriteString(dest, line+"\n"); err != nil {
+		if _, err := io.WriteString(dest, line.Text+"\n"); err != nil {
+			subscription.Close()
+
 			return written, fmt.Errorf("failed to write log line to temp file: %w", err)
 		}
 
 		written++
+
+		for _, observe := range observers {
+			observe(line)
+		}
 	}
 
 	return written, nil
@@ -0,0 +1,28 @@
+package daemonlogs
+
+import (
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/ranparam"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/ptpdaemon"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/logstream"
+)
+
+// streamBuffer is the number of lines buffered for each subscriber of a daemon log stream. The daemon logs several
+// lines per second per process, so this absorbs short pauses of a subscriber without holding back the others.
+const streamBuffer = 1024
+
+// NewDaemonStream returns a stream of the linuxptp daemon container logs on the specified node starting at since. The
+// PTP daemon pod is looked up every time the stream is reopened, so the stream follows the daemon across container
+// restarts and pod recreations. Several consumers, such as [WaitForPodLog] with [WithStream], the stability analyzer
+// and log collection, can subscribe to the same stream. The caller starts it and stops it by cancelling the context
+// passed to Start.
+func NewDaemonStream(client *clients.Settings, nodeName string, since time.Time) *logstream.Stream {
+	resolveDaemonPod := func() (*pod.Builder, error) {
+		return ptpdaemon.GetPtpDaemonPodOnNode(client, nodeName)
+	}
+
+	return logstream.New(logstream.PodSource(client, resolveDaemonPod, ranparam.PtpContainerName), since)
+}
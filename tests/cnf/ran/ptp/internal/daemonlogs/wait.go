@@ -10,12 +10,9 @@ import (
 	"time"
 
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
-	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/ranparam"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/ptpdaemon"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
-	corev1 "k8s.io/api/core/v1"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
-	"k8s.io/apimachinery/pkg/util/wait"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/logstream"
 	"k8s.io/klog/v2"
 )
 
@@ -39,11 +36,11 @@ func RegexpMatcher(regexp *regexp.Regexp) LogMatcher {
 // waitForPodLogOptions is a struct that contains the options for the WaitForPodLog function. It should not be used
 // directly since the WaitForPodLogOption type is used to set the options.
 type waitForPodLogOptions struct {
-	startTime       time.Time
-	timeout         time.Duration
-	pollingInterval time.Duration
-	matcher         LogMatcher
-	ignoreTimeout   bool
+	startTime     time.Time
+	timeout       time.Duration
+	stream        *logstream.Stream
+	matcher       LogMatcher
+	ignoreTimeout bool
 }
 
 // WaitForPodLogOption is a function type that can be used to set the options for the WaitForPodLog function. It should
@@ -72,17 +69,19 @@ func WithTimeout(timeout time.Duration) WaitForPodLogOption {
 	}
 }
 
-// WithPollingInterval sets the interval between polling attempts. Values less than or equal to zero will print a log
-// and this option will be a no-op. It defaults to 1 second.
-func WithPollingInterval(interval time.Duration) WaitForPodLogOption {
-	if interval <= 0 {
-		klog.V(tsparams.LogLevel).Infof("Polling interval cannot be less than or equal to zero, falling back to the default")
+// WithStream sets a daemon log stream, such as one from [NewDaemonStream], to match log lines from instead of opening
+// a new one. This lets several waits and other consumers share a single stream. Only lines published after
+// WaitForPodLog is called are matched, so the start time is ignored, and the stream must already be started by its
+// owner. If a nil stream is provided, a log will be printed and this option will be a no-op.
+func WithStream(stream *logstream.Stream) WaitForPodLogOption {
+	if stream == nil {
+		klog.V(tsparams.LogLevel).Infof("Stream cannot be nil, falling back to a new stream")
 
 		return func(o *waitForPodLogOptions) {}
 	}
 
 	return func(o *waitForPodLogOptions) {
-		o.pollingInterval = interval
+		o.stream = stream
 	}
 }
 
@@ -108,17 +107,17 @@ func defaultMatcher(line string) bool {
 // getDefaultWaitForPodLogOptions returns a waitForPodLogOptions struct with default values.
 func getDefaultWaitForPodLogOptions() *waitForPodLogOptions {
 	return &waitForPodLogOptions{
-		startTime:       time.Now(),
-		timeout:         30 * time.Second,
-		pollingInterval: 1 * time.Second,
-		matcher:         defaultMatcher,
-		ignoreTimeout:   false,
+		startTime:     time.Now(),
+		timeout:       30 * time.Second,
+		matcher:       defaultMatcher,
+		ignoreTimeout: false,
 	}
 }
 
-// WaitForPodLog waits for a message to appear in the PTP daemon pod logs on the specified node. It polls the logs at
-// the specified interval until either the matcher function returns true for a log line or the timeout is reached. Note
-// that the function will lookup the PTP daemon pod on each poll to account for the pod being deleted and recreated.
+// WaitForPodLog waits for a message to appear in the PTP daemon pod logs on the specified node. It follows the logs
+// from the start time until either the matcher function returns true for a log line or the timeout is reached. Unless
+// a stream is provided using [WithStream], a new daemon log stream is used, which follows the PTP daemon pod across
+// restarts and recreations.
 func WaitForPodLog(client *clients.Settings, nodeName string, options ...WaitForPodLogOption) error {
 	logOptions := getDefaultWaitForPodLogOptions()
 
@@ -130,48 +129,42 @@ func WaitForPodLog(client *clients.Settings, nodeName string, options ...WaitFor
 		return fmt.Errorf("matcher function must be provided using WithMatcher option")
 	}
 
-	// Track the last time we successfully fetched logs to avoid missing log entries between polls.
-	lastFetchTime := logOptions.startTime
+	ctx, cancel := context.WithTimeout(context.TODO(), logOptions.timeout)
+	defer cancel()
 
-	return wait.PollUntilContextTimeout(
-		context.TODO(), logOptions.pollingInterval, logOptions.timeout, true, func(ctx context.Context) (bool, error) {
-			// Get the PTP daemon pod on each poll to handle pod restarts.
-			daemonPod, err := ptpdaemon.GetPtpDaemonPodOnNode(client, nodeName)
-			if err != nil {
-				klog.V(tsparams.LogLevel).Infof("Failed to get PTP daemon pod on node %s: %v", nodeName, err)
+	stream := logOptions.stream
+	ownStream := stream == nil
 
-				return false, nil
-			}
+	if ownStream {
+		stream = NewDaemonStream(client, nodeName, logOptions.startTime)
+	}
 
-			// We save the time of the next last fetch before getting the logs to avoid missing log entries
-			// between polls. Once the logs are fetched, we update the last fetch time to this time.
-			localFetchTime := time.Now()
+	subscription := stream.Subscribe(streamBuffer)
+	defer subscription.Close()
 
-			logs, err := daemonPod.GetLogsWithOptions(&corev1.PodLogOptions{
-				SinceTime: &metav1.Time{Time: lastFetchTime},
-				Container: ranparam.PtpContainerName,
-			})
-			if err != nil {
-				klog.V(tsparams.LogLevel).Infof("Failed to get logs starting at %s for PTP daemon pod on node %s: %v",
-					lastFetchTime, nodeName, err)
+	// A stream provided using WithStream is owned by the caller, while our own stream stops along with ctx.
+	if ownStream {
+		stream.Start(ctx)
+	}
 
-				return false, nil
+	for {
+		select {
+		case line, open := <-subscription.Lines:
+			if !open {
+				return fmt.Errorf("log stream of PTP daemon pod on node %s stopped before a matching line", nodeName)
 			}
 
-			// Update the last fetch time to the current time before processing logs. This could cause
-			// duplicates, but this will not affect the result.
-			lastFetchTime = localFetchTime
+			if logOptions.matcher(line.Text) {
+				klog.V(tsparams.LogLevel).Infof(
+					"Found matching log line in PTP daemon pod on node %s: %q", nodeName, line.Text)
 
-			for line := range strings.SplitSeq(string(logs), "\n") {
-				if logOptions.matcher(line) {
-					klog.V(tsparams.LogLevel).Infof("Found matching log line in PTP daemon pod on node %s: %q", nodeName, line)
-
-					return true, nil
-				}
+				return nil
 			}
-
-			return false, nil
-		})
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for matching log line in PTP daemon pod on node %s: %w",
+				nodeName, ctx.Err())
+		}
+	}
 }
 
 // profileLoadMessage is the message that appears in the linuxptp-daemon-container logs when the profiles are loaded.
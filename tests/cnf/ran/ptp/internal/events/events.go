@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strconv"
 	"time"
@@ -13,12 +14,16 @@ import (
 	"github.com/redhat-cne/sdk-go/pkg/event"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/logstream"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/klog/v2"
 )
 
+// streamBuffer is the number of log lines buffered when waiting for events from a log stream.
+const streamBuffer = 256
+
 var (
 	// containsEventRegexp is a regular expression that matches lines in the logs that contain events.
 	containsEventRegexp         = regexp.MustCompile(`msg="(received event|event sent|Got CurrentState:)`)
@@ -33,6 +38,7 @@ var (
 type waitForEventOptions struct {
 	container          string
 	ignoreCurrentState bool
+	stream             *logstream.Stream
 }
 
 // WaitForEventOption is a function that modifies the waitForEventOptions struct. It is used to set options for the
@@ -55,6 +61,17 @@ func WithoutCurrentState(ignoreCurrentState bool) WaitForEventOption {
 	}
 }
 
+// WithLogStream is an option for the WaitForEvent function that specifies a log stream of the cloud event consumer or
+// proxy container to extract events from, instead of fetching the logs of the pod every 5 seconds. This lets several
+// waits share a single stream which follows the pod across restarts. Only lines published after WaitForEvent is called
+// are checked, so the start time and container options are ignored, and the stream must already be started by its
+// owner.
+func WithLogStream(stream *logstream.Stream) WaitForEventOption {
+	return func(options *waitForEventOptions) {
+		options.stream = stream
+	}
+}
+
 // WaitForEvent waits up to the specified timeout for an event to be received by the cloud event consumer. It returns an
 // error if no event matches the provided filter within the timeout period.
 //
@@ -71,6 +88,10 @@ func WaitForEvent(
 		option(&combinedOptions)
 	}
 
+	if combinedOptions.stream != nil {
+		return waitForEventInStream(combinedOptions.stream, timeout, filter, combinedOptions.ignoreCurrentState)
+	}
+
 	return wait.PollUntilContextTimeout(
 		context.TODO(), 5*time.Second, timeout, true, func(ctx context.Context) (bool, error) {
 			// Each loop we save the previous start time and set the new start time to the current time.
@@ -99,6 +120,35 @@ func WaitForEvent(
 		})
 }
 
+// waitForEventInStream waits up to timeout for an event matching filter to be extracted from the lines published by
+// stream.
+func waitForEventInStream(
+	stream *logstream.Stream, timeout time.Duration, filter EventFilter, ignoreCurrentState bool) error {
+	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+	defer cancel()
+
+	subscription := stream.Subscribe(streamBuffer)
+	defer subscription.Close()
+
+	for {
+		select {
+		case line, open := <-subscription.Lines:
+			if !open {
+				return fmt.Errorf("log stream stopped before an event matching %#v was received", filter)
+			}
+
+			extractedEvents := extractEventsFromLogs([]byte(line.Text), ignoreCurrentState)
+			if slices.ContainsFunc(extractedEvents, filter.Filter) {
+				klog.V(tsparams.LogLevel).Infof("Found event matching %#v in pod %s", filter, line.Pod)
+
+				return nil
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for an event matching %#v: %w", filter, ctx.Err())
+		}
+	}
+}
+
 // extractEventsFromLogs extracts events from the logs of either the cloud event consumer or the cloud event proxy
 // containers. Rather than return errors, this function logs them and ignores the line. All lines that were able to be
 // parsed into events are returned.
@@ -0,0 +1,57 @@
+//go:build unit_test
+
+package events
+
+import (
+	"testing"
+
+	"github.com/redhat-cne/sdk-go/pkg/event"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/replay"
+)
+
+// replayedEvent is the golden form of an extracted event. Events only marshal to JSON with fields the log lines do
+// not carry, so the fields read from the logs are copied instead.
+type replayedEvent struct {
+	ID     string
+	Type   string
+	Source string
+	Time   string
+	Values []event.DataValue
+}
+
+// replayedEvents is the golden output of a recorded consumer log, with and without the current state messages.
+type replayedEvents struct {
+	All                 []replayedEvent
+	WithoutCurrentState []replayedEvent
+}
+
+func TestReplayConsumerLogs(t *testing.T) {
+	replay.Run(t, "testdata/consumer-logs", func(recording []byte) (any, error) {
+		recording = replay.StripTimestamps(recording)
+
+		return replayedEvents{
+			All:                 toReplayedEvents(extractEventsFromLogs(recording, false)),
+			WithoutCurrentState: toReplayedEvents(extractEventsFromLogs(recording, true)),
+		}, nil
+	})
+}
+
+func toReplayedEvents(extractedEvents []event.Event) []replayedEvent {
+	replayed := []replayedEvent{}
+
+	for _, extractedEvent := range extractedEvents {
+		replayedEvent := replayedEvent{ID: extractedEvent.ID, Type: extractedEvent.Type, Source: extractedEvent.Source}
+
+		if extractedEvent.Time != nil {
+			replayedEvent.Time = extractedEvent.Time.String()
+		}
+
+		if extractedEvent.Data != nil {
+			replayedEvent.Values = extractedEvent.Data.Values
+		}
+
+		replayed = append(replayed, replayedEvent)
+	}
+
+	return replayed
+}
@@ -3,13 +3,11 @@ package metrics
 import (
 	"context"
 	"fmt"
-	"time"
 
 	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
 	"github.com/prometheus/common/model"
-	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/promclient"
 	"golang.org/x/exp/constraints"
-	"k8s.io/klog/v2"
 )
 
 // ExecuteQuery executes a Prometheus query and returns the result as a model.Vector. If the query has a non-zero end
@@ -29,34 +27,7 @@ func ExecuteQuery[V constraints.Integer](
 
 	metricQuery := query.ToMetricQuery()
 
-	queryTime := metricQuery.End
-	if queryTime.IsZero() {
-		queryTime = time.Now()
-	}
-
-	queryString := metricQuery.String()
-
-	klog.V(tsparams.LogLevel).Infof("Executing query: %s", queryString)
-
-	result, warnings, err := client.Query(ctx, queryString, queryTime)
-	if err != nil {
-		return nil, err
-	}
-
-	for _, warning := range warnings {
-		klog.V(tsparams.LogLevel).Infof("Query returned warning: %s", warning)
-	}
-
-	if result.Type() != model.ValVector {
-		return nil, fmt.Errorf("unexpected result type: %s", result.Type())
-	}
-
-	vector, ok := result.(model.Vector)
-	if !ok {
-		return nil, fmt.Errorf("failed to cast result to vector")
-	}
-
-	return vector, nil
+	return promclient.Query(ctx, client, metricQuery.String(), metricQuery.End)
 }
 
 // ExecuteQueryRange executes a Prometheus query range and returns the result as a model.Matrix. It logs any warnings
@@ -74,27 +45,6 @@ func ExecuteQueryRange[V constraints.Integer](
 	}
 
 	metricQuery := query.ToMetricQuery()
-	queryString := metricQuery.String()
-
-	klog.V(tsparams.LogLevel).Infof("Executing query range: %s", queryString)
-
-	result, warnings, err := client.QueryRange(ctx, queryString, metricQuery.Range())
-	if err != nil {
-		return nil, err
-	}
-
-	for _, warning := range warnings {
-		klog.V(tsparams.LogLevel).Infof("Query returned warning: %s", warning)
-	}
-
-	if result.Type() != model.ValMatrix {
-		return nil, fmt.Errorf("unexpected result type: %s", result.Type())
-	}
-
-	matrix, ok := result.(model.Matrix)
-	if !ok {
-		return nil, fmt.Errorf("failed to cast result to matrix")
-	}
 
-	return matrix, nil
+	return promclient.QueryRange(ctx, client, metricQuery.String(), metricQuery.Range())
 }
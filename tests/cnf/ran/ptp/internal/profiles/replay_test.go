@@ -0,0 +1,21 @@
+//go:build unit_test
+
+package profiles
+
+import (
+	"testing"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/replay"
+)
+
+func TestReplayPortProperties(t *testing.T) {
+	replay.Run(t, "testdata/pmc-port-properties", func(recording []byte) (any, error) {
+		return parsePortPropertiesOutput(string(recording))
+	})
+}
+
+func TestReplayParentDataSet(t *testing.T) {
+	replay.Run(t, "testdata/pmc-parent-data-set", func(recording []byte) (any, error) {
+		return parseParentDataSetOutput(string(recording))
+	})
+}
@@ -42,7 +42,8 @@ func (h HoldOverMap) String() string {
 
 // SetHoldOverTimeouts sets the HoldOverTimeout for the provided profiles to the provided value. It returns a map of
 // profile references to the old HoldOverTimeout values. Note that any errors in pulling or updating the configs may
-// result in a partially modified state.
+// result in a partially modified state. On error, the returned map only has the profiles updated before the error, so
+// it can still be passed to ResetHoldOverTimeouts.
 //
 // Each profile provided must be unique, otherwise the function may return the wrong original HoldOverTimeout values.
 //
@@ -55,29 +56,34 @@ func SetHoldOverTimeouts(
 	for _, profile := range profiles {
 		ptpConfig, err := profile.Reference.PullPtpConfig(client)
 		if err != nil {
-			return nil, fmt.Errorf("failed to pull PtpConfig for profile %s: %w", profile.Reference.ProfileName, err)
+			return oldHoldovers, fmt.Errorf("failed to pull PtpConfig for profile %s: %w",
+				profile.Reference.ProfileName, err)
 		}
 
 		profileIndex := profile.Reference.ProfileIndex
 		if profileIndex < 0 || profileIndex >= len(ptpConfig.Definition.Spec.Profile) {
-			return nil, fmt.Errorf("failed to reset profile %s at index %d: index out of bounds",
+			return oldHoldovers, fmt.Errorf("failed to reset profile %s at index %d: index out of bounds",
 				profile.Reference.ProfileName, profileIndex)
 		}
 
+		var oldHoldover *int64
+
 		if ptpConfig.Definition.Spec.Profile[profileIndex].PtpClockThreshold == nil {
-			oldHoldovers[profile.Reference] = nil
 			ptpConfig.Definition.Spec.Profile[profileIndex].PtpClockThreshold = &ptpv1.PtpClockThreshold{}
 		} else {
 			copiedHoldover := ptpConfig.Definition.Spec.Profile[profileIndex].PtpClockThreshold.HoldOverTimeout
-			oldHoldovers[profile.Reference] = &copiedHoldover
+			oldHoldover = &copiedHoldover
 		}
 
 		ptpConfig.Definition.Spec.Profile[profileIndex].PtpClockThreshold.HoldOverTimeout = holdoverTimeout
 
 		_, err = ptpConfig.Update()
 		if err != nil {
-			return nil, fmt.Errorf("failed to update PtpConfig for profile %s: %w", profile.Reference.ProfileName, err)
+			return oldHoldovers, fmt.Errorf("failed to update PtpConfig for profile %s: %w",
+				profile.Reference.ProfileName, err)
 		}
+
+		oldHoldovers[profile.Reference] = oldHoldover
 	}
 
 	klog.V(tsparams.LogLevel).Infof(
@@ -8,6 +8,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/ranparam"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
 	"k8s.io/klog/v2"
 )
 
@@ -102,7 +103,7 @@ func ExecuteCommandInPtpDaemonPod(
 
 		// If there is the option to retry on error, we only log the error before continuing to retry.
 		// Otherwise, we return the error immediately since we do not retry on it.
-		output, err := daemonPod.ExecCommand([]string{"sh", "-c", command}, execOptions.containerName)
+		output, err := execrecorder.ExecCommand(client, daemonPod, []string{"sh", "-c", command}, execOptions.containerName)
 		if execOptions.retryOnError && err != nil {
 			klog.V(tsparams.LogLevel).Infof("Failed to execute command %q in PTP daemon pod on node %s\nerror: %v\noutput: %s",
 				command, nodeName, err, output.String())
@@ -0,0 +1,85 @@
+// Package ptppreflight defines the ptp feature checked by the eco-preflight command.
+package ptppreflight
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/ranconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/preflight"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/requires"
+)
+
+// NewFeature returns the ptp feature, which needs the PTP operator and at least one PTP profile recommended to a node.
+func NewFeature() preflight.Feature {
+	ranConfig := ranconfig.NewRANConfig()
+	if ranConfig == nil {
+		return preflight.Feature{Name: "ptp"}
+	}
+
+	return preflight.Feature{
+		Name:   "ptp",
+		Config: ranConfig,
+		Requirements: []requires.Requirement{
+			requires.OperatorVersion(ranConfig.PtpOperatorNamespace, "ptp-operator", ""),
+			PTPProfiles(1),
+		},
+		Facts: []preflight.Probe{{Name: "PTP profiles", Probe: probePTPProfiles}},
+	}
+}
+
+// PTPProfiles requires at least minimum PTP profiles to be recommended to the nodes of the cluster, as found by
+// profiles.GetNodeInfoMap. A profile recommended to several nodes is counted once for each.
+func PTPProfiles(minimum int) requires.Requirement {
+	return requires.New(fmt.Sprintf("%d PTP profiles", minimum), func(apiClient *clients.Settings) (bool, string) {
+		nodeInfoMap, err := profiles.GetNodeInfoMap(apiClient)
+		if err != nil {
+			return false, err.Error()
+		}
+
+		count := 0
+
+		for _, nodeInfo := range nodeInfoMap {
+			count += len(nodeInfo.Profiles)
+		}
+
+		if count < minimum {
+			return false, fmt.Sprintf("found %d PTP profiles recommended to nodes, need %d", count, minimum)
+		}
+
+		return true, ""
+	})
+}
+
+// probePTPProfiles returns the names of the PTP profiles recommended to each node, such as "node-0: bc, gm".
+func probePTPProfiles(apiClient *clients.Settings) (string, error) {
+	nodeInfoMap, err := profiles.GetNodeInfoMap(apiClient)
+	if err != nil {
+		return "", err
+	}
+
+	if len(nodeInfoMap) == 0 {
+		return "none", nil
+	}
+
+	var nodes []string
+
+	for nodeName, nodeInfo := range nodeInfoMap {
+		var profileNames []string
+
+		for _, profileInfo := range nodeInfo.Profiles {
+			profileNames = append(profileNames, profileInfo.Reference.ProfileName)
+		}
+
+		sort.Strings(profileNames)
+
+		nodes = append(nodes, fmt.Sprintf("%s: %s", nodeName, strings.Join(profileNames, ", ")))
+	}
+
+	sort.Strings(nodes)
+
+	return strings.Join(nodes, "; "), nil
+}
@@ -0,0 +1,138 @@
+// Package replay runs recorded outputs of the linuxptp daemon, pmc, and dpll tools through the PTP parsers and compares
+// the results against golden JSON files. Each parser keeps a corpus of recordings in the testdata directory of its
+// package, so parsing regressions across linuxptp and tool versions are caught by unit tests without a PTP lab.
+//
+// Recordings are the raw captured outputs, such as container logs from a PTP must-gather, daemon logs from the log
+// collector, or command output from the daemon pod. Golden files are created and updated by running the tests with the
+// -update flag and are reviewed like any other change.
+package replay
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// GoldenSuffix is appended to the name of a recording to get the name of its golden file.
+const GoldenSuffix = ".golden.json"
+
+var update = flag.Bool("update", false, "rewrite the golden files of replayed recordings with the current output")
+
+// timestampPattern matches the RFC3339 timestamp the kubelet prefixes container log lines with when timestamps are
+// requested, as in the pod logs saved by must-gather.
+var timestampPattern = regexp.MustCompile(`(?m)^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2}) `)
+
+// Parser parses a recording into a value which is compared against the golden file once marshalled to JSON.
+type Parser func(recording []byte) (any, error)
+
+// Golden is the content of a golden file. Parser errors are part of the golden result, so recordings which are
+// expected to fail parsing are covered too.
+type Golden struct {
+	Output any    `json:"output,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Run replays every recording in dir through parse as a subtest named after the recording and compares the output
+// with its golden file. Every file in dir without GoldenSuffix is a recording. With the -update flag, the golden files
+// are written instead.
+func Run(t *testing.T, dir string, parse Parser) {
+	t.Helper()
+
+	recordings, err := Recordings(dir)
+	if err != nil {
+		t.Fatalf("failed to list recordings: %v", err)
+	}
+
+	for _, recording := range recordings {
+		t.Run(filepath.Base(recording), func(t *testing.T) {
+			replayRecording(t, recording, parse)
+		})
+	}
+}
+
+// Recordings returns the paths of the recordings in dir, in lexical order. It returns an error if there are none,
+// since an empty corpus would silently pass.
+func Recordings(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var recordings []string
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), GoldenSuffix) {
+			continue
+		}
+
+		recordings = append(recordings, filepath.Join(dir, entry.Name()))
+	}
+
+	if len(recordings) == 0 {
+		return nil, errors.New("no recordings found in " + dir)
+	}
+
+	return recordings, nil
+}
+
+// StripTimestamps removes the kubelet timestamp from the start of every line of recording, so container logs saved
+// with timestamps can be replayed through parsers expecting the raw log lines. Lines without a timestamp are kept as
+// is.
+func StripTimestamps(recording []byte) []byte {
+	return timestampPattern.ReplaceAll(recording, nil)
+}
+
+// replayRecording parses the recording at path and compares the result with its golden file, or writes the golden
+// file with the -update flag.
+func replayRecording(t *testing.T, path string, parse Parser) {
+	t.Helper()
+
+	recording, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read recording: %v", err)
+	}
+
+	golden := Golden{}
+
+	output, err := parse(recording)
+	if err != nil {
+		golden.Error = err.Error()
+	} else {
+		golden.Output = output
+	}
+
+	actual, err := json.MarshalIndent(golden, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal output: %v", err)
+	}
+
+	actual = append(actual, '\n')
+	goldenPath := path + GoldenSuffix
+
+	if *update {
+		err = os.WriteFile(goldenPath, actual, 0o644)
+		if err != nil {
+			t.Fatalf("failed to write golden file: %v", err)
+		}
+
+		return
+	}
+
+	expected, err := os.ReadFile(goldenPath)
+	if err != nil {
+		t.Fatalf("failed to read golden file, run the test with -update to create it: %v", err)
+	}
+
+	if !bytes.Equal(expected, actual) {
+		assert.JSONEq(t, string(expected), string(actual),
+			"output differs from %s, run the test with -update if the change is expected", goldenPath)
+	}
+}
@@ -0,0 +1,38 @@
+//go:build unit_test
+
+package replay
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripTimestamps(t *testing.T) {
+	recording := "2024-05-01T12:00:01.465313672Z ts2phc[82674.465]: offset 1 s2\n" +
+		"2024-05-01T12:00:02+02:00 phc2sys[82675.781]: offset -6 s2\n" +
+		"ptp4l[82676.000]: logged 2024-05-01T12:00:03Z without a timestamp\n"
+
+	assert.Equal(t, "ts2phc[82674.465]: offset 1 s2\n"+
+		"phc2sys[82675.781]: offset -6 s2\n"+
+		"ptp4l[82676.000]: logged 2024-05-01T12:00:03Z without a timestamp\n",
+		string(StripTimestamps([]byte(recording))))
+}
+
+func TestRecordings(t *testing.T) {
+	dir := t.TempDir()
+
+	_, err := Recordings(dir)
+	assert.ErrorContains(t, err, "no recordings found")
+
+	for _, name := range []string{"b.log", "a.txt", "a.txt" + GoldenSuffix} {
+		err = os.WriteFile(filepath.Join(dir, name), nil, 0o644)
+		assert.NoError(t, err)
+	}
+
+	recordings, err := Recordings(dir)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{filepath.Join(dir, "a.txt"), filepath.Join(dir, "b.log")}, recordings)
+}
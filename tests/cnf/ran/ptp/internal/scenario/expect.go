@@ -0,0 +1,87 @@
+package scenario
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/consumer"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/eventmetric"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/events"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/metrics"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"golang.org/x/exp/constraints"
+	"k8s.io/klog/v2"
+)
+
+// defaultExpectationTimeout is the timeout of event and metric expectations which do not set their own.
+const defaultExpectationTimeout = 5 * time.Minute
+
+// ExpectEventMetric returns an expectation which executes the assertion returned by newAssertion for the target. The
+// assertion is run for the target node starting from when the latest fault was injected or recovered. If the assertion
+// has no Prometheus API, the one from the target is used, and if it has no timeout, it defaults to 5 minutes.
+//
+//	scenario.ExpectEventMetric("clock class 7", func(target *scenario.Target) *eventmetric.AssertConfig[...] {
+//		query := metrics.ClockClassQuery{Node: metrics.Equals(target.Node.Name)}
+//		filter := events.All(events.IsType(eventptp.PtpClockClassChange), events.HasValue(events.WithMetric(7)))
+//
+//		return eventmetric.NewAssertion(target.PrometheusAPI, query, metrics.ClockClass7, filter)
+//	})
+func ExpectEventMetric[V constraints.Integer](
+	name string, newAssertion func(target *Target) *eventmetric.AssertConfig[V]) Expectation {
+	return NewExpectation(name, func(ctx context.Context, target *Target, since time.Time) error {
+		assertion := newAssertion(target).ForNode(target.Client, target.Node.Name).WithStartTime(since)
+
+		if assertion.PrometheusAPI == nil {
+			assertion.PrometheusAPI = target.PrometheusAPI
+		}
+
+		if assertion.Timeout == 0 {
+			assertion.Timeout = defaultExpectationTimeout
+		}
+
+		return assertion.ExecuteAssertion(ctx)
+	})
+}
+
+// ExpectMetric returns an expectation that the query returned by newQuery for the target matches expected, starting
+// from when the latest fault was injected or recovered. Unless overridden by the provided options, it times out after 5
+// minutes.
+func ExpectMetric[V constraints.Integer](
+	name string, newQuery func(target *Target) metrics.Query[V], expected V, options ...metrics.QueryAssertOption,
+) Expectation {
+	return NewExpectation(name, func(ctx context.Context, target *Target, since time.Time) error {
+		assertOptions := []metrics.QueryAssertOption{
+			metrics.AssertWithStartTime(since), metrics.AssertWithTimeout(defaultExpectationTimeout),
+		}
+		assertOptions = append(assertOptions, options...)
+
+		return metrics.AssertQuery(ctx, target.PrometheusAPI, newQuery(target), expected, assertOptions...)
+	})
+}
+
+// ExpectEvent returns an expectation that an event matching filter appears on the consumer pod of the target node
+// within timeout, starting from when the latest fault was injected or recovered. It always passes if events are not
+// enabled on the cluster, the same as the event half of [eventmetric.AssertConfig.ExecuteAssertion].
+func ExpectEvent(
+	name string, filter events.EventFilter, timeout time.Duration, options ...events.WaitForEventOption) Expectation {
+	return NewExpectation(name, func(_ context.Context, target *Target, since time.Time) error {
+		eventsEnabled, err := consumer.AreEventsEnabled(target.Client)
+		if err != nil {
+			return fmt.Errorf("failed to check if events are enabled: %w", err)
+		}
+
+		if !eventsEnabled {
+			klog.V(tsparams.LogLevel).Infof("Events are not enabled, skipping expectation %s", name)
+
+			return nil
+		}
+
+		eventPod, err := consumer.GetConsumerPodforNode(target.Client, target.Node.Name)
+		if err != nil {
+			return fmt.Errorf("failed to get consumer pod for node %s: %w", target.Node.Name, err)
+		}
+
+		return events.WaitForEvent(eventPod, since, timeout, filter, options...)
+	})
+}
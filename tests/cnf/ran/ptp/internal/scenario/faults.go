@@ -0,0 +1,263 @@
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/gnss"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/iface"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/processes"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/sma"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// processRestartTimeout is how long the rollback of a killed process waits for the daemon to restart it.
+	processRestartTimeout = 5 * time.Minute
+	// holdoverTimeoutApplyTimeout is how long holdover timeout changes may take to show up in the metrics.
+	holdoverTimeoutApplyTimeout = 5 * time.Minute
+)
+
+// gmProfileTypes are the profile types with a GNSS receiver.
+var gmProfileTypes = []profiles.PtpProfileType{
+	profiles.ProfileTypeGM, profiles.ProfileTypeMultiNICGM, profiles.ProfileTypeNTPFallback,
+}
+
+// InterfaceSelector selects the interface on the target a fault applies to.
+type InterfaceSelector func(target *Target) (iface.Name, error)
+
+// Interface selects the interface with the provided name.
+func Interface(name iface.Name) InterfaceSelector {
+	return func(*Target) (iface.Name, error) {
+		return name, nil
+	}
+}
+
+// UpstreamPort selects the upstream port of the first profile on the target with one of the provided types, as
+// returned by [profiles.GetUpstreamPortForProfile].
+func UpstreamPort(profileTypes ...profiles.PtpProfileType) InterfaceSelector {
+	return func(target *Target) (iface.Name, error) {
+		profileInfo, err := target.profileOfTypes(profileTypes...)
+		if err != nil {
+			return "", err
+		}
+
+		ptpProfile, err := profileInfo.PullProfile(target.Client)
+		if err != nil {
+			return "", err
+		}
+
+		return profiles.GetUpstreamPortForProfile(ptpProfile)
+	}
+}
+
+// ClientInterface selects the client interface of the first profile on the target with one of the provided types. If
+// the profile has several client interfaces, the first by name is selected.
+func ClientInterface(profileTypes ...profiles.PtpProfileType) InterfaceSelector {
+	return func(target *Target) (iface.Name, error) {
+		profileInfo, err := target.profileOfTypes(profileTypes...)
+		if err != nil {
+			return "", err
+		}
+
+		names := profiles.GetInterfacesNames(profileInfo.GetInterfacesByClockType(profiles.ClockTypeClient))
+		if len(names) == 0 {
+			return "", fmt.Errorf("profile %s has no client interfaces", profileInfo.Reference.ProfileName)
+		}
+
+		return slices.Min(names), nil
+	}
+}
+
+// InterfaceDown sets the selected interface down. Its rollback sets the interface back up, and is returned even if
+// setting the interface down fails, since the interface may be down anyway.
+func InterfaceDown(selector InterfaceSelector) Fault {
+	return NewFault("interface down", func(_ context.Context, target *Target) (Rollback, error) {
+		ifaceName, err := selector(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select interface: %w", err)
+		}
+
+		rollback := func(context.Context) error {
+			return iface.SetInterfaceStatus(target.Client, target.Node.Name, ifaceName, iface.InterfaceStateUp)
+		}
+
+		err = iface.SetInterfaceStatus(target.Client, target.Node.Name, ifaceName, iface.InterfaceStateDown)
+		if err != nil {
+			return rollback, fmt.Errorf("failed to set interface %s down: %w", ifaceName, err)
+		}
+
+		return rollback, nil
+	})
+}
+
+// PHCAdjust adjusts the PTP hardware clock of the selected interface by amount seconds. It has no rollback, since the
+// servo steers the clock back once it is adjusted.
+func PHCAdjust(selector InterfaceSelector, amount float64) Fault {
+	name := fmt.Sprintf("phc adjust %gs", amount)
+
+	return NewFault(name, func(_ context.Context, target *Target) (Rollback, error) {
+		ifaceName, err := selector(target)
+		if err != nil {
+			return nil, fmt.Errorf("failed to select interface: %w", err)
+		}
+
+		return nil, iface.AdjustPTPHardwareClock(target.Client, target.Node.Name, ifaceName, amount)
+	})
+}
+
+// ProcessKill kills process the provided number of times. Its rollback waits for the daemon to restart the process,
+// and is returned even if one of the kills fails, since the earlier kills still took effect.
+func ProcessKill(process processes.PtpProcess, times int) Fault {
+	return NewFault(string(process)+" kill", func(_ context.Context, target *Target) (Rollback, error) {
+		rollback := func(context.Context) error {
+			return processes.WaitForProcessRunning(
+				target.Client, target.Node.Name, process, true, processRestartTimeout)
+		}
+
+		err := processes.KillPtpProcessMultipleTimes(target.Client, target.Node.Name, process, times)
+		if err != nil {
+			return rollback, err
+		}
+
+		return rollback, nil
+	})
+}
+
+// GNSSLoss simulates a loss of GNSS sync on the receiver of the first grandmaster profile on the target. Its rollback
+// simulates the recovery of GNSS sync, and is returned even if simulating the loss fails, since the receiver may have
+// been reconfigured anyway.
+func GNSSLoss() Fault {
+	return NewFault("gnss loss", func(_ context.Context, target *Target) (Rollback, error) {
+		profileInfo, err := target.profileOfTypes(gmProfileTypes...)
+		if err != nil {
+			return nil, err
+		}
+
+		ptpProfile, err := profileInfo.PullProfile(target.Client)
+		if err != nil {
+			return nil, err
+		}
+
+		protocolVersion, err := gnss.GetUbloxProtocolVersion(ptpProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get ublox protocol version: %w", err)
+		}
+
+		rollback := func(context.Context) error {
+			return gnss.SimulateSyncRecovery(target.Client, target.Node.Name, protocolVersion)
+		}
+
+		err = gnss.SimulateSyncLoss(target.Client, target.Node.Name, protocolVersion)
+		if err != nil {
+			return rollback, err
+		}
+
+		return rollback, nil
+	})
+}
+
+// SMADisconnect disconnects the active SMA pin of every RX interface of the first multi-NIC grandmaster profile on the
+// target. Its rollback reconnects the pins to their configuration from the profile. If disconnecting a pin fails, the
+// rollback is returned with the error and reconnects the pins it tried to disconnect.
+func SMADisconnect() Fault {
+	return NewFault("sma disconnect", func(_ context.Context, target *Target) (Rollback, error) {
+		profileInfo, err := target.profileOfTypes(profiles.ProfileTypeMultiNICGM)
+		if err != nil {
+			return nil, err
+		}
+
+		ptpProfile, err := profileInfo.PullProfile(target.Client)
+		if err != nil {
+			return nil, err
+		}
+
+		rxInterfaces, err := profiles.GetRxInterfaces(ptpProfile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get RX interfaces: %w", err)
+		}
+
+		var reconnects []Rollback
+
+		rollback := func(ctx context.Context) error {
+			var errs []error
+
+			for _, reconnect := range slices.Backward(reconnects) {
+				errs = append(errs, reconnect(ctx))
+			}
+
+			return errors.Join(errs...)
+		}
+
+		for _, rxIface := range rxInterfaces {
+			pinName, config, err := profiles.GetSmaPinFromProfile(ptpProfile, rxIface)
+			if err != nil {
+				return rollback, fmt.Errorf("failed to get SMA pin for interface %s: %w", rxIface, err)
+			}
+
+			reconnects = append(reconnects, func(context.Context) error {
+				return sma.ReconnectSma(target.Client, target.Node.Name, rxIface, pinName, config)
+			})
+
+			err = sma.DisconnectSma(target.Client, target.Node.Name, rxIface, pinName)
+			if err != nil {
+				return rollback, fmt.Errorf("failed to disconnect SMA for interface %s: %w", rxIface, err)
+			}
+		}
+
+		return rollback, nil
+	})
+}
+
+// HoldoverTimeout sets the holdover timeout of every profile on the target to seconds. Its rollback resets the
+// original holdover timeouts of the profiles that were updated, and is returned even if updating or waiting fails. If
+// the target has a Prometheus API, both wait for the holdover timeouts to show up in the metrics.
+func HoldoverTimeout(seconds int64) Fault {
+	name := fmt.Sprintf("holdover timeout %ds", seconds)
+
+	return NewFault(name, func(_ context.Context, target *Target) (Rollback, error) {
+		oldHoldovers, err := profiles.SetHoldOverTimeouts(target.Client, target.Node.Profiles, seconds)
+
+		rollback := func(context.Context) error {
+			err := profiles.ResetHoldOverTimeouts(target.Client, oldHoldovers)
+			if err != nil || target.PrometheusAPI == nil {
+				return err
+			}
+
+			return profiles.WaitForOldHoldOverTimeouts(
+				target.PrometheusAPI, target.Node.Name, oldHoldovers, holdoverTimeoutApplyTimeout)
+		}
+
+		if err != nil {
+			return rollback, err
+		}
+
+		if target.PrometheusAPI != nil {
+			err = profiles.WaitForHoldOverTimeouts(
+				target.PrometheusAPI, target.Node.Name, target.Node.Profiles, seconds, holdoverTimeoutApplyTimeout)
+			if err != nil {
+				return rollback, fmt.Errorf("failed to wait for holdover timeouts: %w", err)
+			}
+		}
+
+		return rollback, nil
+	})
+}
+
+// profileOfTypes returns the first profile on the target with one of the provided types.
+func (target *Target) profileOfTypes(profileTypes ...profiles.PtpProfileType) (*profiles.ProfileInfo, error) {
+	profileInfos := target.Node.GetProfilesByTypes(profileTypes...)
+	if len(profileInfos) == 0 {
+		return nil, fmt.Errorf("node %s has no profiles of types %v", target.Node.Name, profileTypes)
+	}
+
+	klog.V(tsparams.LogLevel).Infof("Using profile %s on node %s for fault injection",
+		profileInfos[0].Reference.ProfileName, target.Node.Name)
+
+	return profileInfos[0], nil
+}
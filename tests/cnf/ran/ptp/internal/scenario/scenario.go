@@ -0,0 +1,399 @@
+// Package scenario runs PTP fault-injection scenarios declared as a list of timed steps. Steps inject faults, recover
+// from them, wait, and check the expected event and metric outcomes. Every fault returns its own rollback, and any
+// fault still injected when a scenario ends, whether it fails, is cancelled, or panics, is rolled back in reverse
+// order. Scenarios are not tied to a node, so the same scenario can run against every node from
+// [profiles.GetNodeInfoMap] with the profiles it needs.
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"k8s.io/klog/v2"
+)
+
+// ErrNoApplicableNodes is returned by [Scenario.RunOnNodes] when no node has the profiles the scenario needs, so specs
+// can skip rather than fail.
+var ErrNoApplicableNodes = errors.New("no nodes with the profiles required by the scenario")
+
+// Target is the node a scenario runs against, along with the clients faults and expectations use.
+type Target struct {
+	Client        *clients.Settings
+	PrometheusAPI prometheusv1.API
+	Node          *profiles.NodeInfo
+}
+
+// Rollback undoes an injected fault. It receives a context which is not cancelled when the scenario is, so rollbacks
+// still run after a timeout.
+type Rollback func(ctx context.Context) error
+
+// Fault is a fault that can be injected on a target.
+type Fault interface {
+	// Name identifies the fault in steps, results, and errors. Recover steps find the fault to recover by name.
+	Name() string
+	// Inject injects the fault on the target and returns the rollback undoing it. The rollback may be nil when there
+	// is nothing to undo, such as for a one-time clock adjustment. If injecting the fault fails after changing the
+	// target, the rollback undoing those changes should be returned along with the error.
+	Inject(ctx context.Context, target *Target) (Rollback, error)
+}
+
+// Expectation is an outcome expected after a fault is injected or recovered.
+type Expectation interface {
+	// Name identifies the expectation in steps, results, and errors.
+	Name() string
+	// Check returns an error if the outcome was not observed on the target. The since time is when the latest fault
+	// was injected or recovered, so checks only look at what happened after it.
+	Check(ctx context.Context, target *Target, since time.Time) error
+}
+
+// NewFault returns a fault named name which is injected by inject.
+func NewFault(name string, inject func(ctx context.Context, target *Target) (Rollback, error)) Fault {
+	return funcFault{name: name, inject: inject}
+}
+
+// NewExpectation returns an expectation named name which is checked by check.
+func NewExpectation(name string, check func(ctx context.Context, target *Target, since time.Time) error) Expectation {
+	return funcExpectation{name: name, check: check}
+}
+
+// StepKind is the kind of action a step performs.
+type StepKind string
+
+const (
+	// StepKindInject injects a fault.
+	StepKindInject StepKind = "inject"
+	// StepKindRecover rolls back a previously injected fault.
+	StepKindRecover StepKind = "recover"
+	// StepKindExpect checks an expectation.
+	StepKindExpect StepKind = "expect"
+	// StepKindWait waits without doing anything else.
+	StepKindWait StepKind = "wait"
+	// StepKindRollback is a rollback run when the scenario ends with the fault still injected. These only appear in
+	// results.
+	StepKindRollback StepKind = "rollback"
+)
+
+// Step is a single step of a scenario. Steps are created with [Inject], [Recover], [Expect], and [Wait], then
+// optionally delayed with [Step.After].
+type Step struct {
+	Kind StepKind
+	Name string
+	// Delay is how long to wait after the previous step before running this one.
+	Delay time.Duration
+
+	action func(ctx context.Context, run *run) error
+}
+
+// After returns a copy of the step which runs delay after the previous step.
+func (step Step) After(delay time.Duration) Step {
+	step.Delay = delay
+
+	return step
+}
+
+// Inject returns a step injecting fault. Unless recovered by a later [Recover] step, the fault is rolled back when the
+// scenario ends. If the injection fails but returns a rollback, the rollback still runs when the scenario ends.
+func Inject(fault Fault) Step {
+	return Step{Kind: StepKindInject, Name: fault.Name(), action: func(ctx context.Context, run *run) error {
+		run.since = time.Now()
+
+		rollback, err := fault.Inject(ctx, run.target)
+		run.injected = append(run.injected, injectedFault{name: fault.Name(), rollback: rollback})
+
+		if err != nil {
+			return fmt.Errorf("failed to inject %s: %w", fault.Name(), err)
+		}
+
+		return nil
+	}}
+}
+
+// Recover returns a step rolling back the latest injection of fault that has not been recovered yet.
+func Recover(fault Fault) Step {
+	return Step{Kind: StepKindRecover, Name: fault.Name(), action: func(ctx context.Context, run *run) error {
+		index := run.latestInjection(fault.Name())
+		if index == -1 {
+			return fmt.Errorf("cannot recover %s: it is not injected", fault.Name())
+		}
+
+		injected := run.injected[index]
+		run.injected = slices.Delete(run.injected, index, index+1)
+		run.since = time.Now()
+
+		if injected.rollback == nil {
+			return nil
+		}
+
+		if err := injected.rollback(ctx); err != nil {
+			return fmt.Errorf("failed to recover %s: %w", fault.Name(), err)
+		}
+
+		return nil
+	}}
+}
+
+// Expect returns a step checking expectation.
+func Expect(expectation Expectation) Step {
+	return Step{Kind: StepKindExpect, Name: expectation.Name(), action: func(ctx context.Context, run *run) error {
+		if err := expectation.Check(ctx, run.target, run.since); err != nil {
+			return fmt.Errorf("expectation %s not met: %w", expectation.Name(), err)
+		}
+
+		return nil
+	}}
+}
+
+// Wait returns a step waiting for duration, such as to let a clock settle in a state before the next step.
+func Wait(duration time.Duration) Step {
+	return Step{Kind: StepKindWait, Name: duration.String(), Delay: duration, action: func(context.Context, *run) error {
+		return nil
+	}}
+}
+
+// Scenario is a named list of steps.
+type Scenario struct {
+	Name string
+	// ProfileTypes are the profile types a node needs at least one of for the scenario to run on it. The scenario
+	// applies to every node if it is empty.
+	ProfileTypes []profiles.PtpProfileType
+	Steps        []Step
+}
+
+// New returns a scenario named name with the provided steps.
+func New(name string, steps ...Step) *Scenario {
+	return &Scenario{Name: name, Steps: steps}
+}
+
+// ForProfileTypes restricts the scenario to nodes with at least one profile of the provided types.
+func (scenario *Scenario) ForProfileTypes(profileTypes ...profiles.PtpProfileType) *Scenario {
+	scenario.ProfileTypes = profileTypes
+
+	return scenario
+}
+
+// AppliesTo reports whether the scenario can run on the node.
+func (scenario *Scenario) AppliesTo(nodeInfo *profiles.NodeInfo) bool {
+	return len(scenario.ProfileTypes) == 0 || len(nodeInfo.GetProfilesByTypes(scenario.ProfileTypes...)) > 0
+}
+
+// Run runs the steps of the scenario in order against target and stops at the first failing step. Faults still
+// injected when it stops are rolled back in reverse order, even if ctx is cancelled or a step panics. The result
+// records every step that ran, including the rollbacks, and the returned error joins the step and rollback errors.
+func (scenario *Scenario) Run(ctx context.Context, target Target) (result *Result, err error) {
+	run := &run{target: &target, since: time.Now()}
+	result = &Result{Scenario: scenario.Name, Node: target.Node.Name}
+
+	defer func() {
+		rollbackErr := run.rollback(context.WithoutCancel(ctx), result)
+		err = errors.Join(err, rollbackErr)
+	}()
+
+	for _, step := range scenario.Steps {
+		if err := sleep(ctx, step.Delay); err != nil {
+			return result, fmt.Errorf("scenario %s cancelled before %s %s on node %s: %w",
+				scenario.Name, step.Kind, step.Name, target.Node.Name, err)
+		}
+
+		klog.V(tsparams.LogLevel).Infof("Scenario %s on node %s: %s %s",
+			scenario.Name, target.Node.Name, step.Kind, step.Name)
+
+		stepResult := StepResult{Kind: step.Kind, Name: step.Name, Start: time.Now()}
+		stepErr := step.action(ctx, run)
+		stepResult.End = time.Now()
+
+		if stepErr != nil {
+			stepResult.Error = stepErr.Error()
+		}
+
+		result.Steps = append(result.Steps, stepResult)
+
+		if stepErr != nil {
+			return result, fmt.Errorf("scenario %s failed on node %s: %w", scenario.Name, target.Node.Name, stepErr)
+		}
+	}
+
+	return result, nil
+}
+
+// RunOnNodes runs the scenario on every node in nodeInfoMap it applies to, in order of node name. It stops at the first
+// node where the scenario fails, after that node's rollbacks. It returns [ErrNoApplicableNodes] if the scenario applies
+// to no node.
+func (scenario *Scenario) RunOnNodes(
+	ctx context.Context,
+	client *clients.Settings,
+	prometheusAPI prometheusv1.API,
+	nodeInfoMap map[string]*profiles.NodeInfo,
+) ([]*Result, error) {
+	var results []*Result
+
+	for _, nodeName := range slices.Sorted(maps.Keys(nodeInfoMap)) {
+		nodeInfo := nodeInfoMap[nodeName]
+		if !scenario.AppliesTo(nodeInfo) {
+			klog.V(tsparams.LogLevel).Infof("Skipping scenario %s on node %s: no profiles of types %v",
+				scenario.Name, nodeName, scenario.ProfileTypes)
+
+			continue
+		}
+
+		result, err := scenario.Run(ctx, Target{Client: client, PrometheusAPI: prometheusAPI, Node: nodeInfo})
+		results = append(results, result)
+
+		if err != nil {
+			return results, err
+		}
+	}
+
+	if len(results) == 0 {
+		return nil, fmt.Errorf("cannot run scenario %s: %w", scenario.Name, ErrNoApplicableNodes)
+	}
+
+	return results, nil
+}
+
+// StepResult records a step that ran.
+type StepResult struct {
+	Kind  StepKind  `json:"kind"`
+	Name  string    `json:"name"`
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	Error string    `json:"error,omitempty"`
+}
+
+// Result records the steps that ran when a scenario ran on a node, followed by the rollbacks run when it ended.
+type Result struct {
+	Scenario string       `json:"scenario"`
+	Node     string       `json:"node"`
+	Steps    []StepResult `json:"steps"`
+}
+
+// String returns the timeline of the result with one line per step, so it can be added to reports as is.
+func (result *Result) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "scenario %s on node %s\n", result.Scenario, result.Node)
+
+	var start time.Time
+	if len(result.Steps) > 0 {
+		start = result.Steps[0].Start
+	}
+
+	for _, step := range result.Steps {
+		fmt.Fprintf(&builder, "  +%s %s %s (%s)", step.Start.Sub(start).Round(time.Millisecond),
+			step.Kind, step.Name, step.End.Sub(step.Start).Round(time.Millisecond))
+
+		if step.Error != "" {
+			fmt.Fprintf(&builder, ": %s", step.Error)
+		}
+
+		builder.WriteString("\n")
+	}
+
+	return builder.String()
+}
+
+// run is the state of a scenario running on a target.
+type run struct {
+	target *Target
+	// since is when the latest fault was injected or recovered.
+	since    time.Time
+	injected []injectedFault
+}
+
+// latestInjection returns the index of the latest injection of the fault named name that is not recovered, or -1 if
+// there is none.
+func (run *run) latestInjection(name string) int {
+	for index := len(run.injected) - 1; index >= 0; index-- {
+		if run.injected[index].name == name {
+			return index
+		}
+	}
+
+	return -1
+}
+
+type injectedFault struct {
+	name     string
+	rollback Rollback
+}
+
+// rollback rolls back the faults still injected in reverse order and records them in result. It continues past failed
+// rollbacks so one fault failing to roll back does not leave the others injected.
+func (run *run) rollback(ctx context.Context, result *Result) error {
+	var errs []error
+
+	for _, injected := range slices.Backward(run.injected) {
+		if injected.rollback == nil {
+			continue
+		}
+
+		klog.V(tsparams.LogLevel).Infof("Rolling back %s on node %s", injected.name, run.target.Node.Name)
+
+		stepResult := StepResult{Kind: StepKindRollback, Name: injected.name, Start: time.Now()}
+		err := injected.rollback(ctx)
+		stepResult.End = time.Now()
+
+		if err != nil {
+			stepResult.Error = err.Error()
+			errs = append(errs,
+				fmt.Errorf("failed to roll back %s on node %s: %w", injected.name, run.target.Node.Name, err))
+		}
+
+		result.Steps = append(result.Steps, stepResult)
+	}
+
+	run.injected = nil
+
+	return errors.Join(errs...)
+}
+
+type funcFault struct {
+	name   string
+	inject func(ctx context.Context, target *Target) (Rollback, error)
+}
+
+func (fault funcFault) Name() string {
+	return fault.name
+}
+
+func (fault funcFault) Inject(ctx context.Context, target *Target) (Rollback, error) {
+	return fault.inject(ctx, target)
+}
+
+type funcExpectation struct {
+	name  string
+	check func(ctx context.Context, target *Target, since time.Time) error
+}
+
+func (expectation funcExpectation) Name() string {
+	return expectation.name
+}
+
+func (expectation funcExpectation) Check(ctx context.Context, target *Target, since time.Time) error {
+	return expectation.check(ctx, target, since)
+}
+
+// sleep waits for duration or until ctx is done, returning the context error in the latter case.
+func sleep(ctx context.Context, duration time.Duration) error {
+	if duration <= 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,318 @@
+//go:build unit_test
+
+package scenario
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/iface"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name          string
+		steps         func(recorder *recorder) []Step
+		expectedCalls []string
+		expectedSteps []string
+		expectedError string
+	}{
+		{
+			name: "rollback at end",
+			steps: func(recorder *recorder) []Step {
+				return []Step{
+					Inject(recorder.fault("a", nil)),
+					Inject(recorder.fault("b", nil)),
+					Expect(recorder.expectation("locked", nil)),
+				}
+			},
+			expectedCalls: []string{"inject a", "inject b", "check locked", "rollback b", "rollback a"},
+			expectedSteps: []string{"inject a", "inject b", "expect locked", "rollback b", "rollback a"},
+		},
+		{
+			name: "recover before end",
+			steps: func(recorder *recorder) []Step {
+				faultA := recorder.fault("a", nil)
+
+				return []Step{
+					Inject(faultA),
+					Inject(recorder.fault("b", nil)),
+					Recover(faultA),
+					Expect(recorder.expectation("locked", nil)),
+				}
+			},
+			expectedCalls: []string{"inject a", "inject b", "rollback a", "check locked", "rollback b"},
+			expectedSteps: []string{"inject a", "inject b", "recover a", "expect locked", "rollback b"},
+		},
+		{
+			name: "failed expectation",
+			steps: func(recorder *recorder) []Step {
+				return []Step{
+					Inject(recorder.fault("a", nil)),
+					Expect(recorder.expectation("locked", errors.New("still freerun"))),
+					Inject(recorder.fault("b", nil)),
+				}
+			},
+			expectedCalls: []string{"inject a", "check locked", "rollback a"},
+			expectedSteps: []string{"inject a", "expect locked", "rollback a"},
+			expectedError: "scenario test failed on node node-1: expectation locked not met: still freerun",
+		},
+		{
+			name: "failed injection",
+			steps: func(recorder *recorder) []Step {
+				return []Step{
+					Inject(recorder.fault("a", nil)),
+					Inject(recorder.fault("b", errors.New("no gnss"))),
+				}
+			},
+			expectedCalls: []string{"inject a", "inject b", "rollback a"},
+			expectedSteps: []string{"inject a", "inject b", "rollback a"},
+			expectedError: "scenario test failed on node node-1: failed to inject b: no gnss",
+		},
+		{
+			name: "partially failed injection",
+			steps: func(recorder *recorder) []Step {
+				return []Step{
+					Inject(recorder.fault("a", nil)),
+					Inject(NewFault("b", func(context.Context, *Target) (Rollback, error) {
+						recorder.calls = append(recorder.calls, "inject b")
+
+						return func(context.Context) error {
+							recorder.calls = append(recorder.calls, "rollback b")
+
+							return nil
+						}, errors.New("second pin failed")
+					})),
+					Expect(recorder.expectation("locked", nil)),
+				}
+			},
+			expectedCalls: []string{"inject a", "inject b", "rollback b", "rollback a"},
+			expectedSteps: []string{"inject a", "inject b", "rollback b", "rollback a"},
+			expectedError: "scenario test failed on node node-1: failed to inject b: second pin failed",
+		},
+		{
+			name: "recover not injected",
+			steps: func(recorder *recorder) []Step {
+				return []Step{Recover(recorder.fault("a", nil))}
+			},
+			expectedSteps: []string{"recover a"},
+			expectedError: "scenario test failed on node node-1: cannot recover a: it is not injected",
+		},
+		{
+			name: "nil rollback",
+			steps: func(recorder *recorder) []Step {
+				return []Step{
+					Inject(NewFault("adjust", func(context.Context, *Target) (Rollback, error) {
+						return nil, nil
+					})),
+					Inject(recorder.fault("a", nil)),
+				}
+			},
+			expectedCalls: []string{"inject a", "rollback a"},
+			expectedSteps: []string{"inject adjust", "inject a", "rollback a"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			recorder := &recorder{}
+			scenario := New("test", testCase.steps(recorder)...)
+
+			result, err := scenario.Run(context.TODO(), Target{Node: &profiles.NodeInfo{Name: "node-1"}})
+			if testCase.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, testCase.expectedError)
+			}
+
+			assert.Equal(t, testCase.expectedCalls, recorder.calls)
+			assert.Equal(t, testCase.expectedSteps, stepNames(result))
+		})
+	}
+}
+
+func TestRunRollbackErrors(t *testing.T) {
+	recorder := &recorder{rollbackErrors: map[string]error{
+		"a": errors.New("link still down"),
+		"b": errors.New("pin still disconnected"),
+	}}
+	scenario := New("test", Inject(recorder.fault("a", nil)), Inject(recorder.fault("b", nil)))
+
+	result, err := scenario.Run(context.TODO(), Target{Node: &profiles.NodeInfo{Name: "node-1"}})
+	assert.EqualError(t, err, "failed to roll back b on node node-1: pin still disconnected\n"+
+		"failed to roll back a on node node-1: link still down")
+	assert.Equal(t, []string{"inject a", "inject b", "rollback b", "rollback a"}, recorder.calls)
+	assert.Equal(t, "pin still disconnected", result.Steps[2].Error)
+}
+
+func TestRunSince(t *testing.T) {
+	var sinces []time.Time
+
+	recordSince := NewExpectation("since", func(_ context.Context, _ *Target, since time.Time) error {
+		sinces = append(sinces, since)
+
+		return nil
+	})
+	recorder := &recorder{}
+	fault := recorder.fault("a", nil)
+
+	result, err := New("test",
+		Inject(fault),
+		Expect(recordSince).After(10*time.Millisecond),
+		Recover(fault),
+		Expect(recordSince),
+	).Run(context.TODO(), Target{Node: &profiles.NodeInfo{Name: "node-1"}})
+	assert.NoError(t, err)
+
+	if assert.Len(t, sinces, 2) {
+		assert.False(t, sinces[0].Before(result.Steps[0].Start))
+		assert.False(t, sinces[1].Before(result.Steps[2].Start))
+		assert.True(t, result.Steps[1].Start.Sub(sinces[0]) >= 10*time.Millisecond)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.TODO())
+	recorder := &recorder{}
+	recorder.onInject = cancel
+
+	result, err := New("test", Inject(recorder.fault("a", nil)), Wait(time.Hour)).
+		Run(ctx, Target{Node: &profiles.NodeInfo{Name: "node-1"}})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []string{"inject a", "rollback a"}, recorder.calls)
+	assert.Equal(t, []string{"inject a", "rollback a"}, stepNames(result))
+}
+
+func TestRunOnNodes(t *testing.T) {
+	nodeInfoMap := map[string]*profiles.NodeInfo{
+		"node-b": {Name: "node-b", Profiles: []*profiles.ProfileInfo{{ProfileType: profiles.ProfileTypeGM}}},
+		"node-a": {Name: "node-a", Profiles: []*profiles.ProfileInfo{{ProfileType: profiles.ProfileTypeGM}}},
+		"node-c": {Name: "node-c", Profiles: []*profiles.ProfileInfo{{ProfileType: profiles.ProfileTypeOC}}},
+	}
+
+	testCases := []struct {
+		name          string
+		profileTypes  []profiles.PtpProfileType
+		expectedNodes []string
+		expectedError error
+	}{
+		{name: "all nodes", expectedNodes: []string{"node-a", "node-b", "node-c"}},
+		{name: "gm nodes", profileTypes: []profiles.PtpProfileType{profiles.ProfileTypeGM},
+			expectedNodes: []string{"node-a", "node-b"}},
+		{name: "no nodes", profileTypes: []profiles.PtpProfileType{profiles.ProfileTypeMultiNICGM},
+			expectedError: ErrNoApplicableNodes},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			var nodes []string
+
+			recordNode := NewFault("record", func(_ context.Context, target *Target) (Rollback, error) {
+				nodes = append(nodes, target.Node.Name)
+
+				return nil, nil
+			})
+
+			results, err := New("test", Inject(recordNode)).
+				ForProfileTypes(testCase.profileTypes...).
+				RunOnNodes(context.TODO(), nil, nil, nodeInfoMap)
+			assert.ErrorIs(t, err, testCase.expectedError)
+			assert.Equal(t, testCase.expectedNodes, nodes)
+			assert.Len(t, results, len(testCase.expectedNodes))
+		})
+	}
+}
+
+func TestRunOnNodesStopsAtFailure(t *testing.T) {
+	nodeInfoMap := map[string]*profiles.NodeInfo{"node-a": {Name: "node-a"}, "node-b": {Name: "node-b"}}
+	recorder := &recorder{}
+
+	results, err := New("test", Inject(recorder.fault("a", errors.New("failed")))).
+		RunOnNodes(context.TODO(), nil, nil, nodeInfoMap)
+	assert.Error(t, err)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "node-a", results[0].Node)
+}
+
+func TestClientInterface(t *testing.T) {
+	target := &Target{Node: &profiles.NodeInfo{Name: "node-1", Profiles: []*profiles.ProfileInfo{{
+		ProfileType: profiles.ProfileTypeOC,
+		Interfaces: map[iface.Name]*profiles.InterfaceInfo{
+			"ens2f1": {Name: "ens2f1", ClockType: profiles.ClockTypeClient},
+			"ens2f0": {Name: "ens2f0", ClockType: profiles.ClockTypeClient},
+			"ens3f0": {Name: "ens3f0", ClockType: profiles.ClockTypeServer},
+		},
+	}}}}
+
+	ifaceName, err := ClientInterface(profiles.ProfileTypeOC)(target)
+	assert.NoError(t, err)
+	assert.Equal(t, iface.Name("ens2f0"), ifaceName)
+
+	_, err = ClientInterface(profiles.ProfileTypeGM)(target)
+	assert.Error(t, err)
+}
+
+func TestResultString(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	result := &Result{Scenario: "gnss loss", Node: "node-1", Steps: []StepResult{
+		{Kind: StepKindInject, Name: "gnss loss", Start: start, End: start.Add(2 * time.Second)},
+		{Kind: StepKindExpect, Name: "holdover", Start: start.Add(2 * time.Second), End: start.Add(time.Minute),
+			Error: "timed out"},
+		{Kind: StepKindRollback, Name: "gnss loss", Start: start.Add(time.Minute), End: start.Add(61 * time.Second)},
+	}}
+
+	assert.Equal(t, "scenario gnss loss on node node-1\n"+
+		"  +0s inject gnss loss (2s)\n"+
+		"  +2s expect holdover (58s): timed out\n"+
+		"  +1m0s rollback gnss loss (1s)\n", result.String())
+}
+
+// recorder records the calls made to the faults and expectations it creates.
+type recorder struct {
+	calls          []string
+	rollbackErrors map[string]error
+	onInject       func()
+}
+
+func (recorder *recorder) fault(name string, injectErr error) Fault {
+	return NewFault(name, func(context.Context, *Target) (Rollback, error) {
+		recorder.calls = append(recorder.calls, "inject "+name)
+
+		if recorder.onInject != nil {
+			recorder.onInject()
+		}
+
+		if injectErr != nil {
+			return nil, injectErr
+		}
+
+		return func(context.Context) error {
+			recorder.calls = append(recorder.calls, "rollback "+name)
+
+			return recorder.rollbackErrors[name]
+		}, nil
+	})
+}
+
+func (recorder *recorder) expectation(name string, checkErr error) Expectation {
+	return NewExpectation(name, func(context.Context, *Target, time.Time) error {
+		recorder.calls = append(recorder.calls, "check "+name)
+
+		return checkErr
+	})
+}
+
+func stepNames(result *Result) []string {
+	var names []string
+
+	for _, step := range result.Steps {
+		names = append(names, fmt.Sprintf("%s %s", step.Kind, step.Name))
+	}
+
+	return names
+}
@@ -0,0 +1,27 @@
+//go:build unit_test
+
+package sma
+
+import (
+	"testing"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/replay"
+)
+
+func TestReplayDpllPinShow(t *testing.T) {
+	replay.Run(t, "testdata/dpll-pin-show", func(recording []byte) (any, error) {
+		// The pin state has unexported fields, so it is converted to a form which marshals to JSON.
+		parents := []map[string]string{}
+
+		for _, parent := range parseDpllPinShow(string(recording)).parents {
+			parents = append(parents, map[string]string{
+				"id":        parent.id,
+				"direction": parent.direction,
+				"prio":      parent.prio,
+				"state":     parent.state,
+			})
+		}
+
+		return parents, nil
+	})
+}
@@ -3,12 +3,14 @@ package stability
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"regexp"
 	"strings"
 
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/processes"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/logstream"
 	"k8s.io/klog/v2"
 )
 
@@ -161,24 +163,20 @@ func AnalyzeFromFile(filePath string, thresholdAbsoluteNanoseconds int64) (Analy
 	}
 	defer file.Close()
 
-	if thresholdAbsoluteNanoseconds <= 0 {
-		thresholdAbsoluteNanoseconds = DefaultOffsetThresholdAbsoluteNanoseconds
+	result, err := AnalyzeReader(file, thresholdAbsoluteNanoseconds)
+	if err != nil {
+		return AnalysisResult{}, fmt.Errorf("error reading log file %s: %w", filePath, err)
 	}
 
-	result := AnalysisResult{
-		PTP4L: ProcessResult{
-			name:      string(processes.Ptp4l),
-			pattern:   ptp4lPattern,
-			threshold: thresholdAbsoluteNanoseconds,
-		},
-		PHC2SYS: ProcessResult{
-			name:      string(processes.Phc2sys),
-			pattern:   phc2sysPattern,
-			threshold: thresholdAbsoluteNanoseconds,
-		},
-	}
+	return result, nil
+}
 
-	scanner := bufio.NewScanner(file)
+// AnalyzeReader performs a single-pass streaming analysis of the daemon log lines read from reader, such as recorded
+// logs replayed in unit tests.
+func AnalyzeReader(reader io.Reader, thresholdAbsoluteNanoseconds int64) (AnalysisResult, error) {
+	result := newAnalysisResult(thresholdAbsoluteNanoseconds)
+
+	scanner := bufio.NewScanner(reader)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
 	for scanner.Scan() {
@@ -186,7 +184,7 @@ func AnalyzeFromFile(filePath string, thresholdAbsoluteNanoseconds int64) (Analy
 	}
 
 	if err := scanner.Err(); err != nil {
-		return AnalysisResult{}, fmt.Errorf("error reading log file %s: %w", filePath, err)
+		return AnalysisResult{}, err
 	}
 
 	result.finalize()
@@ -194,6 +192,42 @@ func AnalyzeFromFile(filePath string, thresholdAbsoluteNanoseconds int64) (Analy
 	return result, nil
 }
 
+// AnalyzeStream performs a single-pass analysis of the daemon log lines received by subscription, such as one to a
+// daemon log stream from the daemonlogs package, until the stream stops. Subscribing lets the analysis run on the same
+// stream as other consumers, such as log waits, without fetching the logs again.
+func AnalyzeStream(subscription *logstream.Subscription, thresholdAbsoluteNanoseconds int64) AnalysisResult {
+	result := newAnalysisResult(thresholdAbsoluteNanoseconds)
+
+	for line := range subscription.Lines {
+		result.processLine(line.Text)
+	}
+
+	result.finalize()
+
+	return result
+}
+
+// newAnalysisResult returns an empty analysis result using thresholdAbsoluteNanoseconds for both processes, or the
+// default threshold if it is not positive.
+func newAnalysisResult(thresholdAbsoluteNanoseconds int64) AnalysisResult {
+	if thresholdAbsoluteNanoseconds <= 0 {
+		thresholdAbsoluteNanoseconds = DefaultOffsetThresholdAbsoluteNanoseconds
+	}
+
+	return AnalysisResult{
+		PTP4L: ProcessResult{
+			name:      string(processes.Ptp4l),
+			pattern:   ptp4lPattern,
+			threshold: thresholdAbsoluteNanoseconds,
+		},
+		PHC2SYS: ProcessResult{
+			name:      string(processes.Phc2sys),
+			pattern:   phc2sysPattern,
+			threshold: thresholdAbsoluteNanoseconds,
+		},
+	}
+}
+
 // processLine parses and accumulates a single log line.
 func (a *AnalysisResult) processLine(line string) {
 	if containsFaulty(line) {
@@ -0,0 +1,125 @@
+package stability
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/processes"
+)
+
+// Limits are the bounds every window of the checked processes must be within. Zero bounds are not checked.
+//
+// The predefined limits follow the ITU-T G.8273.2 noise generation limits for telecom boundary and slave clocks:
+// max|TE| bounds the absolute offset, while the MTIE and TDEV limits of dTE_L are constant over the 1 s to 1000 s
+// observation intervals and so apply to every computed interval. They are checked against the offsets the daemons log
+// rather than against a time error measured by external equipment, so they are an approximation meant to catch
+// regressions.
+type Limits struct {
+	// Name identifies the limits in violations.
+	Name string
+	// MaxAbsOffset is the maximum absolute offset in nanoseconds.
+	MaxAbsOffset int64
+	// MaxMTIE is the maximum MTIE in nanoseconds at every computed interval.
+	MaxMTIE float64
+	// MaxTDEV is the maximum TDEV in nanoseconds at every computed interval.
+	MaxTDEV float64
+	// MinInterval and MaxInterval bound the intervals MTIE and TDEV are checked at. A zero MaxInterval means no
+	// upper bound.
+	MinInterval, MaxInterval time.Duration
+	// Processes are the names of the processes whose windows are checked. All processes are checked if it is empty.
+	Processes []string
+}
+
+// The G.8273.2 limits of each clock class. They only apply to the processes reporting the time error of the PTP clock:
+// ptp4l for boundary and slave clocks and ts2phc for grandmasters.
+var (
+	ClassALimits = newClassLimits("G.8273.2 class A", 100, 40, 4)
+	ClassBLimits = newClassLimits("G.8273.2 class B", 70, 40, 4)
+	ClassCLimits = newClassLimits("G.8273.2 class C", 30, 10, 2)
+)
+
+// LimitsForClass returns the predefined limits of the G.8273.2 clock class A, B, or C, ignoring case.
+func LimitsForClass(class string) (Limits, error) {
+	switch strings.ToUpper(class) {
+	case "A":
+		return ClassALimits, nil
+	case "B":
+		return ClassBLimits, nil
+	case "C":
+		return ClassCLimits, nil
+	default:
+		return Limits{}, fmt.Errorf("unknown G.8273.2 clock class %q: expected A, B, or C", class)
+	}
+}
+
+// Check returns an error describing every window of the report outside of limits, or nil if all windows are within
+// them. Windows without locked samples are not checked.
+func (report *WindowReport) Check(limits Limits) error {
+	var violations []error
+
+	for _, window := range report.Windows {
+		if window.LockedSamples == 0 {
+			continue
+		}
+
+		if len(limits.Processes) > 0 && !slices.Contains(limits.Processes, window.Process) {
+			continue
+		}
+
+		violations = append(violations, limits.checkWindow(window)...)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	return fmt.Errorf("%d violations of %s limits: %w", len(violations), limits.Name, errors.Join(violations...))
+}
+
+// checkWindow returns one error per bound of limits that window is outside of.
+func (limits Limits) checkWindow(window WindowStats) []error {
+	var violations []error
+
+	prefix := fmt.Sprintf("%s window at %s", window.Process, window.Start.Format(time.RFC3339))
+
+	if limits.MaxAbsOffset > 0 && window.MaxAbs > limits.MaxAbsOffset {
+		violations = append(violations,
+			fmt.Errorf("%s: max|TE| %d ns exceeds %d ns", prefix, window.MaxAbs, limits.MaxAbsOffset))
+	}
+
+	for _, value := range window.MTIE {
+		if limits.MaxMTIE > 0 && limits.appliesAt(value.Interval) && value.Nanoseconds > limits.MaxMTIE {
+			violations = append(violations, fmt.Errorf("%s: MTIE(%s) %.1f ns exceeds %.1f ns",
+				prefix, value.Interval, value.Nanoseconds, limits.MaxMTIE))
+		}
+	}
+
+	for _, value := range window.TDEV {
+		if limits.MaxTDEV > 0 && limits.appliesAt(value.Interval) && value.Nanoseconds > limits.MaxTDEV {
+			violations = append(violations, fmt.Errorf("%s: TDEV(%s) %.2f ns exceeds %.1f ns",
+				prefix, value.Interval, value.Nanoseconds, limits.MaxTDEV))
+		}
+	}
+
+	return violations
+}
+
+// appliesAt reports whether the MTIE and TDEV bounds apply at interval.
+func (limits Limits) appliesAt(interval time.Duration) bool {
+	return interval >= limits.MinInterval && (limits.MaxInterval == 0 || interval <= limits.MaxInterval)
+}
+
+func newClassLimits(name string, maxAbsOffset int64, maxMTIE, maxTDEV float64) Limits {
+	return Limits{
+		Name:         name,
+		MaxAbsOffset: maxAbsOffset,
+		MaxMTIE:      maxMTIE,
+		MaxTDEV:      maxTDEV,
+		MinInterval:  time.Second,
+		MaxInterval:  1000 * time.Second,
+		Processes:    []string{string(processes.Ptp4l), string(processes.Ts2phc)},
+	}
+}
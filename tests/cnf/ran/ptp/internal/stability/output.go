@@ -0,0 +1,258 @@
+package stability
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// csvStates are the servo states whose time in state is written as CSV columns.
+var csvStates = []string{"s0", "s1", "s2", "s3"}
+
+// WriteCSV writes one row per window of the report to writer, with a header row. MTIE and TDEV have a column per
+// interval of the report, empty when the window is too short for the interval.
+func (report *WindowReport) WriteCSV(writer io.Writer) error {
+	csvWriter := csv.NewWriter(writer)
+
+	header := []string{
+		"process", "start", "end", "samples", "locked_samples", "transitions", "min_ns", "max_ns", "mean_ns",
+		"stddev_ns", "p50_abs_ns", "p95_abs_ns", "p99_abs_ns", "max_abs_ns",
+	}
+
+	for _, interval := range report.Intervals {
+		header = append(header, "mtie_"+interval.String()+"_ns")
+	}
+
+	for _, interval := range report.Intervals {
+		header = append(header, "tdev_"+interval.String()+"_ns")
+	}
+
+	for _, state := range csvStates {
+		header = append(header, "time_in_"+state+"_s")
+	}
+
+	if err := csvWriter.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV header: %w", err)
+	}
+
+	for _, window := range report.Windows {
+		if err := csvWriter.Write(report.csvRow(window)); err != nil {
+			return fmt.Errorf("failed to write CSV row: %w", err)
+		}
+	}
+
+	csvWriter.Flush()
+
+	return csvWriter.Error()
+}
+
+// csvRow returns the CSV columns of window.
+func (report *WindowReport) csvRow(window WindowStats) []string {
+	row := []string{
+		window.Process,
+		window.Start.Format(time.RFC3339Nano),
+		window.End.Format(time.RFC3339Nano),
+		strconv.Itoa(window.Samples),
+		strconv.Itoa(window.LockedSamples),
+		strconv.Itoa(window.Transitions),
+		strconv.FormatInt(window.Min, 10),
+		strconv.FormatInt(window.Max, 10),
+		strconv.FormatFloat(window.Mean, 'f', 3, 64),
+		strconv.FormatFloat(window.StdDev, 'f', 3, 64),
+		strconv.FormatInt(window.P50Abs, 10),
+		strconv.FormatInt(window.P95Abs, 10),
+		strconv.FormatInt(window.P99Abs, 10),
+		strconv.FormatInt(window.MaxAbs, 10),
+	}
+
+	row = append(row, intervalColumns(report.Intervals, window.MTIE)...)
+	row = append(row, intervalColumns(report.Intervals, window.TDEV)...)
+
+	for _, state := range csvStates {
+		row = append(row, strconv.FormatFloat(window.TimeInState[state].Seconds(), 'f', 3, 64))
+	}
+
+	return row
+}
+
+// intervalColumns returns a column per interval with the matching value, or an empty column if there is none.
+func intervalColumns(intervals []time.Duration, values []IntervalValue) []string {
+	columns := make([]string, 0, len(intervals))
+
+	for _, interval := range intervals {
+		index := slices.IndexFunc(values, func(value IntervalValue) bool { return value.Interval == interval })
+		if index < 0 {
+			columns = append(columns, "")
+
+			continue
+		}
+
+		columns = append(columns, strconv.FormatFloat(values[index].Nanoseconds, 'f', 3, 64))
+	}
+
+	return columns
+}
+
+const (
+	chartWidth  = 800
+	chartHeight = 300
+)
+
+// chartColors are the colors of the chart series, reused in order when there are more series than colors.
+var chartColors = []string{"#1f77b4", "#d62728", "#2ca02c", "#ff7f0e", "#9467bd", "#8c564b"}
+
+// htmlReport is the data of the HTML report template.
+type htmlReport struct {
+	Title   string
+	Window  time.Duration
+	Width   int
+	Height  int
+	MaxNs   int64
+	Series  []chartSeries
+	Windows []WindowStats
+}
+
+// chartSeries is a line of the chart: one statistic of one process across windows.
+type chartSeries struct {
+	Label  string
+	Color  string
+	Dashed bool
+	Points string
+}
+
+// htmlTemplate renders an htmlReport.
+//
+//go:embed report.html.tmpl
+var htmlTemplateText string
+
+var htmlTemplate = template.Must(template.New("report").Parse(htmlTemplateText))
+
+// WriteHTML writes a self-contained HTML report with title to writer, charting the p99 and maximum absolute offsets of
+// every process across windows and listing the statistics of every window.
+func (report *WindowReport) WriteHTML(writer io.Writer, title string) error {
+	data := htmlReport{
+		Title:   title,
+		Window:  report.Window,
+		Width:   chartWidth,
+		Height:  chartHeight,
+		MaxNs:   1,
+		Windows: report.Windows,
+	}
+
+	var starts []time.Time
+
+	for _, window := range report.Windows {
+		data.MaxNs = max(data.MaxNs, window.MaxAbs)
+
+		if !slices.ContainsFunc(starts, window.Start.Equal) {
+			starts = append(starts, window.Start)
+		}
+	}
+
+	var processNames []string
+
+	for _, window := range report.Windows {
+		if !slices.Contains(processNames, window.Process) {
+			processNames = append(processNames, window.Process)
+		}
+	}
+
+	for index, process := range processNames {
+		color := chartColors[index%len(chartColors)]
+
+		data.Series = append(data.Series,
+			chartSeries{
+				Label:  process + " max",
+				Color:  color,
+				Points: report.points(process, starts, data.MaxNs, func(window WindowStats) int64 { return window.MaxAbs }),
+			},
+			chartSeries{
+				Label:  process + " p99",
+				Color:  color,
+				Dashed: true,
+				Points: report.points(process, starts, data.MaxNs, func(window WindowStats) int64 { return window.P99Abs }),
+			})
+	}
+
+	if err := htmlTemplate.Execute(writer, data); err != nil {
+		return fmt.Errorf("failed to write HTML report: %w", err)
+	}
+
+	return nil
+}
+
+// points returns the SVG points of the statistic returned by value for the windows of process with locked samples,
+// placing each window by the index of its start in starts and scaling values to maxNs.
+func (report *WindowReport) points(
+	process string, starts []time.Time, maxNs int64, value func(WindowStats) int64) string {
+	var points []string
+
+	for _, window := range report.Windows {
+		if window.Process != process || window.LockedSamples == 0 {
+			continue
+		}
+
+		x := chartWidth / 2
+		if len(starts) > 1 {
+			x = slices.IndexFunc(starts, window.Start.Equal) * chartWidth / (len(starts) - 1)
+		}
+
+		y := chartHeight - int(value(window)*chartHeight/maxNs)
+
+		points = append(points, fmt.Sprintf("%d,%d", x, y))
+	}
+
+	return strings.Join(points, " ")
+}
+
+// WriteFiles writes the CSV and HTML reports to dir as name.csv and name.html, creating dir if needed, and returns
+// their paths so they can be attached to the spec report.
+func (report *WindowReport) WriteFiles(dir, name string) (string, string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create report directory %s: %w", dir, err)
+	}
+
+	csvPath := filepath.Join(dir, name+".csv")
+	htmlPath := filepath.Join(dir, name+".html")
+
+	err := writeFile(csvPath, report.WriteCSV)
+	if err != nil {
+		return "", "", err
+	}
+
+	err = writeFile(htmlPath, func(writer io.Writer) error { return report.WriteHTML(writer, name) })
+	if err != nil {
+		return "", "", err
+	}
+
+	return csvPath, htmlPath, nil
+}
+
+// writeFile creates the file at path and writes it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report file %s: %w", path, err)
+	}
+
+	writeErr := write(file)
+	closeErr := file.Close()
+
+	if writeErr != nil {
+		return fmt.Errorf("failed to write report file %s: %w", path, writeErr)
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("failed to close report file %s: %w", path, closeErr)
+	}
+
+	return nil
+}
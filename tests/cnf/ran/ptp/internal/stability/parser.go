@@ -4,6 +4,8 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/processes"
 )
 
 // LogEntry is a parsed delay log line from a synchronization daemon (ptp4l or phc2sys). Each process contains a clock
@@ -25,6 +27,32 @@ var (
 	// phc2sysPattern is a regular expression that matches the phc2sys log lines. For example:
 	//  phc2sys[401304.879]: [ptp4l.1.config:6] CLOCK_REALTIME phc offset        -5 s2 freq  -19334 delay    470
 	phc2sysPattern = regexp.MustCompile(`^phc2sys\[.*?\boffset\s+(?P<offset>-?\d+)\s+(?P<state>s\d+).*delay`)
+	// ts2phcPattern is a regular expression that matches the ts2phc log lines. For example:
+	//  ts2phc[82674.465]: [ts2phc.0.config:6] ens2f0 master offset          1 s2 freq      +1
+	ts2phcPattern = regexp.MustCompile(`^ts2phc\[.*?\boffset\s+(?P<offset>-?\d+)\s+(?P<state>s\d+)`)
+	// chronydPattern is a regular expression that matches chronyd log lines reporting an offset and servo state in
+	// the same format as the other processes.
+	chronydPattern = regexp.MustCompile(`^chronyd\[.*?\boffset\s+(?P<offset>-?\d+)\s+(?P<state>s\d+)`)
+)
+
+// Process is a synchronization process whose offset log lines are analyzed by a WindowAnalyzer.
+type Process struct {
+	// Name is the name of the process used in reports, such as ptp4l.
+	Name string
+	// Pattern matches the offset log lines of the process. It must have the named groups offset, in nanoseconds, and
+	// state, the servo state.
+	Pattern *regexp.Regexp
+}
+
+// Processes whose offset log lines are recognized by default.
+var (
+	PTP4LProcess   = Process{Name: string(processes.Ptp4l), Pattern: ptp4lPattern}
+	PHC2SYSProcess = Process{Name: string(processes.Phc2sys), Pattern: phc2sysPattern}
+	TS2PHCProcess  = Process{Name: string(processes.Ts2phc), Pattern: ts2phcPattern}
+	ChronydProcess = Process{Name: "chronyd", Pattern: chronydPattern}
+
+	// DefaultProcesses are the processes analyzed by a WindowAnalyzer unless others are provided.
+	DefaultProcesses = []Process{PTP4LProcess, PHC2SYSProcess, TS2PHCProcess, ChronydProcess}
 )
 
 // ParseResult holds the outcome of attempting to parse a single log line.
@@ -0,0 +1,41 @@
+//go:build unit_test
+
+package stability
+
+import (
+	"bufio"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/replay"
+)
+
+// replayedDaemonLogs is the golden output of a recorded daemon log: the whole-run analysis and the windowed report.
+type replayedDaemonLogs struct {
+	Analysis AnalysisResult
+	Windows  *WindowReport
+}
+
+func TestReplayDaemonLogs(t *testing.T) {
+	replay.Run(t, "testdata/daemon-logs", func(recording []byte) (any, error) {
+		recording = replay.StripTimestamps(recording)
+
+		analysis, err := AnalyzeReader(bytes.NewReader(recording), DefaultOffsetThresholdAbsoluteNanoseconds)
+		if err != nil {
+			return nil, err
+		}
+
+		analyzer := NewWindowAnalyzer(WindowOptions{
+			Window:    10 * time.Second,
+			Intervals: []time.Duration{time.Second, 2 * time.Second},
+		})
+
+		scanner := bufio.NewScanner(bytes.NewReader(recording))
+		for scanner.Scan() {
+			analyzer.ObserveText(scanner.Text())
+		}
+
+		return replayedDaemonLogs{Analysis: analysis, Windows: analyzer.Report()}, scanner.Err()
+	})
+}
@@ -0,0 +1,149 @@
+package stability
+
+import (
+	"math"
+	"slices"
+	"time"
+)
+
+// sample is a single parsed offset of a process at the time it was logged.
+type sample struct {
+	at     time.Time
+	offset int64
+	state  string
+}
+
+// percentile returns the nearest-rank percentile p, between 0 and 100, of the ascending values. It returns 0 if there
+// are no values.
+func percentile(sorted []int64, p float64) int64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+
+	return sorted[min(max(rank, 1), len(sorted))-1]
+}
+
+// meanStdDev returns the mean and population standard deviation of values. Both are 0 if there are no values.
+func meanStdDev(values []int64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += float64(value)
+	}
+
+	mean := sum / float64(len(values))
+
+	var squares float64
+
+	for _, value := range values {
+		deviation := float64(value) - mean
+		squares += deviation * deviation
+	}
+
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+// mtie returns the maximum time interval error of samples over interval: the largest peak-to-peak offset among the
+// samples within any span of interval. Samples must be in time order. It returns false if the samples span less than
+// interval, since the value would then underestimate the error.
+func mtie(samples []sample, interval time.Duration) (int64, bool) {
+	if len(samples) < 2 || samples[len(samples)-1].at.Sub(samples[0].at) < interval {
+		return 0, false
+	}
+
+	// maxima and minima are monotonic queues of sample indexes, so the extremes of the span ending at the current
+	// sample are at their front.
+	var (
+		maxima, minima []int
+		result         int64
+		start          int
+	)
+
+	for end, current := range samples {
+		for len(maxima) > 0 && samples[maxima[len(maxima)-1]].offset <= current.offset {
+			maxima = maxima[:len(maxima)-1]
+		}
+
+		for len(minima) > 0 && samples[minima[len(minima)-1]].offset >= current.offset {
+			minima = minima[:len(minima)-1]
+		}
+
+		maxima = append(maxima, end)
+		minima = append(minima, end)
+
+		for current.at.Sub(samples[start].at) > interval {
+			start++
+		}
+
+		for maxima[0] < start {
+			maxima = maxima[1:]
+		}
+
+		for minima[0] < start {
+			minima = minima[1:]
+		}
+
+		result = max(result, samples[maxima[0]].offset-samples[minima[0]].offset)
+	}
+
+	return result, true
+}
+
+// tdev returns the time deviation of samples over interval. Samples are treated as evenly spaced by the median spacing
+// between them, which holds for daemons logging their offsets at a fixed rate. It returns false if the samples are too
+// few to contain three adjacent spans of interval.
+func tdev(samples []sample, interval time.Duration) (float64, bool) {
+	spacing := medianSpacing(samples)
+	if spacing <= 0 {
+		return 0, false
+	}
+
+	spans := int(math.Round(float64(interval) / float64(spacing)))
+	if spans < 1 || len(samples) < 3*spans+1 {
+		return 0, false
+	}
+
+	// prefix[i] is the sum of the first i offsets, so the sum of any span is a difference of two prefixes.
+	prefix := make([]float64, len(samples)+1)
+	for index, current := range samples {
+		prefix[index+1] = prefix[index] + float64(current.offset)
+	}
+
+	var (
+		total float64
+		terms int
+	)
+
+	for start := 0; start+3*spans <= len(samples); start++ {
+		first := prefix[start+spans] - prefix[start]
+		second := prefix[start+2*spans] - prefix[start+spans]
+		third := prefix[start+3*spans] - prefix[start+2*spans]
+		secondDifference := (third - 2*second + first) / float64(spans)
+
+		total += secondDifference * secondDifference
+		terms++
+	}
+
+	return math.Sqrt(total / (6 * float64(terms))), true
+}
+
+// medianSpacing returns the median time between adjacent samples, or 0 if there are fewer than two samples.
+func medianSpacing(samples []sample) time.Duration {
+	if len(samples) < 2 {
+		return 0
+	}
+
+	spacings := make([]time.Duration, 0, len(samples)-1)
+	for index := 1; index < len(samples); index++ {
+		spacings = append(spacings, samples[index].at.Sub(samples[index-1].at))
+	}
+
+	slices.Sort(spacings)
+
+	return spacings[len(spacings)/2]
+}
@@ -0,0 +1,333 @@
+package stability
+
+import (
+	"regexp"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/logstream"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// DefaultWindow is the length of the windows statistics are computed over when none is provided.
+	DefaultWindow = time.Minute
+	// lockedState is the servo state of a locked clock. Offset statistics only consider samples in this state.
+	lockedState = "s2"
+)
+
+// DefaultIntervals are the observation intervals MTIE and TDEV are computed at when none are provided. TDEV needs
+// samples spanning three times its interval, so they are kept short enough to fit in the default window.
+var DefaultIntervals = []time.Duration{time.Second, 4 * time.Second, 16 * time.Second}
+
+// uptimePattern matches the uptime in seconds daemons prefix their log lines with, such as ptp4l[401304.873].
+var uptimePattern = regexp.MustCompile(`^\w+\[(\d+(?:\.\d+)?)\]`)
+
+// WindowOptions configures a WindowAnalyzer. Zero values are replaced by their defaults.
+type WindowOptions struct {
+	// Window is the length of the consecutive windows statistics are computed over. Windows are aligned to
+	// multiples of their length. It defaults to DefaultWindow.
+	Window time.Duration
+	// Intervals are the observation intervals MTIE and TDEV are computed at. It defaults to DefaultIntervals.
+	Intervals []time.Duration
+	// Processes are the processes whose offset lines are analyzed. It defaults to DefaultProcesses.
+	Processes []Process
+}
+
+// IntervalValue is a time error metric at an observation interval.
+type IntervalValue struct {
+	// Interval is the observation interval the value was computed at.
+	Interval time.Duration
+	// Nanoseconds is the value of the metric in nanoseconds.
+	Nanoseconds float64
+}
+
+// WindowStats are the statistics of a single process over a single window. Offset statistics and time error metrics
+// are computed over the samples in the locked state, s2, while the sample count, transitions, and time in state
+// consider every sample.
+type WindowStats struct {
+	// Process is the name of the process the statistics are for.
+	Process string
+	// Start is the start of the window.
+	Start time.Time
+	// End is the end of the window, or the time of the last sample if the window is still open.
+	End time.Time
+	// Samples is the number of parsed offsets in the window.
+	Samples int
+	// LockedSamples is the number of parsed offsets in the locked state.
+	LockedSamples int
+	// Transitions is the number of servo state changes in the window.
+	Transitions int
+	// Min and Max are the extreme signed offsets in nanoseconds.
+	Min, Max int64
+	// Mean and StdDev are the mean and population standard deviation of the signed offsets in nanoseconds.
+	Mean, StdDev float64
+	// P50Abs, P95Abs, P99Abs, and MaxAbs are the percentiles of the absolute offsets in nanoseconds.
+	P50Abs, P95Abs, P99Abs, MaxAbs int64
+	// MTIE is the maximum time interval error at each interval the locked samples span.
+	MTIE []IntervalValue
+	// TDEV is the time deviation at each interval with enough locked samples.
+	TDEV []IntervalValue
+	// TimeInState is how long the process spent in each servo state during the window, attributing the time
+	// between two samples to the state of the first one.
+	TimeInState map[string]time.Duration
+}
+
+// WindowReport is the output of a WindowAnalyzer.
+type WindowReport struct {
+	// Window is the length of the windows.
+	Window time.Duration
+	// Intervals are the observation intervals of the time error metrics.
+	Intervals []time.Duration
+	// Windows are the statistics of every process for every window with samples, ordered by start and then by the
+	// order of the processes.
+	Windows []WindowStats
+}
+
+// WindowAnalyzer computes per-window statistics of process offsets while log lines are observed, so results are
+// available as soon as windows end rather than once collection ends.
+type WindowAnalyzer struct {
+	options WindowOptions
+
+	mutex     sync.Mutex
+	current   map[string]*openWindow
+	completed []WindowStats
+}
+
+// openWindow accumulates the samples of a process in the window being observed.
+type openWindow struct {
+	start       time.Time
+	samples     []sample
+	transitions int
+	timeInState map[string]time.Duration
+	previous    *sample
+}
+
+// NewWindowAnalyzer returns an analyzer using options, with zero values replaced by their defaults.
+func NewWindowAnalyzer(options WindowOptions) *WindowAnalyzer {
+	if options.Window <= 0 {
+		options.Window = DefaultWindow
+	}
+
+	if len(options.Intervals) == 0 {
+		options.Intervals = DefaultIntervals
+	}
+
+	if len(options.Processes) == 0 {
+		options.Processes = DefaultProcesses
+	}
+
+	return &WindowAnalyzer{options: options, current: make(map[string]*openWindow)}
+}
+
+// Observe parses text, the text of a single log line logged at the provided time, and adds its offset to the window
+// of its process. Lines which do not match any process are ignored. Lines must be observed in the order they were
+// logged.
+func (analyzer *WindowAnalyzer) Observe(at time.Time, text string) {
+	for _, process := range analyzer.options.Processes {
+		result := tryParseEntry(text, process.Pattern)
+		if !result.Matched {
+			continue
+		}
+
+		if result.Dropped {
+			klog.V(tsparams.LogLevel).Infof("%s: dropping line with unparseable offset %q", process.Name, text)
+
+			return
+		}
+
+		analyzer.mutex.Lock()
+		defer analyzer.mutex.Unlock()
+
+		analyzer.add(process.Name, sample{at: at, offset: result.Entry.Offset, state: result.Entry.State})
+
+		return
+	}
+}
+
+// ObserveLine observes a line from a log stream at the time the kubelet recorded it.
+func (analyzer *WindowAnalyzer) ObserveLine(line logstream.Line) {
+	analyzer.Observe(line.Time, line.Text)
+}
+
+// ObserveText observes a line without a recorded time, such as one read from a collected log file, at the uptime the
+// daemon prefixed it with. Lines without an uptime are ignored.
+func (analyzer *WindowAnalyzer) ObserveText(text string) {
+	match := uptimePattern.FindStringSubmatch(text)
+	if match == nil {
+		return
+	}
+
+	seconds, err := strconv.ParseFloat(match[1], 64)
+	if err != nil {
+		return
+	}
+
+	analyzer.Observe(time.Unix(0, 0).UTC().Add(time.Duration(seconds*float64(time.Second))), text)
+}
+
+// Consume observes every line received by subscription until the stream stops, then returns the report.
+func (analyzer *WindowAnalyzer) Consume(subscription *logstream.Subscription) *WindowReport {
+	for line := range subscription.Lines {
+		analyzer.ObserveLine(line)
+	}
+
+	return analyzer.Report()
+}
+
+// Report returns the statistics of the windows observed so far, including the windows which have not ended yet. It
+// may be called while lines are still being observed.
+func (analyzer *WindowAnalyzer) Report() *WindowReport {
+	analyzer.mutex.Lock()
+	defer analyzer.mutex.Unlock()
+
+	windows := slices.Clone(analyzer.completed)
+
+	for _, process := range analyzer.options.Processes {
+		window, ok := analyzer.current[process.Name]
+		if !ok || len(window.samples) == 0 {
+			continue
+		}
+
+		windows = append(windows, analyzer.stats(process.Name, window, window.samples[len(window.samples)-1].at))
+	}
+
+	order := make(map[string]int, len(analyzer.options.Processes))
+	for index, process := range analyzer.options.Processes {
+		order[process.Name] = index
+	}
+
+	slices.SortStableFunc(windows, func(first, second WindowStats) int {
+		if compared := first.Start.Compare(second.Start); compared != 0 {
+			return compared
+		}
+
+		return order[first.Process] - order[second.Process]
+	})
+
+	return &WindowReport{
+		Window:    analyzer.options.Window,
+		Intervals: slices.Clone(analyzer.options.Intervals),
+		Windows:   windows,
+	}
+}
+
+// add adds current to the open window of process, closing the window first if current is past its end.
+func (analyzer *WindowAnalyzer) add(process string, current sample) {
+	window, ok := analyzer.current[process]
+	if !ok {
+		window = &openWindow{start: current.at.Truncate(analyzer.options.Window)}
+		analyzer.current[process] = window
+	}
+
+	end := window.start.Add(analyzer.options.Window)
+
+	if window.previous != nil {
+		window.addTimeInState(window.previous.state, window.previous.at, earliest(current.at, end))
+	}
+
+	if !current.at.Before(end) {
+		if len(window.samples) > 0 {
+			analyzer.completed = append(analyzer.completed, analyzer.stats(process, window, end))
+		}
+
+		previous := window.previous
+		window = &openWindow{start: current.at.Truncate(analyzer.options.Window), previous: previous}
+		analyzer.current[process] = window
+
+		// The time from the start of the new window to this sample was spent in the state of the last sample.
+		if previous != nil {
+			window.addTimeInState(previous.state, window.start, current.at)
+		}
+	}
+
+	if window.previous != nil && window.previous.state != current.state {
+		window.transitions++
+	}
+
+	window.samples = append(window.samples, current)
+	window.previous = &current
+}
+
+// stats computes the statistics of window for process, ending at end.
+func (analyzer *WindowAnalyzer) stats(process string, window *openWindow, end time.Time) WindowStats {
+	stats := WindowStats{
+		Process:     process,
+		Start:       window.start,
+		End:         end,
+		Samples:     len(window.samples),
+		Transitions: window.transitions,
+		TimeInState: make(map[string]time.Duration, len(window.timeInState)),
+	}
+
+	for state, duration := range window.timeInState {
+		stats.TimeInState[state] = duration
+	}
+
+	var locked []sample
+
+	for _, current := range window.samples {
+		if current.state == lockedState {
+			locked = append(locked, current)
+		}
+	}
+
+	stats.LockedSamples = len(locked)
+	if len(locked) == 0 {
+		return stats
+	}
+
+	offsets := make([]int64, 0, len(locked))
+	absolutes := make([]int64, 0, len(locked))
+
+	for _, current := range locked {
+		offsets = append(offsets, current.offset)
+		absolutes = append(absolutes, abs(current.offset))
+	}
+
+	slices.Sort(absolutes)
+
+	stats.Min, stats.Max = slices.Min(offsets), slices.Max(offsets)
+	stats.Mean, stats.StdDev = meanStdDev(offsets)
+	stats.P50Abs = percentile(absolutes, 50)
+	stats.P95Abs = percentile(absolutes, 95)
+	stats.P99Abs = percentile(absolutes, 99)
+	stats.MaxAbs = absolutes[len(absolutes)-1]
+
+	for _, interval := range analyzer.options.Intervals {
+		if value, ok := mtie(locked, interval); ok {
+			stats.MTIE = append(stats.MTIE, IntervalValue{Interval: interval, Nanoseconds: float64(value)})
+		}
+
+		if value, ok := tdev(locked, interval); ok {
+			stats.TDEV = append(stats.TDEV, IntervalValue{Interval: interval, Nanoseconds: value})
+		}
+	}
+
+	return stats
+}
+
+// addTimeInState adds the time from start to end to state, if end is after start.
+func (window *openWindow) addTimeInState(state string, start, end time.Time) {
+	if !end.After(start) {
+		return
+	}
+
+	if window.timeInState == nil {
+		window.timeInState = make(map[string]time.Duration)
+	}
+
+	window.timeInState[state] += end.Sub(start)
+}
+
+// earliest returns the earlier of two times.
+func earliest(first, second time.Time) time.Time {
+	if second.Before(first) {
+		return second
+	}
+
+	return first
+}
@@ -0,0 +1,225 @@
+//go:build unit_test
+
+package stability
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentile(t *testing.T) {
+	sorted := []int64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+
+	testCases := []struct {
+		name       string
+		values     []int64
+		percentile float64
+		expected   int64
+	}{
+		{name: "median", values: sorted, percentile: 50, expected: 5},
+		{name: "p95", values: sorted, percentile: 95, expected: 10},
+		{name: "zero", values: sorted, percentile: 0, expected: 1},
+		{name: "empty", values: nil, percentile: 99, expected: 0},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, percentile(testCase.values, testCase.percentile))
+		})
+	}
+
+	mean, stdDev := meanStdDev([]int64{2, 4, 4, 4, 5, 5, 7, 9})
+	assert.Equal(t, 5.0, mean)
+	assert.Equal(t, 2.0, stdDev)
+}
+
+func TestMTIE(t *testing.T) {
+	samples := samplesEverySecond(0, 5, -3, 2, 10)
+
+	testCases := []struct {
+		name       string
+		interval   time.Duration
+		expected   int64
+		expectedOk bool
+	}{
+		{name: "adjacent samples", interval: time.Second, expected: 8, expectedOk: true},
+		{name: "three samples", interval: 2 * time.Second, expected: 13, expectedOk: true},
+		{name: "longer than samples", interval: 10 * time.Second, expectedOk: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, ok := mtie(samples, testCase.interval)
+
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.Equal(t, testCase.expected, value)
+		})
+	}
+}
+
+func TestTDEV(t *testing.T) {
+	testCases := []struct {
+		name       string
+		samples    []sample
+		interval   time.Duration
+		expected   float64
+		expectedOk bool
+	}{
+		{
+			name:       "constant offset",
+			samples:    samplesEverySecond(7, 7, 7, 7, 7, 7, 7),
+			interval:   time.Second,
+			expectedOk: true,
+		},
+		{
+			name:       "linear drift",
+			samples:    samplesEverySecond(1, 2, 3, 4, 5, 6, 7),
+			interval:   2 * time.Second,
+			expectedOk: true,
+		},
+		{
+			// Every second difference is 12 ns, so TDEV is sqrt(144 / 6).
+			name:       "alternating offset",
+			samples:    samplesEverySecond(3, -3, 3, -3, 3, -3, 3),
+			interval:   time.Second,
+			expected:   4.899,
+			expectedOk: true,
+		},
+		{
+			name:       "too few samples",
+			samples:    samplesEverySecond(1, 2, 3, 4, 5, 6, 7),
+			interval:   3 * time.Second,
+			expectedOk: false,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			value, ok := tdev(testCase.samples, testCase.interval)
+
+			assert.Equal(t, testCase.expectedOk, ok)
+			assert.InDelta(t, testCase.expected, value, 0.001)
+		})
+	}
+}
+
+func TestWindowAnalyzer(t *testing.T) {
+	analyzer := NewWindowAnalyzer(WindowOptions{Window: time.Minute, Intervals: []time.Duration{time.Second}})
+	start := time.Date(2024, 1, 1, 10, 0, 56, 0, time.UTC)
+
+	lines := []string{
+		"ptp4l[100.0]: [ptp4l.0.config:6] master offset        -50 s1 freq  -94379 path delay       161",
+		"ptp4l[101.0]: [ptp4l.0.config:6] master offset         -4 s2 freq  -94379 path delay       161",
+		"ptp4l[102.0]: [ptp4l.0.config:6] master offset          6 s2 freq  -94379 path delay       161",
+		"ptp4l[103.0]: [ptp4l.0.config:6] master offset         -2 s2 freq  -94379 path delay       161",
+		"ptp4l[104.0]: [ptp4l.0.config:6] master offset        120 s2 freq  -94379 path delay       161",
+		"ptp4l[105.0]: [ptp4l.0.config:6] master offset          3 s2 freq  -94379 path delay       161",
+		"ts2phc[105.5]: [ts2phc.0.config:6] ens2f0 master offset          1 s2 freq      +1",
+		"ptp4l[106.0]: [ptp4l.0.config:6] port 1: announce timeout",
+	}
+
+	for index, line := range lines {
+		analyzer.Observe(start.Add(time.Duration(index)*time.Second), line)
+	}
+
+	report := analyzer.Report()
+	assert.Len(t, report.Windows, 3)
+
+	first, second, ts2phc := report.Windows[0], report.Windows[1], report.Windows[2]
+
+	assert.Equal(t, "ptp4l", first.Process)
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC), first.Start)
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 1, 0, 0, time.UTC), first.End)
+	assert.Equal(t, 4, first.Samples)
+	assert.Equal(t, 3, first.LockedSamples)
+	assert.Equal(t, 1, first.Transitions)
+	assert.Equal(t, int64(-4), first.Min)
+	assert.Equal(t, int64(6), first.MaxAbs)
+	assert.Equal(t, map[string]time.Duration{"s1": time.Second, "s2": 3 * time.Second}, first.TimeInState)
+	assert.Equal(t, []IntervalValue{{Interval: time.Second, Nanoseconds: 10}}, first.MTIE)
+
+	assert.Equal(t, "ptp4l", second.Process)
+	assert.Equal(t, 2, second.Samples)
+	assert.Equal(t, int64(120), second.MaxAbs)
+	assert.Equal(t, map[string]time.Duration{"s2": time.Second}, second.TimeInState)
+
+	assert.Equal(t, "ts2phc", ts2phc.Process)
+	assert.Equal(t, 1, ts2phc.Samples)
+
+	err := report.Check(ClassALimits)
+	assert.ErrorContains(t, err, "2 violations of G.8273.2 class A limits")
+	assert.ErrorContains(t, err, "max|TE| 120 ns exceeds 100 ns")
+	assert.ErrorContains(t, err, "MTIE(1s) 117.0 ns exceeds 40.0 ns")
+
+	_, err = LimitsForClass("b")
+	assert.NoError(t, err)
+
+	_, err = LimitsForClass("D")
+	assert.Error(t, err)
+}
+
+func TestObserveText(t *testing.T) {
+	analyzer := NewWindowAnalyzer(WindowOptions{})
+
+	analyzer.ObserveText("phc2sys[401304.879]: [ptp4l.1.config:6] CLOCK_REALTIME phc offset -5 s2 freq -19334 delay 470")
+	analyzer.ObserveText("chronyd: no uptime prefix offset 3 s2")
+
+	report := analyzer.Report()
+	assert.Len(t, report.Windows, 1)
+	assert.Equal(t, "phc2sys", report.Windows[0].Process)
+	assert.WithinDuration(t, time.Unix(401304, 879000000), report.Windows[0].End, time.Microsecond)
+}
+
+func TestWriteReports(t *testing.T) {
+	analyzer := NewWindowAnalyzer(WindowOptions{})
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	for index, offset := range []int{3, -2, 4, 1, -1} {
+		analyzer.Observe(start.Add(time.Duration(index)*time.Second), fmt.Sprintf(
+			"ptp4l[%d.0]: [ptp4l.0.config:6] master offset %d s2 freq -94379 path delay 161", index, offset))
+	}
+
+	report := analyzer.Report()
+
+	var csvOutput bytes.Buffer
+
+	err := report.WriteCSV(&csvOutput)
+	assert.NoError(t, err)
+
+	records, err := csv.NewReader(&csvOutput).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, 2)
+	assert.Contains(t, records[0], "mtie_4s_ns")
+	assert.Contains(t, records[0], "time_in_s2_s")
+	assert.Equal(t, "ptp4l", records[1][0])
+
+	var htmlOutput strings.Builder
+
+	err = report.WriteHTML(&htmlOutput, "ptp_stability_windows_node")
+	assert.NoError(t, err)
+	assert.Contains(t, htmlOutput.String(), "<title>ptp_stability_windows_node</title>")
+	assert.Contains(t, htmlOutput.String(), `points="400,0"`)
+
+	csvPath, htmlPath, err := report.WriteFiles(t.TempDir(), "report")
+	assert.NoError(t, err)
+	assert.FileExists(t, csvPath)
+	assert.FileExists(t, htmlPath)
+}
+
+// samplesEverySecond returns locked samples of the offsets one second apart.
+func samplesEverySecond(offsets ...int64) []sample {
+	start := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+	samples := make([]sample, 0, len(offsets))
+
+	for index, offset := range offsets {
+		samples = append(samples, sample{at: start.Add(time.Duration(index) * time.Second), offset: offset, state: "s2"})
+	}
+
+	return samples
+}
@@ -0,0 +1,121 @@
+package topology
+
+import (
+	"context"
+	"fmt"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	ptpv1 "github.com/rh-ecosystem-edge/eco-goinfra/pkg/schemes/ptp/v1"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/metrics"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"k8s.io/klog/v2"
+)
+
+type discoverOptions struct {
+	prometheusAPI      prometheusv1.API
+	skipPortIdentities bool
+}
+
+// DiscoverOption configures how the topology is discovered.
+type DiscoverOption func(*discoverOptions)
+
+// WithPrometheusAPI sets the Prometheus API used to add the current clock class of each clock. Clock classes are not
+// added by default. A nil API is logged and ignored.
+func WithPrometheusAPI(prometheusAPI prometheusv1.API) DiscoverOption {
+	return func(options *discoverOptions) {
+		if prometheusAPI == nil {
+			klog.V(tsparams.LogLevel).Info("Prometheus API is nil, topology will not have clock classes")
+
+			return
+		}
+
+		options.prometheusAPI = prometheusAPI
+	}
+}
+
+// WithoutPortIdentities skips looking up port identities with pmc. The topology then only has T-BC and HA links, which
+// is enough for specs only needing the roles, plugins, and upstream ports of the clocks.
+func WithoutPortIdentities() DiscoverOption {
+	return func(options *discoverOptions) {
+		options.skipPortIdentities = true
+	}
+}
+
+// Discover builds the topology of the PTP clocks on the cluster. It gets the node information map, sets port identities
+// on every node, and pulls the PTP profile of every profile.
+func Discover(client *clients.Settings, options ...DiscoverOption) (*Topology, error) {
+	discoverOptions := discoverOptions{}
+
+	for _, option := range options {
+		option(&discoverOptions)
+	}
+
+	nodeInfoMap, err := profiles.GetNodeInfoMap(client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get node info map: %w", err)
+	}
+
+	if !discoverOptions.skipPortIdentities {
+		for nodeName, nodeInfo := range nodeInfoMap {
+			err := nodeInfo.SetPortIdentitiesAndLink(client)
+			if err != nil {
+				return nil, fmt.Errorf("failed to set port identities on node %s: %w", nodeName, err)
+			}
+		}
+	}
+
+	topology := Build(nodeInfoMap, func(profileInfo *profiles.ProfileInfo) (*ptpv1.PtpProfile, error) {
+		return profileInfo.PullProfile(client)
+	})
+
+	if discoverOptions.prometheusAPI != nil {
+		err = topology.SetClockClasses(context.TODO(), discoverOptions.prometheusAPI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to set clock classes: %w", err)
+		}
+	}
+
+	return topology, nil
+}
+
+// SetClockClasses sets the clock class of every clock with a config index to the current value of the ptp4l clock class
+// metric for its config. Clocks without a matching sample keep their previous clock class.
+func (topology *Topology) SetClockClasses(ctx context.Context, prometheusAPI prometheusv1.API) error {
+	query := metrics.ClockClassQuery{Process: metrics.Equals(metrics.ProcessPTP4L)}
+
+	result, err := metrics.ExecuteQuery(ctx, prometheusAPI, query)
+	if err != nil {
+		return fmt.Errorf("failed to execute clock class query: %w", err)
+	}
+
+	topology.setClockClassesFromSamples(result)
+
+	return nil
+}
+
+// setClockClassesFromSamples sets the clock classes from samples of the clock class metric, matching samples to clocks
+// by their node and ptp4l config.
+func (topology *Topology) setClockClassesFromSamples(samples model.Vector) {
+	for _, clock := range topology.Clocks {
+		if clock.ConfigIndex == nil {
+			continue
+		}
+
+		config := fmt.Sprintf("ptp4l.%d.config", *clock.ConfigIndex)
+
+		for _, sample := range samples {
+			if string(sample.Metric[model.LabelName(metrics.KeyNode)]) != clock.Node ||
+				string(sample.Metric[model.LabelName(metrics.KeyConfig)]) != config {
+				continue
+			}
+
+			clockClass := metrics.PtpClockClass(sample.Value)
+			clock.ClockClass = &clockClass
+
+			break
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package topology
+
+import (
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	. "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/raninittools"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/internal/ranparam"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
+	"k8s.io/klog/v2"
+)
+
+// dumpFileName is the name of the DOT and JSON files the topology is dumped to.
+const dumpFileName = "ptp_topology"
+
+// DumpIfFailed discovers the PTP topology and writes it as DOT and JSON files into the failure-dump bundle of the spec
+// if the test has failed, so they are listed in its manifest and published with it. If port identities cannot be
+// looked up, such as when ptp4l is not running after the failure, the topology is dumped without them.
+func DumpIfFailed(report types.SpecReport, testSuite string, client *clients.Settings) {
+	if !report.State.Is(types.SpecStateFailureStates) {
+		return
+	}
+
+	if client == nil {
+		klog.V(ranparam.LogLevel).Info("Client is nil, skipping PTP topology dump")
+
+		return
+	}
+
+	if RANConfig.GetDumpFailedTestReportLocation(testSuite) == "" {
+		klog.V(ranparam.LogLevel).Info("No dump directory configured, skipping PTP topology dump")
+
+		return
+	}
+
+	topology, err := Discover(client)
+	if err != nil {
+		klog.V(ranparam.LogLevel).Infof("Failed to discover PTP topology, retrying without port identities: %v", err)
+
+		topology, err = Discover(client, WithoutPortIdentities())
+		if err != nil {
+			klog.V(ranparam.LogLevel).Infof("Failed to discover PTP topology: %v", err)
+
+			return
+		}
+	}
+
+	bundle, err := reporter.OpenBundle(RANConfig.GeneralConfig, testSuite, report)
+	if err != nil {
+		klog.V(ranparam.LogLevel).Infof("Failed to open failure-dump bundle for PTP topology dump: %v", err)
+
+		return
+	}
+
+	_, _, err = topology.WriteFiles(bundle.Dir(), dumpFileName)
+	if err != nil {
+		klog.V(ranparam.LogLevel).Infof("Failed to write PTP topology dump: %v", err)
+	}
+
+	err = bundle.Close()
+	if err != nil {
+		klog.V(ranparam.LogLevel).Infof("Failed to close failure-dump bundle %s: %v", bundle.Path(), err)
+
+		return
+	}
+
+	klog.V(ranparam.LogLevel).Infof("PTP topology dumped to bundle %s", bundle.Path())
+}
@@ -0,0 +1,195 @@
+package topology
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// Upstream returns the clocks the clock with the provided ID receives time from over PTP and T-BC links, sorted by ID.
+// HA links are not followed, since the HA profile selects between its managed profiles rather than following them.
+func (topology *Topology) Upstream(id string) []*Clock {
+	return topology.neighbors(id, func(link Link) string {
+		if link.To == id {
+			return link.From
+		}
+
+		return ""
+	})
+}
+
+// Downstream returns the clocks receiving time from the clock with the provided ID over PTP and T-BC links, sorted by
+// ID.
+func (topology *Topology) Downstream(id string) []*Clock {
+	return topology.neighbors(id, func(link Link) string {
+		if link.From == id {
+			return link.To
+		}
+
+		return ""
+	})
+}
+
+// Chains returns every path of clocks from a clock without upstream clocks to a clock without downstream clocks, over
+// PTP and T-BC links. Clocks without any such links, such as HA profiles, form chains of their own. Chains are sorted
+// by the IDs of their clocks.
+func (topology *Topology) Chains() [][]*Clock {
+	var chains [][]*Clock
+
+	for _, clock := range topology.Clocks {
+		if len(topology.Upstream(clock.ID)) == 0 {
+			chains = append(chains, topology.chainsFrom([]*Clock{clock})...)
+		}
+	}
+
+	slices.SortFunc(chains, func(a, b []*Clock) int {
+		return strings.Compare(formatChain(a), formatChain(b))
+	})
+
+	return chains
+}
+
+// HasChain reports whether any chain has clocks with the provided roles in order, directly linked to each other. Both
+// sides of a T-BC pair count as one T-BC clock, so a chain of a grandmaster, T-BC, and T-TSC is matched by RoleGM,
+// RoleTBC, and RoleTTSC.
+func (topology *Topology) HasChain(roles ...Role) bool {
+	for _, chain := range topology.Chains() {
+		chainRoles := topology.chainRoles(chain)
+
+		for start := 0; start+len(roles) <= len(chainRoles); start++ {
+			if slices.Equal(chainRoles[start:start+len(roles)], roles) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Expected is the topology a spec expects the cluster to have. Zero values are not checked.
+type Expected struct {
+	// MinClocks is the minimum number of clocks of each role.
+	MinClocks map[Role]int
+	// Chains are sequences of roles which must each be matched by [Topology.HasChain].
+	Chains [][]Role
+}
+
+// Check returns an error describing every way the topology differs from expected, or nil if it matches.
+func (topology *Topology) Check(expected Expected) error {
+	var mismatches []error
+
+	for _, role := range slices.Sorted(maps.Keys(expected.MinClocks)) {
+		count := len(topology.ClocksByRole(role))
+		if count < expected.MinClocks[role] {
+			mismatches = append(mismatches,
+				fmt.Errorf("expected at least %d %s clocks but found %d", expected.MinClocks[role], role, count))
+		}
+	}
+
+	for _, roles := range expected.Chains {
+		if !topology.HasChain(roles...) {
+			mismatches = append(mismatches, fmt.Errorf("expected a %s chain", formatRoles(roles)))
+		}
+	}
+
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	chains := make([]string, 0, len(topology.Chains()))
+	for _, chain := range topology.Chains() {
+		chains = append(chains, formatChain(chain))
+	}
+
+	return fmt.Errorf("topology does not match expected topology with chains [%s]: %w",
+		strings.Join(chains, "; "), errors.Join(mismatches...))
+}
+
+// neighbors returns the clocks selected by neighbor from the PTP and T-BC links, sorted by ID. The neighbor function
+// returns the ID of the neighbor across a link, or an empty string if the link does not connect to id.
+func (topology *Topology) neighbors(id string, neighbor func(Link) string) []*Clock {
+	var clocks []*Clock
+
+	for _, link := range topology.Links {
+		if link.Kind == LinkKindHA {
+			continue
+		}
+
+		neighborID := neighbor(link)
+		if neighborID == "" || neighborID == id {
+			continue
+		}
+
+		clock := topology.Clock(neighborID)
+		if clock != nil && !slices.Contains(clocks, clock) {
+			clocks = append(clocks, clock)
+		}
+	}
+
+	slices.SortFunc(clocks, func(a, b *Clock) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	return clocks
+}
+
+// chainsFrom returns the chains continuing path to every clock without downstream clocks. Clocks already on the path
+// are not revisited, so loops end the chain.
+func (topology *Topology) chainsFrom(path []*Clock) [][]*Clock {
+	var chains [][]*Clock
+
+	for _, next := range topology.Downstream(path[len(path)-1].ID) {
+		if slices.Contains(path, next) {
+			continue
+		}
+
+		chains = append(chains, topology.chainsFrom(append(slices.Clone(path), next))...)
+	}
+
+	if len(chains) == 0 {
+		return [][]*Clock{path}
+	}
+
+	return chains
+}
+
+// chainRoles returns the roles of the clocks in chain, skipping T-BC transmitters following their receiver.
+func (topology *Topology) chainRoles(chain []*Clock) []Role {
+	var roles []Role
+
+	for index, clock := range chain {
+		if index > 0 && topology.hasLink(chain[index-1].ID, clock.ID, LinkKindTBC) {
+			continue
+		}
+
+		roles = append(roles, clock.Role)
+	}
+
+	return roles
+}
+
+func (topology *Topology) hasLink(from, to string, kind LinkKind) bool {
+	return slices.ContainsFunc(topology.Links, func(link Link) bool {
+		return link.From == from && link.To == to && link.Kind == kind
+	})
+}
+
+func formatChain(chain []*Clock) string {
+	clocks := make([]string, 0, len(chain))
+	for _, clock := range chain {
+		clocks = append(clocks, fmt.Sprintf("%s(%s)", clock.Role, clock.ID))
+	}
+
+	return strings.Join(clocks, " → ")
+}
+
+func formatRoles(roles []Role) string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, string(role))
+	}
+
+	return strings.Join(names, " → ")
+}
@@ -0,0 +1,164 @@
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// WriteJSON writes the topology to writer as indented JSON.
+func (topology *Topology) WriteJSON(writer io.Writer) error {
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+
+	if err := encoder.Encode(topology); err != nil {
+		return fmt.Errorf("failed to encode topology: %w", err)
+	}
+
+	return nil
+}
+
+// WriteDOT writes the topology to writer as a Graphviz digraph named name. Clocks on the same node are grouped in a
+// cluster subgraph, external clocks are drawn as ellipses, and T-BC and HA links are dotted and dashed respectively.
+func (topology *Topology) WriteDOT(writer io.Writer, name string) error {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "digraph %q {\n", name)
+	builder.WriteString("  rankdir=TB;\n  node [shape=box];\n")
+
+	var nodeName string
+
+	for _, clock := range topology.Clocks {
+		if clock.Node != nodeName {
+			if nodeName != "" {
+				builder.WriteString("  }\n")
+			}
+
+			nodeName = clock.Node
+
+			if nodeName != "" {
+				fmt.Fprintf(&builder, "  subgraph %q {\n    label=%q;\n", "cluster_"+nodeName, nodeName)
+			}
+		}
+
+		indent := "  "
+		if nodeName != "" {
+			indent = "    "
+		}
+
+		if clock.Role == RoleExternal {
+			fmt.Fprintf(&builder, "%s%q [shape=ellipse, label=%q];\n", indent, clock.ID, "external\n"+clock.ClockIdentity)
+
+			continue
+		}
+
+		fmt.Fprintf(&builder, "%s%q [label=%q];\n", indent, clock.ID, clock.dotLabel())
+	}
+
+	if nodeName != "" {
+		builder.WriteString("  }\n")
+	}
+
+	for _, link := range topology.Links {
+		fmt.Fprintf(&builder, "  %q -> %q [%s];\n", link.From, link.To, link.dotAttributes())
+	}
+
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(writer, builder.String())
+
+	return err
+}
+
+// WriteFiles writes the topology as DOT and JSON files named name in dir, creating dir if needed. It returns the paths
+// of the DOT and JSON files so they can be attached to the report.
+func (topology *Topology) WriteFiles(dir, name string) (string, string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", "", fmt.Errorf("failed to create topology directory %s: %w", dir, err)
+	}
+
+	dotPath := filepath.Join(dir, name+".dot")
+	jsonPath := filepath.Join(dir, name+".json")
+
+	err := writeFile(dotPath, func(writer io.Writer) error { return topology.WriteDOT(writer, name) })
+	if err != nil {
+		return "", "", err
+	}
+
+	err = writeFile(jsonPath, topology.WriteJSON)
+	if err != nil {
+		return "", "", err
+	}
+
+	return dotPath, jsonPath, nil
+}
+
+// dotLabel returns the lines describing the clock in DOT output: its profile, role and clock class, plugins, and
+// enabled pins.
+func (clock *Clock) dotLabel() string {
+	lines := []string{clock.Profile}
+
+	roleLine := string(clock.Role)
+	if clock.ClockClass != nil {
+		roleLine += fmt.Sprintf(" class %d", *clock.ClockClass)
+	}
+
+	lines = append(lines, roleLine)
+
+	if clock.UpstreamPort != "" {
+		lines = append(lines, "upstream "+string(clock.UpstreamPort))
+	}
+
+	if len(clock.Plugins) > 0 {
+		lines = append(lines, strings.Join(clock.Plugins, ", "))
+	}
+
+	for _, pin := range clock.Pins {
+		if pin.State != "disabled" {
+			lines = append(lines, fmt.Sprintf("%s %s %s", pin.Interface, pin.Name, pin.State))
+		}
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// dotAttributes returns the DOT edge attributes of the link.
+func (link Link) dotAttributes() string {
+	switch link.Kind {
+	case LinkKindTBC:
+		return `style=dotted, label="tbc"`
+	case LinkKindHA:
+		return `style=dashed, label="ha"`
+	default:
+		label := string(link.ToPort)
+		if link.FromPort != "" {
+			label = string(link.FromPort) + " → " + label
+		}
+
+		return fmt.Sprintf("label=%q", label)
+	}
+}
+
+// writeFile creates the file at path and writes it with write.
+func writeFile(path string, write func(io.Writer) error) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create topology file %s: %w", path, err)
+	}
+
+	writeErr := write(file)
+	closeErr := file.Close()
+
+	if writeErr != nil {
+		return fmt.Errorf("failed to write topology file %s: %w", path, writeErr)
+	}
+
+	if closeErr != nil {
+		return fmt.Errorf("failed to close topology file %s: %w", path, closeErr)
+	}
+
+	return nil
+}
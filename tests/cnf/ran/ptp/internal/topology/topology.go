@@ -0,0 +1,479 @@
+// Package topology builds a cluster-wide graph of the PTP clocks on a cluster. Each PTP profile recommended to a node
+// is a clock in the graph, and links follow the flow of time between them: from the clock owning a parent port
+// identity to the client ports following it, from T-BC receivers to their transmitters, and from the profiles managed
+// by an HA profile to the HA profile. Upstream clocks outside of the cluster, such as switches, are added as external
+// clocks so GM → BC → OC chains stay connected.
+//
+// The graph can be exported as Graphviz DOT and JSON for reports, and queried by specs that need clocks of a given role
+// instead of hard-coded interface lists.
+package topology
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/ptp"
+	ptpv1 "github.com/rh-ecosystem-edge/eco-goinfra/pkg/schemes/ptp/v1"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/iface"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/metrics"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
+	"k8s.io/klog/v2"
+)
+
+// Role is the role of a clock in the topology. It groups the profile types by the part they play in the chain.
+type Role string
+
+const (
+	// RoleGM is a grandmaster clock, including multi-NIC and NTP fallback grandmasters.
+	RoleGM Role = "GM"
+	// RoleBC is a boundary clock with client and server interfaces in one profile.
+	RoleBC Role = "BC"
+	// RoleTBC is either side of a Telecom Boundary Clock pair.
+	RoleTBC Role = "T-BC"
+	// RoleTTSC is a Telecom Time Slave Clock.
+	RoleTTSC Role = "T-TSC"
+	// RoleOC is an ordinary clock, including two port ordinary clocks.
+	RoleOC Role = "OC"
+	// RoleHA is a profile managing other profiles in a highly available configuration.
+	RoleHA Role = "HA"
+	// RoleExternal is an upstream clock outside of the cluster, known only by its clock identity.
+	RoleExternal Role = "external"
+)
+
+// RoleForProfileType returns the role of clocks for profiles of profileType.
+func RoleForProfileType(profileType profiles.PtpProfileType) Role {
+	switch profileType {
+	case profiles.ProfileTypeGM, profiles.ProfileTypeMultiNICGM, profiles.ProfileTypeNTPFallback:
+		return RoleGM
+	case profiles.ProfileTypeBC:
+		return RoleBC
+	case profiles.ProfileTypeTBCTransmitter, profiles.ProfileTypeTBCReceiver:
+		return RoleTBC
+	case profiles.ProfileTypeTTSC:
+		return RoleTTSC
+	case profiles.ProfileTypeHA:
+		return RoleHA
+	default:
+		return RoleOC
+	}
+}
+
+// PortRole is the role of an interface in its profile.
+type PortRole string
+
+const (
+	// PortRoleClient is an interface following an upstream clock.
+	PortRoleClient PortRole = "client"
+	// PortRoleServer is an interface serving time to downstream clocks.
+	PortRoleServer PortRole = "server"
+)
+
+// LinkKind describes how time flows across a link.
+type LinkKind string
+
+const (
+	// LinkKindPTP links a port to the client port following it, matched by port identities.
+	LinkKindPTP LinkKind = "ptp"
+	// LinkKindTBC links a T-BC receiver to the transmitter it controls.
+	LinkKindTBC LinkKind = "tbc"
+	// LinkKindHA links a profile to the HA profile managing it.
+	LinkKindHA LinkKind = "ha"
+)
+
+// Port is an interface of a clock.
+type Port struct {
+	Interface          iface.Name `json:"interface"`
+	Role               PortRole   `json:"role"`
+	PortIdentity       string     `json:"portIdentity,omitempty"`
+	ParentPortIdentity string     `json:"parentPortIdentity,omitempty"`
+}
+
+// Pin is a pin configured by an Intel plugin, such as an SMA or U.FL connector.
+type Pin struct {
+	Interface iface.Name `json:"interface"`
+	Name      string     `json:"name"`
+	// State is disabled, rx, or tx.
+	State   string `json:"state"`
+	Channel string `json:"channel,omitempty"`
+}
+
+// Clock is a PTP clock in the topology. Clocks on the cluster correspond to a profile on a node, while external clocks
+// only have an ID, role, and clock identity.
+type Clock struct {
+	// ID uniquely identifies the clock. It is node/profile for profiles and external/clockIdentity for external
+	// clocks.
+	ID          string                  `json:"id"`
+	Node        string                  `json:"node,omitempty"`
+	Profile     string                  `json:"profile,omitempty"`
+	Role        Role                    `json:"role"`
+	ProfileType profiles.PtpProfileType `json:"-"`
+	// ClockIdentity is the clock identity shared by the port identities of the clock, if they are known.
+	ClockIdentity string                 `json:"clockIdentity,omitempty"`
+	ClockClass    *metrics.PtpClockClass `json:"clockClass,omitempty"`
+	ConfigIndex   *uint                  `json:"configIndex,omitempty"`
+	Ports         []Port                 `json:"ports,omitempty"`
+	// UpstreamPort is the upstream port set in the profile settings or E810 plugin, if any.
+	UpstreamPort iface.Name `json:"upstreamPort,omitempty"`
+	Plugins      []string   `json:"plugins,omitempty"`
+	Pins         []Pin      `json:"pins,omitempty"`
+	// ControllingProfile is the name of the T-BC receiver profile controlling a T-BC transmitter.
+	ControllingProfile string `json:"controllingProfile,omitempty"`
+	// HAProfiles are the names of the profiles managed by an HA profile.
+	HAProfiles []string `json:"haProfiles,omitempty"`
+	// Info is the profile information the clock was built from. It is nil for external clocks.
+	Info *profiles.ProfileInfo `json:"-"`
+}
+
+// PortsByRole returns the ports of the clock with the provided role.
+func (clock *Clock) PortsByRole(role PortRole) []Port {
+	var ports []Port
+
+	for _, port := range clock.Ports {
+		if port.Role == role {
+			ports = append(ports, port)
+		}
+	}
+
+	return ports
+}
+
+// Link is a directed link between two clocks in the direction time flows.
+type Link struct {
+	From string   `json:"from"`
+	To   string   `json:"to"`
+	Kind LinkKind `json:"kind"`
+	// FromPort and ToPort are the interfaces on either end of PTP links. They are empty for other kinds of links and
+	// FromPort is empty for links from external clocks.
+	FromPort iface.Name `json:"fromPort,omitempty"`
+	ToPort   iface.Name `json:"toPort,omitempty"`
+}
+
+// Topology is the graph of PTP clocks on a cluster. Clocks are sorted by ID and links by their endpoints.
+type Topology struct {
+	Clocks []*Clock `json:"clocks"`
+	Links  []Link   `json:"links"`
+}
+
+// ProfileLookup returns the PTP profile of a profile on the cluster. It is used to add plugins, pins, upstream ports,
+// and HA profiles to clocks.
+type ProfileLookup func(profileInfo *profiles.ProfileInfo) (*ptpv1.PtpProfile, error)
+
+// Build builds the topology from the node information of every PTP node. Port identities should already be set, such as
+// by [profiles.NodeInfo.SetPortIdentitiesAndLink], otherwise only T-BC and HA links can be found. If lookup is not
+// nil, it is used to add details from the PTP profiles; failed lookups are logged and skipped.
+func Build(nodeInfoMap map[string]*profiles.NodeInfo, lookup ProfileLookup) *Topology {
+	topology := &Topology{}
+
+	for nodeName, nodeInfo := range nodeInfoMap {
+		for _, profileInfo := range nodeInfo.Profiles {
+			clock := newClock(nodeName, profileInfo)
+
+			if lookup != nil {
+				ptpProfile, err := lookup(profileInfo)
+				if err != nil {
+					klog.V(tsparams.LogLevel).Infof("Failed to look up profile %s on node %s for topology: %v",
+						profileInfo.Reference.ProfileName, nodeName, err)
+				} else {
+					clock.setProfileDetails(ptpProfile)
+				}
+			}
+
+			topology.Clocks = append(topology.Clocks, clock)
+		}
+	}
+
+	topology.linkPorts()
+	topology.linkProfiles()
+	topology.sortClocks()
+
+	slices.SortFunc(topology.Links, func(a, b Link) int {
+		return strings.Compare(a.From+"\x00"+a.To+"\x00"+string(a.ToPort), b.From+"\x00"+b.To+"\x00"+string(b.ToPort))
+	})
+
+	return topology
+}
+
+// Clock returns the clock with the provided ID, or nil if there is none.
+func (topology *Topology) Clock(id string) *Clock {
+	for _, clock := range topology.Clocks {
+		if clock.ID == id {
+			return clock
+		}
+	}
+
+	return nil
+}
+
+// ClockForProfile returns the clock of the profile on the node, or nil if there is none.
+func (topology *Topology) ClockForProfile(nodeName, profileName string) *Clock {
+	return topology.Clock(clockID(nodeName, profileName))
+}
+
+// ClocksByRole returns the clocks with any of the provided roles, sorted by ID.
+func (topology *Topology) ClocksByRole(roles ...Role) []*Clock {
+	var clocks []*Clock
+
+	for _, clock := range topology.Clocks {
+		if slices.Contains(roles, clock.Role) {
+			clocks = append(clocks, clock)
+		}
+	}
+
+	return clocks
+}
+
+// ClocksByProfileType returns the clocks for profiles of any of the provided profile types, sorted by ID.
+func (topology *Topology) ClocksByProfileType(profileTypes ...profiles.PtpProfileType) []*Clock {
+	var clocks []*Clock
+
+	for _, clock := range topology.Clocks {
+		if clock.Role != RoleExternal && slices.Contains(profileTypes, clock.ProfileType) {
+			clocks = append(clocks, clock)
+		}
+	}
+
+	return clocks
+}
+
+// String returns the topology as JSON, so it can be logged or added to reports as is.
+func (topology *Topology) String() string {
+	marshalled, err := json.Marshal(topology)
+	if err != nil {
+		return fmt.Sprintf("failed to marshal topology: %v", err)
+	}
+
+	return string(marshalled)
+}
+
+// linkPorts adds a PTP link to every client port whose parent port identity belongs to another clock. Parents outside
+// of the cluster are added as external clocks, while parents which are the clock itself, as for grandmasters, are
+// skipped.
+func (topology *Topology) linkPorts() {
+	type owner struct {
+		clock *Clock
+		port  iface.Name
+	}
+
+	portOwners := make(map[string]owner)
+
+	for _, clock := range topology.Clocks {
+		for _, port := range clock.Ports {
+			if port.PortIdentity != "" {
+				portOwners[port.PortIdentity] = owner{clock: clock, port: port.Interface}
+			}
+		}
+	}
+
+	for _, clock := range slices.Clone(topology.Clocks) {
+		for _, port := range clock.PortsByRole(PortRoleClient) {
+			if port.PortIdentity == "" || port.ParentPortIdentity == "" {
+				continue
+			}
+
+			parentIdentity := clockIdentity(port.ParentPortIdentity)
+			if parentIdentity == clock.ClockIdentity {
+				continue
+			}
+
+			link := Link{To: clock.ID, ToPort: port.Interface, Kind: LinkKindPTP}
+
+			if parent, found := portOwners[port.ParentPortIdentity]; found {
+				link.From = parent.clock.ID
+				link.FromPort = parent.port
+			} else {
+				link.From = topology.externalClock(parentIdentity).ID
+			}
+
+			topology.Links = append(topology.Links, link)
+		}
+	}
+}
+
+// linkProfiles adds the links from T-BC receivers to their transmitters and from managed profiles to HA profiles. Both
+// are on the same node as the profile referencing them.
+func (topology *Topology) linkProfiles() {
+	for _, clock := range topology.Clocks {
+		if clock.ControllingProfile != "" {
+			if receiver := topology.ClockForProfile(clock.Node, clock.ControllingProfile); receiver != nil {
+				topology.Links = append(topology.Links, Link{From: receiver.ID, To: clock.ID, Kind: LinkKindTBC})
+			}
+		}
+
+		for _, haProfile := range clock.HAProfiles {
+			if managed := topology.ClockForProfile(clock.Node, haProfile); managed != nil {
+				topology.Links = append(topology.Links, Link{From: managed.ID, To: clock.ID, Kind: LinkKindHA})
+			}
+		}
+	}
+}
+
+// externalClock returns the external clock with the provided clock identity, adding it if it does not exist yet.
+func (topology *Topology) externalClock(identity string) *Clock {
+	id := "external/" + identity
+
+	if clock := topology.Clock(id); clock != nil {
+		return clock
+	}
+
+	clock := &Clock{ID: id, Role: RoleExternal, ClockIdentity: identity}
+	topology.Clocks = append(topology.Clocks, clock)
+
+	return clock
+}
+
+func (topology *Topology) sortClocks() {
+	slices.SortFunc(topology.Clocks, func(a, b *Clock) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+// newClock returns the clock for profileInfo on the node, with the details available without the PTP profile.
+func newClock(nodeName string, profileInfo *profiles.ProfileInfo) *Clock {
+	clock := &Clock{
+		ID:          clockID(nodeName, profileInfo.Reference.ProfileName),
+		Node:        nodeName,
+		Profile:     profileInfo.Reference.ProfileName,
+		Role:        RoleForProfileType(profileInfo.ProfileType),
+		ProfileType: profileInfo.ProfileType,
+		ConfigIndex: profileInfo.ConfigIndex,
+		Info:        profileInfo,
+	}
+
+	for _, interfaceInfo := range profileInfo.Interfaces {
+		port := Port{
+			Interface:          interfaceInfo.Name,
+			Role:               PortRoleClient,
+			PortIdentity:       interfaceInfo.PortIdentity,
+			ParentPortIdentity: interfaceInfo.ParentPortIdentity,
+		}
+
+		if interfaceInfo.ClockType == profiles.ClockTypeServer {
+			port.Role = PortRoleServer
+		}
+
+		clock.Ports = append(clock.Ports, port)
+	}
+
+	slices.SortFunc(clock.Ports, func(a, b Port) int {
+		return strings.Compare(string(a.Interface), string(b.Interface))
+	})
+
+	for _, port := range clock.Ports {
+		if port.PortIdentity != "" {
+			clock.ClockIdentity = clockIdentity(port.PortIdentity)
+
+			break
+		}
+	}
+
+	return clock
+}
+
+// setProfileDetails sets the plugins, pins, upstream port, controlling profile, and HA profiles of the clock from its
+// PTP profile.
+func (clock *Clock) setProfileDetails(ptpProfile *ptpv1.PtpProfile) {
+	pluginTypes, err := profiles.GetPluginTypesFromProfile(ptpProfile)
+	if err == nil {
+		for _, pluginType := range pluginTypes {
+			clock.Plugins = append(clock.Plugins, string(pluginType))
+		}
+
+		slices.Sort(clock.Plugins)
+	}
+
+	clock.Pins = getPins(ptpProfile)
+
+	upstreamPort, err := profiles.GetUpstreamPortForProfile(ptpProfile)
+	if err == nil {
+		clock.UpstreamPort = upstreamPort
+	}
+
+	if ptpProfile.PtpSettings == nil {
+		return
+	}
+
+	clock.ControllingProfile = ptpProfile.PtpSettings["controllingProfile"]
+
+	if ptpProfile.PtpSettings["haProfiles"] != "" {
+		for _, name := range strings.Split(ptpProfile.PtpSettings["haProfiles"], ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				clock.HAProfiles = append(clock.HAProfiles, name)
+			}
+		}
+	}
+}
+
+// getPins returns the pins of the E810 and E825 plugins in the profile, sorted by interface and pin name. Plugins that
+// cannot be unmarshalled are skipped.
+func getPins(ptpProfile *ptpv1.PtpProfile) []Pin {
+	var pins []Pin
+
+	for _, pluginType := range []ptp.PluginType{ptp.PluginTypeE810, ptp.PluginTypeE825} {
+		plugin, found := ptpProfile.Plugins[string(pluginType)]
+		if !found || plugin == nil {
+			continue
+		}
+
+		intelPlugin := ptp.IntelPlugin{}
+
+		err := json.Unmarshal(plugin.Raw, &intelPlugin)
+		if err != nil {
+			klog.V(tsparams.LogLevel).Infof("Failed to unmarshal %s plugin for topology: %v", pluginType, err)
+
+			continue
+		}
+
+		for ifaceName, pinValues := range intelPlugin.Pins {
+			for pinName, value := range pinValues {
+				pins = append(pins, newPin(iface.Name(ifaceName), pinName, value))
+			}
+		}
+	}
+
+	slices.SortFunc(pins, func(a, b Pin) int {
+		return strings.Compare(string(a.Interface)+"\x00"+a.Name, string(b.Interface)+"\x00"+b.Name)
+	})
+
+	return pins
+}
+
+// newPin parses the "state channel" value of a plugin pin.
+func newPin(ifaceName iface.Name, name, value string) Pin {
+	pin := Pin{Interface: ifaceName, Name: name, State: value}
+	fields := strings.Fields(value)
+
+	if len(fields) > 0 {
+		switch fields[0] {
+		case fmt.Sprint(int(profiles.PinStateDisabled)):
+			pin.State = "disabled"
+		case fmt.Sprint(int(profiles.PinStateRx)):
+			pin.State = "rx"
+		case fmt.Sprint(int(profiles.PinStateTx)):
+			pin.State = "tx"
+		}
+	}
+
+	if len(fields) > 1 {
+		pin.Channel = fields[1]
+	}
+
+	return pin
+}
+
+func clockID(nodeName, profileName string) string {
+	return nodeName + "/" + profileName
+}
+
+// clockIdentity returns the clock identity of a port identity of the form "clockIdentity-portNumber", such as
+// "507c6f.fffe.5c4c82" for "507c6f.fffe.5c4c82-1".
+func clockIdentity(portIdentity string) string {
+	lastDash := strings.LastIndex(portIdentity, "-")
+	if lastDash == -1 {
+		return portIdentity
+	}
+
+	return portIdentity[:lastDash]
+}
@@ -0,0 +1,258 @@
+//go:build unit_test
+
+package topology
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/common/model"
+	ptpv1 "github.com/rh-ecosystem-edge/eco-goinfra/pkg/schemes/ptp/v1"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/iface"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/metrics"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
+	"github.com/stretchr/testify/assert"
+	apiextv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/utils/ptr"
+)
+
+func TestBuild(t *testing.T) {
+	topology := buildTestTopology()
+
+	clockIDs := make([]string, 0, len(topology.Clocks))
+	for _, clock := range topology.Clocks {
+		clockIDs = append(clockIDs, clock.ID)
+	}
+
+	assert.Equal(t, []string{
+		"external/dddddd.fffe.000009", "gm-node/gm", "oc-node/ha", "oc-node/oc", "oc-node/tsc", "tbc-node/tbc-rx",
+		"tbc-node/tbc-tx",
+	}, clockIDs)
+
+	assert.Equal(t, []Link{
+		{From: "external/dddddd.fffe.000009", To: "oc-node/oc", Kind: LinkKindPTP, ToPort: "ens4f0"},
+		{From: "gm-node/gm", To: "tbc-node/tbc-rx", Kind: LinkKindPTP, FromPort: "ens1f0", ToPort: "ens2f0"},
+		{From: "oc-node/tsc", To: "oc-node/ha", Kind: LinkKindHA},
+		{From: "tbc-node/tbc-rx", To: "tbc-node/tbc-tx", Kind: LinkKindTBC},
+		{From: "tbc-node/tbc-tx", To: "oc-node/tsc", Kind: LinkKindPTP, FromPort: "ens2f1", ToPort: "ens3f0"},
+	}, topology.Links)
+
+	gmClock := topology.ClockForProfile("gm-node", "gm")
+	assert.Equal(t, RoleGM, gmClock.Role)
+	assert.Equal(t, "aaaaaa.fffe.000001", gmClock.ClockIdentity)
+	assert.Equal(t, []string{"e810"}, gmClock.Plugins)
+	assert.Equal(t, []Pin{
+		{Interface: "ens1f0", Name: "SMA1", State: "tx", Channel: "1"},
+		{Interface: "ens1f0", Name: "U.FL2", State: "disabled", Channel: "2"},
+	}, gmClock.Pins)
+	assert.Equal(t, iface.Name("ens1f0"), gmClock.UpstreamPort)
+
+	assert.Equal(t, []string{"tsc", "missing"}, topology.ClockForProfile("oc-node", "ha").HAProfiles)
+	assert.Len(t, topology.ClocksByProfileType(profiles.ProfileTypeTBCReceiver), 1)
+	assert.Len(t, topology.ClocksByRole(RoleTBC, RoleTTSC), 3)
+}
+
+func TestChains(t *testing.T) {
+	topology := buildTestTopology()
+
+	assert.Equal(t, []string{"tbc-node/tbc-tx"}, clockIDsOf(topology.Upstream("oc-node/tsc")))
+	assert.Equal(t, []string{"oc-node/tsc"}, clockIDsOf(topology.Downstream("tbc-node/tbc-tx")))
+	assert.Empty(t, topology.Upstream("oc-node/ha"))
+
+	chains := make([]string, 0, len(topology.Chains()))
+	for _, chain := range topology.Chains() {
+		chains = append(chains, formatChain(chain))
+	}
+
+	assert.Equal(t, []string{
+		"GM(gm-node/gm) → T-BC(tbc-node/tbc-rx) → T-BC(tbc-node/tbc-tx) → T-TSC(oc-node/tsc)",
+		"HA(oc-node/ha)",
+		"external(external/dddddd.fffe.000009) → OC(oc-node/oc)",
+	}, chains)
+
+	testCases := []struct {
+		name     string
+		roles    []Role
+		expected bool
+	}{
+		{name: "full chain", roles: []Role{RoleGM, RoleTBC, RoleTTSC}, expected: true},
+		{name: "partial chain", roles: []Role{RoleTBC, RoleTTSC}, expected: true},
+		{name: "external upstream", roles: []Role{RoleExternal, RoleOC}, expected: true},
+		{name: "pair counted twice", roles: []Role{RoleGM, RoleTBC, RoleTBC}, expected: false},
+		{name: "missing role", roles: []Role{RoleGM, RoleBC}, expected: false},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, topology.HasChain(testCase.roles...))
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	topology := buildTestTopology()
+
+	err := topology.Check(Expected{
+		MinClocks: map[Role]int{RoleGM: 1, RoleTBC: 2},
+		Chains:    [][]Role{{RoleGM, RoleTBC, RoleTTSC}},
+	})
+	assert.NoError(t, err)
+
+	err = topology.Check(Expected{
+		MinClocks: map[Role]int{RoleBC: 1},
+		Chains:    [][]Role{{RoleGM, RoleBC, RoleOC}},
+	})
+	assert.ErrorContains(t, err, "expected at least 1 BC clocks but found 0")
+	assert.ErrorContains(t, err, "expected a GM → BC → OC chain")
+	assert.ErrorContains(t, err, "GM(gm-node/gm) → T-BC(tbc-node/tbc-rx)")
+}
+
+func TestSetClockClassesFromSamples(t *testing.T) {
+	topology := buildTestTopology()
+
+	topology.setClockClassesFromSamples(model.Vector{
+		clockClassSample("gm-node", "ptp4l.0.config", 6),
+		clockClassSample("oc-node", "ptp4l.0.config", 248),
+		clockClassSample("oc-node", "ptp4l.1.config", 255),
+	})
+
+	assert.Equal(t, ptr.To(metrics.ClockClass6), topology.ClockForProfile("gm-node", "gm").ClockClass)
+	assert.Equal(t, ptr.To(metrics.ClockClass248), topology.ClockForProfile("oc-node", "tsc").ClockClass)
+	assert.Nil(t, topology.ClockForProfile("tbc-node", "tbc-rx").ClockClass)
+}
+
+func TestWriteOutputs(t *testing.T) {
+	topology := buildTestTopology()
+	topology.ClockForProfile("gm-node", "gm").ClockClass = ptr.To(metrics.ClockClass6)
+
+	var dotOutput strings.Builder
+
+	err := topology.WriteDOT(&dotOutput, "ptp_topology")
+	assert.NoError(t, err)
+	assert.Contains(t, dotOutput.String(), `digraph "ptp_topology" {`)
+	assert.Contains(t, dotOutput.String(), `subgraph "cluster_gm-node" {`)
+	assert.Contains(t, dotOutput.String(), `"gm-node/gm" [label="gm\nGM class 6\nupstream ens1f0\ne810\nens1f0 SMA1 tx"];`)
+	assert.Contains(t, dotOutput.String(), `"external/dddddd.fffe.000009" [shape=ellipse`)
+	assert.Contains(t, dotOutput.String(), `"gm-node/gm" -> "tbc-node/tbc-rx" [label="ens1f0 → ens2f0"];`)
+	assert.Contains(t, dotOutput.String(), `"tbc-node/tbc-rx" -> "tbc-node/tbc-tx" [style=dotted, label="tbc"];`)
+
+	var jsonOutput strings.Builder
+
+	err = topology.WriteJSON(&jsonOutput)
+	assert.NoError(t, err)
+	assert.Contains(t, jsonOutput.String(), `"clockClass": 6`)
+	assert.Contains(t, jsonOutput.String(), `"controllingProfile": "tbc-rx"`)
+
+	dotPath, jsonPath, err := topology.WriteFiles(t.TempDir(), "ptp_topology")
+	assert.NoError(t, err)
+	assert.FileExists(t, dotPath)
+	assert.FileExists(t, jsonPath)
+}
+
+func TestNewPin(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    string
+		expected Pin
+	}{
+		{name: "disabled", value: "0 1", expected: Pin{Interface: "ens1f0", Name: "SMA1", State: "disabled", Channel: "1"}},
+		{name: "rx", value: "1 2", expected: Pin{Interface: "ens1f0", Name: "SMA1", State: "rx", Channel: "2"}},
+		{name: "tx", value: "2 1", expected: Pin{Interface: "ens1f0", Name: "SMA1", State: "tx", Channel: "1"}},
+		{name: "unknown state", value: "7", expected: Pin{Interface: "ens1f0", Name: "SMA1", State: "7"}},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, newPin("ens1f0", "SMA1", testCase.value))
+		})
+	}
+}
+
+// buildTestTopology builds a topology of a grandmaster feeding a T-BC pair feeding a T-TSC managed by an HA profile,
+// and an ordinary clock following a switch outside of the cluster.
+func buildTestTopology() *Topology {
+	gmProfile := newTestProfileInfo("gm", profiles.ProfileTypeGM, 0,
+		newTestInterface("ens1f0", profiles.ClockTypeServer, "aaaaaa.fffe.000001-1", "aaaaaa.fffe.000001-0"))
+	tbcReceiver := newTestProfileInfo("tbc-rx", profiles.ProfileTypeTBCReceiver, 0,
+		newTestInterface("ens2f0", profiles.ClockTypeClient, "bbbbbb.fffe.000002-1", "aaaaaa.fffe.000001-1"))
+	tbcTransmitter := newTestProfileInfo("tbc-tx", profiles.ProfileTypeTBCTransmitter, 1,
+		newTestInterface("ens2f1", profiles.ClockTypeServer, "bbbbbb.fffe.000003-1", "bbbbbb.fffe.000003-0"))
+	tscProfile := newTestProfileInfo("tsc", profiles.ProfileTypeTTSC, 0,
+		newTestInterface("ens3f0", profiles.ClockTypeClient, "cccccc.fffe.000004-1", "bbbbbb.fffe.000003-1"))
+	haProfile := newTestProfileInfo("ha", profiles.ProfileTypeHA, 2)
+	ocProfile := newTestProfileInfo("oc", profiles.ProfileTypeOC, 1,
+		newTestInterface("ens4f0", profiles.ClockTypeClient, "eeeeee.fffe.000005-1", "dddddd.fffe.000009-3"))
+
+	nodeInfoMap := map[string]*profiles.NodeInfo{
+		"gm-node":  {Name: "gm-node", Profiles: []*profiles.ProfileInfo{gmProfile}},
+		"tbc-node": {Name: "tbc-node", Profiles: []*profiles.ProfileInfo{tbcReceiver, tbcTransmitter}},
+		"oc-node":  {Name: "oc-node", Profiles: []*profiles.ProfileInfo{tscProfile, haProfile, ocProfile}},
+	}
+
+	ptpProfiles := map[string]*ptpv1.PtpProfile{
+		"gm": {
+			Plugins: map[string]*apiextv1.JSON{"e810": {Raw: []byte(
+				`{"pins": {"ens1f0": {"SMA1": "2 1", "U.FL2": "0 2"}}, "interconnections": [{"upstreamPort": "ens1f0"}]}`)}},
+		},
+		"tbc-tx": {PtpSettings: map[string]string{"controllingProfile": "tbc-rx"}},
+		"ha":     {PtpSettings: map[string]string{"haProfiles": "tsc, missing"}},
+	}
+
+	return Build(nodeInfoMap, func(profileInfo *profiles.ProfileInfo) (*ptpv1.PtpProfile, error) {
+		ptpProfile, found := ptpProfiles[profileInfo.Reference.ProfileName]
+		if !found {
+			return nil, fmt.Errorf("profile %s not found", profileInfo.Reference.ProfileName)
+		}
+
+		return ptpProfile, nil
+	})
+}
+
+func newTestProfileInfo(
+	name string, profileType profiles.PtpProfileType, configIndex uint, interfaces ...*profiles.InterfaceInfo,
+) *profiles.ProfileInfo {
+	profileInfo := &profiles.ProfileInfo{
+		ProfileType: profileType,
+		Reference:   profiles.ProfileReference{ProfileName: name},
+		Interfaces:  map[iface.Name]*profiles.InterfaceInfo{},
+		ConfigIndex: ptr.To(configIndex),
+	}
+
+	for _, interfaceInfo := range interfaces {
+		interfaceInfo.Profile = profileInfo
+		profileInfo.Interfaces[interfaceInfo.Name] = interfaceInfo
+	}
+
+	return profileInfo
+}
+
+func newTestInterface(
+	name iface.Name, clockType profiles.PtpClockType, portIdentity, parentPortIdentity string,
+) *profiles.InterfaceInfo {
+	return &profiles.InterfaceInfo{
+		Name:               name,
+		ClockType:          clockType,
+		PortIdentity:       portIdentity,
+		ParentPortIdentity: parentPortIdentity,
+	}
+}
+
+func clockClassSample(node, config string, value float64) *model.Sample {
+	return &model.Sample{
+		Metric: model.Metric{
+			model.LabelName(metrics.KeyNode):   model.LabelValue(node),
+			model.LabelName(metrics.KeyConfig): model.LabelValue(config),
+		},
+		Value: model.SampleValue(value),
+	}
+}
+
+func clockIDsOf(clocks []*Clock) []string {
+	ids := make([]string, 0, len(clocks))
+	for _, clock := range clocks {
+		ids = append(ids, clock.ID)
+	}
+
+	return ids
+}
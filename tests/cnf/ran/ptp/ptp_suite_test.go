@@ -20,9 +20,11 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/iface"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/metrics"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/mustgather"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/topology"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
 	_ "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/tests"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/snapshot"
 )
 
 var (
@@ -30,6 +32,9 @@ var (
 
 	// savedPtpServiceMonitor holds the original PTP ServiceMonitor state so it can be restored in AfterSuite.
 	savedPtpServiceMonitor *monv1.ServiceMonitor
+	// ptpConfigSnapshot holds the PtpConfigs from before the suite, so the AfterSuite can report those the specs
+	// leaked or left modified.
+	ptpConfigSnapshot *snapshot.Snapshot
 )
 
 func TestPTP(t *testing.T) {
@@ -46,6 +51,13 @@ var _ = BeforeSuite(func() {
 	isSpoke1Present := rancluster.AreClustersPresent([]*clients.Settings{Spoke1APIClient})
 	Expect(isSpoke1Present).To(BeTrue(), "Spoke 1 cluster must be present for PTP tests")
 
+	By("taking a snapshot of the PtpConfigs")
+
+	var err error
+
+	ptpConfigSnapshot, err = snapshot.Take(RANConfig.Spoke1APIClient, snapshot.PtpConfigs)
+	Expect(err).ToNot(HaveOccurred(), "Failed to take a snapshot of the PtpConfigs")
+
 	By("creating a Prometheus API client")
 
 	prometheusAPI, err := querier.CreatePrometheusAPIForCluster(RANConfig.Spoke1APIClient)
@@ -89,6 +101,12 @@ var _ = AfterSuite(func() {
 
 	err = querier.CleanupQuerierResources(RANConfig.Spoke1APIClient)
 	Expect(err).ToNot(HaveOccurred(), "Failed to cleanup Prometheus API client resources")
+
+	By("verifying the PtpConfigs did not drift")
+
+	if ptpConfigSnapshot != nil {
+		snapshot.Verify(RANConfig.Spoke1APIClient, ptpConfigSnapshot, RANConfig.SnapshotRestore)
+	}
 })
 
 var _ = JustAfterEach(func() {
@@ -104,6 +122,8 @@ var _ = JustAfterEach(func() {
 		return err
 	}).WithTimeout(time.Minute).WithPolling(10*time.Second).Should(Succeed(), "Reachability check to spoke 1 failed")
 
+	// Both the topology and the cluster dump go into the failure-dump bundle of the spec, which each one reopens.
+	topology.DumpIfFailed(CurrentSpecReport(), currentFile, RANConfig.Spoke1APIClient)
 	reporter.ReportIfFailed(
 		CurrentSpecReport(), currentFile, tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
 	mustgather.MustGatherIfFailed(CurrentSpecReport(), currentFile, RANConfig.Spoke1APIClient)
@@ -111,6 +131,7 @@ var _ = JustAfterEach(func() {
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, RANConfig.GetReportPath(), RANConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 
 	By("generating network interface information report")
 
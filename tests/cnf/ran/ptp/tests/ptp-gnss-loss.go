@@ -25,6 +25,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/processes"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/ptpdaemon"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/scenario"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
 	"k8s.io/klog/v2"
 )
@@ -101,6 +102,7 @@ var _ = Describe("PTP T-GM GNSS Loss", Label(tsparams.LabelGNSSLoss), func() {
 	It("verifies t-gm transition from holdover to locked due to gnss recovery",
 		reportxml.ID("78463"), func() {
 			testActuallyRan := false
+			gnssRecoveryScenario := newGNSSRecoveryScenario(eventTimeout)
 
 			By("getting node info map")
 
@@ -116,14 +118,6 @@ var _ = Describe("PTP T-GM GNSS Loss", Label(tsparams.LabelGNSSLoss), func() {
 				testActuallyRan = true
 				gmProfileInfo := gmProfilesInfo[0]
 
-				gmProfile, err := gmProfileInfo.PullProfile(RANConfig.Spoke1APIClient)
-				Expect(err).ToNot(HaveOccurred(), "Failed to pull GM profile for node %s", nodeName)
-
-				By("getting the ublox protocol version for node " + nodeName)
-
-				protocolVersion, err := gnss.GetUbloxProtocolVersion(gmProfile)
-				Expect(err).ToNot(HaveOccurred(), "Failed to get ublox protocol version for node %s", nodeName)
-
 				ptpConfig, err := gmProfileInfo.Reference.PullPtpConfig(RANConfig.Spoke1APIClient)
 				Expect(err).ToNot(HaveOccurred(), "Failed to pull PtpConfig for node %s", nodeName)
 
@@ -170,78 +164,17 @@ var _ = Describe("PTP T-GM GNSS Loss", Label(tsparams.LabelGNSSLoss), func() {
 
 				assertNMEAStatusAvailable(prometheusAPI, nodeName)
 
-				By("simulating GNSS loss on node " + nodeName)
-
-				DeferCleanup(cleanupGNSSSync(prometheusAPI, nodeName, protocolVersion))
-
-				gpsLossTime := time.Now()
-
-				err = gnss.SimulateSyncLoss(RANConfig.Spoke1APIClient, nodeName, protocolVersion)
-				Expect(err).ToNot(HaveOccurred(), "Failed to simulate GNSS loss on node %s", nodeName)
-
-				By("getting the event consumer pod for node " + nodeName)
-
-				eventPod, err := consumer.GetConsumerPodforNode(RANConfig.Spoke1APIClient, nodeName)
-				Expect(err).ToNot(HaveOccurred(), "Failed to get event pod for node %s", nodeName)
-
-				By("waiting for FAILURE-NOFIX GNSS event")
-
-				err = events.WaitForEvent(eventPod, gpsLossTime, eventTimeout, events.All(
-					events.IsType(eventptp.GnssStateChange),
-					events.HasValue(events.WithSyncState(eventptp.FAILURE_NOFIX)),
-				))
-				Expect(err).ToNot(HaveOccurred(), "Failed to receive FAILURE-NOFIX GNSS event on node %s", nodeName)
-
-				By("waiting for HOLDOVER state event")
-
-				err = events.WaitForEvent(eventPod, gpsLossTime, eventTimeout, events.All(
-					events.IsType(eventptp.PtpStateChange),
-					events.HasValue(
-						events.WithSyncState(eventptp.HOLDOVER),
-						events.ContainingResource(string(iface.Master)),
-					),
-				))
-				Expect(err).ToNot(HaveOccurred(), "Failed to receive HOLDOVER state event on node %s", nodeName)
-
-				By("waiting for clock class 7 event")
-
-				err = events.WaitForEvent(eventPod, gpsLossTime, eventTimeout, events.All(
-					events.IsType(eventptp.PtpClockClassChange),
-					events.HasValue(events.WithMetric(int64(metrics.ClockClass7))),
-				))
-				Expect(err).ToNot(HaveOccurred(), "Failed to receive clock class 7 event on node %s", nodeName)
-
-				By("restoring GNSS sync on node " + nodeName)
-
-				err = gnss.SimulateSyncRecovery(RANConfig.Spoke1APIClient, nodeName, protocolVersion)
-				Expect(err).ToNot(HaveOccurred(), "Failed to restore GNSS sync on node %s", nodeName)
-
-				By("waiting for SYNCHRONIZED GNSS event")
-
-				err = events.WaitForEvent(eventPod, gpsLossTime, eventTimeout, events.All(
-					events.IsType(eventptp.GnssStateChange),
-					events.HasValue(events.WithSyncState(eventptp.SYNCHRONIZED)),
-				))
-				Expect(err).ToNot(HaveOccurred(), "Failed to receive SYNCHRONIZED GNSS event on node %s", nodeName)
-
-				By("waiting for LOCKED state event")
-
-				err = events.WaitForEvent(eventPod, gpsLossTime, eventTimeout, events.All(
-					events.IsType(eventptp.PtpStateChange),
-					events.HasValue(
-						events.WithSyncState(eventptp.LOCKED),
-						events.ContainingResource(string(iface.Master)),
-					),
-				))
-				Expect(err).ToNot(HaveOccurred(), "Failed to receive LOCKED state event on node %s", nodeName)
+				By("running GNSS loss and recovery scenario on node " + nodeName)
 
-				By("waiting for clock class 6 event")
+				result, err := gnssRecoveryScenario.Run(context.TODO(), scenario.Target{
+					Client:        RANConfig.Spoke1APIClient,
+					PrometheusAPI: prometheusAPI,
+					Node:          nodeInfo,
+				})
+				AddReportEntry(result.Scenario, result.String())
+				Expect(err).ToNot(HaveOccurred(), "Failed to run GNSS recovery scenario on node %s", nodeName)
 
-				err = events.WaitForEvent(eventPod, gpsLossTime, eventTimeout, events.All(
-					events.IsType(eventptp.PtpClockClassChange),
-					events.HasValue(events.WithMetric(int64(metrics.ClockClass6))),
-				))
-				Expect(err).ToNot(HaveOccurred(), "Failed to receive clock class 6 event on node %s", nodeName)
+				gpsLossTime := result.Steps[0].Start
 
 				By("verifying NMEA status is available after recovery on node " + nodeName)
 
@@ -262,6 +195,11 @@ var _ = Describe("PTP T-GM GNSS Loss", Label(tsparams.LabelGNSSLoss), func() {
 				Expect(err).ToNot(HaveOccurred(),
 					"Failed to assert clock state is LOCKED in metrics on node %s", nodeName)
 
+				By("getting the event consumer pod for node " + nodeName)
+
+				eventPod, err := consumer.GetConsumerPodforNode(RANConfig.Spoke1APIClient, nodeName)
+				Expect(err).ToNot(HaveOccurred(), "Failed to get event pod for node %s", nodeName)
+
 				By("validating no FREERUN events were received")
 
 				err = events.WaitForEvent(eventPod, gpsLossTime, 30*time.Second, events.All(
@@ -713,6 +651,41 @@ var _ = Describe("PTP T-GM GNSS Loss", Label(tsparams.LabelGNSSLoss), func() {
 		})
 })
 
+// newGNSSRecoveryScenario returns the scenario where GNSS sync is lost until the clock is in holdover with clock class
+// 7, then recovered until the clock is locked again with clock class 6.
+func newGNSSRecoveryScenario(eventTimeout time.Duration) *scenario.Scenario {
+	gnssLoss := scenario.GNSSLoss()
+
+	return scenario.New("gnss loss and recovery",
+		scenario.Inject(gnssLoss),
+		scenario.Expect(scenario.ExpectEvent("FAILURE-NOFIX GNSS event", events.All(
+			events.IsType(eventptp.GnssStateChange),
+			events.HasValue(events.WithSyncState(eventptp.FAILURE_NOFIX)),
+		), eventTimeout)),
+		scenario.Expect(scenario.ExpectEvent("HOLDOVER state event", events.All(
+			events.IsType(eventptp.PtpStateChange),
+			events.HasValue(events.WithSyncState(eventptp.HOLDOVER), events.ContainingResource(string(iface.Master))),
+		), eventTimeout)),
+		scenario.Expect(scenario.ExpectEvent("clock class 7 event", events.All(
+			events.IsType(eventptp.PtpClockClassChange),
+			events.HasValue(events.WithMetric(int64(metrics.ClockClass7))),
+		), eventTimeout)),
+		scenario.Recover(gnssLoss),
+		scenario.Expect(scenario.ExpectEvent("SYNCHRONIZED GNSS event", events.All(
+			events.IsType(eventptp.GnssStateChange),
+			events.HasValue(events.WithSyncState(eventptp.SYNCHRONIZED)),
+		), eventTimeout)),
+		scenario.Expect(scenario.ExpectEvent("LOCKED state event", events.All(
+			events.IsType(eventptp.PtpStateChange),
+			events.HasValue(events.WithSyncState(eventptp.LOCKED), events.ContainingResource(string(iface.Master))),
+		), eventTimeout)),
+		scenario.Expect(scenario.ExpectEvent("clock class 6 event", events.All(
+			events.IsType(eventptp.PtpClockClassChange),
+			events.HasValue(events.WithMetric(int64(metrics.ClockClass6))),
+		), eventTimeout)),
+	).ForProfileTypes(profiles.ProfileTypeGM, profiles.ProfileTypeMultiNICGM)
+}
+
 func validateGpsdFileEmpty(nodeName string) {
 	GinkgoHelper()
 
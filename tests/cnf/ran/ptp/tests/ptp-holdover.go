@@ -3,6 +3,7 @@ package tests
 import (
 	"context"
 	"fmt"
+	"slices"
 	"time"
 
 	. "github.com/onsi/ginkgo/v2"
@@ -20,6 +21,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/iface"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/metrics"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/profiles"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/topology"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/ptp/internal/tsparams"
 	"k8s.io/klog/v2"
 )
@@ -703,44 +705,36 @@ func changeHoldoverSettings(
 		expectedLockedClass, clockClassChanges, timeout)
 }
 
-// discoverHoldoverTestData finds the first node with a matching profile type that supports holdover tests
+// discoverHoldoverTestData finds the first clock with a matching profile type that supports holdover tests
 // and returns the test context. Returns nil if no suitable profile is found.
 // Without a HardwareConfig CR, only E810 supports holdover via the plugin path.
 func discoverHoldoverTestData(
 	prometheusAPI prometheusv1.API,
 	profileType profiles.PtpProfileType,
 ) *holdoverTestData {
-	nodeInfoMap, err := profiles.GetNodeInfoMap(RANConfig.Spoke1APIClient)
-	Expect(err).ToNot(HaveOccurred(), "Failed to get node info map")
+	ptpTopology, err := topology.Discover(RANConfig.Spoke1APIClient, topology.WithoutPortIdentities())
+	Expect(err).ToNot(HaveOccurred(), "Failed to discover PTP topology")
 
-	for name, nodeInfo := range nodeInfoMap {
-		for _, profileInfo := range nodeInfo.GetProfilesByTypes(profileType) {
-			ptpProfile, pullErr := profileInfo.PullProfile(RANConfig.Spoke1APIClient)
-			Expect(pullErr).ToNot(HaveOccurred())
-
-			if profileInfo.HardwareConfig == nil && !profiles.HasPlugin(ptpProfile, ptp.PluginTypeE810) {
-				klog.V(tsparams.LogLevel).Infof(
-					"Skipping profile %s on node %s: unsupported holdover path: %+v",
-					profileInfo.Reference.ProfileName, name, ptpProfile.Plugins)
+	for _, clock := range ptpTopology.ClocksByProfileType(profileType) {
+		if clock.Info.HardwareConfig == nil && !slices.Contains(clock.Plugins, string(ptp.PluginTypeE810)) {
+			klog.V(tsparams.LogLevel).Infof(
+				"Skipping profile %s on node %s: unsupported holdover path: %v", clock.Profile, clock.Node, clock.Plugins)
 
-				continue
-			}
+			continue
+		}
 
-			port, portErr := profiles.GetUpstreamPortForProfile(ptpProfile)
-			if portErr != nil {
-				klog.V(tsparams.LogLevel).Infof(
-					"Skipping profile %s on node %s: cannot determine upstream port: %v",
-					profileInfo.Reference.ProfileName, name, portErr)
+		if clock.UpstreamPort == "" {
+			klog.V(tsparams.LogLevel).Infof(
+				"Skipping profile %s on node %s: cannot determine upstream port", clock.Profile, clock.Node)
 
-				continue
-			}
+			continue
+		}
 
-			return &holdoverTestData{
-				prometheusAPI: prometheusAPI,
-				nodeName:      name,
-				profileInfo:   profileInfo,
-				upstreamIface: port,
-			}
+		return &holdoverTestData{
+			prometheusAPI: prometheusAPI,
+			nodeName:      clock.Node,
+			profileInfo:   clock.Info,
+			upstreamIface: clock.UpstreamPort,
 		}
 	}
 
@@ -84,8 +84,10 @@ var _ = Describe("PTP Stability", Label(tsparams.LabelStability), func() {
 			Expect(err).ToNot(HaveOccurred(), "Failed to assert phc2sys process status is UP on node %s", nodeInfo.Name)
 
 			By(fmt.Sprintf("collecting daemon logs from node %s for %s", nodeInfo.Name, RANConfig.PtpStabilityDuration))
+			windowAnalyzer := stability.NewWindowAnalyzer(stability.WindowOptions{Window: RANConfig.PtpStabilityWindow})
 			collectionResult, err := daemonlogs.CollectDaemonLogs(
-				RANConfig.Spoke1APIClient, nodeInfo.Name, RANConfig.PtpStabilityDuration)
+				RANConfig.Spoke1APIClient, nodeInfo.Name, RANConfig.PtpStabilityDuration,
+				daemonlogs.WithLineObserver(windowAnalyzer.ObserveLine))
 			Expect(err).ToNot(HaveOccurred(), "Failed to collect daemon logs on node %s", nodeInfo.Name)
 
 			DeferCleanup(os.Remove, collectionResult.TempFilePath)
@@ -103,7 +105,27 @@ var _ = Describe("PTP Stability", Label(tsparams.LabelStability), func() {
 
 			AddReportEntry("ptp_stability_analysis_"+nodeInfo.Name, analysisResult.DiagnosticMessage())
 
+			By("writing windowed stability reports for node " + nodeInfo.Name)
+
+			windowReport := windowAnalyzer.Report()
+			csvPath, htmlPath, err := windowReport.WriteFiles(
+				RANConfig.ReportsDirAbsPath, "ptp_stability_windows_"+nodeInfo.Name)
+			Expect(err).ToNot(HaveOccurred(), "Failed to write windowed stability reports for node %s", nodeInfo.Name)
+
+			AddReportEntry("ptp_stability_windows_"+nodeInfo.Name, csvPath, htmlPath)
+
 			Expect(analysisResult.Passed).To(BeTrue(), analysisResult.DiagnosticMessage())
+
+			if RANConfig.PtpStabilityLimits != "" {
+				By(fmt.Sprintf("asserting windows on node %s are within class %s limits",
+					nodeInfo.Name, RANConfig.PtpStabilityLimits))
+
+				limits, err := stability.LimitsForClass(RANConfig.PtpStabilityLimits)
+				Expect(err).ToNot(HaveOccurred(), "Failed to get stability limits")
+
+				err = windowReport.Check(limits)
+				Expect(err).ToNot(HaveOccurred(), "Stability windows on node %s exceed limits", nodeInfo.Name)
+			}
 		}
 
 		if !testRanAtLeastOnce {
@@ -1,8 +1,6 @@
 package talm
 
 import (
-	"fmt"
-	"path"
 	"runtime"
 	"testing"
 
@@ -13,6 +11,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/talm/internal/setup"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/talm/internal/tsparams"
 	_ "github.com/rh-ecosystem-edge/eco-gotests/tests/cnf/ran/talm/tests"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 )
 
@@ -27,6 +26,10 @@ func TestTalm(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	clusters.SetReporter(clusters.Hub, tsparams.ReporterHubNamespacesToDump, tsparams.ReporterHubCRsToDump)
+	clusters.SetReporter(clusters.Spoke(1), tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
+	clusters.SetReporter(clusters.Spoke(2), tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
+
 	err := setup.VerifyTalmIsInstalled()
 	Expect(err).ToNot(HaveOccurred(), "Failed to verify that TALM is installed")
 
@@ -45,35 +48,10 @@ var _ = AfterSuite(func() {
 })
 
 var _ = JustAfterEach(func() {
-	var (
-		currentDir, currentFilename = path.Split(currentFile)
-		hubReportPath               = fmt.Sprintf("%shub_%s", currentDir, currentFilename)
-		spoke2ReportPath            = fmt.Sprintf("%sspoke2_%s", currentDir, currentFilename)
-		report                      = CurrentSpecReport()
-	)
-
-	reporter.ReportIfFailed(
-		report, currentFile, tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
-
-	if HubAPIClient != nil {
-		reporter.ReportIfFailedOnCluster(
-			RANConfig.HubKubeconfig,
-			report,
-			hubReportPath,
-			tsparams.ReporterHubNamespacesToDump,
-			tsparams.ReporterHubCRsToDump)
-	}
-
-	if Spoke2APIClient != nil {
-		reporter.ReportIfFailedOnCluster(
-			RANConfig.Spoke2Kubeconfig,
-			report,
-			spoke2ReportPath,
-			tsparams.ReporterSpokeNamespacesToDump,
-			tsparams.ReporterSpokeCRsToDump)
-	}
+	reporter.ReportIfFailedOnClusters(CurrentSpecReport(), currentFile)
 })
 
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(report, RANConfig.GetReportPath(), RANConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
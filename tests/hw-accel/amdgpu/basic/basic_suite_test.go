@@ -26,6 +26,7 @@ func TestBasic(t *testing.T) {
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(
 		report, GeneralConfig.GetReportPath(), GeneralConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
@@ -0,0 +1,29 @@
+// Package amdgpupreflight defines the amdgpu feature checked by the eco-preflight command.
+package amdgpupreflight
+
+import (
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/hw-accel/amdgpu/internal/amdgpuconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/hw-accel/internal/hwaccelparams"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/preflight"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/requires"
+)
+
+// amdGPUNodeLabel is set by NFD on nodes with a PCI device of the AMD vendor ID.
+const amdGPUNodeLabel = "feature.node.kubernetes.io/pci-1002.present=true"
+
+// NewFeature returns the amdgpu feature, which needs NFD and a node with an AMD GPU.
+func NewFeature() preflight.Feature {
+	amdConfig := amdgpuconfig.NewAMDConfig()
+	if amdConfig == nil {
+		return preflight.Feature{Name: "amdgpu"}
+	}
+
+	return preflight.Feature{
+		Name:   "amdgpu",
+		Config: amdConfig,
+		Requirements: []requires.Requirement{
+			requires.OperatorVersion(hwaccelparams.NFDNamespace, "nfd", ""),
+			requires.Nodes(amdGPUNodeLabel, 1),
+		},
+	}
+}
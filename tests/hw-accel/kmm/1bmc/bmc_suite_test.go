@@ -166,6 +166,7 @@ var _ = AfterSuite(func() {
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(
 		report, GeneralConfig.GetReportPath(), GeneralConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
@@ -27,6 +27,7 @@ func TestUpgrade(tt *testing.T) {
 
 var _ = ReportAfterSuite("1upgrade", func(report Report) {
 	reportxml.Create(report, GeneralConfig.GetReportPath(), GeneralConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
@@ -12,7 +12,7 @@ import (
 
 // ModulesConfig contains environment information related to kmm tests.
 type ModulesConfig struct {
-	PullSecret           string `envconfig:"ECO_HWACCEL_KMM_PULL_SECRET"`
+	PullSecret           string `envconfig:"ECO_HWACCEL_KMM_PULL_SECRET" eco_secret:"true"`
 	Registry             string `envconfig:"ECO_HWACCEL_KMM_REGISTRY"`
 	DevicePluginImage    string `envconfig:"ECO_HWACCEL_KMM_DEVICE_PLUGIN_IMAGE"`
 	SubscriptionName     string `envconfig:"ECO_HWACCEL_KMM_SUBSCRIPTION_NAME"`
@@ -27,6 +27,7 @@ func TestUpgrade(tt *testing.T) {
 var _ = ReportAfterSuite(tsparams.NfdUpgradeLabel, func(report Report) {
 	reportxml.Create(
 		report, GeneralConfig.GetReportPath(), GeneralConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
@@ -0,0 +1,29 @@
+// Package nvidiagpupreflight defines the nvidiagpu feature checked by the eco-preflight command.
+package nvidiagpupreflight
+
+import (
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/hw-accel/internal/hwaccelparams"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/hw-accel/nvidiagpu/internal/nvidiagpuconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/preflight"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/requires"
+)
+
+// nvidiaGPUNodeLabel is set by NFD on nodes with a PCI device of the NVIDIA vendor ID.
+const nvidiaGPUNodeLabel = "feature.node.kubernetes.io/pci-10de.present=true"
+
+// NewFeature returns the nvidiagpu feature, which needs NFD and a node with an NVIDIA GPU.
+func NewFeature() preflight.Feature {
+	nvidiaGPUConfig := nvidiagpuconfig.NewNvidiaGPUConfig()
+	if nvidiaGPUConfig == nil {
+		return preflight.Feature{Name: "nvidiagpu"}
+	}
+
+	return preflight.Feature{
+		Name:   "nvidiagpu",
+		Config: nvidiaGPUConfig,
+		Requirements: []requires.Requirement{
+			requires.OperatorVersion(hwaccelparams.NFDNamespace, "nfd", ""),
+			requires.Nodes(nvidiaGPUNodeLabel, 1),
+		},
+	}
+}
@@ -0,0 +1,245 @@
+// Package await provides event-driven waits over the resources of a cluster. Instead of listing them every few seconds,
+// a wait watches the resources through an informer and evaluates its condition over the informer cache every time one
+// of them changes. It works for any typed or unstructured object, and records a timeline of every state transition
+// observed during the wait, so a timeout reports how the resources got stuck instead of only the deadline being
+// exceeded.
+package await
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	apiwatch "k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/klog/v2"
+)
+
+// Watch selects the objects a wait is evaluated over. T is the type the objects are converted to before being passed to
+// the condition, either a typed API object such as corev1.Pod or unstructured.Unstructured.
+type Watch[T any] struct {
+	GVK schema.GroupVersionKind
+	// Namespace restricts the watch to one namespace. It is left empty for cluster-scoped resources or to watch every
+	// namespace.
+	Namespace     string
+	LabelSelector string
+	FieldSelector string
+	// Describe summarizes the state of an object for the timeline. Updates which do not change the summary are not
+	// recorded. If nil, the phase and conditions of the status are used.
+	Describe func(object *T) string
+}
+
+// Condition reports whether the objects currently selected by a Watch satisfy the wait. It is evaluated once the
+// informer cache is synced and again after every change to the objects. Returning an error stops the wait.
+type Condition[T any] func(objects []*T) (bool, error)
+
+// Until waits up to timeout for condition to be satisfied by the objects selected by watch. The returned timeline holds
+// the state transitions observed during the wait. If the timeout expires, the error is a *TimeoutError holding the
+// timeline as well.
+func Until[T any](
+	apiClient *clients.Settings, watch Watch[T], timeout time.Duration, condition Condition[T]) (Timeline, error) {
+	if apiClient == nil {
+		return nil, fmt.Errorf("cannot wait for %s: apiClient is nil", watch)
+	}
+
+	klog.V(90).Infof("Waiting up to %s for %s", timeout, watch)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+	defer cancel()
+
+	informer := newInformer(apiClient, watch)
+	recorder := newRecorder(watch)
+
+	_, err := informer.AddEventHandler(recorder.handler())
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch %s: %w", watch, err)
+	}
+
+	go informer.Run(ctx.Done())
+
+	if !cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+		return recorder.timeline(), recorder.timeout(watch, ctx.Err())
+	}
+
+	for {
+		satisfied, err := evaluate(informer.GetStore(), condition)
+		if err != nil {
+			return recorder.timeline(), fmt.Errorf("failed to evaluate condition for %s: %w", watch, err)
+		}
+
+		if satisfied {
+			klog.V(90).Infof("Condition for %s satisfied", watch)
+
+			return recorder.timeline(), nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return recorder.timeline(), recorder.timeout(watch, ctx.Err())
+		case <-recorder.changed:
+		}
+	}
+}
+
+// String describes the objects selected by watch.
+func (watch Watch[T]) String() string {
+	description := watch.GVK.Kind + " objects"
+
+	if watch.Namespace != "" {
+		description += " in namespace " + watch.Namespace
+	}
+
+	if watch.LabelSelector != "" {
+		description += " with labels " + watch.LabelSelector
+	}
+
+	if watch.FieldSelector != "" {
+		description += " with fields " + watch.FieldSelector
+	}
+
+	return description
+}
+
+// Exists returns a condition satisfied once the object called name exists and satisfies predicate. A nil predicate
+// only waits for the object to exist.
+func Exists[T any](name string, predicate func(object *T) bool) Condition[T] {
+	return func(objects []*T) (bool, error) {
+		for _, object := range objects {
+			if objectName(object) == name {
+				return predicate == nil || predicate(object), nil
+			}
+		}
+
+		return false, nil
+	}
+}
+
+// Deleted returns a condition satisfied once no object is called name.
+func Deleted[T any](name string) Condition[T] {
+	return func(objects []*T) (bool, error) {
+		for _, object := range objects {
+			if objectName(object) == name {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
+
+// All returns a condition satisfied once there are at least minimum objects and every one of them satisfies predicate.
+func All[T any](minimum int, predicate func(object *T) bool) Condition[T] {
+	return func(objects []*T) (bool, error) {
+		if len(objects) < minimum {
+			return false, nil
+		}
+
+		for _, object := range objects {
+			if !predicate(object) {
+				return false, nil
+			}
+		}
+
+		return true, nil
+	}
+}
+
+// newInformer returns an informer of the objects selected by watch, listed and watched through the dynamic client.
+func newInformer[T any](apiClient *clients.Settings, watch Watch[T]) cache.SharedIndexInformer {
+	gvr, _ := meta.UnsafeGuessKindToResource(watch.GVK)
+	resourceClient := apiClient.Interface.Resource(gvr).Namespace(watch.Namespace)
+
+	selectOptions := func(options *metav1.ListOptions) {
+		options.LabelSelector = watch.LabelSelector
+		options.FieldSelector = watch.FieldSelector
+	}
+
+	listWatch := &cache.ListWatch{
+		ListWithContextFunc: func(ctx context.Context, options metav1.ListOptions) (runtime.Object, error) {
+			selectOptions(&options)
+
+			return resourceClient.List(ctx, options)
+		},
+		WatchFuncWithContext: func(ctx context.Context, options metav1.ListOptions) (apiwatch.Interface, error) {
+			selectOptions(&options)
+
+			return resourceClient.Watch(ctx, options)
+		},
+	}
+
+	return cache.NewSharedIndexInformer(
+		cache.ToListWatcherWithWatchListSemantics(listWatch, apiClient.Interface),
+		&unstructured.Unstructured{}, 0, cache.Indexers{})
+}
+
+// evaluate converts the objects of store, sorted by namespace and name, and evaluates condition over them.
+func evaluate[T any](store cache.Store, condition Condition[T]) (bool, error) {
+	var objects []*T
+
+	for _, item := range store.List() {
+		unstructuredObject, ok := item.(*unstructured.Unstructured)
+		if !ok {
+			continue
+		}
+
+		object, err := convert[T](unstructuredObject)
+		if err != nil {
+			return false, err
+		}
+
+		objects = append(objects, object)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objectKey(objects[i]) < objectKey(objects[j])
+	})
+
+	return condition(objects)
+}
+
+// convert returns a copy of object as a T.
+func convert[T any](object *unstructured.Unstructured) (*T, error) {
+	converted := new(T)
+
+	if unstructuredObject, ok := any(converted).(*unstructured.Unstructured); ok {
+		object.DeepCopyInto(unstructuredObject)
+
+		return converted, nil
+	}
+
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, converted)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s %s: %w", object.GetKind(), object.GetName(), err)
+	}
+
+	return converted, nil
+}
+
+func objectName(object any) string {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return ""
+	}
+
+	return accessor.GetName()
+}
+
+func objectKey(object any) string {
+	accessor, err := meta.Accessor(object)
+	if err != nil {
+		return ""
+	}
+
+	if accessor.GetNamespace() == "" {
+		return accessor.GetName()
+	}
+
+	return accessor.GetNamespace() + "/" + accessor.GetName()
+}
@@ -0,0 +1,168 @@
+package await
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/fakecluster"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var podGVK = schema.GroupVersionKind{Version: "v1", Kind: "Pod"}
+
+func TestUntilTyped(t *testing.T) {
+	apiClient := buildFakeCluster(t)
+	podWatch := Watch[corev1.Pod]{GVK: podGVK, Namespace: "test-ns"}
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		setPodPhase(t, apiClient, "pod-a", "Running")
+	}()
+
+	timeline, err := Until(apiClient, podWatch, 10*time.Second, Exists("pod-a", func(pod *corev1.Pod) bool {
+		return pod.Status.Phase == corev1.PodRunning
+	}))
+	assert.NoError(t, err)
+
+	if assert.Len(t, timeline, 3) {
+		assert.Equal(t, Transition{Time: timeline[0].Time, Event: "added", Object: "test-ns/pod-a",
+			State: "phase=Pending"}, timeline[0])
+		assert.Equal(t, "added", timeline[1].Event)
+		assert.Equal(t, "test-ns/pod-b", timeline[1].Object)
+		assert.Equal(t, "updated", timeline[2].Event)
+		assert.Equal(t, "phase=Running", timeline[2].State)
+	}
+}
+
+func TestUntilUnstructured(t *testing.T) {
+	apiClient := buildFakeCluster(t)
+	podWatch := Watch[unstructured.Unstructured]{
+		GVK:       podGVK,
+		Namespace: "test-ns",
+		Describe: func(pod *unstructured.Unstructured) string {
+			return "name=" + pod.GetName()
+		},
+	}
+
+	_, err := Until(apiClient, podWatch, 10*time.Second, All(2, func(pod *unstructured.Unstructured) bool {
+		return pod.GetNamespace() == "test-ns"
+	}))
+	assert.NoError(t, err)
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+
+		err := apiClient.Interface.Resource(podGVK.GroupVersion().WithResource("pods")).Namespace("test-ns").Delete(
+			context.TODO(), "pod-b", metav1.DeleteOptions{})
+		assert.NoError(t, err)
+	}()
+
+	timeline, err := Until(apiClient, podWatch, 10*time.Second, Deleted[unstructured.Unstructured]("pod-b"))
+	assert.NoError(t, err)
+
+	if assert.Len(t, timeline, 3) {
+		assert.Equal(t, "name=pod-a", timeline[0].State)
+		assert.Equal(t, "deleted", timeline[2].Event)
+		assert.Equal(t, "test-ns/pod-b", timeline[2].Object)
+	}
+}
+
+func TestUntilTimeout(t *testing.T) {
+	apiClient := buildFakeCluster(t)
+	podWatch := Watch[corev1.Pod]{GVK: podGVK, Namespace: "test-ns"}
+
+	timeline, err := Until(apiClient, podWatch, 500*time.Millisecond, Deleted[corev1.Pod]("pod-a"))
+	assert.Len(t, timeline, 2)
+
+	var timeoutError *TimeoutError
+
+	if assert.ErrorAs(t, err, &timeoutError) {
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Equal(t, "Pod objects in namespace test-ns", timeoutError.Watched)
+		assert.Contains(t, err.Error(), "added test-ns/pod-a: phase=Pending")
+	}
+
+	_, err = Until(apiClient, podWatch, 10*time.Second, func(_ []*corev1.Pod) (bool, error) {
+		return false, errors.New("condition failed")
+	})
+	assert.ErrorContains(t, err, "condition failed")
+	assert.NotErrorAs(t, err, &timeoutError)
+
+	_, err = Until(nil, podWatch, time.Second, Deleted[corev1.Pod]("pod-a"))
+	assert.Error(t, err)
+}
+
+func TestDefaultState(t *testing.T) {
+	testCases := []struct {
+		name     string
+		object   map[string]any
+		expected string
+	}{
+		{
+			name: "phase and conditions",
+			object: map[string]any{"status": map[string]any{"phase": "Running", "conditions": []any{
+				map[string]any{"type": "Ready", "status": "True"},
+				map[string]any{"type": "Initialized", "status": "False"},
+			}}},
+			expected: "phase=Running Ready=True Initialized=False",
+		},
+		{
+			name:     "no status",
+			object:   map[string]any{"metadata": map[string]any{"resourceVersion": "42"}},
+			expected: "resourceVersion=42",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			assert.Equal(t, testCase.expected, defaultState(&unstructured.Unstructured{Object: testCase.object}))
+		})
+	}
+}
+
+func TestTimelineString(t *testing.T) {
+	assert.Equal(t, "no transitions observed", Timeline{}.String())
+
+	observed := time.Date(2024, 1, 1, 10, 30, 15, 250000000, time.UTC)
+	timeline := Timeline{
+		{Time: observed, Event: "added", Object: "test-ns/pod-a", State: "phase=Pending"},
+		{Time: observed, Event: "deleted", Object: "test-ns/pod-a"},
+	}
+
+	assert.Equal(t, "10:30:15.250 added test-ns/pod-a: phase=Pending\n10:30:15.250 deleted test-ns/pod-a",
+		timeline.String())
+}
+
+func buildFakeCluster(t *testing.T) *clients.Settings {
+	t.Helper()
+
+	apiClient, err := fakecluster.New(newPod("pod-a"), newPod("pod-b"))
+	assert.NoError(t, err)
+
+	return apiClient
+}
+
+func newPod(name string) *unstructured.Unstructured {
+	return fakecluster.Object(podGVK, "test-ns", name, map[string]any{"status": map[string]any{"phase": "Pending"}})
+}
+
+func setPodPhase(t *testing.T, apiClient *clients.Settings, name, phase string) {
+	t.Helper()
+
+	pods := apiClient.Interface.Resource(podGVK.GroupVersion().WithResource("pods")).Namespace("test-ns")
+
+	pod, err := pods.Get(context.TODO(), name, metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	pod.Object["status"] = map[string]any{"phase": phase}
+
+	_, err = pods.Update(context.TODO(), pod, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+}
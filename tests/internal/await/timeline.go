@@ -0,0 +1,192 @@
+package await
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/tools/cache"
+)
+
+// Transition is a state transition of an object observed during a wait.
+type Transition struct {
+	Time time.Time
+	// Event is added, updated or deleted.
+	Event string
+	// Object is the namespace and name of the object.
+	Object string
+	// State is the summary of the object after the transition.
+	State string
+}
+
+// Timeline is the transitions observed during a wait, in the order they were observed.
+type Timeline []Transition
+
+// String returns one line per transition.
+func (timeline Timeline) String() string {
+	if len(timeline) == 0 {
+		return "no transitions observed"
+	}
+
+	lines := make([]string, 0, len(timeline))
+
+	for _, transition := range timeline {
+		line := fmt.Sprintf("%s %s %s", transition.Time.Format("15:04:05.000"), transition.Event, transition.Object)
+
+		if transition.State != "" {
+			line += ": " + transition.State
+		}
+
+		lines = append(lines, line)
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// TimeoutError is returned when the condition of a wait is not satisfied before its timeout. It holds the transitions
+// observed until then.
+type TimeoutError struct {
+	// Watched describes the objects of the wait.
+	Watched  string
+	Timeline Timeline
+	Err      error
+}
+
+// Error returns the cause of the timeout followed by the timeline.
+func (timeoutError *TimeoutError) Error() string {
+	return fmt.Sprintf("timed out waiting for %s: %v; observed:\n%s",
+		timeoutError.Watched, timeoutError.Err, timeoutError.Timeline)
+}
+
+// Unwrap returns the context error which ended the wait.
+func (timeoutError *TimeoutError) Unwrap() error {
+	return timeoutError.Err
+}
+
+// recorder records the transitions of the objects of a wait and signals every change to them.
+type recorder[T any] struct {
+	mutex       sync.Mutex
+	transitions Timeline
+	states      map[string]string
+	describe    func(object *unstructured.Unstructured) string
+	changed     chan struct{}
+}
+
+func newRecorder[T any](watch Watch[T]) *recorder[T] {
+	describe := defaultState
+
+	if watch.Describe != nil {
+		describe = func(object *unstructured.Unstructured) string {
+			converted, err := convert[T](object)
+			if err != nil {
+				return err.Error()
+			}
+
+			return watch.Describe(converted)
+		}
+	}
+
+	return &recorder[T]{
+		states:   make(map[string]string),
+		describe: describe,
+		changed:  make(chan struct{}, 1),
+	}
+}
+
+// handler returns the event handler feeding the recorder from an informer.
+func (recorder *recorder[T]) handler() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(object any) {
+			recorder.record("added", object)
+		},
+		UpdateFunc: func(_, object any) {
+			recorder.record("updated", object)
+		},
+		DeleteFunc: func(object any) {
+			if tombstone, ok := object.(cache.DeletedFinalStateUnknown); ok {
+				object = tombstone.Obj
+			}
+
+			recorder.record("deleted", object)
+		},
+	}
+}
+
+// record appends a transition for object unless it is an update which did not change its state, then signals the
+// change.
+func (recorder *recorder[T]) record(event string, object any) {
+	unstructuredObject, ok := object.(*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+
+	key := objectKey(unstructuredObject)
+	state := ""
+
+	if event != "deleted" {
+		state = recorder.describe(unstructuredObject)
+	}
+
+	recorder.mutex.Lock()
+
+	previous, seen := recorder.states[key]
+	if event != "updated" || !seen || previous != state {
+		recorder.transitions = append(recorder.transitions, Transition{
+			Time: time.Now(), Event: event, Object: key, State: state})
+	}
+
+	if event == "deleted" {
+		delete(recorder.states, key)
+	} else {
+		recorder.states[key] = state
+	}
+
+	recorder.mutex.Unlock()
+
+	select {
+	case recorder.changed <- struct{}{}:
+	default:
+	}
+}
+
+// timeline returns a copy of the transitions recorded so far.
+func (recorder *recorder[T]) timeline() Timeline {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+
+	return append(Timeline{}, recorder.transitions...)
+}
+
+// timeout returns the error of a wait for watch ending with err before its condition was satisfied.
+func (recorder *recorder[T]) timeout(watch Watch[T], err error) *TimeoutError {
+	return &TimeoutError{Watched: watch.String(), Timeline: recorder.timeline(), Err: err}
+}
+
+// defaultState summarizes object by the phase and conditions of its status, such as "phase=Running Ready=True". Objects
+// without either are summarized by their resource version, so every update is a transition.
+func defaultState(object *unstructured.Unstructured) string {
+	var parts []string
+
+	if phase, found, _ := unstructured.NestedString(object.Object, "status", "phase"); found {
+		parts = append(parts, "phase="+phase)
+	}
+
+	conditions, _, _ := unstructured.NestedSlice(object.Object, "status", "conditions")
+
+	for _, condition := range conditions {
+		fields, ok := condition.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		parts = append(parts, fmt.Sprintf("%v=%v", fields["type"], fields["status"]))
+	}
+
+	if len(parts) == 0 {
+		return "resourceVersion=" + object.GetResourceVersion()
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -18,6 +18,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nodes"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/internal/inittools"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -96,7 +97,7 @@ func ExecCmd(apiClient *clients.Settings, nodeSelector string, shellCmd string)
 
 			klog.V(90).Infof("Exec cmd %v on pod %s", cmdToExec, mcPod.Definition.Name)
 
-			buf, err := mcPod.ExecCommand(cmdToExec)
+			buf, err := execrecorder.ExecCommand(apiClient, mcPod, cmdToExec)
 			if err != nil {
 				return fmt.Errorf("%w\n%s", err, buf.String())
 			}
@@ -169,7 +170,7 @@ func ExecCmdWithStdout(
 
 			hostnameCmd := []string{"sh", "-c", "nsenter --mount=/proc/1/ns/mnt -- sh -c 'printf $(hostname)'"}
 
-			hostnameBuf, err := mcPod.ExecCommand(hostnameCmd)
+			hostnameBuf, err := execrecorder.ExecCommand(apiClient, mcPod, hostnameCmd)
 			if err != nil {
 				return nil, fmt.Errorf("failed gathering node hostname: %w", err)
 			}
@@ -178,7 +179,7 @@ func ExecCmdWithStdout(
 
 			klog.V(90).Infof("Exec cmd %v on pod %s", cmdToExec, mcPod.Definition.Name)
 
-			commandBuf, err := mcPod.ExecCommand(cmdToExec)
+			commandBuf, err := execrecorder.ExecCommand(apiClient, mcPod, cmdToExec)
 			if err != nil {
 				return nil, fmt.Errorf("failed executing command '%s' on node %s: %w", shellCmd, hostnameBuf.String(), err)
 			}
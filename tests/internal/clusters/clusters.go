@@ -0,0 +1,251 @@
+// Package clusters is a registry of the clusters a suite runs against, keyed by their role, such as the hub and spokes
+// of RAN and ZTP suites or the seed and target of image based upgrades. Clients are created lazily from the kubeconfig
+// of each cluster, and the namespaces and CRs to dump for each role are attached to it, so the reporter can dump every
+// registered cluster when a spec fails.
+package clusters
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/openshift-kni/k8sreporter"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clusterversion"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/requires"
+	"k8s.io/klog/v2"
+)
+
+// Role is the role of a cluster in a suite.
+type Role string
+
+// Roles of the clusters used by multi-cluster suites. Spoke clusters are numbered, see Spoke.
+const (
+	Hub    Role = "hub"
+	Seed   Role = "seed"
+	Target Role = "target"
+)
+
+// Spoke returns the role of the spoke cluster numbered index, starting at 1.
+func Spoke(index int) Role {
+	return Role(fmt.Sprintf("spoke-%d", index))
+}
+
+// Cluster is a cluster registered for a role.
+type Cluster struct {
+	Role Role
+	// Kubeconfig is the path to the kubeconfig of the cluster. It is empty for the cluster from KUBECONFIG.
+	Kubeconfig string
+	// NamespacesToDump are the namespaces dumped by the reporter when a spec fails.
+	NamespacesToDump map[string]string
+	// CRsToDump are the CRs dumped by the reporter when a spec fails.
+	CRsToDump []k8sreporter.CRData
+	// DumpPrefix is prepended to the suite file the cluster is dumped to, such as hub_<suite file>. It defaults to the
+	// role without dashes, such as spoke2, and is empty for the cluster from KUBECONFIG, which is dumped to the suite
+	// file itself.
+	DumpPrefix string
+
+	// mutex guards the lazy creation of apiClient.
+	mutex     sync.Mutex
+	apiClient *clients.Settings
+}
+
+var (
+	registryMutex sync.RWMutex
+	registry      = make(map[Role]*Cluster)
+)
+
+// Register registers the cluster of kubeconfig for role, replacing any cluster previously registered for it. Its client
+// is only created once first requested.
+func Register(role Role, kubeconfig string) *Cluster {
+	return RegisterClient(role, kubeconfig, nil)
+}
+
+// RegisterClient registers the cluster of kubeconfig for role with an existing client, such as inittools.APIClient,
+// replacing any cluster previously registered for it. A nil apiClient is created lazily, like with Register.
+func RegisterClient(role Role, kubeconfig string, apiClient *clients.Settings) *Cluster {
+	klog.V(90).Infof("Registering %s cluster with kubeconfig %q", role, kubeconfig)
+
+	cluster := &Cluster{
+		Role:       role,
+		Kubeconfig: kubeconfig,
+		DumpPrefix: defaultDumpPrefix(role, kubeconfig),
+		apiClient:  apiClient,
+	}
+
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	registry[role] = cluster
+
+	return cluster
+}
+
+// Unregister removes the cluster registered for role, if any.
+func Unregister(role Role) {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	delete(registry, role)
+}
+
+// Get returns the cluster registered for role and whether there is one.
+func Get(role Role) (*Cluster, bool) {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	cluster, found := registry[role]
+
+	return cluster, found
+}
+
+// APIClient returns the client of the cluster registered for role. It returns nil if no cluster is registered for
+// role or its client cannot be created, mirroring clients.New.
+func APIClient(role Role) *clients.Settings {
+	cluster, found := Get(role)
+	if !found {
+		klog.V(90).Infof("No cluster registered for role %s", role)
+
+		return nil
+	}
+
+	apiClient, err := cluster.GetAPIClient()
+	if err != nil {
+		klog.V(90).Infof("Failed to get client of %s cluster: %v", role, err)
+
+		return nil
+	}
+
+	return apiClient
+}
+
+// SetReporter sets the namespaces and CRs dumped by the reporter for the cluster registered for role when a spec fails.
+// It reports whether a cluster is registered for role, so suites can set them for optional clusters unconditionally.
+func SetReporter(role Role, namespacesToDump map[string]string, crsToDump []k8sreporter.CRData) bool {
+	cluster, found := Get(role)
+	if !found {
+		return false
+	}
+
+	cluster.SetReporter(namespacesToDump, crsToDump)
+
+	return true
+}
+
+// All returns the registered clusters sorted by role.
+func All() []*Cluster {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	registered := make([]*Cluster, 0, len(registry))
+
+	for _, cluster := range registry {
+		registered = append(registered, cluster)
+	}
+
+	sort.Slice(registered, func(i, j int) bool {
+		return registered[i].Role < registered[j].Role
+	})
+
+	return registered
+}
+
+// GetAPIClient returns the client of cluster, creating it from its kubeconfig on first use. It satisfies the
+// APIClientGetter interface of the cluster package.
+func (cluster *Cluster) GetAPIClient() (*clients.Settings, error) {
+	cluster.mutex.Lock()
+	defer cluster.mutex.Unlock()
+
+	if cluster.apiClient != nil {
+		return cluster.apiClient, nil
+	}
+
+	klog.V(90).Infof("Creating client of %s cluster from kubeconfig %q", cluster.Role, cluster.Kubeconfig)
+
+	apiClient := clients.New(cluster.Kubeconfig)
+	if apiClient == nil {
+		return nil, fmt.Errorf("failed to create client of %s cluster from kubeconfig %q", cluster.Role, cluster.Kubeconfig)
+	}
+
+	cluster.apiClient = apiClient
+
+	return apiClient, nil
+}
+
+// SetReporter sets the namespaces and CRs dumped by the reporter for cluster when a spec fails. It returns cluster so
+// it can be chained after registering it.
+func (cluster *Cluster) SetReporter(namespacesToDump map[string]string, crsToDump []k8sreporter.CRData) *Cluster {
+	cluster.NamespacesToDump = namespacesToDump
+	cluster.CRsToDump = crsToDump
+
+	return cluster
+}
+
+// SetDumpPrefix sets the prefix of the suite file cluster is dumped to, so suites can keep the dump names they used
+// before the registry. An empty prefix dumps cluster to the suite file itself. It returns cluster so it can be chained
+// after registering it.
+func (cluster *Cluster) SetDumpPrefix(prefix string) *Cluster {
+	cluster.DumpPrefix = prefix
+
+	return cluster
+}
+
+// HasReporter reports whether any namespace or CR is dumped for cluster when a spec fails.
+func (cluster *Cluster) HasReporter() bool {
+	return len(cluster.NamespacesToDump) > 0 || len(cluster.CRsToDump) > 0
+}
+
+// Check verifies cluster is reachable and, unless versionConstraint is empty, that its desired OCP version satisfies
+// it, such as ">=4.16". Pre-release and build metadata of the version are ignored.
+func (cluster *Cluster) Check(versionConstraint string) error {
+	apiClient, err := cluster.GetAPIClient()
+	if err != nil {
+		return err
+	}
+
+	_, err = apiClient.K8sClient.Discovery().ServerVersion()
+	if err != nil {
+		return fmt.Errorf("failed to reach %s cluster: %w", cluster.Role, err)
+	}
+
+	if versionConstraint == "" {
+		return nil
+	}
+
+	clusterVersion, err := clusterversion.Pull(apiClient)
+	if err != nil {
+		return fmt.Errorf("failed to get clusterversion of %s cluster: %w", cluster.Role, err)
+	}
+
+	met, message := requires.CheckVersion(
+		fmt.Sprintf("%s cluster", cluster.Role), clusterVersion.Object.Status.Desired.Version, versionConstraint)
+	if !met {
+		return errors.New(message)
+	}
+
+	return nil
+}
+
+// CheckAll checks every registered cluster like Check and returns the first error.
+func CheckAll(versionConstraint string) error {
+	for _, cluster := range All() {
+		err := cluster.Check(versionConstraint)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// defaultDumpPrefix returns the dump prefix of a cluster registered for role with kubeconfig. Dashes are dropped from
+// the role, so spoke clusters keep the spoke2_<suite file> names suites used before the registry.
+func defaultDumpPrefix(role Role, kubeconfig string) string {
+	if kubeconfig == "" {
+		return ""
+	}
+
+	return strings.ReplaceAll(string(role), "-", "")
+}
@@ -0,0 +1,108 @@
+package clusters
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/openshift-kni/k8sreporter"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/fakecluster"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Cleanup(func() {
+		Unregister(Hub)
+		Unregister(Spoke(1))
+	})
+
+	hub := Register(Hub, "/kubeconfigs/hub").SetReporter(map[string]string{"open-cluster-management": ""}, nil)
+	spoke := RegisterClient(Spoke(1), "", nil)
+
+	registered, found := Get(Hub)
+	assert.True(t, found)
+	assert.Same(t, hub, registered)
+	assert.True(t, hub.HasReporter())
+	assert.False(t, spoke.HasReporter())
+
+	_, found = Get(Seed)
+	assert.False(t, found)
+
+	assert.Equal(t, []*Cluster{hub, spoke}, All())
+	assert.Equal(t, Role("spoke-1"), spoke.Role)
+	assert.Equal(t, "hub", hub.DumpPrefix)
+	assert.Empty(t, spoke.DumpPrefix)
+	assert.Equal(t, "spoke2", Register(Spoke(2), "/kubeconfigs/spoke2").DumpPrefix)
+
+	Unregister(Spoke(2))
+
+	Unregister(Spoke(1))
+	assert.Equal(t, []*Cluster{hub}, All())
+}
+
+func TestLazyClient(t *testing.T) {
+	t.Cleanup(func() { Unregister(Target) })
+
+	Register(Target, filepath.Join(t.TempDir(), "missing-kubeconfig"))
+
+	assert.Nil(t, APIClient(Target))
+	assert.Nil(t, APIClient(Seed))
+
+	cluster, _ := Get(Target)
+	err := cluster.Check("")
+	assert.ErrorContains(t, err, "failed to create client of target cluster")
+}
+
+func TestCheck(t *testing.T) {
+	t.Cleanup(func() { Unregister(Seed) })
+
+	clusterVersion := fakecluster.Object(configv1.GroupVersion.WithKind("ClusterVersion"), "", "version", map[string]any{
+		"status": map[string]any{"desired": map[string]any{"version": "4.20.0-rc.1"}},
+	})
+
+	apiClient, err := fakecluster.New(clusterVersion)
+	assert.NoError(t, err)
+
+	seed := RegisterClient(Seed, "", apiClient).SetReporter(nil, []k8sreporter.CRData{})
+	assert.False(t, seed.HasReporter())
+	assert.Same(t, apiClient, APIClient(Seed))
+
+	testCases := []struct {
+		name          string
+		constraint    string
+		expectedError string
+	}{
+		{
+			name:       "connectivity only",
+			constraint: "",
+		},
+		{
+			name:       "version satisfied",
+			constraint: ">=4.20",
+		},
+		{
+			name:          "version not satisfied",
+			constraint:    "<4.20",
+			expectedError: "seed cluster version 4.20.0-rc.1 does not satisfy <4.20",
+		},
+		{
+			name:          "invalid constraint",
+			constraint:    "four",
+			expectedError: "invalid version constraint",
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			err := seed.Check(testCase.constraint)
+
+			if testCase.expectedError == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, testCase.expectedError)
+			}
+		})
+	}
+
+	assert.NoError(t, CheckAll(">=4.19"))
+}
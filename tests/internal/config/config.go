@@ -1,69 +1,76 @@
 package config
 
 import (
+	_ "embed"
 	"fmt"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
-
-	"github.com/kelseyhightower/envconfig"
-	"gopkg.in/yaml.v2"
 )
 
-const (
-	// PathToDefaultParamsFile path to config file with default parameters.
-	PathToDefaultParamsFile = "./default.yaml"
-)
+// defaultParams keeps the default parameters, which are the first configuration layer.
+//
+//go:embed default.yaml
+var defaultParams []byte
 
 // GeneralConfig type keeps general configuration.
 type GeneralConfig struct {
-	ReportsDirAbsPath         string `yaml:"reports_dump_dir" envconfig:"ECO_REPORTS_DUMP_DIR"`
+	ReportsDirAbsPath         string `yaml:"reports_dump_dir" envconfig:"ECO_REPORTS_DUMP_DIR" eco_required:"true"`
 	VerboseLevel              string `yaml:"verbose_level" envconfig:"ECO_VERBOSE_LEVEL"`
 	DumpFailedTests           bool   `yaml:"dump_failed_tests" envconfig:"ECO_DUMP_FAILED_TESTS"`
 	EnableReport              bool   `yaml:"enable_report" envconfig:"ECO_ENABLE_REPORT"`
 	DryRun                    bool   `yaml:"dry_run" envconfig:"ECO_DRY_RUN"`
+	OfflineFixtures           string `yaml:"offline_fixtures" envconfig:"ECO_OFFLINE_FIXTURES"`
+	SnapshotRestore           bool   `yaml:"snapshot_restore" envconfig:"ECO_SNAPSHOT_RESTORE"`
 	SSHKeyPath                string `envconfig:"ECO_SSH_KEY_PATH"`
 	SSHUser                   string `yaml:"ssh_user" envconfig:"ECO_SSH_USER"`
-	KubernetesRolePrefix      string `yaml:"kubernetes_role_prefix" envconfig:"ECO_KUBERNETES_ROLE_PREFIX"`
-	WorkerLabelEnvVar         string `yaml:"worker_label" envconfig:"ECO_WORKER_LABEL"`
+	KubernetesRolePrefix      string `yaml:"kubernetes_role_prefix" envconfig:"ECO_KUBERNETES_ROLE_PREFIX" eco_required:"true"`
+	WorkerLabelEnvVar         string `yaml:"worker_label" envconfig:"ECO_WORKER_LABEL" eco_required:"true"`
 	WorkerLabel               string
-	ControlPlaneLabel         string `yaml:"control_plane_label" envconfig:"ECO_CONTROL_PLANE_LABEL"`
+	ControlPlaneLabel         string `yaml:"control_plane_label" envconfig:"ECO_CONTROL_PLANE_LABEL" eco_required:"true"`
 	TCPrefix                  string `yaml:"tc_prefix" envconfig:"ECO_TC_PREFIX"`
-	MCONamespace              string `yaml:"mco_namespace" envconfig:"ECO_MCO_NAMESPACE"`
+	MCONamespace              string `yaml:"mco_namespace" envconfig:"ECO_MCO_NAMESPACE" eco_required:"true"`
 	LoggingOperatorNamespace  string `yaml:"logging_operator_namespace" envconfig:"ECO_LOGGING_OPERATOR_NAMESPACE"`
 	MCOConfigDaemonName       string `yaml:"mco_config_daemon_name" envconfig:"ECO_MCO_CONFIG_DAEMON_NAME"`
 	SriovOperatorNamespace    string `yaml:"sriov_operator_namespace" envconfig:"ECO_SRIOV_OPERATOR_NAMESPACE"`
 	NMStateOperatorNamespace  string `yaml:"nmstate_operator_namespace" envconfig:"ECO_NMSTATE_OPERATOR_NAMESPACE"`
 	SriovFecOperatorNamespace string `yaml:"sriov_fec_operator_namespace" envconfig:"ECO_SRIOV_FEC_OPERATOR_NAMESPACE"`
+	ArtifactSink              string `yaml:"artifact_sink" envconfig:"ECO_ARTIFACT_SINK"`
+	ArtifactSinkPath          string `yaml:"artifact_sink_path" envconfig:"ECO_ARTIFACT_SINK_PATH"`
+	ArtifactS3Endpoint        string `yaml:"artifact_s3_endpoint" envconfig:"ECO_ARTIFACT_S3_ENDPOINT"`
+	ArtifactS3Region          string `yaml:"artifact_s3_region" envconfig:"ECO_ARTIFACT_S3_REGION"`
+	ArtifactS3Bucket          string `yaml:"artifact_s3_bucket" envconfig:"ECO_ARTIFACT_S3_BUCKET"`
+	ArtifactS3Prefix          string `yaml:"artifact_s3_prefix" envconfig:"ECO_ARTIFACT_S3_PREFIX"`
+	ArtifactS3AccessKeyID     string `yaml:"artifact_s3_access_key_id" envconfig:"ECO_ARTIFACT_S3_ACCESS_KEY_ID"`
+	ArtifactS3SecretAccessKey string `yaml:"artifact_s3_secret_access_key" envconfig:"ECO_ARTIFACT_S3_SECRET_ACCESS_KEY" eco_secret:"true"` //nolint:lll
+	ParallelLockDir           string `yaml:"parallel_lock_dir" envconfig:"ECO_PARALLEL_LOCK_DIR"`
+	NodeExecutor              string `yaml:"node_executor" envconfig:"ECO_NODE_EXECUTOR"`
+	NodeExecutorImage         string `yaml:"node_executor_image" envconfig:"ECO_NODE_EXECUTOR_IMAGE"`
+	NodeExecutorAttempts      uint   `yaml:"node_executor_attempts" envconfig:"ECO_NODE_EXECUTOR_ATTEMPTS"`
 	WorkerLabelMap            map[string]string
 	ControlPlaneLabelMap      map[string]string
+
+	sources Sources
 }
 
-// NewConfig returns instance of GeneralConfig config type.
+// NewConfig returns instance of GeneralConfig config type. The embedded defaults are overridden by the user config
+// file from ECO_CONFIG_FILE, then by the profiles listed in ECO_CONFIG_PROFILES and finally by environment variables.
 func NewConfig() *GeneralConfig {
 	log.Print("Creating new GeneralConfig struct")
 
 	var conf GeneralConfig
 
-	_, filename, _, _ := runtime.Caller(0)
-	baseDir := filepath.Dir(filename)
-	confFile := filepath.Join(baseDir, PathToDefaultParamsFile)
-
-	err := readFile(&conf, confFile)
+	sources, err := loadLayers(&conf, defaultParams, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers: %v", err)
 
 		return nil
 	}
 
-	err = readEnv(&conf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	conf.sources = sources
+	conf.setLabels()
 
 	err = deployReportDir(conf.ReportsDirAbsPath)
 	if err != nil {
@@ -111,38 +118,35 @@ func (cfg *GeneralConfig) GetDumpFailedTestReportLocation(file string) string {
 	return ""
 }
 
-func readFile(cfg *GeneralConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
+// Sources returns the layer which set each configuration field, including the fields recorded by suite configs.
+func (cfg *GeneralConfig) Sources() Sources {
+	return maps.Clone(cfg.sources)
+}
 
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
+// Source returns the layer which set the named field. It returns an empty layer if no layer set the field.
+func (cfg *GeneralConfig) Source(field string) Layer {
+	return cfg.sources[field]
+}
 
-	decoder := yaml.NewDecoder(openedCfgFile)
+// RecordSources merges the sources of a suite config embedding the GeneralConfig, so that Sources covers the suite
+// specific fields as well.
+func (cfg *GeneralConfig) RecordSources(sources Sources) {
+	if cfg == nil {
+		return
+	}
 
-	err = decoder.Decode(&cfg)
-	if err != nil {
-		return err
+	if cfg.sources == nil {
+		cfg.sources = make(Sources)
 	}
 
-	return nil
+	maps.Copy(cfg.sources, sources)
 }
 
-func readEnv(cfg *GeneralConfig) error {
-	err := envconfig.Process("", cfg)
-	if err != nil {
-		return err
-	}
-
+func (cfg *GeneralConfig) setLabels() {
 	cfg.WorkerLabel = fmt.Sprintf("%s/%s", cfg.KubernetesRolePrefix, cfg.WorkerLabelEnvVar)
 	cfg.ControlPlaneLabel = fmt.Sprintf("%s/%s", cfg.KubernetesRolePrefix, cfg.ControlPlaneLabel)
 	cfg.WorkerLabelMap = map[string]string{cfg.WorkerLabel: ""}
 	cfg.ControlPlaneLabelMap = map[string]string{cfg.ControlPlaneLabel: ""}
-
-	return nil
 }
 
 func deployReportDir(dirName string) error {
@@ -1 +1,284 @@
 package config
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testUserFile = `
+ssh_user: file-user
+tc_prefix: FILE-
+suite_image: file-image
+profiles:
+  lab-a:
+    ssh_user: lab-a-user
+    suite_image: lab-a-image
+  sno-ipv6:
+    mco_namespace: sno-mco
+`
+
+type testSuiteConfig struct {
+	*GeneralConfig
+
+	SuiteImage   string `yaml:"suite_image" envconfig:"TEST_SUITE_IMAGE"`
+	SuiteTimeout string `yaml:"suite_timeout" envconfig:"TEST_SUITE_TIMEOUT"`
+}
+
+func TestNewConfigLayers(t *testing.T) {
+	testCases := []struct {
+		name             string
+		profiles         string
+		env              map[string]string
+		expectedSSHUser  string
+		expectedTCPrefix string
+		expectedMCO      string
+		expectedSources  Sources
+	}{
+		{
+			name:             "file only",
+			expectedSSHUser:  "file-user",
+			expectedTCPrefix: "FILE-",
+			expectedMCO:      "openshift-machine-config-operator",
+			expectedSources: Sources{
+				"SSHUser":      LayerFile,
+				"TCPrefix":     LayerFile,
+				"MCONamespace": LayerDefault,
+			},
+		},
+		{
+			name:             "stacked profiles",
+			profiles:         "lab-a, sno-ipv6",
+			expectedSSHUser:  "lab-a-user",
+			expectedTCPrefix: "FILE-",
+			expectedMCO:      "sno-mco",
+			expectedSources: Sources{
+				"SSHUser":      LayerProfile("lab-a"),
+				"TCPrefix":     LayerFile,
+				"MCONamespace": LayerProfile("sno-ipv6"),
+			},
+		},
+		{
+			name:             "env over profile",
+			profiles:         "lab-a",
+			env:              map[string]string{"ECO_SSH_USER": "env-user"},
+			expectedSSHUser:  "env-user",
+			expectedTCPrefix: "FILE-",
+			expectedMCO:      "openshift-machine-config-operator",
+			expectedSources: Sources{
+				"SSHUser":      LayerEnv,
+				"TCPrefix":     LayerFile,
+				"MCONamespace": LayerDefault,
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			setUserFile(t, testUserFile)
+			t.Setenv(EnvConfigProfiles, testCase.profiles)
+
+			for key, value := range testCase.env {
+				t.Setenv(key, value)
+			}
+
+			conf := NewConfig()
+			assert.NotNil(t, conf)
+
+			assert.Equal(t, testCase.expectedSSHUser, conf.SSHUser)
+			assert.Equal(t, testCase.expectedTCPrefix, conf.TCPrefix)
+			assert.Equal(t, testCase.expectedMCO, conf.MCONamespace)
+			assert.Equal(t, "node-role.kubernetes.io/worker", conf.WorkerLabel)
+
+			for field, layer := range testCase.expectedSources {
+				assert.Equal(t, layer, conf.Source(field), "unexpected source of %s", field)
+			}
+		})
+	}
+}
+
+func TestNewConfigUnknownProfile(t *testing.T) {
+	setUserFile(t, testUserFile)
+	t.Setenv(EnvConfigProfiles, "lab-b")
+
+	assert.Nil(t, NewConfig())
+}
+
+func TestLoadLayersSuiteConfig(t *testing.T) {
+	setUserFile(t, testUserFile)
+	t.Setenv(EnvConfigProfiles, "lab-a")
+	t.Setenv("TEST_SUITE_TIMEOUT", "10m")
+
+	defaultsFile := filepath.Join(t.TempDir(), "default.yaml")
+	err := os.WriteFile(defaultsFile, []byte("suite_image: default-image\nsuite_timeout: 5m\n"), 0644)
+	assert.Nil(t, err)
+
+	var suiteConfig testSuiteConfig
+
+	suiteConfig.GeneralConfig = NewConfig()
+	assert.NotNil(t, suiteConfig.GeneralConfig)
+
+	sources, err := LoadLayers(&suiteConfig, defaultsFile, "")
+	assert.Nil(t, err)
+
+	suiteConfig.RecordSources(sources)
+
+	assert.Equal(t, "lab-a-image", suiteConfig.SuiteImage)
+	assert.Equal(t, "10m", suiteConfig.SuiteTimeout)
+	assert.Equal(t, LayerProfile("lab-a"), suiteConfig.Source("SuiteImage"))
+	assert.Equal(t, LayerEnv, suiteConfig.Source("SuiteTimeout"))
+	assert.Equal(t, LayerProfile("lab-a"), suiteConfig.Source("SSHUser"))
+}
+
+func setUserFile(t *testing.T, content string) {
+	t.Helper()
+
+	userFile := filepath.Join(t.TempDir(), "eco.yaml")
+	err := os.WriteFile(userFile, []byte(content), 0644)
+	assert.Nil(t, err)
+
+	t.Setenv(EnvConfigFile, userFile)
+	t.Setenv("ECO_REPORTS_DUMP_DIR", t.TempDir())
+}
+
+type testDuration struct {
+	Seconds int
+}
+
+func (duration *testDuration) Decode(value string) error {
+	seconds, err := strconv.Atoi(strings.TrimSuffix(value, "s"))
+	if err != nil {
+		return err
+	}
+
+	duration.Seconds = seconds
+
+	return nil
+}
+
+type testInspectConfig struct {
+	Image    string       `yaml:"image" envconfig:"TEST_INSPECT_IMAGE" eco_required:"true"`
+	Password string       `yaml:"password" envconfig:"TEST_INSPECT_PASSWORD" eco_secret:"true"`
+	Timeout  testDuration `envconfig:"TEST_INSPECT_TIMEOUT"`
+	Nested   struct {
+		Name string `yaml:"name"`
+	} `yaml:"nested"`
+}
+
+func TestInspect(t *testing.T) {
+	setUserFile(t, "password: file-password\nnested:\n  name: file-name\n")
+
+	var defaults, conf testInspectConfig
+
+	defaults.Image = "default-image"
+
+	sources, err := LoadLayers(&conf, "", "")
+	assert.Nil(t, err)
+
+	reports := make(map[string]FieldReport)
+
+	for _, report := range Inspect(&conf, &defaults) {
+		reports[report.Name] = report
+	}
+
+	assert.Len(t, reports, 4)
+	assert.Equal(t, "default-image", reports["Image"].Default)
+	assert.Equal(t, "TEST_INSPECT_IMAGE", reports["Image"].EnvVar)
+	assert.True(t, reports["Image"].Required)
+	assert.True(t, reports["Password"].Secret)
+	assert.Equal(t, maskedValue, reports["Password"].Value)
+	assert.Equal(t, "", reports["Password"].Default)
+	assert.Equal(t, "nested.name", reports["Nested.Name"].YAMLKey)
+	assert.Equal(t, "file-name", reports["Nested.Name"].Value)
+	assert.Equal(t, LayerFile, sources["Nested.Name"])
+}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name           string
+		userFile       string
+		profiles       string
+		env            map[string]string
+		sharedKeys     []string
+		expectedErrors []string
+	}{
+		{
+			name:     "valid",
+			userFile: "image: file-image\n",
+		},
+		{
+			name:           "required empty",
+			userFile:       "password: secret\n",
+			expectedErrors: []string{"required field Image is empty"},
+		},
+		{
+			name:           "unknown keys",
+			userFile:       "image: file-image\nimgae: typo\nnested:\n  nmae: typo\n",
+			expectedErrors: []string{"unknown key imgae in file layer", "unknown key nested.nmae in file layer"},
+		},
+		{
+			name:           "unknown profile key",
+			userFile:       "image: file-image\nprofiles:\n  lab:\n    other: value\n",
+			profiles:       "lab,missing",
+			expectedErrors: []string{"profile missing not found", "unknown key other in profile:lab layer"},
+		},
+		{
+			name: "key shared with other suite",
+			userFile: "image: file-image\n" +
+				"profiles:\n  lab-a:\n    ptpOperatorNamespace: ptp\n    other:\n      key: value\n",
+			profiles:   "lab-a",
+			sharedKeys: []string{"ptpOperatorNamespace", "other.key"},
+		},
+		{
+			name:           "undecodable value",
+			userFile:       "image: [a, b]\n",
+			expectedErrors: []string{"failed to decode file layer", "required field Image is empty"},
+		},
+		{
+			name:           "invalid env",
+			userFile:       "image: file-image\n",
+			env:            map[string]string{"TEST_INSPECT_TIMEOUT": "ten"},
+			expectedErrors: []string{"invalid value for field Timeout"},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			setUserFile(t, testCase.userFile)
+			t.Setenv(EnvConfigProfiles, testCase.profiles)
+
+			for key, value := range testCase.env {
+				t.Setenv(key, value)
+			}
+
+			var conf testInspectConfig
+
+			// Errors from loading are expected for some cases and are reported again by Validate.
+			_, _ = LoadLayers(&conf, "", "")
+
+			errs := Validate(&conf, testCase.sharedKeys...)
+			assert.Len(t, errs, len(testCase.expectedErrors))
+
+			for index, err := range errs {
+				if index < len(testCase.expectedErrors) {
+					assert.Contains(t, err.Error(), testCase.expectedErrors[index])
+				}
+			}
+		})
+	}
+}
+
+func TestMissingRequired(t *testing.T) {
+	var conf testInspectConfig
+
+	assert.Equal(t, []string{"Image (set yaml key image or env var TEST_INSPECT_IMAGE)"}, MissingRequired(&conf))
+
+	conf.Image = "image"
+	assert.Empty(t, MissingRequired(&conf))
+
+	assert.Empty(t, MissingRequired((*testInspectConfig)(nil)))
+}
@@ -0,0 +1,449 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// RequiredTag is the struct tag marking configuration fields which must not be empty once all layers are
+	// applied. The required tag of envconfig only considers environment variables, so it cannot be used for fields
+	// which are usually set from yaml.
+	RequiredTag = "eco_required"
+	// SecretTag is the struct tag marking configuration fields which hold credentials. Their values are masked
+	// whenever the config is printed.
+	SecretTag = "eco_secret"
+
+	maskedValue = "******"
+)
+
+// FieldReport describes a single configuration field and the layer its effective value comes from.
+type FieldReport struct {
+	Name     string
+	YAMLKey  string
+	EnvVar   string
+	Type     string
+	Default  string
+	Value    string
+	Source   Layer
+	Secret   bool
+	Required bool
+}
+
+// Inspect returns a report for every field of cfg which is tagged for yaml or envconfig. The default values are
+// taken from defaults, which must have the same type as cfg and be loaded without the user layers. Values of secret
+// fields are masked.
+func Inspect(cfg, defaults any) []FieldReport {
+	defaultValues := make(map[string]string)
+
+	walkFields(defaults, "", func(field fieldInfo) {
+		defaultValues[field.Name] = formatValue(field.Value)
+	})
+
+	var sources Sources
+
+	if sourcer, ok := cfg.(interface{ Sources() Sources }); ok {
+		sources = sourcer.Sources()
+	}
+
+	var reports []FieldReport
+
+	walkFields(cfg, "", func(field fieldInfo) {
+		if !isConfigField(field) {
+			return
+		}
+
+		report := FieldReport{
+			Name:     field.Name,
+			EnvVar:   field.EnvAlt,
+			Type:     field.Field.Type.String(),
+			Default:  defaultValues[field.Name],
+			Value:    formatValue(field.Value),
+			Source:   sources[field.Name],
+			Secret:   isTrue(field.Field.Tag.Get(SecretTag)),
+			Required: isTrue(field.Field.Tag.Get(RequiredTag)),
+		}
+
+		if field.Field.Tag.Get("yaml") != "" && field.LayerPath != nil {
+			report.YAMLKey = strings.Join(field.LayerPath, ".")
+		}
+
+		if report.EnvVar == "" {
+			report.EnvVar = field.EnvKey
+		}
+
+		if report.Secret {
+			report.Default = maskValue(report.Default)
+			report.Value = maskValue(report.Value)
+		}
+
+		reports = append(reports, report)
+	})
+
+	return reports
+}
+
+// Validate checks cfg together with the user config file, the selected profiles and the environment variables. It
+// returns an error for every unknown yaml key, every value which cannot be decoded and every required field which is
+// empty. A key is unknown if it is neither a key of cfg nor one of the dotted sharedKeys, so a user file shared between
+// suites can be validated by passing the keys of the other suites.
+func Validate(cfg any, sharedKeys ...string) []error {
+	return validate(cfg, true, sharedKeys)
+}
+
+// MissingRequired returns the required fields of cfg which are empty, each with how to set it. Unlike Validate, it
+// only looks at cfg, so it can check a config which was already loaded.
+func MissingRequired(cfg any) []string {
+	var missing []string
+
+	walkFields(cfg, "", func(field fieldInfo) {
+		if isTrue(field.Field.Tag.Get(RequiredTag)) && field.Value.IsZero() {
+			missing = append(missing, fmt.Sprintf("%s (set %s)", field.Name, describeField(field)))
+		}
+	})
+
+	return missing
+}
+
+// validate is Validate with the required check optional, since required fields are always empty when the config
+// could not be loaded.
+func validate(cfg any, checkRequired bool, sharedKeys []string) []error {
+	var errs []error
+
+	userFile, userMap, err := readUserFile()
+	if err != nil {
+		return []error{err}
+	}
+
+	profiles, err := extractProfiles(userFile, userMap)
+	if err != nil {
+		return []error{err}
+	}
+
+	layers := map[Layer]map[interface{}]interface{}{LayerFile: userMap}
+
+	for _, name := range profileNames() {
+		profile, found := profiles[name]
+		if !found {
+			errs = append(errs, fmt.Errorf("profile %s not found in config file %s", name, userFile))
+
+			continue
+		}
+
+		layers[LayerProfile(name)] = profile
+	}
+
+	known := knownLayerKeys(cfg)
+	addKnownKeys(known, sharedKeys)
+
+	for _, name := range sortedLayers(layers) {
+		for _, key := range unknownKeys(layers[name], known, nil) {
+			errs = append(errs, fmt.Errorf("unknown key %s in %s layer", key, name))
+		}
+
+		errs = append(errs, decodeLayer(cfg, name, layers[name])...)
+	}
+
+	walkFields(cfg, "", func(field fieldInfo) {
+		if err := checkEnvValue(field); err != nil {
+			errs = append(errs, err)
+		}
+
+		if checkRequired && isTrue(field.Field.Tag.Get(RequiredTag)) && field.Value.IsZero() {
+			errs = append(errs, fmt.Errorf("required field %s is empty, set %s", field.Name, describeField(field)))
+		}
+	})
+
+	return errs
+}
+
+// RunInspection loads the config with newConfig, prints all of its fields to stdout and validates it, accepting the
+// keys of the config and sharedKeys. Problems are printed to stderr. It returns the exit code of the inspection, which
+// is non-zero when the config cannot be loaded or is invalid.
+func RunInspection[T any](newConfig func() *T, stdout, stderr io.Writer, sharedKeys ...string) int {
+	defaults := loadDefaults(newConfig)
+	if defaults == nil {
+		defaults = newEmpty[T]()
+	}
+
+	exitCode := 0
+
+	cfg := newConfig()
+	if cfg == nil {
+		_, _ = fmt.Fprintln(stderr, "failed to load config, see the log above for details")
+
+		cfg = newEmpty[T]()
+		exitCode = 1
+	}
+
+	writer := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(writer, "FIELD\tYAML KEY\tENV\tTYPE\tDEFAULT\tVALUE\tSOURCE")
+
+	for _, report := range Inspect(cfg, defaults) {
+		_, _ = fmt.Fprintf(writer, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			report.Name, orDash(report.YAMLKey), orDash(report.EnvVar), report.Type,
+			orDash(report.Default), orDash(report.Value), orDash(string(report.Source)))
+	}
+
+	_ = writer.Flush()
+
+	for _, err := range validate(cfg, exitCode == 0, sharedKeys) {
+		_, _ = fmt.Fprintf(stderr, "error: %v\n", err)
+
+		exitCode = 1
+	}
+
+	return exitCode
+}
+
+// loadDefaults calls newConfig with all ECO_ environment variables unset, so only the defaults are loaded.
+func loadDefaults[T any](newConfig func() *T) *T {
+	saved := make(map[string]string)
+
+	for _, env := range os.Environ() {
+		key, value, _ := strings.Cut(env, "=")
+		if strings.HasPrefix(key, "ECO_") {
+			saved[key] = value
+			_ = os.Unsetenv(key)
+		}
+	}
+
+	defer func() {
+		for key, value := range saved {
+			_ = os.Setenv(key, value)
+		}
+	}()
+
+	return newConfig()
+}
+
+// newEmpty returns a new T with all embedded struct pointers allocated, so it can be walked like a loaded config.
+func newEmpty[T any]() *T {
+	empty := new(T)
+	allocateEmbedded(reflect.ValueOf(empty).Elem())
+
+	return empty
+}
+
+func allocateEmbedded(value reflect.Value) {
+	for index := 0; index < value.NumField(); index++ {
+		structField := value.Type().Field(index)
+		fieldValue := value.Field(index)
+
+		if !structField.Anonymous || !fieldValue.CanSet() {
+			continue
+		}
+
+		if fieldValue.Kind() == reflect.Ptr && fieldValue.Type().Elem().Kind() == reflect.Struct {
+			fieldValue.Set(reflect.New(fieldValue.Type().Elem()))
+			fieldValue = fieldValue.Elem()
+		}
+
+		if fieldValue.Kind() == reflect.Struct {
+			allocateEmbedded(fieldValue)
+		}
+	}
+}
+
+// knownLayerKeys returns all keys which may appear in a layer file for cfg, including the intermediate keys of
+// nested structs.
+func knownLayerKeys(cfg any) map[string]bool {
+	known := make(map[string]bool)
+
+	walkFields(cfg, "", func(field fieldInfo) {
+		addKnownPath(known, field.LayerPath)
+	})
+
+	return known
+}
+
+// addKnownKeys adds the dotted keys and their intermediate keys to known.
+func addKnownKeys(known map[string]bool, keys []string) {
+	for _, key := range keys {
+		addKnownPath(known, strings.Split(key, "."))
+	}
+}
+
+// addKnownPath adds every prefix of path to known. Prefixes of nested keys are also added with a trailing dot, which
+// marks maps whose keys are checked.
+func addKnownPath(known map[string]bool, path []string) {
+	for index := range path {
+		key := strings.Join(path[:index+1], ".")
+		known[key] = true
+
+		if index < len(path)-1 {
+			known[key+"."] = true
+		}
+	}
+}
+
+// unknownKeys returns the dotted keys of layer which are not known. Maps are only descended into when their key is
+// the prefix of a known key, so values of map fields are not checked.
+func unknownKeys(layer map[interface{}]interface{}, known map[string]bool, prefix []string) []string {
+	var unknown []string
+
+	for rawKey, value := range layer {
+		path := append(append([]string{}, prefix...), fmt.Sprint(rawKey))
+		key := strings.Join(path, ".")
+
+		if !known[key] {
+			unknown = append(unknown, key)
+
+			continue
+		}
+
+		nested, isMap := value.(map[interface{}]interface{})
+		if isMap && known[key+"."] {
+			unknown = append(unknown, unknownKeys(nested, known, path)...)
+		}
+	}
+
+	sort.Strings(unknown)
+
+	return unknown
+}
+
+// decodeLayer decodes the layer into a new instance of cfg and of every config embedded in it, the same way the
+// config constructors do, and returns the decoding errors.
+func decodeLayer(cfg any, name Layer, layer map[interface{}]interface{}) []error {
+	if len(layer) == 0 {
+		return nil
+	}
+
+	content, err := yaml.Marshal(layer)
+	if err != nil {
+		return []error{fmt.Errorf("failed to marshal %s layer: %w", name, err)}
+	}
+
+	var errs []error
+
+	for _, configType := range embeddedTypes(reflect.TypeOf(cfg)) {
+		err = yaml.Unmarshal(content, reflect.New(configType).Interface())
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to decode %s layer into %s: %w", name, configType, err))
+		}
+	}
+
+	return errs
+}
+
+// embeddedTypes returns the struct type behind configType and the types of all structs embedded in it.
+func embeddedTypes(configType reflect.Type) []reflect.Type {
+	for configType.Kind() == reflect.Ptr {
+		configType = configType.Elem()
+	}
+
+	if configType.Kind() != reflect.Struct {
+		return nil
+	}
+
+	types := []reflect.Type{configType}
+
+	for index := 0; index < configType.NumField(); index++ {
+		if structField := configType.Field(index); structField.Anonymous && structField.IsExported() {
+			types = append(types, embeddedTypes(structField.Type)...)
+		}
+	}
+
+	return types
+}
+
+// checkEnvValue decodes the environment variable of the field using envconfig on a single field struct, so custom
+// Decode methods are checked the same way they are applied.
+func checkEnvValue(field fieldInfo) error {
+	if !field.envSet() {
+		return nil
+	}
+
+	tag := fmt.Sprintf(`envconfig:"%s"`, field.EnvKey)
+	if _, found := os.LookupEnv(field.EnvKey); !found {
+		tag = fmt.Sprintf(`envconfig:"%s"`, field.EnvAlt)
+	}
+
+	probeType := reflect.StructOf([]reflect.StructField{{
+		Name: "Value",
+		Type: field.Field.Type,
+		Tag:  reflect.StructTag(tag),
+	}})
+
+	err := envconfig.Process("", reflect.New(probeType).Interface())
+	if err != nil {
+		return fmt.Errorf("invalid value for field %s: %w", field.Name, err)
+	}
+
+	return nil
+}
+
+func isConfigField(field fieldInfo) bool {
+	return field.Field.Tag.Get("yaml") != "" || field.Field.Tag.Get("envconfig") != ""
+}
+
+func describeField(field fieldInfo) string {
+	var setters []string
+
+	if field.Field.Tag.Get("yaml") != "" && field.LayerPath != nil {
+		setters = append(setters, "yaml key "+strings.Join(field.LayerPath, "."))
+	}
+
+	if field.EnvAlt != "" {
+		setters = append(setters, "env var "+field.EnvAlt)
+	} else if field.EnvKey != "" {
+		setters = append(setters, "env var "+field.EnvKey)
+	}
+
+	return strings.Join(setters, " or ")
+}
+
+func formatValue(value reflect.Value) string {
+	for value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface {
+		if value.IsNil() {
+			return ""
+		}
+
+		value = value.Elem()
+	}
+
+	if !value.IsValid() || !value.CanInterface() {
+		return ""
+	}
+
+	return fmt.Sprintf("%v", value.Interface())
+}
+
+func maskValue(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return maskedValue
+}
+
+func orDash(value string) string {
+	if value == "" {
+		return "-"
+	}
+
+	return value
+}
+
+func sortedLayers(layers map[Layer]map[interface{}]interface{}) []Layer {
+	names := make([]Layer, 0, len(layers))
+
+	for name := range layers {
+		names = append(names, name)
+	}
+
+	sort.Slice(names, func(i, j int) bool {
+		return names[i] < names[j]
+	})
+
+	return names
+}
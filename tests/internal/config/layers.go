@@ -0,0 +1,431 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/kelseyhightower/envconfig"
+	"gopkg.in/yaml.v2"
+)
+
+const (
+	// EnvConfigFile is the environment variable holding the path to the user config file.
+	EnvConfigFile = "ECO_CONFIG_FILE"
+	// EnvConfigProfiles is the environment variable holding the comma-separated list of profiles to apply.
+	EnvConfigProfiles = "ECO_CONFIG_PROFILES"
+	// ProfilesKey is the top-level key of the user config file holding the named profiles.
+	ProfilesKey = "profiles"
+)
+
+// Layer identifies the configuration layer which set a field.
+type Layer string
+
+const (
+	// LayerDefault marks fields set by the defaults file shipped with the package.
+	LayerDefault Layer = "default"
+	// LayerFile marks fields set by the user config file referenced by ECO_CONFIG_FILE.
+	LayerFile Layer = "file"
+	// LayerEnv marks fields set by environment variables.
+	LayerEnv Layer = "env"
+
+	layerProfilePrefix = "profile:"
+)
+
+// LayerProfile returns the layer of the named profile.
+func LayerProfile(name string) Layer {
+	return Layer(layerProfilePrefix + name)
+}
+
+// Sources maps the name of each configuration field to the layer which set it last. Fields of embedded structs use
+// their promoted name, fields of nested structs are joined with a dot.
+type Sources map[string]Layer
+
+// LoadLayers decodes all configuration layers into cfg, which must be a pointer to a struct with yaml and envconfig
+// tags. The layers are applied in order: the defaults file, the user file from ECO_CONFIG_FILE, the profiles listed
+// in ECO_CONFIG_PROFILES and finally the environment variables processed with envPrefix. An empty defaultsFile skips
+// the defaults layer. It returns the layer which set each field.
+func LoadLayers(cfg any, defaultsFile string, envPrefix string) (Sources, error) {
+	var defaults []byte
+
+	if defaultsFile != "" {
+		var err error
+
+		defaults, err = os.ReadFile(defaultsFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return loadLayers(cfg, defaults, envPrefix)
+}
+
+func loadLayers(cfg any, defaults []byte, envPrefix string) (Sources, error) {
+	sources := make(Sources)
+
+	defaultsMap, err := parseLayer(defaults)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse defaults: %w", err)
+	}
+
+	err = applyLayer(cfg, defaultsMap, LayerDefault, sources)
+	if err != nil {
+		return nil, err
+	}
+
+	userFile, userMap, err := readUserFile()
+	if err != nil {
+		return nil, err
+	}
+
+	profiles, err := extractProfiles(userFile, userMap)
+	if err != nil {
+		return nil, err
+	}
+
+	err = applyLayer(cfg, userMap, LayerFile, sources)
+	if err != nil {
+		return nil, fmt.Errorf("failed to apply config file %s: %w", userFile, err)
+	}
+
+	for _, name := range profileNames() {
+		profile, found := profiles[name]
+		if !found {
+			return nil, fmt.Errorf("profile %s not found in config file %s", name, userFile)
+		}
+
+		err = applyLayer(cfg, profile, LayerProfile(name), sources)
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply profile %s: %w", name, err)
+		}
+	}
+
+	err = envconfig.Process(envPrefix, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	walkFields(cfg, envPrefix, func(field fieldInfo) {
+		if field.envSet() {
+			sources[field.Name] = LayerEnv
+		}
+	})
+
+	return sources, nil
+}
+
+// readUserFile reads the user config file referenced by ECO_CONFIG_FILE. It returns an empty map when the variable
+// is not set.
+func readUserFile() (string, map[interface{}]interface{}, error) {
+	userFile := os.Getenv(EnvConfigFile)
+	if userFile == "" {
+		return "", nil, nil
+	}
+
+	content, err := os.ReadFile(userFile)
+	if err != nil {
+		return userFile, nil, fmt.Errorf("failed to read config file %s: %w", userFile, err)
+	}
+
+	userMap, err := parseLayer(content)
+	if err != nil {
+		return userFile, nil, fmt.Errorf("failed to parse config file %s: %w", userFile, err)
+	}
+
+	return userFile, userMap, nil
+}
+
+// extractProfiles removes the profiles section from the user file content and returns it keyed by profile name.
+func extractProfiles(userFile string, userMap map[interface{}]interface{}) (
+	map[string]map[interface{}]interface{}, error) {
+	profiles := make(map[string]map[interface{}]interface{})
+
+	rawProfiles, found := userMap[ProfilesKey]
+	if !found {
+		return profiles, nil
+	}
+
+	delete(userMap, ProfilesKey)
+
+	profilesMap, ok := rawProfiles.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("%s in config file %s must be a map of profile names", ProfilesKey, userFile)
+	}
+
+	for name, rawProfile := range profilesMap {
+		if rawProfile == nil {
+			profiles[fmt.Sprint(name)] = nil
+
+			continue
+		}
+
+		profile, ok := rawProfile.(map[interface{}]interface{})
+		if !ok {
+			return nil, fmt.Errorf("profile %v in config file %s must be a map", name, userFile)
+		}
+
+		profiles[fmt.Sprint(name)] = profile
+	}
+
+	return profiles, nil
+}
+
+// profileNames returns the profiles listed in ECO_CONFIG_PROFILES in the order they should be applied.
+func profileNames() []string {
+	var names []string
+
+	for _, name := range strings.Split(os.Getenv(EnvConfigProfiles), ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+func parseLayer(content []byte) (map[interface{}]interface{}, error) {
+	layer := make(map[interface{}]interface{})
+
+	err := yaml.Unmarshal(content, &layer)
+	if err != nil {
+		return nil, err
+	}
+
+	return layer, nil
+}
+
+// applyLayer decodes the layer into cfg and records the layer as the source of every field it contains.
+func applyLayer(cfg any, layer map[interface{}]interface{}, name Layer, sources Sources) error {
+	if len(layer) == 0 {
+		return nil
+	}
+
+	content, err := yaml.Marshal(layer)
+	if err != nil {
+		return err
+	}
+
+	err = yaml.Unmarshal(content, cfg)
+	if err != nil {
+		return err
+	}
+
+	walkFields(cfg, "", func(field fieldInfo) {
+		if field.YAMLPath != nil && hasYAMLPath(layer, field.YAMLPath) {
+			sources[field.Name] = name
+		}
+	})
+
+	return nil
+}
+
+func hasYAMLPath(layer map[interface{}]interface{}, path []string) bool {
+	for index, key := range path {
+		value, found := layer[key]
+		if !found {
+			return false
+		}
+
+		if index == len(path)-1 {
+			return true
+		}
+
+		if layer, found = value.(map[interface{}]interface{}); !found {
+			return false
+		}
+	}
+
+	return false
+}
+
+// fieldInfo describes a single configuration field as seen by both the yaml decoder and envconfig.
+type fieldInfo struct {
+	// Name is the promoted field name, nested struct fields are joined with a dot.
+	Name string
+	// YAMLPath is the list of yaml keys leading to the field. It is nil when the field cannot be set from yaml.
+	YAMLPath []string
+	// LayerPath is the list of keys leading to the field in a layer file. Since every embedded config decodes the
+	// same layer file, embedded structs are flattened unlike in YAMLPath.
+	LayerPath []string
+	// EnvKey is the environment variable envconfig reads first. It is empty when the field is ignored by envconfig.
+	EnvKey string
+	// EnvAlt is the environment variable envconfig falls back to.
+	EnvAlt string
+	Field  reflect.StructField
+	Value  reflect.Value
+}
+
+func (field fieldInfo) envSet() bool {
+	if field.EnvKey == "" {
+		return false
+	}
+
+	if _, found := os.LookupEnv(field.EnvKey); found {
+		return true
+	}
+
+	if field.EnvAlt == "" {
+		return false
+	}
+
+	_, found := os.LookupEnv(field.EnvAlt)
+
+	return found
+}
+
+// walkFields calls visit for every leaf field of the struct pointed to by cfg. Structs are descended into the same
+// way envconfig does, so types implementing a custom decoder are treated as leaves.
+func walkFields(cfg any, envPrefix string, visit func(field fieldInfo)) {
+	value := reflect.ValueOf(cfg)
+	if value.Kind() != reflect.Ptr || value.IsNil() || value.Elem().Kind() != reflect.Struct {
+		return
+	}
+
+	walkStruct(value.Elem(), "", []string{}, []string{}, envPrefix, map[uintptr]bool{value.Pointer(): true}, visit)
+}
+
+func walkStruct(
+	value reflect.Value,
+	namePrefix string,
+	yamlPrefix []string,
+	layerPrefix []string,
+	envPrefix string,
+	visited map[uintptr]bool,
+	visit func(field fieldInfo)) {
+	valueType := value.Type()
+
+	for index := 0; index < value.NumField(); index++ {
+		structField := valueType.Field(index)
+		fieldValue := value.Field(index)
+
+		if !fieldValue.CanSet() {
+			continue
+		}
+
+		for fieldValue.Kind() == reflect.Ptr && !fieldValue.IsNil() && fieldValue.Elem().Kind() == reflect.Struct {
+			if visited[fieldValue.Pointer()] {
+				break
+			}
+
+			visited[fieldValue.Pointer()] = true
+			fieldValue = fieldValue.Elem()
+		}
+
+		info := fieldInfo{
+			Name:      structField.Name,
+			YAMLPath:  childYAMLPath(yamlPrefix, structField),
+			LayerPath: childYAMLPath(layerPrefix, structField),
+			Field:     structField,
+			Value:     fieldValue,
+		}
+
+		if namePrefix != "" {
+			info.Name = namePrefix + "." + structField.Name
+		}
+
+		if !isTrue(structField.Tag.Get("ignored")) {
+			info.EnvKey, info.EnvAlt = envKey(envPrefix, structField)
+		}
+
+		if fieldValue.Kind() != reflect.Struct || isLeaf(fieldValue) {
+			visit(info)
+
+			continue
+		}
+
+		childName := info.Name
+		childLayerPrefix := info.LayerPath
+		childEnvPrefix := info.EnvKey
+
+		if structField.Anonymous {
+			childName = namePrefix
+			childLayerPrefix = layerPrefix
+			childEnvPrefix = envPrefix
+		}
+
+		if isInline(structField) {
+			info.YAMLPath = yamlPrefix
+			childLayerPrefix = layerPrefix
+		}
+
+		visitChild := func(child fieldInfo) {
+			if info.EnvKey == "" {
+				child.EnvKey, child.EnvAlt = "", ""
+			}
+
+			visit(child)
+		}
+
+		walkStruct(fieldValue, childName, info.YAMLPath, childLayerPrefix, childEnvPrefix, visited, visitChild)
+	}
+}
+
+// childYAMLPath returns the yaml path of the field following the yaml.v2 naming rules. Inline fields are returned
+// as nil and handled by the caller.
+func childYAMLPath(yamlPrefix []string, structField reflect.StructField) []string {
+	if yamlPrefix == nil {
+		return nil
+	}
+
+	tag := structField.Tag.Get("yaml")
+	if tag == "-" || isInline(structField) {
+		return nil
+	}
+
+	key := strings.Split(tag, ",")[0]
+	if key == "" {
+		key = strings.ToLower(structField.Name)
+	}
+
+	path := make([]string, 0, len(yamlPrefix)+1)
+
+	return append(append(path, yamlPrefix...), key)
+}
+
+func isInline(structField reflect.StructField) bool {
+	tagParts := strings.Split(structField.Tag.Get("yaml"), ",")
+
+	for _, part := range tagParts[1:] {
+		if part == "inline" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// envKey returns the environment variable names envconfig uses for the field.
+func envKey(envPrefix string, structField reflect.StructField) (string, string) {
+	alt := strings.ToUpper(structField.Tag.Get("envconfig"))
+	key := structField.Name
+
+	if alt != "" {
+		key = alt
+	}
+
+	if envPrefix != "" {
+		key = fmt.Sprintf("%s_%s", envPrefix, key)
+	}
+
+	return strings.ToUpper(key), alt
+}
+
+// isLeaf returns true when envconfig decodes the struct value as a whole rather than descending into its fields.
+func isLeaf(value reflect.Value) bool {
+	if !value.CanAddr() {
+		return true
+	}
+
+	switch value.Addr().Interface().(type) {
+	case envconfig.Decoder, envconfig.Setter, encoding.TextUnmarshaler, encoding.BinaryUnmarshaler:
+		return true
+	}
+
+	return false
+}
+
+func isTrue(value string) bool {
+	return strings.EqualFold(value, "true")
+}
@@ -0,0 +1,368 @@
+// Package execrecorder records the commands executed through the shared exec helpers during each spec. Records are
+// kept in memory per ginkgo process, so parallel processes never share them, and are attached to the report of the
+// spec that ran them as JSONL by Attach, which the reporter calls once it reported the spec.
+package execrecorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+const (
+	// ReportEntryName is the name of the report entry holding the records of a spec.
+	ReportEntryName = "exec-records"
+	// MaxOutputSize is the number of bytes of stdout and of stderr kept in each record. Longer output keeps its head
+	// and tail.
+	MaxOutputSize = 4096
+	// MaxRecords is the number of records kept per spec. Older records are dropped once it is reached.
+	MaxRecords = 1000
+
+	// exitStatusUnknown is the exit status of commands which failed without an exit code, such as failed connections.
+	exitStatusUnknown = -1
+)
+
+// Target describes where a command was executed.
+type Target struct {
+	// Cluster identifies the cluster by its kubeconfig path. It is empty for commands not run through a cluster.
+	Cluster   string `json:"cluster,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Pod       string `json:"pod,omitempty"`
+	Container string `json:"container,omitempty"`
+	Node      string `json:"node,omitempty"`
+}
+
+// Record is a single command execution.
+type Record struct {
+	Target
+
+	Command    string    `json:"command"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	ExitStatus int       `json:"exitStatus"`
+	Error      string    `json:"error,omitempty"`
+	Stdout     string    `json:"stdout,omitempty"`
+	Stderr     string    `json:"stderr,omitempty"`
+	Truncated  bool      `json:"truncated,omitempty"`
+}
+
+// Execution is a command which has started but not finished yet.
+type Execution struct {
+	record Record
+}
+
+var (
+	mutex   sync.Mutex
+	records []Record
+	dropped int
+)
+
+// Start records the start of command on target. The returned Execution must be finished once the command returns.
+func Start(target Target, command string) *Execution {
+	return &Execution{record: Record{Target: target, Command: command, Start: time.Now()}}
+}
+
+// Finish records the output and error of the execution.
+func (execution *Execution) Finish(stdout, stderr string, err error) {
+	record := execution.record
+	record.End = time.Now()
+	record.ExitStatus = exitStatus(err)
+
+	var stdoutTruncated, stderrTruncated bool
+
+	record.Stdout, stdoutTruncated = truncate(stdout)
+	record.Stderr, stderrTruncated = truncate(stderr)
+	record.Truncated = stdoutTruncated || stderrTruncated
+
+	if err != nil {
+		record.Error = err.Error()
+	}
+
+	add(record)
+}
+
+// ExecCommand executes command in the container of podBuilder, the same way as pod.Builder.ExecCommand, and records
+// it. An empty containerName means the first container. The command runs without a TTY so its stdout and stderr are
+// recorded separately, while the returned buffer still holds both in the order they were received. The apiClient must
+// be the client podBuilder was created with.
+func ExecCommand(apiClient *clients.Settings,
+	podBuilder *pod.Builder, command []string, containerName ...string) (bytes.Buffer, error) {
+	return execCommand(context.TODO(), apiClient, podBuilder, command, containerName...)
+}
+
+// ExecCommandWithTimeout is ExecCommand which stops waiting for the command after timeout, like
+// pod.Builder.ExecCommandWithTimeout.
+func ExecCommandWithTimeout(apiClient *clients.Settings,
+	podBuilder *pod.Builder, command []string, timeout time.Duration, containerName ...string) (bytes.Buffer, error) {
+	if timeout <= 0 {
+		return bytes.Buffer{}, fmt.Errorf("cannot execute command with non-positive timeout %s", timeout)
+	}
+
+	ctx, cancel := context.WithTimeout(context.TODO(), timeout)
+	defer cancel()
+
+	return execCommand(ctx, apiClient, podBuilder, command, containerName...)
+}
+
+// PodTarget returns the target of commands executed in the container of podBuilder on the cluster of apiClient.
+func PodTarget(apiClient *clients.Settings, podBuilder *pod.Builder, containerName ...string) Target {
+	target := Target{}
+
+	if apiClient != nil {
+		target.Cluster = apiClient.KubeconfigPath
+	}
+
+	if len(containerName) > 0 {
+		target.Container = containerName[0]
+	}
+
+	if podBuilder == nil {
+		return target
+	}
+
+	podObject := podBuilder.Object
+	if podObject == nil {
+		podObject = podBuilder.Definition
+	}
+
+	if podObject != nil {
+		target.Namespace = podObject.Namespace
+		target.Pod = podObject.Name
+		target.Node = podObject.Spec.NodeName
+	}
+
+	return target
+}
+
+// Output is the output of a command executed by Exec.
+type Output struct {
+	Stdout string
+	Stderr string
+	// Combined holds both stdout and stderr in the order they were received.
+	Combined string
+	// ExitStatus is 0 when the command succeeded, its exit code when it failed and -1 when it did not exit, such as
+	// when the connection failed.
+	ExitStatus int
+}
+
+// Exec executes argv without a TTY in the container of the pod named by target, on the cluster of apiClient, and
+// records it as command. The target must have a namespace, a pod and a container. Since there is no TTY, stdout and
+// stderr are kept separate and the exit status of argv is reported.
+func Exec(ctx context.Context,
+	apiClient *clients.Settings, target Target, command string, argv []string) (Output, error) {
+	request := apiClient.CoreV1Interface.RESTClient().
+		Post().
+		Namespace(target.Namespace).
+		Resource("pods").
+		Name(target.Pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: target.Container,
+			Command:   argv,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	execution := Start(target, command)
+
+	var stdout, stderr bytes.Buffer
+
+	combined := &outputBuffer{}
+
+	executor, err := remotecommand.NewSPDYExecutor(apiClient.Config, "POST", request.URL())
+	if err == nil {
+		err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{
+			Stdout: io.MultiWriter(&stdout, combined),
+			Stderr: io.MultiWriter(&stderr, combined),
+		})
+	}
+
+	execution.Finish(stdout.String(), stderr.String(), err)
+
+	return Output{
+		Stdout:     stdout.String(),
+		Stderr:     stderr.String(),
+		Combined:   combined.buffer.String(),
+		ExitStatus: exitStatus(err),
+	}, err
+}
+
+// Records returns the records of the current spec.
+func Records() []Record {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	return append([]Record{}, records...)
+}
+
+// JSONL returns the records of the current spec with one JSON object per line.
+func JSONL() string {
+	var builder strings.Builder
+
+	encoder := json.NewEncoder(&builder)
+
+	for _, record := range Records() {
+		// Records only contain strings, numbers and times so encoding cannot fail.
+		_ = encoder.Encode(record)
+	}
+
+	return builder.String()
+}
+
+// Attach adds the records of the current spec to its report as JSONL and clears them, so the next spec starts without
+// records. It must be called once per spec, which the reporter does after reporting the spec, and does nothing when no
+// commands were recorded.
+func Attach() {
+	content := pendingJSONL()
+
+	Reset()
+
+	if content == "" {
+		return
+	}
+
+	ginkgo.AddReportEntry(ReportEntryName, content, ginkgo.ReportEntryVisibilityNever)
+}
+
+// FromReport returns the records attached to report as JSONL, or an empty string when it has none. Records are only
+// attached once the spec was reported, so this is how ReportAfterEach nodes get them.
+func FromReport(report types.SpecReport) string {
+	var content strings.Builder
+
+	for _, entry := range report.ReportEntries {
+		if entry.Name == ReportEntryName {
+			content.WriteString(entry.StringRepresentation())
+		}
+	}
+
+	return content.String()
+}
+
+// ForReport returns the records of the spec of report as JSONL. These are the records attached to report if there
+// are any, otherwise the records of the current spec which are not attached yet, such as when reporting from a
+// JustAfterEach node.
+func ForReport(report types.SpecReport) string {
+	if content := FromReport(report); content != "" {
+		return content
+	}
+
+	return pendingJSONL()
+}
+
+// Reset clears the records of the current spec.
+func Reset() {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	records = nil
+	dropped = 0
+}
+
+func execCommand(ctx context.Context, apiClient *clients.Settings,
+	podBuilder *pod.Builder, command []string, containerName ...string) (bytes.Buffer, error) {
+	if apiClient == nil {
+		return bytes.Buffer{}, fmt.Errorf("cannot execute command %v with nil apiClient", command)
+	}
+
+	if podBuilder == nil || podBuilder.Definition == nil {
+		return bytes.Buffer{}, fmt.Errorf("cannot execute command %v in undefined pod", command)
+	}
+
+	if !podBuilder.Exists() {
+		return bytes.Buffer{}, fmt.Errorf("pod object %s does not exist in namespace %s",
+			podBuilder.Definition.Name, podBuilder.Definition.Namespace)
+	}
+
+	container := podBuilder.Object.Spec.Containers[0].Name
+	if len(containerName) > 0 && containerName[0] != "" {
+		container = containerName[0]
+	}
+
+	output, err := Exec(ctx, apiClient, PodTarget(apiClient, podBuilder, container), strings.Join(command, " "), command)
+
+	var combined bytes.Buffer
+
+	combined.WriteString(output.Combined)
+
+	return combined, err
+}
+
+// outputBuffer is a buffer which can be written to concurrently by the stdout and stderr streams of an execution.
+type outputBuffer struct {
+	mutex  sync.Mutex
+	buffer bytes.Buffer
+}
+
+func (output *outputBuffer) Write(data []byte) (int, error) {
+	output.mutex.Lock()
+	defer output.mutex.Unlock()
+
+	return output.buffer.Write(data)
+}
+
+// pendingJSONL returns the records of the current spec as JSONL, preceded by the number of dropped records if any
+// were dropped.
+func pendingJSONL() string {
+	mutex.Lock()
+	droppedRecords := dropped
+	mutex.Unlock()
+
+	content := JSONL()
+	if content != "" && droppedRecords > 0 {
+		content = fmt.Sprintf("{\"dropped\":%d}\n%s", droppedRecords, content)
+	}
+
+	return content
+}
+
+func add(record Record) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if len(records) >= MaxRecords {
+		records = records[1:]
+		dropped++
+	}
+
+	records = append(records, record)
+}
+
+func exitStatus(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitErr utilexec.ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.ExitStatus()
+	}
+
+	return exitStatusUnknown
+}
+
+// truncate keeps the head and tail of output when it is longer than MaxOutputSize.
+func truncate(output string) (string, bool) {
+	if len(output) <= MaxOutputSize {
+		return output, false
+	}
+
+	half := MaxOutputSize / 2
+
+	return fmt.Sprintf("%s\n...[%d bytes truncated]...\n%s",
+		output[:half], len(output)-MaxOutputSize, output[len(output)-half:]), true
+}
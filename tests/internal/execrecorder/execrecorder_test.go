@@ -0,0 +1,135 @@
+package execrecorder
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilexec "k8s.io/client-go/util/exec"
+)
+
+func TestFinish(t *testing.T) {
+	testCases := []struct {
+		stdout             string
+		err                error
+		expectedExitStatus int
+		expectedError      string
+		expectedTruncated  bool
+	}{
+		{
+			stdout:             "ok",
+			expectedExitStatus: 0,
+		},
+		{
+			stdout:             "not found",
+			err:                utilexec.CodeExitError{Err: errors.New("command terminated"), Code: 127},
+			expectedExitStatus: 127,
+			expectedError:      "command terminated",
+		},
+		{
+			err:                errors.New("connection refused"),
+			expectedExitStatus: exitStatusUnknown,
+			expectedError:      "connection refused",
+		},
+		{
+			stdout:             strings.Repeat("a", MaxOutputSize+10),
+			expectedExitStatus: 0,
+			expectedTruncated:  true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		Reset()
+
+		Start(Target{Node: "worker-0"}, "uptime").Finish(testCase.stdout, "", testCase.err)
+
+		records := Records()
+		assert.Len(t, records, 1)
+		assert.Equal(t, "worker-0", records[0].Node)
+		assert.Equal(t, "uptime", records[0].Command)
+		assert.Equal(t, testCase.expectedExitStatus, records[0].ExitStatus)
+		assert.Equal(t, testCase.expectedError, records[0].Error)
+		assert.Equal(t, testCase.expectedTruncated, records[0].Truncated)
+		assert.False(t, records[0].End.Before(records[0].Start))
+	}
+
+	Reset()
+}
+
+func TestTruncate(t *testing.T) {
+	output := strings.Repeat("h", MaxOutputSize/2) + strings.Repeat("m", 100) + strings.Repeat("t", MaxOutputSize/2)
+
+	truncated, isTruncated := truncate(output)
+	assert.True(t, isTruncated)
+	assert.True(t, strings.HasPrefix(truncated, strings.Repeat("h", MaxOutputSize/2)+"\n...[100 bytes truncated]...\n"))
+	assert.True(t, strings.HasSuffix(truncated, strings.Repeat("t", MaxOutputSize/2)))
+	assert.NotContains(t, truncated, "m")
+
+	truncated, isTruncated = truncate("short")
+	assert.False(t, isTruncated)
+	assert.Equal(t, "short", truncated)
+}
+
+func TestJSONL(t *testing.T) {
+	Reset()
+	t.Cleanup(Reset)
+
+	Start(Target{Cluster: "/kubeconfig", Pod: "ptp-daemon"}, "pmc -u -b 0 'GET CURRENT_DATA_SET'").Finish("ok", "", nil)
+	Start(Target{Node: "worker-1"}, "hostname").Finish("worker-1", "", nil)
+
+	lines := strings.Split(strings.TrimSuffix(JSONL(), "\n"), "\n")
+	assert.Len(t, lines, 2)
+
+	var record Record
+
+	err := json.Unmarshal([]byte(lines[0]), &record)
+	assert.Nil(t, err)
+	assert.Equal(t, "/kubeconfig", record.Cluster)
+	assert.Equal(t, "ptp-daemon", record.Pod)
+	assert.Equal(t, "ok", record.Stdout)
+
+	report := types.SpecReport{ReportEntries: types.ReportEntries{
+		{Name: "other", Value: types.WrapEntryValue("ignored")},
+		{Name: ReportEntryName, Value: types.WrapEntryValue(JSONL())},
+	}}
+	assert.Equal(t, JSONL(), FromReport(report))
+	assert.Empty(t, FromReport(types.SpecReport{}))
+	assert.Equal(t, JSONL(), ForReport(report))
+	assert.Equal(t, JSONL(), ForReport(types.SpecReport{}))
+}
+
+func TestMaxRecords(t *testing.T) {
+	Reset()
+	t.Cleanup(Reset)
+
+	for range MaxRecords + 5 {
+		Start(Target{}, "true").Finish("", "", nil)
+	}
+
+	assert.Len(t, Records(), MaxRecords)
+	assert.Equal(t, 5, dropped)
+	assert.True(t, strings.HasPrefix(ForReport(types.SpecReport{}), "{\"dropped\":5}\n"))
+}
+
+func TestPodTarget(t *testing.T) {
+	podBuilder := &pod.Builder{Definition: &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "machine-config-daemon-abcde", Namespace: "openshift-machine-config-operator"},
+		Spec:       corev1.PodSpec{NodeName: "master-0"},
+	}}
+
+	assert.Equal(t, Target{
+		Cluster:   "/spoke/kubeconfig",
+		Namespace: "openshift-machine-config-operator",
+		Pod:       "machine-config-daemon-abcde",
+		Container: "machine-config-daemon",
+		Node:      "master-0",
+	}, PodTarget(&clients.Settings{KubeconfigPath: "/spoke/kubeconfig"}, podBuilder, "machine-config-daemon"))
+	assert.Equal(t, Target{}, PodTarget(nil, nil))
+}
@@ -0,0 +1,248 @@
+// Package fakecluster provides an offline clients.Settings backed by fake clients preloaded with objects, usually
+// loaded from a directory of YAML fixtures such as a must-gather snapshot. It lets suite setup helpers run in go test
+// or with ECO_OFFLINE_FIXTURES without a cluster.
+package fakecluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	configv1fake "github.com/openshift/client-go/config/clientset/versioned/fake"
+	configv1scheme "github.com/openshift/client-go/config/clientset/versioned/scheme"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	kubescheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/klog/v2"
+	runtimeclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// NewFromDir returns a fake clients.Settings preloaded with the objects of the fixtures under dir. See LoadFixtures
+// for how the fixtures are read.
+func NewFromDir(dir string) (*clients.Settings, error) {
+	objects, err := LoadFixtures(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(objects...)
+}
+
+// Object returns an object of kind gvk called name in namespace, with the other top-level fields, such as spec and
+// status, taken from fields. It builds the objects passed to New in tests without writing fixture files.
+func Object(gvk schema.GroupVersionKind, namespace, name string, fields map[string]any) *unstructured.Unstructured {
+	object := &unstructured.Unstructured{Object: fields}
+	object.SetGroupVersionKind(gvk)
+	object.SetNamespace(namespace)
+	object.SetName(name)
+
+	return object
+}
+
+// New returns a clients.Settings whose clients are all backed by the same set of objects:
+//
+//   - the controller-runtime client contains every object, with custom resources added once their scheme is attached,
+//   - the dynamic client contains every object,
+//   - the kubernetes clientset and its typed interfaces contain the objects of built-in kinds,
+//   - the OpenShift config interface contains the objects of config.openshift.io kinds.
+//
+// The clients are independent, so changes through one are not seen through the others. The remaining OpenShift
+// interfaces and the rest config are nil, since they have no fake implementation.
+func New(objects ...*unstructured.Unstructured) (*clients.Settings, error) {
+	pending := &pendingObjects{}
+
+	apiClient, clientBuilder := clients.GetModifiableTestClients(clients.TestClientParams{
+		InterceptorFuncs: pending.interceptors(),
+	})
+	if apiClient == nil {
+		return nil, fmt.Errorf("failed to create fake clients")
+	}
+
+	// The scheme of the controller-runtime client is not exported, so an identical one tells which objects it knows.
+	clientScheme := runtime.NewScheme()
+
+	err := clients.SetScheme(clientScheme)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create fake client scheme: %w", err)
+	}
+
+	var (
+		runtimeObjects, dynamicObjects, kubeObjects, configObjects []runtime.Object
+	)
+
+	gvrToListKind := make(map[schema.GroupVersionResource]string)
+
+	for _, object := range objects {
+		gvk := object.GroupVersionKind()
+
+		if clientScheme.Recognizes(gvk) {
+			runtimeObjects = append(runtimeObjects, object.DeepCopy())
+		} else {
+			pending.objects = append(pending.objects, object.DeepCopy())
+		}
+
+		dynamicObjects = append(dynamicObjects, object.DeepCopy())
+
+		gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+		gvrToListKind[gvr] = gvk.Kind + "List"
+
+		typed, err := toTyped(kubescheme.Scheme, object)
+		if err != nil {
+			return nil, err
+		}
+
+		if typed != nil {
+			kubeObjects = append(kubeObjects, typed)
+
+			continue
+		}
+
+		typed, err = toTyped(configv1scheme.Scheme, object)
+		if err != nil {
+			return nil, err
+		}
+
+		if typed != nil {
+			configObjects = append(configObjects, typed)
+		}
+	}
+
+	klog.V(90).Infof("Creating fake cluster with %d objects, %d built-in and %d OpenShift config",
+		len(objects), len(kubeObjects), len(configObjects))
+
+	apiClient.Client = clientBuilder.WithRuntimeObjects(runtimeObjects...).Build()
+	apiClient.Interface = dynamicfake.NewSimpleDynamicClientWithCustomListKinds(
+		runtime.NewScheme(), gvrToListKind, dynamicObjects...)
+
+	kubeClient := k8sfake.NewClientset(kubeObjects...)
+	apiClient.K8sClient = kubeClient
+	apiClient.CoreV1Interface = kubeClient.CoreV1()
+	apiClient.AppsV1Interface = kubeClient.AppsV1()
+	apiClient.NetworkingV1Interface = kubeClient.NetworkingV1()
+	apiClient.RbacV1Interface = kubeClient.RbacV1()
+	apiClient.StorageV1Interface = kubeClient.StorageV1()
+	apiClient.PolicyV1Interface = kubeClient.PolicyV1()
+	apiClient.ConfigV1Interface = configv1fake.NewClientset(configObjects...).ConfigV1()
+
+	return apiClient, nil
+}
+
+// pendingObjects holds the objects whose kind is not in the scheme of the controller-runtime client when it is built,
+// usually custom resources, since the client cannot return them as typed objects once their scheme is attached by
+// eco-goinfra. They are created in the client on the first call after their scheme is attached.
+type pendingObjects struct {
+	mutex   sync.Mutex
+	objects []*unstructured.Unstructured
+}
+
+// create creates the pending objects whose kind is now known to the scheme of client.
+func (pending *pendingObjects) create(ctx context.Context, client runtimeclient.WithWatch) error {
+	pending.mutex.Lock()
+	defer pending.mutex.Unlock()
+
+	var remaining []*unstructured.Unstructured
+
+	for _, object := range pending.objects {
+		typed, err := toTyped(client.Scheme(), object)
+		if err != nil {
+			return err
+		}
+
+		if typed == nil {
+			remaining = append(remaining, object)
+
+			continue
+		}
+
+		typedObject, ok := typed.(runtimeclient.Object)
+		if !ok {
+			return fmt.Errorf("%s is not a client object", object.GroupVersionKind())
+		}
+
+		typedObject.SetResourceVersion("")
+
+		err = client.Create(ctx, typedObject)
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create fixture %s: %w", objectKey(object), err)
+		}
+	}
+
+	pending.objects = remaining
+
+	return nil
+}
+
+func (pending *pendingObjects) interceptors() interceptor.Funcs {
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, client runtimeclient.WithWatch,
+			key runtimeclient.ObjectKey, obj runtimeclient.Object, opts ...runtimeclient.GetOption) error {
+			if err := pending.create(ctx, client); err != nil {
+				return err
+			}
+
+			return client.Get(ctx, key, obj, opts...)
+		},
+		List: func(ctx context.Context, client runtimeclient.WithWatch,
+			list runtimeclient.ObjectList, opts ...runtimeclient.ListOption) error {
+			if err := pending.create(ctx, client); err != nil {
+				return err
+			}
+
+			return client.List(ctx, list, opts...)
+		},
+		Update: func(ctx context.Context, client runtimeclient.WithWatch,
+			obj runtimeclient.Object, opts ...runtimeclient.UpdateOption) error {
+			if err := pending.create(ctx, client); err != nil {
+				return err
+			}
+
+			return client.Update(ctx, obj, opts...)
+		},
+		Patch: func(ctx context.Context, client runtimeclient.WithWatch,
+			obj runtimeclient.Object, patch runtimeclient.Patch, opts ...runtimeclient.PatchOption) error {
+			if err := pending.create(ctx, client); err != nil {
+				return err
+			}
+
+			return client.Patch(ctx, obj, patch, opts...)
+		},
+		Delete: func(ctx context.Context, client runtimeclient.WithWatch,
+			obj runtimeclient.Object, opts ...runtimeclient.DeleteOption) error {
+			if err := pending.create(ctx, client); err != nil {
+				return err
+			}
+
+			return client.Delete(ctx, obj, opts...)
+		},
+	}
+}
+
+// toTyped converts object to the type registered for its kind in scheme. It returns nil if the kind is not registered.
+func toTyped(scheme *runtime.Scheme, object *unstructured.Unstructured) (runtime.Object, error) {
+	gvk := object.GroupVersionKind()
+	if !scheme.Recognizes(gvk) || strings.HasSuffix(gvk.Kind, "List") {
+		return nil, nil
+	}
+
+	typed, err := scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(object.Object, typed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert %s %s/%s: %w", gvk.Kind, object.GetNamespace(), object.GetName(), err)
+	}
+
+	typed.GetObjectKind().SetGroupVersionKind(gvk)
+
+	return typed, nil
+}
@@ -0,0 +1,89 @@
+package fakecluster
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/daemonset"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/namespace"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nodes"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/ptp"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const mustGatherDir = "testdata/must-gather"
+
+func TestLoadFixtures(t *testing.T) {
+	objects, err := LoadFixtures(mustGatherDir)
+	assert.NoError(t, err)
+
+	var keys []string
+	for _, object := range objects {
+		keys = append(keys, objectKey(object))
+	}
+
+	assert.ElementsMatch(t, []string{
+		"config.openshift.io/v1/ClusterVersion//version",
+		"v1/Node//worker-0",
+		"v1/Node//master-0",
+		"ptp.openshift.io/v1/PtpConfig/openshift-ptp/slave",
+		"apps/v1/DaemonSet/openshift-sriov-network-operator/sriov-network-config-daemon",
+		"apps/v1/DaemonSet/openshift-sriov-network-operator/operator-webhook",
+		"apps/v1/DaemonSet/openshift-sriov-network-operator/network-resources-injector",
+		"v1/Namespace//openshift-sriov-network-operator",
+	}, keys)
+
+	for _, object := range objects {
+		if object.GetName() == "worker-0" {
+			assert.Contains(t, object.GetLabels(), "ptp/slave", "first occurrence of worker-0 should be kept")
+		}
+	}
+}
+
+func TestLoadFixturesErrors(t *testing.T) {
+	_, err := LoadFixtures("testdata/missing")
+	assert.Error(t, err)
+
+	dir := t.TempDir()
+	err = os.WriteFile(filepath.Join(dir, "invalid.yaml"), []byte("kind: [Node"), 0o600)
+	assert.NoError(t, err)
+
+	_, err = LoadFixtures(dir)
+	assert.Error(t, err)
+}
+
+func TestNewFromDir(t *testing.T) {
+	apiClient, err := NewFromDir(mustGatherDir)
+	assert.NoError(t, err)
+
+	nodeList, err := nodes.List(apiClient)
+	assert.NoError(t, err)
+	assert.Len(t, nodeList, 2)
+
+	assert.True(t, namespace.NewBuilder(apiClient, "openshift-sriov-network-operator").Exists())
+	assert.False(t, namespace.NewBuilder(apiClient, "openshift-ptp").Exists())
+
+	configDaemon, err := daemonset.Pull(apiClient, "sriov-network-config-daemon", "openshift-sriov-network-operator")
+	assert.NoError(t, err)
+	assert.True(t, configDaemon.IsReady(time.Second))
+
+	ptpConfigs, err := ptp.ListPtpConfigs(apiClient)
+	assert.NoError(t, err)
+	assert.Len(t, ptpConfigs, 1)
+
+	clusterVersion, err := apiClient.ConfigV1Interface.ClusterVersions().Get(
+		context.TODO(), "version", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "4.20.0", clusterVersion.Status.Desired.Version)
+
+	daemonSets, err := apiClient.Interface.Resource(schema.GroupVersionResource{
+		Group: "apps", Version: "v1", Resource: "daemonsets",
+	}).Namespace("openshift-sriov-network-operator").List(context.TODO(), metav1.ListOptions{})
+	assert.NoError(t, err)
+	assert.Len(t, daemonSets.Items, 3)
+}
@@ -0,0 +1,156 @@
+package fakecluster
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/klog/v2"
+)
+
+// fixtureExtensions are the extensions of the files loaded from a fixture directory.
+var fixtureExtensions = []string{".yaml", ".yml", ".json"}
+
+// LoadFixtures reads every YAML and JSON file under dir, recursively, and returns the objects they contain. Files may
+// contain several documents and lists, such as the PodList files of a must-gather, are expanded into their items.
+// Documents without a kind or name are skipped and only the first occurrence of an object is kept, since must-gather
+// snapshots often include the same object more than once.
+func LoadFixtures(dir string) ([]*unstructured.Unstructured, error) {
+	klog.V(90).Infof("Loading fake cluster fixtures from %s", dir)
+
+	var objects []*unstructured.Unstructured
+
+	seen := make(map[string]bool)
+
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || !hasFixtureExtension(path) {
+			return nil
+		}
+
+		fileObjects, err := loadFixtureFile(path)
+		if err != nil {
+			return err
+		}
+
+		for _, object := range fileObjects {
+			key := objectKey(object)
+			if seen[key] {
+				klog.V(90).Infof("Skipping duplicate fixture object %s from %s", key, path)
+
+				continue
+			}
+
+			seen[key] = true
+
+			objects = append(objects, object)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to load fixtures from %s: %w", dir, err)
+	}
+
+	klog.V(90).Infof("Loaded %d fake cluster objects from %s", len(objects), dir)
+
+	return objects, nil
+}
+
+func loadFixtureFile(path string) ([]*unstructured.Unstructured, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	defer file.Close()
+
+	var objects []*unstructured.Unstructured
+
+	decoder := yaml.NewYAMLOrJSONDecoder(file, 4096)
+
+	for {
+		document := map[string]any{}
+
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return objects, nil
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode fixture file %s: %w", path, err)
+		}
+
+		documentObjects, err := expandDocument(&unstructured.Unstructured{Object: document})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read fixture file %s: %w", path, err)
+		}
+
+		objects = append(objects, documentObjects...)
+	}
+}
+
+// expandDocument returns the items of document, recursively, if it is a list, or document itself if it is an object.
+// Documents that are neither are skipped.
+func expandDocument(document *unstructured.Unstructured) ([]*unstructured.Unstructured, error) {
+	if document.GetKind() == "" || document.GetAPIVersion() == "" {
+		return nil, nil
+	}
+
+	if !document.IsList() {
+		if document.GetName() == "" {
+			return nil, nil
+		}
+
+		return []*unstructured.Unstructured{document}, nil
+	}
+
+	list, err := document.ToList()
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []*unstructured.Unstructured
+
+	for index := range list.Items {
+		item := &list.Items[index]
+
+		// Items of typed lists, such as PodList, may omit their kind.
+		if item.GetKind() == "" && document.GetKind() != "List" {
+			item.SetAPIVersion(document.GetAPIVersion())
+			item.SetKind(strings.TrimSuffix(document.GetKind(), "List"))
+		}
+
+		items, err := expandDocument(item)
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, items...)
+	}
+
+	return objects, nil
+}
+
+func hasFixtureExtension(path string) bool {
+	for _, extension := range fixtureExtensions {
+		if strings.EqualFold(filepath.Ext(path), extension) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func objectKey(object *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s/%s", object.GetAPIVersion(), object.GetKind(), object.GetNamespace(), object.GetName())
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/go-logr/logr"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/fakecluster"
 	"k8s.io/klog/v2"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -38,6 +39,16 @@ func init() {
 
 	_ = flag.Set("v", GeneralConfig.VerboseLevel)
 
+	if GeneralConfig.OfflineFixtures != "" {
+		var err error
+
+		if APIClient, err = fakecluster.NewFromDir(GeneralConfig.OfflineFixtures); err != nil {
+			klog.Exitf("can not load offline fixtures: %v", err)
+		}
+
+		return
+	}
+
 	if APIClient = clients.New(""); APIClient == nil {
 		if GeneralConfig.DryRun {
 			return
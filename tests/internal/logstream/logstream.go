@@ -0,0 +1,383 @@
+// Package logstream follows the logs of a container and fans its lines out to several subscribers. Unlike fetching
+// windows of logs with SinceTime at an interval, a stream follows the logs as they are written and reopens itself when
+// the container restarts or the pod is recreated. Lines are timestamped by the kubelet and deduplicated across
+// reconnections by their timestamp and sequence, so long-running subscribers neither miss nor count lines twice.
+package logstream
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+// DefaultRetryInterval is the time waited before reopening a stream which ended or failed to open.
+const DefaultRetryInterval = time.Second
+
+// Line is a log line of the followed container.
+type Line struct {
+	// Time is the timestamp the kubelet recorded for the line.
+	Time time.Time
+	// Sequence is the position of the line among the lines with the same Time, starting at 0. Together with Time it
+	// identifies the line across reconnections.
+	Sequence int
+	// Pod is the name of the pod the line was read from.
+	Pod string
+	// Text is the line without its timestamp.
+	Text string
+}
+
+// Source opens a follow-mode stream of the logs of a container, with every line prefixed by its RFC3339Nano timestamp,
+// starting at since. It returns the stream and the name of the pod it belongs to.
+type Source func(ctx context.Context, since time.Time) (io.ReadCloser, string, error)
+
+// PodResolver returns the pod whose logs are followed. It is called every time the stream is reopened, so the stream
+// follows pods which are recreated under a different name.
+type PodResolver func() (*pod.Builder, error)
+
+// PodSource returns a Source following the logs of container in the pod returned by resolve.
+func PodSource(apiClient *clients.Settings, resolve PodResolver, container string) Source {
+	return func(ctx context.Context, since time.Time) (io.ReadCloser, string, error) {
+		if apiClient == nil {
+			return nil, "", fmt.Errorf("cannot stream logs: apiClient is nil")
+		}
+
+		podBuilder, err := resolve()
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to get pod to stream logs from: %w", err)
+		}
+
+		namespace, name := podBuilder.Definition.Namespace, podBuilder.Definition.Name
+
+		reader, err := apiClient.CoreV1Interface.Pods(namespace).GetLogs(name, &corev1.PodLogOptions{
+			Container:  container,
+			Follow:     true,
+			Timestamps: true,
+			SinceTime:  &metav1.Time{Time: since},
+		}).Stream(ctx)
+		if err != nil {
+			return nil, name, fmt.Errorf("failed to stream logs of pod %s/%s: %w", namespace, name, err)
+		}
+
+		return reader, name, nil
+	}
+}
+
+// NamedPod returns a PodResolver pulling the pod called name in namespace.
+func NamedPod(apiClient *clients.Settings, name, namespace string) PodResolver {
+	return func() (*pod.Builder, error) {
+		return pod.Pull(apiClient, name, namespace)
+	}
+}
+
+// Stats counts what a stream did so far.
+type Stats struct {
+	// Lines is the number of lines published to subscribers.
+	Lines int
+	// Duplicates is the number of lines skipped because they were already published before a reconnection.
+	Duplicates int
+	// Reconnects is the number of times the stream was reopened after the first time.
+	Reconnects int
+	// Errors are the errors opening or reading the stream. They are retried until the stream is stopped.
+	Errors []error
+}
+
+// Stream follows the logs of a Source and publishes every line to its subscribers.
+type Stream struct {
+	source        Source
+	since         time.Time
+	retryInterval time.Duration
+
+	mutex       sync.Mutex
+	subscribers map[*Subscription]struct{}
+	stats       Stats
+	started     bool
+	done        chan struct{}
+
+	// lastTime and lastSequence identify the last published line.
+	lastTime     time.Time
+	lastSequence int
+}
+
+// New returns a stream of the logs of source starting at since. It does not read anything until started.
+func New(source Source, since time.Time) *Stream {
+	return &Stream{
+		source:        source,
+		since:         since,
+		retryInterval: DefaultRetryInterval,
+		subscribers:   make(map[*Subscription]struct{}),
+		done:          make(chan struct{}),
+		lastSequence:  -1,
+	}
+}
+
+// WithRetryInterval sets the time waited before reopening the stream. Non-positive values are ignored.
+func (stream *Stream) WithRetryInterval(retryInterval time.Duration) *Stream {
+	if retryInterval > 0 {
+		stream.retryInterval = retryInterval
+	}
+
+	return stream
+}
+
+// Subscription receives the lines published by a stream after it subscribed.
+type Subscription struct {
+	// Lines receives the published lines. It is closed once the stream stops.
+	Lines <-chan Line
+
+	lines     chan Line
+	closed    chan struct{}
+	closeOnce sync.Once
+	stream    *Stream
+}
+
+// Subscribe returns a subscription receiving every line published from now on, buffering up to buffer lines. A
+// subscriber which does not keep up holds back the other subscribers rather than missing lines, so subscribers should
+// close their subscription once done with it. Subscribing to a stopped stream returns a subscription whose Lines is
+// already closed.
+func (stream *Stream) Subscribe(buffer int) *Subscription {
+	lines := make(chan Line, max(buffer, 0))
+	subscription := &Subscription{Lines: lines, lines: lines, closed: make(chan struct{}), stream: stream}
+
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	select {
+	case <-stream.done:
+		close(lines)
+	default:
+		stream.subscribers[subscription] = struct{}{}
+	}
+
+	return subscription
+}
+
+// Close stops the subscription from receiving lines. Lines is not written to after Close returns.
+func (subscription *Subscription) Close() {
+	subscription.closeOnce.Do(func() {
+		close(subscription.closed)
+
+		subscription.stream.mutex.Lock()
+		defer subscription.stream.mutex.Unlock()
+
+		delete(subscription.stream.subscribers, subscription)
+	})
+}
+
+// Start follows the logs in the background until ctx is done, then closes the Lines of every subscription. Calling it
+// more than once has no effect.
+func (stream *Stream) Start(ctx context.Context) {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	if stream.started {
+		return
+	}
+
+	stream.started = true
+
+	go stream.run(ctx)
+}
+
+// Done returns a channel closed once the stream stopped.
+func (stream *Stream) Done() <-chan struct{} {
+	return stream.done
+}
+
+// Stats returns a copy of the counters of the stream.
+func (stream *Stream) Stats() Stats {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	stats := stream.stats
+	stats.Errors = append([]error{}, stream.stats.Errors...)
+
+	return stats
+}
+
+// run opens the source and publishes its lines until ctx is done, reopening it every time it ends.
+func (stream *Stream) run(ctx context.Context) {
+	defer stream.stop()
+
+	for opened := 0; ctx.Err() == nil; opened++ {
+		if opened > 0 {
+			stream.update(func(stats *Stats) { stats.Reconnects++ })
+
+			if !sleep(ctx, stream.retryInterval) {
+				return
+			}
+		}
+
+		since := stream.since
+		if stream.lastTime.After(since) {
+			since = stream.lastTime
+		}
+
+		reader, podName, err := stream.source(ctx, since)
+		if err != nil {
+			stream.recordError(ctx, err)
+
+			continue
+		}
+
+		err = stream.read(ctx, reader, podName)
+		_ = reader.Close()
+
+		if err != nil {
+			stream.recordError(ctx, err)
+
+			continue
+		}
+
+		klog.V(90).Infof("Log stream of pod %s ended, reopening it", podName)
+	}
+}
+
+// read publishes the lines of reader which were not published yet, until it ends or ctx is done.
+func (stream *Stream) read(ctx context.Context, reader io.Reader, podName string) error {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var (
+		currentTime     time.Time
+		currentSequence int
+	)
+
+	for scanner.Scan() {
+		line, err := parseLine(scanner.Text())
+		if err != nil {
+			klog.V(90).Infof("Skipping log line of pod %s: %v", podName, err)
+
+			continue
+		}
+
+		if line.Time.Equal(currentTime) {
+			currentSequence++
+		} else {
+			currentTime, currentSequence = line.Time, 0
+		}
+
+		line.Sequence = currentSequence
+		line.Pod = podName
+
+		if line.Time.Before(stream.since) {
+			continue
+		}
+
+		if stream.published(line) {
+			stream.update(func(stats *Stats) { stats.Duplicates++ })
+
+			continue
+		}
+
+		if !stream.publish(ctx, line) {
+			return nil
+		}
+	}
+
+	if ctx.Err() != nil {
+		return nil
+	}
+
+	return scanner.Err()
+}
+
+// published reports whether line was published before the stream was reopened.
+func (stream *Stream) published(line Line) bool {
+	if line.Time.Before(stream.lastTime) {
+		return true
+	}
+
+	return line.Time.Equal(stream.lastTime) && line.Sequence <= stream.lastSequence
+}
+
+// publish sends line to every subscriber, waiting for each one to receive it or close its subscription. It returns
+// false if ctx is done first.
+func (stream *Stream) publish(ctx context.Context, line Line) bool {
+	stream.mutex.Lock()
+
+	subscriptions := make([]*Subscription, 0, len(stream.subscribers))
+	for subscription := range stream.subscribers {
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	stream.mutex.Unlock()
+
+	for _, subscription := range subscriptions {
+		select {
+		case subscription.lines <- line:
+		case <-subscription.closed:
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	stream.lastTime, stream.lastSequence = line.Time, line.Sequence
+	stream.update(func(stats *Stats) { stats.Lines++ })
+
+	return true
+}
+
+// stop closes the Lines of every remaining subscription and marks the stream as done.
+func (stream *Stream) stop() {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	for subscription := range stream.subscribers {
+		close(subscription.lines)
+		delete(stream.subscribers, subscription)
+	}
+
+	close(stream.done)
+}
+
+func (stream *Stream) recordError(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	klog.V(90).Infof("Error streaming logs, retrying in %s: %v", stream.retryInterval, err)
+
+	stream.update(func(stats *Stats) { stats.Errors = append(stats.Errors, err) })
+}
+
+func (stream *Stream) update(modify func(stats *Stats)) {
+	stream.mutex.Lock()
+	defer stream.mutex.Unlock()
+
+	modify(&stream.stats)
+}
+
+// parseLine splits a log line into its timestamp and text.
+func parseLine(raw string) (Line, error) {
+	timestamp, text, _ := strings.Cut(raw, " ")
+
+	parsed, err := time.Parse(time.RFC3339Nano, timestamp)
+	if err != nil {
+		return Line{}, fmt.Errorf("failed to parse timestamp of line %q: %w", raw, err)
+	}
+
+	return Line{Time: parsed, Text: text}, nil
+}
+
+// sleep waits for duration and reports whether ctx is still not done.
+func sleep(ctx context.Context, duration time.Duration) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,192 @@
+package logstream
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamReconnects(t *testing.T) {
+	// The second connection overlaps the first one like a SinceTime truncated to seconds, including a line with the
+	// same timestamp as another one, then the container restarts and the third connection holds the new container.
+	source := &fakeSource{connections: []string{
+		"2024-01-01T10:00:00.100Z before start\n" +
+			"2024-01-01T10:00:01.100Z first\n" +
+			"2024-01-01T10:00:01.200Z second\n",
+		"",
+		"2024-01-01T10:00:01.100Z first\n" +
+			"2024-01-01T10:00:01.200Z second\n" +
+			"2024-01-01T10:00:01.200Z second again\n" +
+			"not a timestamped line\n" +
+			"2024-01-01T10:00:02.000Z third\n",
+		"2024-01-01T10:00:03.000Z restarted\n",
+	}}
+
+	stream := New(source.open, time.Date(2024, 1, 1, 10, 0, 1, 0, time.UTC)).WithRetryInterval(time.Millisecond)
+	first := stream.Subscribe(0)
+	second := stream.Subscribe(10)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	stream.Start(ctx)
+
+	var texts []string
+
+	for line := range first.Lines {
+		texts = append(texts, line.Text)
+
+		if line.Text == "restarted" {
+			cancel()
+		}
+	}
+
+	<-stream.Done()
+
+	assert.Equal(t, []string{"first", "second", "second again", "third", "restarted"}, texts)
+	assert.Len(t, drain(second), 5)
+
+	stats := stream.Stats()
+	assert.Equal(t, 5, stats.Lines)
+	assert.Equal(t, 2, stats.Duplicates)
+	assert.GreaterOrEqual(t, stats.Reconnects, 3)
+	assert.Len(t, stats.Errors, 1)
+
+	since := source.sinces()
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 1, 0, time.UTC), since[0])
+	assert.Equal(t, time.Date(2024, 1, 1, 10, 0, 1, 200000000, time.UTC), since[2])
+
+	_, open := <-stream.Subscribe(1).Lines
+	assert.False(t, open)
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	source := &fakeSource{connections: []string{
+		"2024-01-01T10:00:01Z first\n2024-01-01T10:00:02Z second\n2024-01-01T10:00:03Z third\n",
+	}}
+
+	stream := New(source.open, time.Time{}).WithRetryInterval(time.Millisecond)
+	closed := stream.Subscribe(0)
+	open := stream.Subscribe(0)
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	stream.Start(ctx)
+
+	line := <-closed.Lines
+	assert.Equal(t, "first", line.Text)
+	assert.Equal(t, "fake-pod", line.Pod)
+
+	closed.Close()
+	closed.Close()
+
+	var texts []string
+	for line := range open.Lines {
+		texts = append(texts, line.Text)
+
+		if line.Text == "third" {
+			cancel()
+		}
+	}
+
+	assert.Equal(t, []string{"first", "second", "third"}, texts)
+}
+
+func TestParseLine(t *testing.T) {
+	testCases := []struct {
+		name          string
+		raw           string
+		expected      Line
+		expectedError bool
+	}{
+		{
+			name:     "timestamped",
+			raw:      "2024-01-01T10:00:01.123456789Z ptp4l[1.2]: master offset 3 s2",
+			expected: Line{Time: time.Date(2024, 1, 1, 10, 0, 1, 123456789, time.UTC), Text: "ptp4l[1.2]: master offset 3 s2"},
+		},
+		{
+			name:     "empty text",
+			raw:      "2024-01-01T10:00:01Z",
+			expected: Line{Time: time.Date(2024, 1, 1, 10, 0, 1, 0, time.UTC)},
+		},
+		{
+			name:          "no timestamp",
+			raw:           "ptp4l[1.2]: master offset 3 s2",
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			line, err := parseLine(testCase.raw)
+
+			if testCase.expectedError {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, testCase.expected, line)
+			}
+		})
+	}
+}
+
+// fakeSource serves one scripted connection per call, failing the call after the first one, and blocks until the
+// stream stops once they are exhausted.
+type fakeSource struct {
+	mutex       sync.Mutex
+	connections []string
+	calls       int
+	since       []time.Time
+}
+
+func (source *fakeSource) open(ctx context.Context, since time.Time) (io.ReadCloser, string, error) {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	source.calls++
+	if source.calls == 2 {
+		return nil, "", errors.New("pod not found")
+	}
+
+	source.since = append(source.since, since)
+
+	if len(source.connections) == 0 {
+		reader, writer := io.Pipe()
+
+		go func() {
+			<-ctx.Done()
+			_ = writer.Close()
+		}()
+
+		return reader, "fake-pod", nil
+	}
+
+	connection := source.connections[0]
+	source.connections = source.connections[1:]
+
+	return io.NopCloser(strings.NewReader(connection)), "fake-pod", nil
+}
+
+func (source *fakeSource) sinces() []time.Time {
+	source.mutex.Lock()
+	defer source.mutex.Unlock()
+
+	return append([]time.Time{}, source.since...)
+}
+
+func drain(subscription *Subscription) []Line {
+	var lines []Line
+
+	for line := range subscription.Lines {
+		lines = append(lines, line)
+	}
+
+	return lines
+}
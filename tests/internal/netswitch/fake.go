@@ -0,0 +1,369 @@
+package netswitch
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+)
+
+// VendorFake is the vendor of FakeSwitch.
+const VendorFake = "fake"
+
+// FakeInterface is the state of an interface of FakeSwitch.
+type FakeInterface struct {
+	Name     string
+	Disabled bool
+	// LinkDown simulates the peer of a physical port going down. It is not part of the configuration, so snapshots
+	// leave it unchanged.
+	LinkDown bool `json:"-"`
+	// LAG is the name of the aggregated interface a physical port is a member of.
+	LAG         string
+	LACP        LACPMode
+	FastRate    bool
+	LACPBlocked bool
+	Trunk       Trunk
+	QinQ        bool
+}
+
+// FakeSwitch is an in-memory Switch with a simple model of its interfaces. Physical ports always exist and deleting
+// them only resets their configuration, while other interfaces are created by configuring them. A LAG is up when it is
+// enabled, LACP packets are not blocked if LACP is used, and at least one of its members is up.
+type FakeSwitch struct {
+	mutex      sync.Mutex
+	ports      map[string]bool
+	interfaces map[string]*FakeInterface
+	clearedMAC []string
+	errors     map[string]error
+}
+
+var _ Switch = (*FakeSwitch)(nil)
+
+// NewFakeSwitch returns a FakeSwitch with the given physical ports, all enabled and up.
+func NewFakeSwitch(ports ...string) *FakeSwitch {
+	fake := &FakeSwitch{
+		ports:      make(map[string]bool),
+		interfaces: make(map[string]*FakeInterface),
+		errors:     make(map[string]error),
+	}
+
+	for _, port := range ports {
+		fake.ports[port] = true
+		fake.interfaces[port] = &FakeInterface{Name: port}
+	}
+
+	return fake
+}
+
+// SetError makes every call to the Switch method with the given name return err, without changing the switch, until
+// it is called again with a nil error.
+func (fake *FakeSwitch) SetError(method string, err error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if err == nil {
+		delete(fake.errors, method)
+
+		return
+	}
+
+	fake.errors[method] = err
+}
+
+// SetLinkDown simulates the peer of a physical port going down or coming back up.
+func (fake *FakeSwitch) SetLinkDown(port string, down bool) error {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if !fake.ports[port] {
+		return fmt.Errorf("switch port %s does not exist", port)
+	}
+
+	fake.interfaces[port].LinkDown = down
+
+	return nil
+}
+
+// Interface returns a copy of the state of an interface and whether it exists.
+func (fake *FakeSwitch) Interface(name string) (FakeInterface, bool) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	iface, found := fake.interfaces[name]
+	if !found {
+		return FakeInterface{}, false
+	}
+
+	return copyFakeInterface(iface), true
+}
+
+// ClearedMACAddresses returns the MAC addresses cleared from the switch, in order.
+func (fake *FakeSwitch) ClearedMACAddresses() []string {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	return slices.Clone(fake.clearedMAC)
+}
+
+// Vendor returns VendorFake.
+func (fake *FakeSwitch) Vendor() string {
+	return VendorFake
+}
+
+// Close does nothing.
+func (fake *FakeSwitch) Close() {}
+
+// SetInterfaceEnabled administratively enables or disables an interface.
+func (fake *FakeSwitch) SetInterfaceEnabled(name string, enabled bool) error {
+	return fake.update("SetInterfaceEnabled", func() error {
+		fake.getOrCreate(name).Disabled = !enabled
+
+		return nil
+	})
+}
+
+// IsInterfaceUp reports whether an interface is up according to the model of FakeSwitch.
+func (fake *FakeSwitch) IsInterfaceUp(name string) (bool, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if err := fake.errors["IsInterfaceUp"]; err != nil {
+		return false, err
+	}
+
+	iface, found := fake.interfaces[name]
+	if !found {
+		return false, fmt.Errorf("switch interface %s does not exist", name)
+	}
+
+	return fake.isUp(iface), nil
+}
+
+// DeleteInterfaces resets the configuration of physical ports and removes other interfaces.
+func (fake *FakeSwitch) DeleteInterfaces(names ...string) error {
+	return fake.update("DeleteInterfaces", func() error {
+		for _, name := range names {
+			fake.delete(name)
+		}
+
+		return nil
+	})
+}
+
+// ConfigureLAG aggregates the members of lag into its interface with its LACP mode.
+func (fake *FakeSwitch) ConfigureLAG(lag LAG) error {
+	return fake.update("ConfigureLAG", func() error {
+		switch lag.LACP {
+		case "":
+			lag.LACP = LACPDisabled
+		case LACPDisabled, LACPActive, LACPPassive:
+		default:
+			return fmt.Errorf("unknown LACP mode %s for switch LAG %s", lag.LACP, lag.Name)
+		}
+
+		for _, member := range lag.Members {
+			if !fake.ports[member] {
+				return fmt.Errorf("switch port %s does not exist", member)
+			}
+		}
+
+		for _, member := range lag.Members {
+			fake.interfaces[member].LAG = lag.Name
+		}
+
+		aggregated := fake.getOrCreate(lag.Name)
+		aggregated.LACP = lag.LACP
+		aggregated.FastRate = lag.FastRate && lag.LACP != LACPDisabled
+
+		return nil
+	})
+}
+
+// DeleteLAG removes the members of lag from the group and deletes its interface.
+func (fake *FakeSwitch) DeleteLAG(lag LAG) error {
+	return fake.update("DeleteLAG", func() error {
+		for _, member := range lag.Members {
+			if iface, found := fake.interfaces[member]; found && iface.LAG == lag.Name {
+				iface.LAG = ""
+			}
+		}
+
+		fake.delete(lag.Name)
+
+		return nil
+	})
+}
+
+// SetLACPBlocked drops or allows LACP packets received on an interface.
+func (fake *FakeSwitch) SetLACPBlocked(name string, blocked bool) error {
+	return fake.update("SetLACPBlocked", func() error {
+		fake.getOrCreate(name).LACPBlocked = blocked
+
+		return nil
+	})
+}
+
+// SetTrunk configures an interface as a trunk with the given VLANs, adding them to those already allowed.
+func (fake *FakeSwitch) SetTrunk(name string, trunk Trunk) error {
+	return fake.update("SetTrunk", func() error {
+		iface := fake.getOrCreate(name)
+
+		for _, vlan := range trunk.VLANs {
+			if !slices.Contains(iface.Trunk.VLANs, vlan) {
+				iface.Trunk.VLANs = append(iface.Trunk.VLANs, vlan)
+			}
+		}
+
+		if trunk.NativeVLAN > 0 {
+			iface.Trunk.NativeVLAN = trunk.NativeVLAN
+		}
+
+		if trunk.MTU > 0 {
+			iface.Trunk.MTU = trunk.MTU
+		}
+
+		return nil
+	})
+}
+
+// SetQinQ enables or disables 802.1ad tagging on an interface.
+func (fake *FakeSwitch) SetQinQ(name string, enabled bool) error {
+	return fake.update("SetQinQ", func() error {
+		fake.getOrCreate(name).QinQ = enabled
+
+		return nil
+	})
+}
+
+// ClearMACAddress records mac as cleared.
+func (fake *FakeSwitch) ClearMACAddress(mac string) error {
+	return fake.update("ClearMACAddress", func() error {
+		fake.clearedMAC = append(fake.clearedMAC, mac)
+
+		return nil
+	})
+}
+
+// Snapshot saves the configuration of the given interfaces as JSON.
+func (fake *FakeSwitch) Snapshot(names ...string) (*Snapshot, error) {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if err := fake.errors["Snapshot"]; err != nil {
+		return nil, err
+	}
+
+	snapshot := &Snapshot{Vendor: VendorFake, Interfaces: slices.Clone(names), Configs: make(map[string]string)}
+
+	for _, name := range names {
+		iface, found := fake.interfaces[name]
+		if !found {
+			continue
+		}
+
+		config, err := json.Marshal(iface)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal switch interface %s: %w", name, err)
+		}
+
+		snapshot.Configs[name] = string(config)
+	}
+
+	return snapshot, nil
+}
+
+// Restore deletes the configuration of the interfaces of snapshot and applies the saved one.
+func (fake *FakeSwitch) Restore(snapshot *Snapshot) error {
+	err := checkSnapshotVendor(fake, snapshot)
+	if err != nil {
+		return err
+	}
+
+	return fake.update("Restore", func() error {
+		saved := make(map[string]*FakeInterface)
+
+		for name, config := range snapshot.Configs {
+			iface := &FakeInterface{}
+
+			err := json.Unmarshal([]byte(config), iface)
+			if err != nil {
+				return fmt.Errorf("failed to unmarshal switch interface %s: %w", name, err)
+			}
+
+			saved[name] = iface
+		}
+
+		for _, name := range snapshot.Interfaces {
+			fake.delete(name)
+
+			if iface, found := saved[name]; found {
+				iface.LinkDown = fake.getOrCreate(name).LinkDown
+				fake.interfaces[name] = iface
+			}
+		}
+
+		return nil
+	})
+}
+
+// update runs change with the mutex held, unless an error was set for method. Changes are not atomic, so a failing
+// change may be partially applied like on a real switch.
+func (fake *FakeSwitch) update(method string, change func() error) error {
+	fake.mutex.Lock()
+	defer fake.mutex.Unlock()
+
+	if err := fake.errors[method]; err != nil {
+		return err
+	}
+
+	return change()
+}
+
+func (fake *FakeSwitch) getOrCreate(name string) *FakeInterface {
+	iface, found := fake.interfaces[name]
+	if !found {
+		iface = &FakeInterface{Name: name}
+		fake.interfaces[name] = iface
+	}
+
+	return iface
+}
+
+func (fake *FakeSwitch) delete(name string) {
+	if !fake.ports[name] {
+		delete(fake.interfaces, name)
+
+		return
+	}
+
+	fake.interfaces[name] = &FakeInterface{Name: name, LinkDown: fake.interfaces[name].LinkDown}
+}
+
+func (fake *FakeSwitch) isUp(iface *FakeInterface) bool {
+	if iface.Disabled {
+		return false
+	}
+
+	if fake.ports[iface.Name] {
+		return !iface.LinkDown
+	}
+
+	if iface.LACP != LACPDisabled && iface.LACPBlocked {
+		return false
+	}
+
+	for _, member := range fake.interfaces {
+		if member.LAG == iface.Name && fake.ports[member.Name] && !member.Disabled && !member.LinkDown {
+			return true
+		}
+	}
+
+	return false
+}
+
+func copyFakeInterface(iface *FakeInterface) FakeInterface {
+	copied := *iface
+	copied.Trunk.VLANs = slices.Clone(iface.Trunk.VLANs)
+
+	return copied
+}
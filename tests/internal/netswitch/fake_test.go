@@ -0,0 +1,135 @@
+package netswitch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFakeSwitchLAGStatus(t *testing.T) {
+	fake := NewFakeSwitch("xe-0/0/1", "xe-0/0/2")
+	lag := LAG{Name: "ae10", Members: []string{"xe-0/0/1", "xe-0/0/2"}, LACP: LACPActive}
+
+	assert.NoError(t, fake.ConfigureLAG(lag))
+	assert.True(t, isFakeInterfaceUp(t, fake, "ae10"))
+
+	assert.NoError(t, fake.SetInterfaceEnabled("xe-0/0/1", false))
+	assert.True(t, isFakeInterfaceUp(t, fake, "ae10"))
+
+	assert.NoError(t, fake.SetLinkDown("xe-0/0/2", true))
+	assert.False(t, isFakeInterfaceUp(t, fake, "ae10"))
+
+	assert.NoError(t, fake.SetInterfaceEnabled("xe-0/0/1", true))
+	assert.True(t, isFakeInterfaceUp(t, fake, "ae10"))
+
+	assert.NoError(t, fake.SetLACPBlocked("ae10", true))
+	assert.False(t, isFakeInterfaceUp(t, fake, "ae10"))
+
+	assert.NoError(t, fake.DeleteLAG(lag))
+
+	_, err := fake.IsInterfaceUp("ae10")
+	assert.Error(t, err)
+
+	member, found := fake.Interface("xe-0/0/1")
+	assert.True(t, found)
+	assert.Empty(t, member.LAG)
+
+	assert.Error(t, fake.ConfigureLAG(LAG{Name: "ae11", Members: []string{"xe-0/0/3"}}))
+	assert.Error(t, fake.ConfigureLAG(LAG{Name: "ae11", LACP: "slow"}))
+}
+
+func TestFakeSwitchSnapshotRestore(t *testing.T) {
+	fake := NewFakeSwitch("xe-0/0/1", "xe-0/0/2")
+
+	assert.NoError(t, fake.SetTrunk("xe-0/0/1", Trunk{VLANs: []int{100}, NativeVLAN: 100}))
+
+	snapshot, err := fake.Snapshot("xe-0/0/1", "xe-0/0/2", "ae10")
+	assert.NoError(t, err)
+	assert.Len(t, snapshot.Configs, 2)
+
+	assert.NoError(t, fake.ConfigureLAG(LAG{Name: "ae10", Members: []string{"xe-0/0/1", "xe-0/0/2"}}))
+	assert.NoError(t, fake.SetTrunk("xe-0/0/1", Trunk{VLANs: []int{200}, MTU: 9216}))
+	assert.NoError(t, fake.SetQinQ("xe-0/0/2", true))
+	assert.NoError(t, fake.SetLinkDown("xe-0/0/2", true))
+
+	assert.NoError(t, fake.Restore(snapshot))
+
+	iface, _ := fake.Interface("xe-0/0/1")
+	assert.Equal(t, FakeInterface{Name: "xe-0/0/1", Trunk: Trunk{VLANs: []int{100}, NativeVLAN: 100}}, iface)
+
+	iface, _ = fake.Interface("xe-0/0/2")
+	assert.Equal(t, FakeInterface{Name: "xe-0/0/2", LinkDown: true}, iface)
+
+	_, found := fake.Interface("ae10")
+	assert.False(t, found)
+
+	assert.Error(t, fake.Restore(&Snapshot{Vendor: VendorJunos}))
+}
+
+func TestWithRollback(t *testing.T) {
+	errConfigure := errors.New("commit failed")
+	errRestore := errors.New("session closed")
+
+	testCases := []struct {
+		name          string
+		configureErr  error
+		restoreErr    error
+		expectedLAG   bool
+		expectedError []error
+	}{
+		{
+			name:        "configure succeeds",
+			expectedLAG: true,
+		},
+		{
+			name:          "configure fails",
+			configureErr:  errConfigure,
+			expectedError: []error{errConfigure},
+		},
+		{
+			name:          "configure and restore fail",
+			configureErr:  errConfigure,
+			restoreErr:    errRestore,
+			expectedLAG:   true,
+			expectedError: []error{errConfigure, errRestore},
+		},
+	}
+
+	for _, testCase := range testCases {
+		fake := NewFakeSwitch("xe-0/0/1")
+		fake.SetError("Restore", testCase.restoreErr)
+
+		snapshot, err := WithRollback(fake, []string{"xe-0/0/1", "ae10"}, func() error {
+			lagErr := fake.ConfigureLAG(LAG{Name: "ae10", Members: []string{"xe-0/0/1"}})
+			assert.NoError(t, lagErr, testCase.name)
+
+			return testCase.configureErr
+		})
+
+		assert.NotNil(t, snapshot, testCase.name)
+		assert.Equal(t, len(testCase.expectedError) > 0, err != nil, testCase.name)
+
+		for _, expectedError := range testCase.expectedError {
+			assert.ErrorIs(t, err, expectedError, testCase.name)
+		}
+
+		_, found := fake.Interface("ae10")
+		assert.Equal(t, testCase.expectedLAG, found, testCase.name)
+	}
+
+	fake := NewFakeSwitch()
+	fake.SetError("Snapshot", errRestore)
+
+	_, err := WithRollback(fake, []string{"ae10"}, func() error { return nil })
+	assert.ErrorIs(t, err, errRestore)
+}
+
+func isFakeInterfaceUp(t *testing.T, fake *FakeSwitch, name string) bool {
+	t.Helper()
+
+	up, err := fake.IsInterfaceUp(name)
+	assert.NoError(t, err)
+
+	return up
+}
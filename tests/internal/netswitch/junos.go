@@ -0,0 +1,270 @@
+package netswitch
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	// VendorJunos is the vendor of Junos switches.
+	VendorJunos = "junos"
+
+	junosLACPBlockFilter = "BLOCK-LACP"
+)
+
+// junosSession is the NETCONF session used by Junos, implemented by netconfSession.
+type junosSession interface {
+	Config(commands []string) error
+	ApplyConfigInterface(config string) error
+	RunCommand(command string) (string, error)
+	GetInterfaceConfig(switchInterface string) (string, error)
+	IsSwitchInterfaceUp(switchInterface string) (bool, error)
+	Close()
+}
+
+// Junos is a Switch for Juniper switches, configured through set commands over NETCONF. VLANs are referred to by
+// their name, which must be vlan followed by the ID.
+type Junos struct {
+	session junosSession
+}
+
+var _ Switch = (*Junos)(nil)
+
+// NewJunos connects to the Junos switch at host with the given credentials.
+func NewJunos(host, user, password string) (*Junos, error) {
+	klog.V(90).Infof("Connecting to Junos switch %s", host)
+
+	session, err := newNetconfSession(host, user, password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Junos switch %s: %w", host, err)
+	}
+
+	return &Junos{session: session}, nil
+}
+
+// Vendor returns VendorJunos.
+func (junos *Junos) Vendor() string {
+	return VendorJunos
+}
+
+// Close closes the NETCONF session.
+func (junos *Junos) Close() {
+	junos.session.Close()
+}
+
+// SetInterfaceEnabled administratively enables or disables an interface.
+func (junos *Junos) SetInterfaceEnabled(name string, enabled bool) error {
+	klog.V(90).Infof("Setting switch interface %s enabled to %t", name, enabled)
+
+	action := "set"
+	if enabled {
+		action = "delete"
+	}
+
+	return junos.Config(fmt.Sprintf("%s interfaces %s disable", action, name))
+}
+
+// IsInterfaceUp reports whether the operational status of an interface is up.
+func (junos *Junos) IsInterfaceUp(name string) (bool, error) {
+	return junos.session.IsSwitchInterfaceUp(name)
+}
+
+// DeleteInterfaces removes all configuration from the given interfaces.
+func (junos *Junos) DeleteInterfaces(names ...string) error {
+	klog.V(90).Infof("Deleting switch interfaces %v", names)
+
+	var commands []string
+
+	for _, name := range names {
+		commands = append(commands, fmt.Sprintf("delete interfaces %s", name))
+	}
+
+	return junos.Config(commands...)
+}
+
+// ConfigureLAG aggregates the members of lag into its interface with its LACP mode.
+func (junos *Junos) ConfigureLAG(lag LAG) error {
+	klog.V(90).Infof("Configuring switch LAG %s with members %v and LACP %s", lag.Name, lag.Members, lag.LACP)
+
+	var commands []string
+
+	for _, member := range lag.Members {
+		commands = append(commands, fmt.Sprintf("set interfaces %s ether-options 802.3ad %s", member, lag.Name))
+	}
+
+	switch lag.LACP {
+	case LACPDisabled, "":
+		commands = append(commands, fmt.Sprintf("set interfaces %s aggregated-ether-options lacp disable", lag.Name))
+	case LACPActive, LACPPassive:
+		commands = append(commands,
+			fmt.Sprintf("set interfaces %s aggregated-ether-options lacp %s", lag.Name, lag.LACP))
+
+		if lag.FastRate {
+			commands = append(commands,
+				fmt.Sprintf("set interfaces %s aggregated-ether-options lacp periodic fast", lag.Name))
+		}
+	default:
+		return fmt.Errorf("unknown LACP mode %s for switch LAG %s", lag.LACP, lag.Name)
+	}
+
+	return junos.Config(commands...)
+}
+
+// DeleteLAG removes the members of lag from the group and deletes its interface.
+func (junos *Junos) DeleteLAG(lag LAG) error {
+	klog.V(90).Infof("Deleting switch LAG %s with members %v", lag.Name, lag.Members)
+
+	var commands []string
+
+	for _, member := range lag.Members {
+		commands = append(commands, fmt.Sprintf("delete interfaces %s ether-options 802.3ad", member))
+	}
+
+	commands = append(commands, fmt.Sprintf("delete interfaces %s", lag.Name))
+
+	return junos.Config(commands...)
+}
+
+// SetLACPBlocked applies or removes a firewall filter discarding LACP packets on an interface. The filter is created
+// when needed and left on the switch.
+func (junos *Junos) SetLACPBlocked(name string, blocked bool) error {
+	klog.V(90).Infof("Setting LACP blocked on switch interface %s to %t", name, blocked)
+
+	applyFilter := fmt.Sprintf("interfaces %s unit 0 family ethernet-switching filter input %s", name,
+		junosLACPBlockFilter)
+
+	if !blocked {
+		return junos.Config("delete " + applyFilter)
+	}
+
+	filter := "firewall family ethernet-switching filter " + junosLACPBlockFilter
+
+	return junos.Config(
+		fmt.Sprintf("set %s term BLOCK from ether-type 0x8809", filter),
+		fmt.Sprintf("set %s term BLOCK then discard", filter),
+		fmt.Sprintf("set %s term ALLOW-OTHER then accept", filter),
+		"set "+applyFilter)
+}
+
+// SetTrunk configures an interface as a trunk with the given VLANs.
+func (junos *Junos) SetTrunk(name string, trunk Trunk) error {
+	klog.V(90).Infof("Configuring switch interface %s as trunk with %+v", name, trunk)
+
+	commands := []string{fmt.Sprintf("set interfaces %s unit 0 family ethernet-switching interface-mode trunk", name)}
+
+	for _, vlan := range trunk.VLANs {
+		commands = append(commands, fmt.Sprintf(
+			"set interfaces %s unit 0 family ethernet-switching interface-mode trunk vlan members vlan%d", name, vlan))
+	}
+
+	if trunk.NativeVLAN > 0 {
+		commands = append(commands, fmt.Sprintf("set interfaces %s native-vlan-id %d", name, trunk.NativeVLAN))
+	}
+
+	if trunk.MTU > 0 {
+		commands = append(commands, fmt.Sprintf("set interfaces %s mtu %d", name, trunk.MTU))
+	}
+
+	return junos.Config(commands...)
+}
+
+// SetQinQ enables or disables 802.1ad tagging on an interface through the extended VLAN bridge encapsulation.
+func (junos *Junos) SetQinQ(name string, enabled bool) error {
+	klog.V(90).Infof("Setting QinQ on switch interface %s to %t", name, enabled)
+
+	if enabled {
+		return junos.Config(fmt.Sprintf("set interfaces %s vlan-tagging encapsulation extended-vlan-bridge", name))
+	}
+
+	return junos.Config(
+		fmt.Sprintf("delete interfaces %s vlan-tagging", name),
+		fmt.Sprintf("delete interfaces %s encapsulation extended-vlan-bridge", name))
+}
+
+// RunCommand runs an operational mode command on the switch and returns its JSON output. Like Config, it is meant for
+// what Switch does not cover.
+func (junos *Junos) RunCommand(command string) (string, error) {
+	output, err := junos.session.RunCommand(command)
+	if err != nil {
+		return "", fmt.Errorf("failed to run command %q on Junos switch: %w", command, err)
+	}
+
+	return output, nil
+}
+
+// ClearMACAddress removes a MAC address from the ethernet switching table.
+func (junos *Junos) ClearMACAddress(mac string) error {
+	klog.V(90).Infof("Clearing MAC address %s from the switch", mac)
+
+	_, err := junos.RunCommand(fmt.Sprintf("clear ethernet-switching table %s", mac))
+
+	return err
+}
+
+// Snapshot saves the XML configuration of the given interfaces. Interfaces whose configuration cannot be read are
+// skipped, since they might not exist yet.
+func (junos *Junos) Snapshot(names ...string) (*Snapshot, error) {
+	klog.V(90).Infof("Saving configuration of switch interfaces %v", names)
+
+	if len(names) == 0 {
+		return nil, fmt.Errorf("cannot save the configuration of no switch interfaces")
+	}
+
+	snapshot := &Snapshot{Vendor: VendorJunos, Interfaces: names, Configs: make(map[string]string)}
+
+	for _, name := range names {
+		config, err := junos.session.GetInterfaceConfig(name)
+		if err != nil {
+			klog.V(90).Infof("Failed to get configuration of switch interface %s: %v", name, err)
+
+			continue
+		}
+
+		if config != "" {
+			snapshot.Configs[name] = config
+		}
+	}
+
+	return snapshot, nil
+}
+
+// Restore deletes the configuration of the interfaces of snapshot and loads the saved one.
+func (junos *Junos) Restore(snapshot *Snapshot) error {
+	err := checkSnapshotVendor(junos, snapshot)
+	if err != nil {
+		return err
+	}
+
+	klog.V(90).Infof("Restoring configuration of switch interfaces %v", snapshot.Interfaces)
+
+	err = junos.DeleteInterfaces(snapshot.Interfaces...)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range snapshot.Interfaces {
+		config, found := snapshot.Configs[name]
+		if !found {
+			continue
+		}
+
+		err = junos.session.ApplyConfigInterface(config)
+		if err != nil {
+			return fmt.Errorf("failed to restore configuration of switch interface %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
+// Config loads and commits set commands on the switch. It is meant for Junos specific configuration which is not
+// covered by Switch.
+func (junos *Junos) Config(commands ...string) error {
+	err := junos.session.Config(commands)
+	if err != nil {
+		return fmt.Errorf("failed to configure Junos switch: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,169 @@
+package netswitch
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeJunosSession records the configuration sent to it and returns the interface configs it holds.
+type fakeJunosSession struct {
+	commands [][]string
+	applied  []string
+	configs  map[string]string
+}
+
+func (session *fakeJunosSession) Config(commands []string) error {
+	session.commands = append(session.commands, commands)
+
+	return nil
+}
+
+func (session *fakeJunosSession) ApplyConfigInterface(config string) error {
+	session.applied = append(session.applied, config)
+
+	return nil
+}
+
+func (session *fakeJunosSession) RunCommand(command string) (string, error) {
+	session.commands = append(session.commands, []string{command})
+
+	return "{}", nil
+}
+
+func (session *fakeJunosSession) GetInterfaceConfig(switchInterface string) (string, error) {
+	config, found := session.configs[switchInterface]
+	if !found {
+		return "", errors.New("no output available")
+	}
+
+	return config, nil
+}
+
+func (session *fakeJunosSession) IsSwitchInterfaceUp(_ string) (bool, error) {
+	return true, nil
+}
+
+func (session *fakeJunosSession) Close() {}
+
+func TestJunosCommands(t *testing.T) {
+	testCases := []struct {
+		name             string
+		configure        func(*Junos) error
+		expectedCommands [][]string
+	}{
+		{
+			name: "disable interface",
+			configure: func(junos *Junos) error {
+				return junos.SetInterfaceEnabled("xe-0/0/1", false)
+			},
+			expectedCommands: [][]string{{"set interfaces xe-0/0/1 disable"}},
+		},
+		{
+			name: "lacp lag",
+			configure: func(junos *Junos) error {
+				return junos.ConfigureLAG(LAG{
+					Name: "ae10", Members: []string{"xe-0/0/1", "xe-0/0/2"}, LACP: LACPActive, FastRate: true,
+				})
+			},
+			expectedCommands: [][]string{{
+				"set interfaces xe-0/0/1 ether-options 802.3ad ae10",
+				"set interfaces xe-0/0/2 ether-options 802.3ad ae10",
+				"set interfaces ae10 aggregated-ether-options lacp active",
+				"set interfaces ae10 aggregated-ether-options lacp periodic fast",
+			}},
+		},
+		{
+			name: "static lag",
+			configure: func(junos *Junos) error {
+				return junos.ConfigureLAG(LAG{Name: "ae10", Members: []string{"xe-0/0/1"}, FastRate: true})
+			},
+			expectedCommands: [][]string{{
+				"set interfaces xe-0/0/1 ether-options 802.3ad ae10",
+				"set interfaces ae10 aggregated-ether-options lacp disable",
+			}},
+		},
+		{
+			name: "delete lag",
+			configure: func(junos *Junos) error {
+				return junos.DeleteLAG(LAG{Name: "ae10", Members: []string{"xe-0/0/1"}})
+			},
+			expectedCommands: [][]string{{
+				"delete interfaces xe-0/0/1 ether-options 802.3ad",
+				"delete interfaces ae10",
+			}},
+		},
+		{
+			name: "trunk",
+			configure: func(junos *Junos) error {
+				return junos.SetTrunk("ae10", Trunk{VLANs: []int{100}, NativeVLAN: 100, MTU: 9216})
+			},
+			expectedCommands: [][]string{{
+				"set interfaces ae10 unit 0 family ethernet-switching interface-mode trunk",
+				"set interfaces ae10 unit 0 family ethernet-switching interface-mode trunk vlan members vlan100",
+				"set interfaces ae10 native-vlan-id 100",
+				"set interfaces ae10 mtu 9216",
+			}},
+		},
+		{
+			name:      "disable qinq",
+			configure: func(junos *Junos) error { return junos.SetQinQ("xe-0/0/1", false) },
+			expectedCommands: [][]string{{
+				"delete interfaces xe-0/0/1 vlan-tagging",
+				"delete interfaces xe-0/0/1 encapsulation extended-vlan-bridge",
+			}},
+		},
+		{
+			name:      "unblock lacp",
+			configure: func(junos *Junos) error { return junos.SetLACPBlocked("ae10", false) },
+			expectedCommands: [][]string{
+				{"delete interfaces ae10 unit 0 family ethernet-switching filter input BLOCK-LACP"},
+			},
+		},
+		{
+			name:             "clear mac",
+			configure:        func(junos *Junos) error { return junos.ClearMACAddress("00:11:22:33:44:55") },
+			expectedCommands: [][]string{{"clear ethernet-switching table 00:11:22:33:44:55"}},
+		},
+		{
+			name: "raw config",
+			configure: func(junos *Junos) error {
+				return junos.Config("delete interfaces xe-0/0/1 unit 0",
+					"set interfaces xe-0/0/1 flexible-vlan-tagging encapsulation extended-vlan-bridge")
+			},
+			expectedCommands: [][]string{{
+				"delete interfaces xe-0/0/1 unit 0",
+				"set interfaces xe-0/0/1 flexible-vlan-tagging encapsulation extended-vlan-bridge",
+			}},
+		},
+	}
+
+	for _, testCase := range testCases {
+		session := &fakeJunosSession{}
+
+		err := testCase.configure(&Junos{session: session})
+		assert.NoError(t, err, testCase.name)
+		assert.Equal(t, testCase.expectedCommands, session.commands, testCase.name)
+	}
+}
+
+func TestJunosSnapshotRestore(t *testing.T) {
+	session := &fakeJunosSession{configs: map[string]string{"xe-0/0/1": "<configuration>xe-0/0/1</configuration>"}}
+	junos := &Junos{session: session}
+
+	snapshot, err := junos.Snapshot("xe-0/0/1", "ae10")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"xe-0/0/1": "<configuration>xe-0/0/1</configuration>"}, snapshot.Configs)
+
+	err = junos.Restore(snapshot)
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"delete interfaces xe-0/0/1", "delete interfaces ae10"}}, session.commands)
+	assert.Equal(t, []string{"<configuration>xe-0/0/1</configuration>"}, session.applied)
+
+	err = junos.Restore(&Snapshot{Vendor: VendorFake})
+	assert.Error(t, err)
+
+	_, err = junos.Snapshot()
+	assert.Error(t, err)
+}
@@ -0,0 +1,122 @@
+// Package netswitch configures the lab switch connected to the cluster nodes through the vendor-neutral Switch
+// interface. Junos drives Juniper switches over NETCONF and FakeSwitch models a switch in memory, so the switch
+// orchestration of the suites can be tested without one.
+package netswitch
+
+import (
+	"fmt"
+
+	"k8s.io/klog/v2"
+)
+
+// LACPMode is the LACP mode of a link aggregation group.
+type LACPMode string
+
+const (
+	// LACPDisabled aggregates the member ports statically, without LACP.
+	LACPDisabled LACPMode = "disabled"
+	// LACPActive initiates LACP negotiation with the peer.
+	LACPActive LACPMode = "active"
+	// LACPPassive only answers LACP negotiation started by the peer.
+	LACPPassive LACPMode = "passive"
+)
+
+// LAG is a link aggregation group of switch ports.
+type LAG struct {
+	// Name is the name of the aggregated interface, such as ae10 on Junos.
+	Name string
+	// Members are the physical ports aggregated in the group.
+	Members []string
+	LACP    LACPMode
+	// FastRate requests LACP packets every second instead of every 30 seconds. It is ignored when LACP is disabled.
+	FastRate bool
+}
+
+// Trunk is the VLAN trunking configuration of a switch interface.
+type Trunk struct {
+	// VLANs are the IDs of the VLANs allowed on the interface.
+	VLANs []int
+	// NativeVLAN is the VLAN of untagged frames. It is not set when zero.
+	NativeVLAN int
+	// MTU is the MTU of the interface. It is not set when zero.
+	MTU int
+}
+
+// Snapshot is the saved configuration of a set of switch interfaces, as returned by Switch.Snapshot. Configs are in a
+// vendor specific format and only interfaces that had a configuration have an entry.
+type Snapshot struct {
+	Vendor     string
+	Interfaces []string
+	Configs    map[string]string
+}
+
+// Switch is a lab switch whose interfaces can be reconfigured by the tests. Every method applies and commits its
+// changes before returning.
+type Switch interface {
+	// Vendor returns the name of the switch vendor, which is also the format of its snapshots.
+	Vendor() string
+	// Close releases the connection to the switch.
+	Close()
+
+	// SetInterfaceEnabled administratively enables or disables an interface.
+	SetInterfaceEnabled(name string, enabled bool) error
+	// IsInterfaceUp reports whether the operational status of an interface is up.
+	IsInterfaceUp(name string) (bool, error)
+	// DeleteInterfaces removes all configuration from the given interfaces.
+	DeleteInterfaces(names ...string) error
+
+	// ConfigureLAG aggregates the members of lag into its interface with its LACP mode.
+	ConfigureLAG(lag LAG) error
+	// DeleteLAG removes the members of lag from the group and deletes its interface.
+	DeleteLAG(lag LAG) error
+	// SetLACPBlocked drops or allows LACP packets received on an interface, which breaks LACP negotiation without
+	// bringing the link down.
+	SetLACPBlocked(name string, blocked bool) error
+
+	// SetTrunk configures an interface as a trunk with the given VLANs.
+	SetTrunk(name string, trunk Trunk) error
+	// SetQinQ enables or disables 802.1ad tagging on an interface.
+	SetQinQ(name string, enabled bool) error
+	// ClearMACAddress removes a MAC address from the forwarding table of the switch.
+	ClearMACAddress(mac string) error
+
+	// Snapshot saves the configuration of the given interfaces.
+	Snapshot(names ...string) (*Snapshot, error)
+	// Restore deletes the configuration of the interfaces of snapshot and applies the saved one.
+	Restore(snapshot *Snapshot) error
+}
+
+// WithRollback saves the configuration of the given interfaces, then runs configure and restores the saved
+// configuration if it fails. The error of configure is returned, wrapping the restore error if any.
+func WithRollback(netSwitch Switch, names []string, configure func() error) (*Snapshot, error) {
+	snapshot, err := netSwitch.Snapshot(names...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save switch interfaces %v: %w", names, err)
+	}
+
+	err = configure()
+	if err == nil {
+		return snapshot, nil
+	}
+
+	klog.V(90).Infof("Switch configuration failed, restoring interfaces %v: %v", names, err)
+
+	restoreErr := netSwitch.Restore(snapshot)
+	if restoreErr != nil {
+		return snapshot, fmt.Errorf("%w (failed to restore switch interfaces: %w)", err, restoreErr)
+	}
+
+	return snapshot, err
+}
+
+func checkSnapshotVendor(netSwitch Switch, snapshot *Snapshot) error {
+	if snapshot == nil {
+		return fmt.Errorf("cannot restore a nil snapshot")
+	}
+
+	if snapshot.Vendor != netSwitch.Vendor() {
+		return fmt.Errorf("cannot restore %s snapshot on %s switch", snapshot.Vendor, netSwitch.Vendor())
+	}
+
+	return nil
+}
@@ -1,4 +1,4 @@
-package cmd
+package netswitch
 
 import (
 	"context"
@@ -25,12 +25,12 @@ var (
 )
 
 type (
-	// Junos creates a struct to retrieve output from the lab Juniper switch.
-	Junos struct {
-		Session *netconf.Session
+	// netconfSession is the NETCONF session to a Junos switch used by Junos.
+	netconfSession struct {
+		session *netconf.Session
 	}
-	// InterfaceStatus is struct that collects the data from the Juniper interfaces.
-	InterfaceStatus struct {
+	// interfaceStatus collects the data from the Juniper interfaces.
+	interfaceStatus struct {
 		InterfaceInformation []struct {
 			PhysicalInterface []struct {
 				Name []struct {
@@ -67,9 +67,9 @@ type (
 	}
 )
 
-// NewSession establishes a new connection to a Junos device that we will use
+// newNetconfSession establishes a new connection to a Junos device that we will use
 // to run our commands against.
-func NewSession(host, user, password string) (*Junos, error) {
+func newNetconfSession(host, user, password string) (*netconfSession, error) {
 	var session *netconf.Session
 
 	err := wait.PollUntilContextTimeout(context.TODO(), 30*time.Second, 120*time.Second, true,
@@ -87,21 +87,21 @@ func NewSession(host, user, password string) (*Junos, error) {
 		return nil, err
 	}
 
-	return &Junos{
-		Session: session,
+	return &netconfSession{
+		session: session,
 	}, nil
 }
 
 // Close disconnects the session to the device.
-func (j *Junos) Close() {
-	j.Session.Transport.Close()
+func (j *netconfSession) Close() {
+	j.session.Transport.Close()
 }
 
 // Commit commits the configuration.
-func (j *Junos) Commit() error {
+func (j *netconfSession) Commit() error {
 	var errs commitResults
 
-	reply, err := j.Session.Exec(netconf.RawMethod(rpcCommit))
+	reply, err := j.session.Exec(netconf.RawMethod(rpcCommit))
 	if err != nil {
 		return err
 	}
@@ -119,6 +119,11 @@ func (j *Junos) Commit() error {
 
 	if errs.Errors != nil {
 		for _, m := range errs.Errors {
+			// Lab switches without the feature licenses warn about them on every commit, which is not a failure.
+			if strings.Contains(m.Message, "license") {
+				continue
+			}
+
 			message := fmt.Sprintf("[%s]\n    %s\nError: %s", strings.Trim(m.Path, "[\r\n]"),
 				strings.Trim(m.Element, "[\r\n]"), strings.Trim(m.Message, "[\r\n]"))
 
@@ -130,10 +135,10 @@ func (j *Junos) Commit() error {
 }
 
 // Config sends commands to a Juniper switch.
-func (j *Junos) Config(commands []string) error {
+func (j *netconfSession) Config(commands []string) error {
 	command := fmt.Sprintf(rpcConfigStringSet, strings.Join(commands, "\n"))
 
-	reply, err := j.Session.Exec(netconf.RawMethod(command))
+	reply, err := j.session.Exec(netconf.RawMethod(command))
 	if err != nil {
 		return err
 	}
@@ -153,10 +158,10 @@ func (j *Junos) Config(commands []string) error {
 }
 
 // ApplyConfigInterface applies given interface configuration to a switch.
-func (j *Junos) ApplyConfigInterface(config string) error {
+func (j *netconfSession) ApplyConfigInterface(config string) error {
 	command := fmt.Sprintf(rpcApplyConfig, config)
 
-	reply, err := j.Session.Exec(netconf.RawMethod(command))
+	reply, err := j.session.Exec(netconf.RawMethod(command))
 	if err != nil {
 		return err
 	}
@@ -176,10 +181,10 @@ func (j *Junos) ApplyConfigInterface(config string) error {
 }
 
 // RunCommand executes any operational mode command, such as "show" or "request".
-func (j *Junos) RunCommand(cmd string) (string, error) {
+func (j *netconfSession) RunCommand(cmd string) (string, error) {
 	command := fmt.Sprintf(rpcCommandJSON, cmd)
 
-	reply, err := j.Session.Exec(netconf.RawMethod(command))
+	reply, err := j.session.Exec(netconf.RawMethod(command))
 	if err != nil {
 		return "", err
 	}
@@ -198,32 +203,32 @@ func (j *Junos) RunCommand(cmd string) (string, error) {
 }
 
 // IsSwitchInterfaceUp reports whether the given switch interface oper-status is up.
-func (j *Junos) IsSwitchInterfaceUp(switchInterface string) (bool, error) {
+func (j *netconfSession) IsSwitchInterfaceUp(switchInterface string) (bool, error) {
 	jsonOutput, err := j.RunCommand(fmt.Sprintf("show interfaces %s", switchInterface))
 	if err != nil {
 		return false, err
 	}
 
-	var interfaceStatus InterfaceStatus
+	var status interfaceStatus
 
-	if err := json.Unmarshal([]byte(jsonOutput), &interfaceStatus); err != nil {
+	if err := json.Unmarshal([]byte(jsonOutput), &status); err != nil {
 		return false, err
 	}
 
-	if len(interfaceStatus.InterfaceInformation) == 0 ||
-		len(interfaceStatus.InterfaceInformation[0].PhysicalInterface) == 0 ||
-		len(interfaceStatus.InterfaceInformation[0].PhysicalInterface[0].OperStatus) == 0 {
+	if len(status.InterfaceInformation) == 0 ||
+		len(status.InterfaceInformation[0].PhysicalInterface) == 0 ||
+		len(status.InterfaceInformation[0].PhysicalInterface[0].OperStatus) == 0 {
 		return false, fmt.Errorf("no oper-status for switch interface %s", switchInterface)
 	}
 
-	return interfaceStatus.InterfaceInformation[0].PhysicalInterface[0].OperStatus[0].Data == "up", nil
+	return status.InterfaceInformation[0].PhysicalInterface[0].OperStatus[0].Data == "up", nil
 }
 
 // GetInterfaceConfig returns configuration for given interface.
-func (j *Junos) GetInterfaceConfig(switchInterface string) (string, error) {
+func (j *netconfSession) GetInterfaceConfig(switchInterface string) (string, error) {
 	command := fmt.Sprintf(rpcGetInterfaceConfig, switchInterface)
 
-	reply, err := j.Session.Exec(netconf.RawMethod(command))
+	reply, err := j.session.Exec(netconf.RawMethod(command))
 	if err != nil {
 		return "", err
 	}
@@ -240,76 +245,3 @@ func (j *Junos) GetInterfaceConfig(switchInterface string) (string, error) {
 
 	return reply.Data, nil
 }
-
-// SaveInterfaceConfigs saves the configuration of multiple switch interfaces.
-func (j *Junos) SaveInterfaceConfigs(interfaces []string) ([]string, error) {
-	if len(interfaces) == 0 {
-		return nil, fmt.Errorf("interfaces list cannot be empty")
-	}
-
-	var configs []string
-
-	for _, iface := range interfaces {
-		config, err := j.GetInterfaceConfig(iface)
-		if err != nil {
-			// Interface might not exist or have no config - that's okay, we'll just skip it
-			klog.V(90).Infof("Failed to get config for interface %s: %v", iface, err)
-
-			continue
-		}
-
-		if config != "" {
-			configs = append(configs, config)
-		}
-	}
-
-	return configs, nil
-}
-
-// RestoreInterfaceConfigs restores the configuration of multiple switch interfaces.
-func (j *Junos) RestoreInterfaceConfigs(configs []string) error {
-	if len(configs) == 0 {
-		return fmt.Errorf("configs list cannot be empty")
-	}
-
-	for _, config := range configs {
-		err := j.ApplyConfigInterface(config)
-		if err != nil {
-			return fmt.Errorf("failed to restore interface config: %w", err)
-		}
-	}
-
-	return nil
-}
-
-// DisableLACP removes LACP configuration from switch interfaces.
-func (j *Junos) DisableLACP(lacpInterfaces, physicalInterfaces []string) error {
-	if len(lacpInterfaces) == 0 {
-		return fmt.Errorf("lacpInterfaces list cannot be empty")
-	}
-
-	if len(physicalInterfaces) == 0 {
-		return fmt.Errorf("physicalInterfaces list cannot be empty")
-	}
-
-	var commands []string
-
-	for _, physicalInterface := range physicalInterfaces {
-		commands = append(commands, fmt.Sprintf("delete interfaces %s ether-options 802.3ad", physicalInterface))
-	}
-
-	for _, lacpInterface := range lacpInterfaces {
-		commands = append(commands, fmt.Sprintf("delete interfaces %s", lacpInterface))
-	}
-
-	for _, physicalInterface := range physicalInterfaces {
-		commands = append(commands, fmt.Sprintf("delete interfaces %s", physicalInterface))
-	}
-
-	err := j.Config(commands)
-	if err != nil {
-		return fmt.Errorf("failed to execute switch cleanup commands: %w", err)
-	}
-
-	return nil
-}
@@ -0,0 +1,41 @@
+package nodeexec
+
+import (
+	"fmt"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+)
+
+// New returns the NodeExecutor selected by the node_executor config, wrapped with DefaultRetryPolicy using the
+// node_executor_attempts config. Debug pods are created in the MCO namespace, which allows privileged pods. The
+// daemon-pod backend needs suite specific parameters, so it is created using NewDaemonPodExecutor instead.
+func New(apiClient *clients.Settings, generalConfig *config.GeneralConfig) (NodeExecutor, error) {
+	if generalConfig == nil {
+		return nil, fmt.Errorf("cannot create node executor without a general config")
+	}
+
+	var executor NodeExecutor
+
+	switch generalConfig.NodeExecutor {
+	case BackendMCD, "":
+		executor = NewMCDExecutor(apiClient, generalConfig.MCONamespace, generalConfig.MCOConfigDaemonName)
+	case BackendDebugPod:
+		executor = NewDebugPodExecutor(apiClient, generalConfig.MCONamespace, generalConfig.NodeExecutorImage)
+	case BackendSSH:
+		executor = NewSSHExecutor(apiClient, generalConfig.SSHUser, generalConfig.SSHKeyPath)
+	case BackendDaemonPod:
+		return nil, fmt.Errorf("node executor backend %s must be created with NewDaemonPodExecutor", BackendDaemonPod)
+	default:
+		return nil, fmt.Errorf("unknown node executor backend %q, must be one of %s, %s or %s",
+			generalConfig.NodeExecutor, BackendMCD, BackendDebugPod, BackendSSH)
+	}
+
+	policy := DefaultRetryPolicy
+
+	if generalConfig.NodeExecutorAttempts > 0 {
+		policy.Attempts = generalConfig.NodeExecutorAttempts
+	}
+
+	return WithRetries(executor, policy), nil
+}
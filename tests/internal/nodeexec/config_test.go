@@ -0,0 +1,44 @@
+package nodeexec
+
+import (
+	"testing"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNew(t *testing.T) {
+	testCases := []struct {
+		backend         string
+		expectedBackend string
+		expectedError   bool
+	}{
+		{backend: "", expectedBackend: BackendMCD},
+		{backend: BackendMCD, expectedBackend: BackendMCD},
+		{backend: BackendDebugPod, expectedBackend: BackendDebugPod},
+		{backend: BackendSSH, expectedBackend: BackendSSH},
+		{backend: BackendDaemonPod, expectedError: true},
+		{backend: "telnet", expectedError: true},
+	}
+
+	for _, testCase := range testCases {
+		executor, err := New(nil, &config.GeneralConfig{NodeExecutor: testCase.backend, NodeExecutorAttempts: 5})
+
+		if testCase.expectedError {
+			assert.Error(t, err)
+
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expectedBackend, executor.Backend())
+
+		retrying, isRetrying := executor.(*retryingExecutor)
+		if assert.True(t, isRetrying) {
+			assert.Equal(t, uint(5), retrying.policy.Attempts)
+		}
+	}
+
+	_, err := New(nil, nil)
+	assert.Error(t, err)
+}
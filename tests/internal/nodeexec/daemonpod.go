@@ -0,0 +1,58 @@
+package nodeexec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+)
+
+// BackendDaemonPod is the backend executing in the pods of a daemonset, such as the PTP daemon.
+const BackendDaemonPod = "daemon-pod"
+
+// DaemonPodExecutor executes commands in the pod of a daemonset running on the node. The pod is looked up again on
+// every execution, so commands keep working after the daemon pods are recreated. Commands run in the container
+// unless HostNamespaces is set.
+type DaemonPodExecutor struct {
+	APIClient     *clients.Settings
+	Namespace     string
+	LabelSelector string
+	// Container is the container to execute in. The first container of the pod is used when it is empty.
+	Container string
+	// HostNamespaces runs commands in the namespaces of the host using nsenter. The pod must share the PID namespace
+	// of the host and be privileged.
+	HostNamespaces bool
+}
+
+// NewDaemonPodExecutor returns a DaemonPodExecutor for the pods in namespace matching labelSelector.
+func NewDaemonPodExecutor(apiClient *clients.Settings, namespace, labelSelector, container string) *DaemonPodExecutor {
+	return &DaemonPodExecutor{
+		APIClient:     apiClient,
+		Namespace:     namespace,
+		LabelSelector: labelSelector,
+		Container:     container,
+	}
+}
+
+// Execute runs argv in the daemon pod on nodeName.
+func (executor *DaemonPodExecutor) Execute(ctx context.Context, nodeName string, argv ...string) (Result, error) {
+	if executor.Namespace == "" || executor.LabelSelector == "" {
+		return Result{}, fmt.Errorf("daemon pod namespace and label selector cannot be empty")
+	}
+
+	daemonPod, err := findNodePod(executor.APIClient, executor.Namespace, executor.LabelSelector, nodeName)
+	if err != nil {
+		return Result{}, err
+	}
+
+	if executor.HostNamespaces {
+		argv = hostArgv(argv)
+	}
+
+	return execInPod(ctx, executor.APIClient, daemonPod, executor.Container, nodeName, argv)
+}
+
+// Backend returns BackendDaemonPod.
+func (executor *DaemonPodExecutor) Backend() string {
+	return BackendDaemonPod
+}
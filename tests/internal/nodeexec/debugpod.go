@@ -0,0 +1,129 @@
+package nodeexec
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// BackendDebugPod is the backend executing in privileged debug pods created on each node.
+	BackendDebugPod = "debug-pod"
+
+	// debugPodLabel is the label of the debug pods, set to the name of their node.
+	debugPodLabel = "eco-nodeexec-debug"
+	// debugPodDeleteTimeout is how long to wait for debug pods to be deleted.
+	debugPodDeleteTimeout = time.Minute
+)
+
+// DebugPodExecutor executes commands on the host through a privileged pod sharing the network and PID namespaces of
+// the host. One pod is created per node on its first use and reused until Close is called.
+type DebugPodExecutor struct {
+	APIClient *clients.Settings
+	// Namespace is where the debug pods are created. It must allow privileged pods.
+	Namespace string
+	// Image is the image of the debug pods. It only needs to provide nsenter.
+	Image string
+
+	mutex sync.Mutex
+	pods  map[string]*pod.Builder
+}
+
+// NewDebugPodExecutor returns a DebugPodExecutor creating pods from image in namespace.
+func NewDebugPodExecutor(apiClient *clients.Settings, namespace, image string) *DebugPodExecutor {
+	return &DebugPodExecutor{APIClient: apiClient, Namespace: namespace, Image: image}
+}
+
+// Execute runs argv on the host of nodeName, creating the debug pod on the node first if needed.
+func (executor *DebugPodExecutor) Execute(ctx context.Context, nodeName string, argv ...string) (Result, error) {
+	debugPod, err := executor.getPod(nodeName)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return execInPod(ctx, executor.APIClient, debugPod, "", nodeName, hostArgv(argv))
+}
+
+// Backend returns BackendDebugPod.
+func (executor *DebugPodExecutor) Backend() string {
+	return BackendDebugPod
+}
+
+// Close deletes the debug pods created by the executor. It should be called once the suite is done using it, for
+// example in AfterAll.
+func (executor *DebugPodExecutor) Close() error {
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+
+	for nodeName, debugPod := range executor.pods {
+		klog.V(90).Infof("Deleting debug pod %s on node %s", debugPod.Definition.Name, nodeName)
+
+		_, err := debugPod.DeleteAndWait(debugPodDeleteTimeout)
+		if err != nil {
+			return fmt.Errorf("failed to delete debug pod on node %s: %w", nodeName, err)
+		}
+
+		delete(executor.pods, nodeName)
+	}
+
+	return nil
+}
+
+// getPod returns the debug pod on nodeName, reusing a running pod left by a previous executor and creating it
+// otherwise.
+func (executor *DebugPodExecutor) getPod(nodeName string) (*pod.Builder, error) {
+	if executor.Namespace == "" || executor.Image == "" {
+		return nil, fmt.Errorf("debug pod namespace and image cannot be empty")
+	}
+
+	executor.mutex.Lock()
+	defer executor.mutex.Unlock()
+
+	if debugPod, found := executor.pods[nodeName]; found && debugPod.Exists() {
+		return debugPod, nil
+	}
+
+	debugPod, err := findNodePod(executor.APIClient, executor.Namespace, debugPodLabel+"="+nodeName, nodeName)
+	if err != nil {
+		klog.V(90).Infof("Creating debug pod on node %s since no existing one was found: %v", nodeName, err)
+
+		debugPod, err = pod.NewBuilder(executor.APIClient, debugPodName(nodeName), executor.Namespace, executor.Image).
+			WithPrivilegedFlag().
+			WithHostNetwork().
+			WithHostPid(true).
+			WithTolerationToControlPlane().
+			WithTolerationToMaster().
+			WithLabel(debugPodLabel, nodeName).
+			WithNodeSelector(map[string]string{"kubernetes.io/hostname": nodeName}).
+			CreateAndWaitUntilRunning(podReadyTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create debug pod on node %s: %w", nodeName, err)
+		}
+	}
+
+	if executor.pods == nil {
+		executor.pods = make(map[string]*pod.Builder)
+	}
+
+	executor.pods[nodeName] = debugPod
+
+	return debugPod, nil
+}
+
+// debugPodName returns the name of the debug pod on nodeName, keeping it within the 63 characters allowed for the
+// hostname of pods without ending in a separator.
+func debugPodName(nodeName string) string {
+	name := "eco-nodeexec-" + nodeName
+
+	if len(name) > 63 {
+		name = strings.TrimRight(name[:63], "-.")
+	}
+
+	return name
+}
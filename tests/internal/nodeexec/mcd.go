@@ -0,0 +1,47 @@
+package nodeexec
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// BackendMCD is the backend executing in the machine-config-daemon pod of each node.
+const BackendMCD = "mcd"
+
+// MCDExecutor executes commands on the host through the machine-config-daemon pod running on the node, which shares
+// the PID namespace of the host. It needs no extra pods but only works on clusters managed by the MCO.
+type MCDExecutor struct {
+	APIClient *clients.Settings
+	// Namespace is the namespace of the MCO, usually GeneralConfig.MCONamespace.
+	Namespace string
+	// DaemonName is the name of the machine-config-daemon daemonset, usually GeneralConfig.MCOConfigDaemonName.
+	DaemonName string
+}
+
+// NewMCDExecutor returns an MCDExecutor using the machine-config-daemon pods in namespace.
+func NewMCDExecutor(apiClient *clients.Settings, namespace, daemonName string) *MCDExecutor {
+	return &MCDExecutor{APIClient: apiClient, Namespace: namespace, DaemonName: daemonName}
+}
+
+// Execute runs argv on the host of nodeName.
+func (executor *MCDExecutor) Execute(ctx context.Context, nodeName string, argv ...string) (Result, error) {
+	if executor.Namespace == "" || executor.DaemonName == "" {
+		return Result{}, fmt.Errorf("mco namespace and config daemon name cannot be empty")
+	}
+
+	mcdPod, err := findNodePod(executor.APIClient, executor.Namespace,
+		labels.SelectorFromSet(labels.Set{"k8s-app": executor.DaemonName}).String(), nodeName)
+	if err != nil {
+		return Result{}, err
+	}
+
+	return execInPod(ctx, executor.APIClient, mcdPod, "", nodeName, hostArgv(argv))
+}
+
+// Backend returns BackendMCD.
+func (executor *MCDExecutor) Backend() string {
+	return BackendMCD
+}
@@ -0,0 +1,112 @@
+// Package nodeexec runs commands on the host of cluster nodes through a single NodeExecutor interface. Backends
+// execute in the machine-config-daemon pods, in privileged debug pods, in the pods of a daemonset or over SSH, but
+// all of them take the command as argv, honor the context for cancellation and timeouts, keep stdout and stderr
+// separate and report the exit code. Every execution is recorded through execrecorder.
+package nodeexec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// NodeExecutor executes commands on the host of a node.
+type NodeExecutor interface {
+	// Execute runs argv on nodeName and returns its result. Commands exiting with a non-zero code return an
+	// *ExitError along with their result, while other errors mean the command may not have run at all.
+	Execute(ctx context.Context, nodeName string, argv ...string) (Result, error)
+	// Backend returns the name of the backend, as used in the node_executor config.
+	Backend() string
+}
+
+// Result is the output of a command executed on a node.
+type Result struct {
+	Stdout   string
+	Stderr   string
+	ExitCode int
+}
+
+// ExitError is returned when a command ran but exited with a non-zero code.
+type ExitError struct {
+	Node     string
+	Argv     []string
+	ExitCode int
+	Stderr   string
+}
+
+// Error returns the command, node and exit code along with the stderr of the command, if any.
+func (exitError *ExitError) Error() string {
+	message := fmt.Sprintf("command %s on node %s exited with code %d",
+		Quote(exitError.Argv...), exitError.Node, exitError.ExitCode)
+
+	if stderr := strings.TrimSpace(exitError.Stderr); stderr != "" {
+		message += ": " + stderr
+	}
+
+	return message
+}
+
+// IsExitError returns true if err is or wraps an *ExitError, meaning the command ran but failed.
+func IsExitError(err error) bool {
+	var exitError *ExitError
+
+	return errors.As(err, &exitError)
+}
+
+// Shell returns the argv running script with sh -c. It should only be used for commands needing pipes, redirects or
+// other shell features, with any untrusted value quoted using Quote.
+func Shell(script string) []string {
+	return []string{"sh", "-c", script}
+}
+
+// Quote returns argv as a single shell command line, with each argument single-quoted unless it only contains
+// characters the shell never interprets.
+func Quote(argv ...string) string {
+	quoted := make([]string, 0, len(argv))
+
+	for _, arg := range argv {
+		quoted = append(quoted, quoteArg(arg))
+	}
+
+	return strings.Join(quoted, " ")
+}
+
+// Output runs argv on nodeName using executor and returns its stdout. It is a shorthand for callers that only need
+// the output of successful commands.
+func Output(ctx context.Context, executor NodeExecutor, nodeName string, argv ...string) (string, error) {
+	result, err := executor.Execute(ctx, nodeName, argv...)
+	if err != nil {
+		return "", err
+	}
+
+	return result.Stdout, nil
+}
+
+func quoteArg(arg string) string {
+	if arg == "" {
+		return "''"
+	}
+
+	isSafe := strings.IndexFunc(arg, func(char rune) bool {
+		return !(char >= 'a' && char <= 'z' || char >= 'A' && char <= 'Z' || char >= '0' && char <= '9' ||
+			strings.ContainsRune("-_./=:,+@%", char))
+	}) < 0
+
+	if isSafe {
+		return arg
+	}
+
+	return "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+}
+
+// newResult returns the result of a command from its output and error, converting exit codes into an *ExitError.
+func newResult(nodeName string, argv []string, stdout, stderr string, exitCode int, err error) (Result, error) {
+	result := Result{Stdout: stdout, Stderr: stderr, ExitCode: exitCode}
+
+	if err != nil && exitCode > 0 {
+		return result, &ExitError{Node: nodeName, Argv: argv, ExitCode: exitCode, Stderr: stderr}
+	}
+
+	return result, err
+}
@@ -0,0 +1,85 @@
+package nodeexec
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQuote(t *testing.T) {
+	testCases := []struct {
+		argv     []string
+		expected string
+	}{
+		{
+			argv:     []string{"cat", "/proc/cmdline"},
+			expected: "cat /proc/cmdline",
+		},
+		{
+			argv:     []string{"echo", "hello world"},
+			expected: "echo 'hello world'",
+		},
+		{
+			argv:     []string{"echo", "it's"},
+			expected: `echo 'it'\''s'`,
+		},
+		{
+			argv:     []string{"echo", "$(reboot)", "a;b", ""},
+			expected: "echo '$(reboot)' 'a;b' ''",
+		},
+		{
+			argv:     []string{"sysctl", "net.ipv4.ip_forward=1"},
+			expected: "sysctl net.ipv4.ip_forward=1",
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expected, Quote(testCase.argv...))
+	}
+}
+
+func TestNewResult(t *testing.T) {
+	testCases := []struct {
+		exitCode          int
+		err               error
+		expectedExitError bool
+	}{
+		{
+			exitCode: 0,
+		},
+		{
+			exitCode:          2,
+			err:               errors.New("command terminated with exit code 2"),
+			expectedExitError: true,
+		},
+		{
+			exitCode: -1,
+			err:      errors.New("connection refused"),
+		},
+	}
+
+	for _, testCase := range testCases {
+		result, err := newResult("worker-0", []string{"ls", "/missing dir"}, "out", "no such file\n",
+			testCase.exitCode, testCase.err)
+
+		assert.Equal(t, Result{Stdout: "out", Stderr: "no such file\n", ExitCode: testCase.exitCode}, result)
+		assert.Equal(t, testCase.err != nil, err != nil)
+		assert.Equal(t, testCase.expectedExitError, IsExitError(err))
+		assert.Equal(t, testCase.expectedExitError, IsExitError(fmt.Errorf("wrapped: %w", err)))
+
+		if testCase.expectedExitError {
+			assert.Equal(t, "command ls '/missing dir' on node worker-0 exited with code 2: no such file", err.Error())
+		}
+	}
+}
+
+func TestDebugPodName(t *testing.T) {
+	assert.Equal(t, "eco-nodeexec-worker-0", debugPodName("worker-0"))
+
+	longName := debugPodName(strings.Repeat("a", 49) + "-b.example.com")
+	assert.Len(t, longName, 62)
+	assert.False(t, strings.HasSuffix(longName, "-"))
+}
@@ -0,0 +1,75 @@
+package nodeexec
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/klog/v2"
+)
+
+// podReadyTimeout is how long pods are waited for to be running before executing in them.
+const podReadyTimeout = 5 * time.Minute
+
+// hostArgv returns argv wrapped with nsenter so it runs in the namespaces of the host from a pod sharing its PID
+// namespace. Unlike wrapping the command in sh -c, arguments are passed through unchanged.
+func hostArgv(argv []string) []string {
+	return append([]string{"nsenter", "--target", "1", "--mount", "--uts", "--ipc", "--net", "--pid", "--"}, argv...)
+}
+
+// findNodePod returns the first pod in namespace matching labelSelector that is scheduled on nodeName, once it is
+// running.
+func findNodePod(apiClient *clients.Settings, namespace, labelSelector, nodeName string) (*pod.Builder, error) {
+	podList, err := pod.List(apiClient, namespace, metav1.ListOptions{
+		LabelSelector: labelSelector,
+		FieldSelector: fields.SelectorFromSet(fields.Set{"spec.nodeName": nodeName}).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods matching %s in namespace %s: %w", labelSelector, namespace, err)
+	}
+
+	if len(podList) == 0 {
+		return nil, fmt.Errorf("no pods matching %s in namespace %s on node %s", labelSelector, namespace, nodeName)
+	}
+
+	err = podList[0].WaitUntilRunning(podReadyTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("pod %s on node %s is not running: %w", podList[0].Definition.Name, nodeName, err)
+	}
+
+	return podList[0], nil
+}
+
+// execInPod runs argv in containerName of podBuilder without a TTY, so stdout and stderr are kept separate and the
+// exit code is reported. An empty containerName means the first container. The execution is recorded through
+// execrecorder.
+func execInPod(ctx context.Context, apiClient *clients.Settings,
+	podBuilder *pod.Builder, containerName, nodeName string, argv []string) (Result, error) {
+	if len(argv) == 0 {
+		return Result{}, fmt.Errorf("cannot execute an empty command on node %s", nodeName)
+	}
+
+	podObject := podBuilder.Object
+	if podObject == nil {
+		podObject = podBuilder.Definition
+	}
+
+	if containerName == "" && len(podObject.Spec.Containers) > 0 {
+		containerName = podObject.Spec.Containers[0].Name
+	}
+
+	klog.V(90).Infof("Executing %s on node %s in pod %s/%s container %s",
+		Quote(argv...), nodeName, podObject.Namespace, podObject.Name, containerName)
+
+	target := execrecorder.PodTarget(apiClient, podBuilder, containerName)
+	target.Node = nodeName
+
+	output, err := execrecorder.Exec(ctx, apiClient, target, Quote(argv...), argv)
+
+	return newResult(nodeName, argv, output.Stdout, output.Stderr, output.ExitStatus, err)
+}
@@ -0,0 +1,95 @@
+package nodeexec
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+// RetryPolicy controls how failed executions are retried by a NodeExecutor returned from WithRetries.
+type RetryPolicy struct {
+	// Attempts is the total number of attempts, including the first one. Values below 1 mean a single attempt.
+	Attempts uint
+	// Delay is how long to wait between attempts.
+	Delay time.Duration
+	// Timeout bounds each attempt. It is not applied when zero, leaving only the deadline of the context.
+	Timeout time.Duration
+	// RetryOnExitError retries commands that ran but exited with a non-zero code. By default only errors running the
+	// command, such as a pod being restarted or a connection being dropped, are retried.
+	RetryOnExitError bool
+	// RetryOnEmptyOutput retries commands that succeeded without writing to stdout.
+	RetryOnEmptyOutput bool
+}
+
+// DefaultRetryPolicy retries errors running the command, but not the failures of the command itself, three times
+// with a delay of 5 seconds.
+var DefaultRetryPolicy = RetryPolicy{Attempts: 3, Delay: 5 * time.Second}
+
+type retryingExecutor struct {
+	executor NodeExecutor
+	policy   RetryPolicy
+}
+
+// WithRetries returns a NodeExecutor running commands through executor and retrying them according to policy. The
+// result and error of the last attempt are returned once attempts run out or the context is done.
+func WithRetries(executor NodeExecutor, policy RetryPolicy) NodeExecutor {
+	return &retryingExecutor{executor: executor, policy: policy}
+}
+
+// Execute runs argv on nodeName, retrying according to the policy.
+func (retrying *retryingExecutor) Execute(ctx context.Context, nodeName string, argv ...string) (Result, error) {
+	attempts := max(retrying.policy.Attempts, 1)
+
+	var (
+		result Result
+		err    error
+	)
+
+	for attempt := range attempts {
+		if attempt > 0 {
+			klog.V(90).Infof("Retrying command %s on node %s after attempt %d of %d: %v",
+				Quote(argv...), nodeName, attempt, attempts, err)
+
+			select {
+			case <-ctx.Done():
+				return result, err
+			case <-time.After(retrying.policy.Delay):
+			}
+		}
+
+		result, err = retrying.attempt(ctx, nodeName, argv)
+		if !retrying.shouldRetry(result, err) || ctx.Err() != nil {
+			return result, err
+		}
+	}
+
+	return result, err
+}
+
+// Backend returns the backend of the wrapped executor.
+func (retrying *retryingExecutor) Backend() string {
+	return retrying.executor.Backend()
+}
+
+func (retrying *retryingExecutor) attempt(ctx context.Context, nodeName string, argv []string) (Result, error) {
+	if retrying.policy.Timeout > 0 {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, retrying.policy.Timeout)
+		defer cancel()
+	}
+
+	return retrying.executor.Execute(ctx, nodeName, argv...)
+}
+
+func (retrying *retryingExecutor) shouldRetry(result Result, err error) bool {
+	switch {
+	case err == nil:
+		return retrying.policy.RetryOnEmptyOutput && result.Stdout == ""
+	case IsExitError(err):
+		return retrying.policy.RetryOnExitError
+	default:
+		return true
+	}
+}
@@ -0,0 +1,95 @@
+package nodeexec
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeExecutor returns its results in order, repeating the last one once they run out.
+type fakeExecutor struct {
+	results []Result
+	errors  []error
+	calls   int
+}
+
+func (executor *fakeExecutor) Execute(_ context.Context, _ string, _ ...string) (Result, error) {
+	index := min(executor.calls, len(executor.results)-1)
+	executor.calls++
+
+	return executor.results[index], executor.errors[index]
+}
+
+func (executor *fakeExecutor) Backend() string {
+	return "fake"
+}
+
+func TestWithRetries(t *testing.T) {
+	errExit := &ExitError{Node: "worker-0", Argv: []string{"false"}, ExitCode: 1}
+	errConnection := errors.New("connection refused")
+
+	testCases := []struct {
+		policy        RetryPolicy
+		results       []Result
+		errors        []error
+		expectedCalls int
+		expectedError error
+	}{
+		{
+			policy:        RetryPolicy{Attempts: 3},
+			results:       []Result{{}, {Stdout: "ok"}},
+			errors:        []error{errConnection, nil},
+			expectedCalls: 2,
+		},
+		{
+			policy:        RetryPolicy{Attempts: 3},
+			results:       []Result{{ExitCode: 1}},
+			errors:        []error{errExit},
+			expectedCalls: 1,
+			expectedError: errExit,
+		},
+		{
+			policy:        RetryPolicy{Attempts: 3, RetryOnExitError: true},
+			results:       []Result{{ExitCode: 1}},
+			errors:        []error{errExit},
+			expectedCalls: 3,
+			expectedError: errExit,
+		},
+		{
+			policy:        RetryPolicy{Attempts: 3, RetryOnEmptyOutput: true},
+			results:       []Result{{}, {Stdout: "ok"}},
+			errors:        []error{nil, nil},
+			expectedCalls: 2,
+		},
+		{
+			policy:        RetryPolicy{},
+			results:       []Result{{}},
+			errors:        []error{errConnection},
+			expectedCalls: 1,
+			expectedError: errConnection,
+		},
+	}
+
+	for _, testCase := range testCases {
+		executor := &fakeExecutor{results: testCase.results, errors: testCase.errors}
+
+		_, err := WithRetries(executor, testCase.policy).Execute(context.TODO(), "worker-0", "true")
+		assert.Equal(t, testCase.expectedCalls, executor.calls)
+		assert.Equal(t, testCase.expectedError, err)
+	}
+}
+
+func TestWithRetriesContextDone(t *testing.T) {
+	errConnection := errors.New("connection refused")
+	executor := &fakeExecutor{results: []Result{{}}, errors: []error{errConnection}}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	_, err := WithRetries(executor, RetryPolicy{Attempts: 3, Delay: time.Hour}).Execute(ctx, "worker-0", "true")
+	assert.Equal(t, errConnection, err)
+	assert.Equal(t, 1, executor.calls)
+}
@@ -0,0 +1,179 @@
+package nodeexec
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nodes"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
+	"golang.org/x/crypto/ssh"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// BackendSSH is the backend executing over SSH to the internal address of each node.
+	BackendSSH = "ssh"
+
+	// defaultSSHPort is the port used when SSHExecutor.Port is zero.
+	defaultSSHPort = 22
+)
+
+// SSHExecutor executes commands on nodes over SSH with public key authentication. It does not depend on the API
+// server being reachable once the address of the node is known, so it keeps working while nodes reboot or the
+// control plane is down.
+type SSHExecutor struct {
+	// APIClient is used to look up the internal address of nodes. When it is nil the node name is used as the host.
+	APIClient *clients.Settings
+	User      string
+	// KeyPath is the path of the private key, usually GeneralConfig.SSHKeyPath.
+	KeyPath string
+	Port    int
+}
+
+// NewSSHExecutor returns an SSHExecutor connecting as user with the private key at keyPath.
+func NewSSHExecutor(apiClient *clients.Settings, user, keyPath string) *SSHExecutor {
+	return &SSHExecutor{APIClient: apiClient, User: user, KeyPath: keyPath}
+}
+
+// Execute runs argv on nodeName. Since SSH takes a command line rather than argv, arguments are quoted using Quote.
+// The session is closed if the context is done before the command exits.
+func (executor *SSHExecutor) Execute(ctx context.Context, nodeName string, argv ...string) (Result, error) {
+	if len(argv) == 0 {
+		return Result{}, fmt.Errorf("cannot execute an empty command on node %s", nodeName)
+	}
+
+	address, err := executor.address(nodeName)
+	if err != nil {
+		return Result{}, err
+	}
+
+	client, err := executor.dial(ctx, address)
+	if err != nil {
+		return Result{}, err
+	}
+
+	defer client.Close()
+
+	session, err := client.NewSession()
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to create ssh session on node %s: %w", nodeName, err)
+	}
+
+	defer session.Close()
+
+	var stdout, stderr bytes.Buffer
+
+	session.Stdout = &stdout
+	session.Stderr = &stderr
+
+	command := Quote(argv...)
+
+	klog.V(90).Infof("Executing %s on node %s over ssh to %s", command, nodeName, address)
+
+	execution := execrecorder.Start(execrecorder.Target{Node: nodeName}, command)
+
+	done := make(chan error, 1)
+
+	go func() {
+		done <- session.Run(command)
+	}()
+
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		_ = session.Close()
+		<-done
+
+		err = ctx.Err()
+	}
+
+	execution.Finish(stdout.String(), stderr.String(), err)
+
+	return newResult(nodeName, argv, stdout.String(), stderr.String(), sshExitCode(err), err)
+}
+
+// Backend returns BackendSSH.
+func (executor *SSHExecutor) Backend() string {
+	return BackendSSH
+}
+
+// address returns the host and port to connect to for nodeName.
+func (executor *SSHExecutor) address(nodeName string) (string, error) {
+	port := executor.Port
+	if port == 0 {
+		port = defaultSSHPort
+	}
+
+	if executor.APIClient == nil {
+		return net.JoinHostPort(nodeName, strconv.Itoa(port)), nil
+	}
+
+	node, err := nodes.Pull(executor.APIClient, nodeName)
+	if err != nil {
+		return "", fmt.Errorf("failed to pull node %s: %w", nodeName, err)
+	}
+
+	for _, address := range node.Object.Status.Addresses {
+		if address.Type == corev1.NodeInternalIP {
+			return net.JoinHostPort(address.Address, strconv.Itoa(port)), nil
+		}
+	}
+
+	return "", fmt.Errorf("node %s has no internal IP address", nodeName)
+}
+
+// dial connects to address, aborting if the context is done first.
+func (executor *SSHExecutor) dial(ctx context.Context, address string) (*ssh.Client, error) {
+	keyBytes, err := os.ReadFile(executor.KeyPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ssh private key %s: %w", executor.KeyPath, err)
+	}
+
+	signer, err := ssh.ParsePrivateKey(keyBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ssh private key %s: %w", executor.KeyPath, err)
+	}
+
+	config := &ssh.ClientConfig{
+		User:            executor.User,
+		Auth:            []ssh.AuthMethod{ssh.PublicKeys(signer)},
+		HostKeyCallback: ssh.InsecureIgnoreHostKey(),
+	}
+
+	dialer := &net.Dialer{}
+
+	conn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to %s: %w", address, err)
+	}
+
+	sshConn, channels, requests, err := ssh.NewClientConn(conn, address, config)
+	if err != nil {
+		conn.Close()
+
+		return nil, fmt.Errorf("failed to establish ssh connection to %s: %w", address, err)
+	}
+
+	return ssh.NewClient(sshConn, channels, requests), nil
+}
+
+// sshExitCode returns the exit status of the remote command, 0 if err is nil and -1 if the command did not exit.
+func sshExitCode(err error) int {
+	if err == nil {
+		return 0
+	}
+
+	var exitError *ssh.ExitError
+	if errors.As(err, &exitError) {
+		return exitError.ExitStatus()
+	}
+
+	return -1
+}
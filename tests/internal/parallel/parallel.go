@@ -0,0 +1,107 @@
+// Package parallel supports running suites with ginkgo -p. Every ginkgo process loads its own configuration and API
+// client, so the remaining conflicts between processes are on cluster objects: namespaces created with fixed names are
+// suffixed per process with Namespace, and specs mutating the same cluster-scoped resources are serialised with file
+// locks taken from their Mutates labels.
+package parallel
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+)
+
+const (
+	// maxNamespaceLength is the maximum length of a namespace name, as for any DNS label.
+	maxNamespaceLength = 63
+
+	processFlag = "ginkgo.parallel.process"
+	totalFlag   = "ginkgo.parallel.total"
+)
+
+var (
+	// process and total are parsed from the command line since the ginkgo flags are only parsed once the test binary
+	// runs its tests, after the package-level variables of the suites holding namespace names are initialised.
+	process, total = processFromArgs(os.Args[1:])
+	// generalCfg holds the configuration for the lock directory.
+	generalCfg *config.GeneralConfig
+)
+
+// init initializes the parallel configuration.
+func init() {
+	// Skip loading config if running unit tests
+	if os.Getenv("UNIT_TEST") == "true" {
+		return
+	}
+
+	generalCfg = config.NewConfig()
+}
+
+// Process returns the number of the current ginkgo parallel process, starting from 1.
+func Process() int {
+	return process
+}
+
+// Total returns the number of ginkgo parallel processes. It is 1 when the suite runs serially.
+func Total() int {
+	return total
+}
+
+// Suffix returns the suffix unique to the current ginkgo process, or an empty string when the suite runs serially so
+// serial runs keep their existing names.
+func Suffix() string {
+	if total <= 1 {
+		return ""
+	}
+
+	return fmt.Sprintf("-p%d", process)
+}
+
+// Namespace returns name suffixed for the current ginkgo process. Names that would be longer than a namespace allows
+// are shortened before the suffix is added. It is safe to use when initialising package-level variables.
+func Namespace(name string) string {
+	suffix := Suffix()
+
+	if len(name)+len(suffix) > maxNamespaceLength {
+		name = strings.TrimRight(name[:maxNamespaceLength-len(suffix)], "-")
+	}
+
+	return name + suffix
+}
+
+// processFromArgs returns the ginkgo parallel process and total from the command line arguments, defaulting to a
+// serial run.
+func processFromArgs(args []string) (int, int) {
+	parsedProcess, parsedTotal := 1, 1
+
+	for index, arg := range args {
+		name, value, hasValue := strings.Cut(strings.TrimLeft(arg, "-"), "=")
+
+		if name != processFlag && name != totalFlag {
+			continue
+		}
+
+		if !hasValue && index+1 < len(args) {
+			value = args[index+1]
+		}
+
+		number, err := strconv.Atoi(value)
+		if err != nil || number < 1 {
+			continue
+		}
+
+		if name == processFlag {
+			parsedProcess = number
+		} else {
+			parsedTotal = number
+		}
+	}
+
+	if parsedProcess > parsedTotal {
+		return 1, 1
+	}
+
+	return parsedProcess, parsedTotal
+}
@@ -0,0 +1,161 @@
+package parallel
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessFromArgs(t *testing.T) {
+	testCases := []struct {
+		args            []string
+		expectedProcess int
+		expectedTotal   int
+	}{
+		{
+			args:            nil,
+			expectedProcess: 1,
+			expectedTotal:   1,
+		},
+		{
+			args:            []string{"--ginkgo.parallel.process=3", "--ginkgo.parallel.total=4", "--ginkgo.v"},
+			expectedProcess: 3,
+			expectedTotal:   4,
+		},
+		{
+			args:            []string{"-ginkgo.parallel.process", "2", "-ginkgo.parallel.total", "2"},
+			expectedProcess: 2,
+			expectedTotal:   2,
+		},
+		{
+			args:            []string{"--ginkgo.parallel.process=5", "--ginkgo.parallel.total=4"},
+			expectedProcess: 1,
+			expectedTotal:   1,
+		},
+		{
+			args:            []string{"--ginkgo.parallel.process=x"},
+			expectedProcess: 1,
+			expectedTotal:   1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		parsedProcess, parsedTotal := processFromArgs(testCase.args)
+		assert.Equal(t, testCase.expectedProcess, parsedProcess)
+		assert.Equal(t, testCase.expectedTotal, parsedTotal)
+	}
+}
+
+func TestNamespace(t *testing.T) {
+	savedProcess, savedTotal := process, total
+
+	t.Cleanup(func() { process, total = savedProcess, savedTotal })
+
+	process, total = 1, 1
+	assert.Equal(t, "policy-tests", Namespace("policy-tests"))
+
+	process, total = 2, 4
+	assert.Equal(t, "policy-tests-p2", Namespace("policy-tests"))
+
+	longName := Namespace(strings.Repeat("a", 59) + "-bcd")
+	assert.Equal(t, strings.Repeat("a", 59)+"-p2", longName)
+	assert.LessOrEqual(t, len(longName), maxNamespaceLength)
+}
+
+func TestResourcesFromLabels(t *testing.T) {
+	labels := append([]string{"ptp", "mutates-nothing"},
+		Mutates(AllNodes, MachineConfigPool("worker"), Node("worker-0.example.com"))...)
+
+	assert.Equal(t, []Resource{
+		{Kind: KindNode},
+		{Kind: KindMachineConfigPool, Name: "worker"},
+		{Kind: KindNode, Name: "worker-0.example.com"},
+	}, ResourcesFromLabels(labels))
+}
+
+func TestLockModes(t *testing.T) {
+	assert.Equal(t, map[string]int{
+		"mcp":           syscall.LOCK_SH,
+		"mcp=worker":    syscall.LOCK_EX,
+		"node":          syscall.LOCK_EX,
+		"node=worker-0": syscall.LOCK_EX,
+	}, lockModes([]Resource{MachineConfigPool("worker"), Node("worker-0"), AllNodes}))
+}
+
+func TestLock(t *testing.T) {
+	generalCfg = &config.GeneralConfig{ParallelLockDir: t.TempDir()}
+
+	t.Cleanup(func() { generalCfg = nil })
+
+	unlock, err := Lock(MachineConfigPool("worker"))
+	assert.Nil(t, err)
+
+	// Another process may lock a different pool but neither the same pool nor every pool.
+	assert.True(t, tryLock(t, "mcp", syscall.LOCK_SH))
+	assert.True(t, tryLock(t, "mcp=master", syscall.LOCK_EX))
+	assert.False(t, tryLock(t, "mcp=worker", syscall.LOCK_EX))
+	assert.False(t, tryLock(t, "mcp", syscall.LOCK_EX))
+
+	unlock()
+
+	assert.True(t, tryLock(t, "mcp=worker", syscall.LOCK_EX))
+	assert.True(t, tryLock(t, "mcp", syscall.LOCK_EX))
+}
+
+func TestLockReentrant(t *testing.T) {
+	generalCfg = &config.GeneralConfig{ParallelLockDir: t.TempDir()}
+
+	t.Cleanup(func() { generalCfg = nil })
+
+	// Like an Ordered container locking the pool in BeforeAll before the BeforeEach of its specs locks it again.
+	unlockAll, err := Lock(MachineConfigPool("worker"))
+	assert.Nil(t, err)
+
+	unlockSpec, err := Lock(MachineConfigPool("worker"))
+	assert.Nil(t, err)
+
+	// Locking the whole kind upgrades the shared lock held on it.
+	unlockKind, err := Lock(Resource{Kind: KindMachineConfigPool})
+	assert.Nil(t, err)
+	assert.False(t, tryLock(t, "mcp", syscall.LOCK_SH))
+
+	unlockKind()
+	unlockKind()
+
+	assert.True(t, tryLock(t, "mcp", syscall.LOCK_SH))
+	assert.False(t, tryLock(t, "mcp", syscall.LOCK_EX))
+
+	unlockSpec()
+
+	assert.False(t, tryLock(t, "mcp=worker", syscall.LOCK_EX))
+
+	unlockAll()
+
+	assert.True(t, tryLock(t, "mcp=worker", syscall.LOCK_EX))
+	assert.True(t, tryLock(t, "mcp", syscall.LOCK_EX))
+	assert.Empty(t, heldLocks)
+}
+
+// tryLock opens the lock file separately, as another process would, and reports whether it can be locked.
+func tryLock(t *testing.T, name string, mode int) bool {
+	t.Helper()
+
+	lockFile, err := os.OpenFile(filepath.Join(generalCfg.ParallelLockDir, name+".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	assert.Nil(t, err)
+
+	defer lockFile.Close()
+
+	err = syscall.Flock(int(lockFile.Fd()), mode|syscall.LOCK_NB)
+	if err != nil {
+		return false
+	}
+
+	_ = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+
+	return true
+}
@@ -0,0 +1,283 @@
+package parallel
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"syscall"
+
+	"github.com/onsi/ginkgo/v2"
+)
+
+// mutatesLabelKey is the key of the ginkgo label set listing the resources a spec mutates.
+const mutatesLabelKey = "mutates"
+
+// Kinds of the cluster-scoped resources specs commonly mutate.
+const (
+	KindNode                 = "node"
+	KindMachineConfigPool    = "mcp"
+	KindMachineConfig        = "machineconfig"
+	KindPerformanceProfile   = "performanceprofile"
+	KindClusterVersion       = "clusterversion"
+	KindClusterOperator      = "clusteroperator"
+	KindClusterNetworkConfig = "network"
+)
+
+// Resource is a cluster-scoped resource mutated by a spec. An empty Name stands for every resource of the Kind.
+type Resource struct {
+	Kind string
+	Name string
+}
+
+// AllNodes is every node of the cluster, for specs rebooting or relabelling nodes they do not know in advance.
+var AllNodes = Resource{Kind: KindNode}
+
+// Node returns the node resource called name.
+func Node(name string) Resource {
+	return Resource{Kind: KindNode, Name: name}
+}
+
+// MachineConfigPool returns the machine config pool resource called name.
+func MachineConfigPool(name string) Resource {
+	return Resource{Kind: KindMachineConfigPool, Name: name}
+}
+
+// String returns the resource as used in the mutates label, either kind or kind=name.
+func (resource Resource) String() string {
+	if resource.Name == "" {
+		return resource.Kind
+	}
+
+	return resource.Kind + "=" + resource.Name
+}
+
+// Mutates returns the labels registering the resources the spec mutates. Specs, or containers, decorated with them
+// hold a lock on each resource while they run, once their suite calls LockMutatedResources, so specs in other ginkgo
+// processes mutating the same resources wait for them. A resource with a name only conflicts with the same resource and with the whole kind:
+//
+//	It("reboots the worker", parallel.Mutates(parallel.MachineConfigPool("worker"), parallel.AllNodes), func() {})
+func Mutates(resources ...Resource) ginkgo.Labels {
+	labels := ginkgo.Labels{}
+
+	for _, resource := range resources {
+		labels = append(labels, mutatesLabelKey+":"+resource.String())
+	}
+
+	return labels
+}
+
+// ResourcesFromLabels returns the resources registered by the Mutates labels in labels.
+func ResourcesFromLabels(labels []string) []Resource {
+	var resources []Resource
+
+	for _, label := range labels {
+		key, value, found := strings.Cut(label, ":")
+		if !found || strings.TrimSpace(key) != mutatesLabelKey {
+			continue
+		}
+
+		kind, name, _ := strings.Cut(strings.TrimSpace(value), "=")
+		resources = append(resources, Resource{Kind: kind, Name: name})
+	}
+
+	return resources
+}
+
+// LockMutatedResources locks the resources the current spec registered with Mutates until the spec ends. Suites using
+// Mutates call it from a top-level BeforeEach, so the locks are held for the whole spec. Since BeforeAll nodes run
+// before BeforeEach nodes, Ordered containers mutating resources in BeforeAll must call Lock themselves.
+//
+//	var _ = BeforeEach(func() {
+//		parallel.LockMutatedResources()
+//	})
+func LockMutatedResources() {
+	resources := ResourcesFromLabels(ginkgo.CurrentSpecReport().Labels())
+	if len(resources) == 0 {
+		return
+	}
+
+	unlock, err := Lock(resources...)
+	if err != nil {
+		ginkgo.Fail(fmt.Sprintf("failed to lock mutated resources: %v", err))
+	}
+
+	ginkgo.DeferCleanup(unlock)
+}
+
+// heldLock is a lock file held by the current process, with the number of holders of each mode.
+type heldLock struct {
+	file      *os.File
+	shared    int
+	exclusive int
+}
+
+var (
+	heldLocksMutex sync.Mutex
+	heldLocks      = make(map[string]*heldLock)
+)
+
+// Lock blocks until the current process holds the locks on resources and returns the function releasing them. Whole
+// kinds are locked exclusively while named resources lock their kind shared and themselves exclusively. Lock files
+// are always taken in the same order so processes locking overlapping resources cannot deadlock.
+//
+// Locks are re-entrant within the process: locking a resource the process already holds, such as from a BeforeAll and
+// then from the BeforeEach of a spec labelled with Mutates, reuses the held lock and only releases it once every
+// holder released it.
+func Lock(resources ...Resource) (func(), error) {
+	if len(resources) == 0 {
+		return func() {}, nil
+	}
+
+	lockDir := lockDirectory()
+
+	err := os.MkdirAll(lockDir, 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	modes := lockModes(resources)
+	names := make([]string, 0, len(modes))
+
+	for name := range modes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	heldLocksMutex.Lock()
+	defer heldLocksMutex.Unlock()
+
+	var acquired []string
+
+	for _, name := range names {
+		err = acquireLock(filepath.Join(lockDir, name+".lock"), name, modes[name])
+		if err != nil {
+			releaseLocks(acquired, modes)
+
+			return nil, err
+		}
+
+		acquired = append(acquired, name)
+	}
+
+	var once sync.Once
+
+	return func() {
+		once.Do(func() {
+			heldLocksMutex.Lock()
+			defer heldLocksMutex.Unlock()
+
+			releaseLocks(acquired, modes)
+		})
+	}, nil
+}
+
+// acquireLock takes the lock file at lockPath in mode, reusing it when the process already holds it. A shared lock
+// already held is upgraded when mode is exclusive. heldLocksMutex must be held.
+func acquireLock(lockPath, name string, mode int) error {
+	held, found := heldLocks[name]
+	if !found {
+		lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0644)
+		if err != nil {
+			return err
+		}
+
+		err = syscall.Flock(int(lockFile.Fd()), mode)
+		if err != nil {
+			_ = lockFile.Close()
+
+			return fmt.Errorf("failed to lock %s: %w", name, err)
+		}
+
+		held = &heldLock{file: lockFile}
+		heldLocks[name] = held
+	} else if mode == syscall.LOCK_EX && held.exclusive == 0 {
+		err := syscall.Flock(int(held.file.Fd()), syscall.LOCK_EX)
+		if err != nil {
+			return fmt.Errorf("failed to upgrade lock %s: %w", name, err)
+		}
+	}
+
+	if mode == syscall.LOCK_EX {
+		held.exclusive++
+	} else {
+		held.shared++
+	}
+
+	return nil
+}
+
+// releaseLocks releases the lock files names, taken in modes, in reverse order. heldLocksMutex must be held.
+func releaseLocks(names []string, modes map[string]int) {
+	for index := len(names) - 1; index >= 0; index-- {
+		releaseLock(names[index], modes[names[index]])
+	}
+}
+
+// releaseLock releases one holder of the lock file name in mode. The lock file is unlocked once it has no holders and
+// downgraded to shared once it has no exclusive holders. heldLocksMutex must be held.
+func releaseLock(name string, mode int) {
+	held, found := heldLocks[name]
+	if !found {
+		return
+	}
+
+	if mode == syscall.LOCK_EX {
+		held.exclusive--
+	} else {
+		held.shared--
+	}
+
+	switch {
+	case held.exclusive+held.shared == 0:
+		_ = syscall.Flock(int(held.file.Fd()), syscall.LOCK_UN)
+		_ = held.file.Close()
+
+		delete(heldLocks, name)
+	case mode == syscall.LOCK_EX && held.exclusive == 0:
+		_ = syscall.Flock(int(held.file.Fd()), syscall.LOCK_SH)
+	}
+}
+
+// lockModes returns the flock mode of each lock file needed for resources. Exclusive locks take precedence over
+// shared ones on the same file.
+func lockModes(resources []Resource) map[string]int {
+	modes := make(map[string]int)
+
+	setMode := func(name string, mode int) {
+		if modes[name] != syscall.LOCK_EX {
+			modes[name] = mode
+		}
+	}
+
+	for _, resource := range resources {
+		kind := lockFileName(resource.Kind)
+
+		if resource.Name == "" {
+			setMode(kind, syscall.LOCK_EX)
+
+			continue
+		}
+
+		setMode(kind, syscall.LOCK_SH)
+		setMode(kind+"="+lockFileName(resource.Name), syscall.LOCK_EX)
+	}
+
+	return modes
+}
+
+// lockFileName makes value safe to use in a file name.
+func lockFileName(value string) string {
+	return strings.NewReplacer("/", "_", string(filepath.Separator), "_").Replace(value)
+}
+
+func lockDirectory() string {
+	if generalCfg != nil && generalCfg.ParallelLockDir != "" {
+		return generalCfg.ParallelLockDir
+	}
+
+	return filepath.Join(os.TempDir(), "eco-gotests-locks")
+}
@@ -0,0 +1,151 @@
+package powercontrol
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/nodeexec"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// DefaultPowerOffTimeout is how long GracefulReboot of an IPMIController waits for the operating system to shut down
+// and the node to power off before giving up, when the context has no earlier deadline.
+const DefaultPowerOffTimeout = 10 * time.Minute
+
+// IPMIController controls the power of a node with ipmitool. With a Host, ipmitool runs locally and reaches the BMC
+// over the lanplus interface. Without one, it runs on the node itself through Executor and uses the in-band
+// interface, which only works for actions the node survives long enough to send.
+type IPMIController struct {
+	NodeWatcher
+
+	// Host is the address of the BMC. It is empty for in-band access.
+	Host     string
+	Username string
+	Password string
+
+	// Executor and NodeName are used to run ipmitool on the node for in-band access.
+	Executor nodeexec.NodeExecutor
+	NodeName string
+
+	// PowerOffTimeout bounds how long GracefulReboot waits for the node to power off. DefaultPowerOffTimeout is used
+	// when it is zero.
+	PowerOffTimeout time.Duration
+}
+
+// NewIPMIController returns an IPMIController reaching the BMC at host over the network.
+func NewIPMIController(host, username, password string, status StatusFunc) *IPMIController {
+	return &IPMIController{
+		NodeWatcher: NodeWatcher{Status: status},
+		Host:        host,
+		Username:    username,
+		Password:    password,
+	}
+}
+
+// NewInBandIPMIController returns an IPMIController running ipmitool on nodeName through executor.
+func NewInBandIPMIController(executor nodeexec.NodeExecutor, nodeName string, status StatusFunc) *IPMIController {
+	return &IPMIController{NodeWatcher: NodeWatcher{Status: status}, Executor: executor, NodeName: nodeName}
+}
+
+// PowerOn powers the node on.
+func (controller *IPMIController) PowerOn(ctx context.Context) error {
+	return controller.run(ctx, "chassis", "power", "on")
+}
+
+// PowerOff forces the node off.
+func (controller *IPMIController) PowerOff(ctx context.Context) error {
+	return controller.run(ctx, "chassis", "power", "off")
+}
+
+// PowerCycle power cycles the node.
+func (controller *IPMIController) PowerCycle(ctx context.Context) error {
+	return controller.run(ctx, "chassis", "power", "cycle")
+}
+
+// GracefulReboot asks the operating system to shut down through ACPI, then powers the node on once it is off. Since
+// IPMI has no graceful restart, the node is polled for its power state in between, for at most PowerOffTimeout.
+func (controller *IPMIController) GracefulReboot(ctx context.Context) error {
+	err := controller.run(ctx, "chassis", "power", "soft")
+	if err != nil {
+		return err
+	}
+
+	err = wait.PollUntilContextTimeout(ctx, controller.pollInterval(), controller.powerOffTimeout(), true,
+		func(ctx context.Context) (bool, error) {
+			output, err := controller.output(ctx, "chassis", "power", "status")
+
+			return err == nil && strings.Contains(output, "is off"), nil
+		})
+	if err != nil {
+		return fmt.Errorf("failed waiting for node to power off: %w", err)
+	}
+
+	return controller.PowerOn(ctx)
+}
+
+// InjectNMI sends an NMI to the node through the chassis diagnostic interrupt.
+func (controller *IPMIController) InjectNMI(ctx context.Context) error {
+	return controller.run(ctx, "chassis", "power", "diag")
+}
+
+// Argv returns the ipmitool command for args. The password is passed through the IPMI_PASSWORD environment variable
+// so it does not appear in the command line or the exec records.
+func (controller *IPMIController) Argv(args ...string) []string {
+	if controller.Host == "" {
+		return append([]string{"ipmitool"}, args...)
+	}
+
+	return append([]string{"ipmitool", "-I", "lanplus", "-H", controller.Host, "-U", controller.Username, "-E"}, args...)
+}
+
+func (controller *IPMIController) powerOffTimeout() time.Duration {
+	if controller.PowerOffTimeout <= 0 {
+		return DefaultPowerOffTimeout
+	}
+
+	return controller.PowerOffTimeout
+}
+
+func (controller *IPMIController) run(ctx context.Context, args ...string) error {
+	_, err := controller.output(ctx, args...)
+
+	return err
+}
+
+func (controller *IPMIController) output(ctx context.Context, args ...string) (string, error) {
+	argv := controller.Argv(args...)
+
+	klog.V(90).Infof("Running %s", nodeexec.Quote(argv...))
+
+	if controller.Host == "" {
+		if controller.Executor == nil {
+			return "", fmt.Errorf("cannot run in-band ipmitool without an executor")
+		}
+
+		return nodeexec.Output(ctx, controller.Executor, controller.NodeName, argv...)
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	command := exec.CommandContext(ctx, argv[0], argv[1:]...)
+	command.Env = append(os.Environ(), "IPMI_PASSWORD="+controller.Password)
+	command.Stdout = &stdout
+	command.Stderr = &stderr
+
+	execution := execrecorder.Start(execrecorder.Target{Node: controller.Host}, nodeexec.Quote(argv...))
+	err := command.Run()
+	execution.Finish(stdout.String(), stderr.String(), err)
+
+	if err != nil {
+		return "", fmt.Errorf("failed to run %s: %w: %s", nodeexec.Quote(argv...), err, stderr.String())
+	}
+
+	return stdout.String(), nil
+}
@@ -0,0 +1,146 @@
+// Package powercontrol disrupts nodes through a single PowerController interface, whether by powering them through
+// their BMC over Redfish or IPMI or by running systemctl on the node. Every controller also tracks the boot ID of its
+// node, so callers can wait for the node to come back after a reboot. RedfishSimulator allows testing the reboot
+// flows without hardware.
+package powercontrol
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nodes"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+)
+
+// DefaultPollInterval is how often the status of the node is checked while waiting for it to reboot.
+const DefaultPollInterval = 10 * time.Second
+
+// ErrUnsupported is returned by controllers for actions their backend cannot perform, such as powering on a node
+// through the node itself.
+var ErrUnsupported = errors.New("power action not supported by this backend")
+
+// PowerController controls the power of a single node. Power actions return once the backend accepted them, without
+// waiting for the node, so callers should save the boot ID first and use WaitReadyAfterReboot.
+type PowerController interface {
+	PowerOn(ctx context.Context) error
+	// PowerOff turns the node off without shutting it down gracefully.
+	PowerOff(ctx context.Context) error
+	// PowerCycle turns the node off and on again without shutting it down gracefully.
+	PowerCycle(ctx context.Context) error
+	// GracefulReboot shuts the node down gracefully and starts it again.
+	GracefulReboot(ctx context.Context) error
+	// InjectNMI sends a non-maskable interrupt to the node, which crashes the kernel and triggers kdump when it is
+	// configured.
+	InjectNMI(ctx context.Context) error
+	// GetBootID returns the current boot ID of the node.
+	GetBootID(ctx context.Context) (string, error)
+	// WaitReadyAfterReboot waits until the node has a boot ID other than previousBootID and is ready.
+	WaitReadyAfterReboot(ctx context.Context, previousBootID string) error
+}
+
+// NodeStatus is the state of a node relevant to reboots.
+type NodeStatus struct {
+	BootID string
+	Ready  bool
+}
+
+// StatusFunc returns the current status of a node.
+type StatusFunc func(ctx context.Context) (NodeStatus, error)
+
+// ClusterStatus returns a StatusFunc reading the boot ID and Ready condition of nodeName from the cluster.
+func ClusterStatus(apiClient *clients.Settings, nodeName string) StatusFunc {
+	return func(ctx context.Context) (NodeStatus, error) {
+		node, err := nodes.Pull(apiClient, nodeName)
+		if err != nil {
+			return NodeStatus{}, err
+		}
+
+		status := NodeStatus{BootID: node.Object.Status.NodeInfo.BootID}
+
+		for _, condition := range node.Object.Status.Conditions {
+			if condition.Type == corev1.NodeReady {
+				status.Ready = condition.Status == corev1.ConditionTrue
+			}
+		}
+
+		return status, nil
+	}
+}
+
+// NodeWatcher implements the boot ID methods of PowerController on top of a StatusFunc. It is embedded by every
+// controller in this package.
+type NodeWatcher struct {
+	Status StatusFunc
+	// PollInterval is how often Status is called while waiting. DefaultPollInterval is used when it is zero.
+	PollInterval time.Duration
+}
+
+// GetBootID returns the current boot ID of the node.
+func (watcher NodeWatcher) GetBootID(ctx context.Context) (string, error) {
+	if watcher.Status == nil {
+		return "", fmt.Errorf("cannot get boot ID without a node status function")
+	}
+
+	status, err := watcher.Status(ctx)
+	if err != nil {
+		return "", fmt.Errorf("failed to get node status: %w", err)
+	}
+
+	return status.BootID, nil
+}
+
+// WaitReadyAfterReboot waits until the node has a boot ID other than previousBootID and is ready. Errors getting the
+// status are retried since the API server may be unavailable while the node reboots, especially on SNO. The deadline
+// of the context bounds the wait.
+func (watcher NodeWatcher) WaitReadyAfterReboot(ctx context.Context, previousBootID string) error {
+	if watcher.Status == nil {
+		return fmt.Errorf("cannot wait for node reboot without a node status function")
+	}
+
+	err := wait.PollUntilContextCancel(ctx, watcher.pollInterval(), true, func(ctx context.Context) (bool, error) {
+		status, err := watcher.Status(ctx)
+		if err != nil {
+			klog.V(90).Infof("Failed to get node status while waiting for reboot: %v", err)
+
+			return false, nil
+		}
+
+		klog.V(90).Infof("Node has boot ID %s (previous %s) and ready %t", status.BootID, previousBootID, status.Ready)
+
+		return status.BootID != "" && status.BootID != previousBootID && status.Ready, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed waiting for node to be ready after reboot: %w", err)
+	}
+
+	return nil
+}
+
+func (watcher NodeWatcher) pollInterval() time.Duration {
+	if watcher.PollInterval <= 0 {
+		return DefaultPollInterval
+	}
+
+	return watcher.PollInterval
+}
+
+// RebootAndWait saves the boot ID of the node, runs action, which should be one of the methods of controller, and
+// waits for the node to be ready with a new boot ID.
+func RebootAndWait(ctx context.Context, controller PowerController, action func(context.Context) error) error {
+	bootID, err := controller.GetBootID(ctx)
+	if err != nil {
+		return err
+	}
+
+	err = action(ctx)
+	if err != nil {
+		return err
+	}
+
+	return controller.WaitReadyAfterReboot(ctx, bootID)
+}
@@ -0,0 +1,73 @@
+package powercontrol
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNodeWatcherWaitReadyAfterReboot(t *testing.T) {
+	errUnavailable := errors.New("api server unavailable")
+
+	testCases := []struct {
+		name          string
+		statuses      []NodeStatus
+		errors        []error
+		expectedCalls int
+		expectedError bool
+	}{
+		{
+			name:          "ready with new boot ID",
+			statuses:      []NodeStatus{{BootID: "new", Ready: true}},
+			errors:        []error{nil},
+			expectedCalls: 1,
+		},
+		{
+			name:          "errors and old boot ID retried",
+			statuses:      []NodeStatus{{}, {BootID: "old", Ready: true}, {BootID: "new"}, {BootID: "new", Ready: true}},
+			errors:        []error{errUnavailable, nil, nil, nil},
+			expectedCalls: 4,
+		},
+		{
+			name:          "never reboots",
+			statuses:      []NodeStatus{{BootID: "old", Ready: true}},
+			errors:        []error{nil},
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		calls := 0
+		watcher := NodeWatcher{
+			PollInterval: time.Millisecond,
+			Status: func(context.Context) (NodeStatus, error) {
+				index := min(calls, len(testCase.statuses)-1)
+				calls++
+
+				return testCase.statuses[index], testCase.errors[index]
+			},
+		}
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 100*time.Millisecond)
+		err := watcher.WaitReadyAfterReboot(ctx, "old")
+
+		cancel()
+
+		assert.Equal(t, testCase.expectedError, err != nil, testCase.name)
+
+		if !testCase.expectedError {
+			assert.Equal(t, testCase.expectedCalls, calls, testCase.name)
+		}
+	}
+}
+
+func TestNodeWatcherWithoutStatus(t *testing.T) {
+	_, err := NodeWatcher{}.GetBootID(context.TODO())
+	assert.Error(t, err)
+
+	err = NodeWatcher{}.WaitReadyAfterReboot(context.TODO(), "old")
+	assert.Error(t, err)
+}
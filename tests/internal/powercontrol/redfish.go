@@ -0,0 +1,78 @@
+package powercontrol
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/bmc"
+	"github.com/stmcginnis/gofish/redfish"
+	"k8s.io/klog/v2"
+)
+
+// RedfishController controls the power of a node through the Redfish API of its BMC.
+type RedfishController struct {
+	NodeWatcher
+
+	BMC *bmc.BMC
+}
+
+// NewRedfishController returns a RedfishController for the BMC at host, which may include a port, using the given
+// credentials. Actions go through bmc.BMC, so its default timeouts apply.
+func NewRedfishController(host, username, password string, status StatusFunc) *RedfishController {
+	return &RedfishController{
+		NodeWatcher: NodeWatcher{Status: status},
+		BMC:         bmc.New(host).WithRedfishUser(username, password),
+	}
+}
+
+// PowerOn powers the node on.
+func (controller *RedfishController) PowerOn(ctx context.Context) error {
+	return controller.reset(ctx, redfish.OnResetType)
+}
+
+// PowerOff forces the node off.
+func (controller *RedfishController) PowerOff(ctx context.Context) error {
+	return controller.reset(ctx, redfish.ForceOffResetType)
+}
+
+// PowerCycle power cycles the node, falling back to forcing it off then on when the BMC does not support power
+// cycles.
+func (controller *RedfishController) PowerCycle(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	klog.V(90).Info("Power cycling node through redfish")
+
+	err := controller.BMC.SystemPowerCycle()
+	if err != nil {
+		return fmt.Errorf("failed to power cycle node through redfish: %w", err)
+	}
+
+	return nil
+}
+
+// GracefulReboot restarts the node gracefully.
+func (controller *RedfishController) GracefulReboot(ctx context.Context) error {
+	return controller.reset(ctx, redfish.GracefulRestartResetType)
+}
+
+// InjectNMI sends an NMI to the node.
+func (controller *RedfishController) InjectNMI(ctx context.Context) error {
+	return controller.reset(ctx, redfish.NmiResetType)
+}
+
+func (controller *RedfishController) reset(ctx context.Context, resetType redfish.ResetType) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	klog.V(90).Infof("Performing redfish reset %s", resetType)
+
+	err := controller.BMC.SystemResetAction(resetType)
+	if err != nil {
+		return fmt.Errorf("failed to perform redfish reset %s: %w", resetType, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package powercontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stmcginnis/gofish/redfish"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSimulatedController(t *testing.T, resetTypes ...redfish.ResetType) (*RedfishController, *RedfishSimulator) {
+	t.Helper()
+
+	simulator := NewRedfishSimulator(resetTypes...)
+	t.Cleanup(simulator.Close)
+
+	controller := NewRedfishController(simulator.Host(), simulator.Username, simulator.Password, simulator.Status)
+	controller.PollInterval = 10 * time.Millisecond
+
+	return controller, simulator
+}
+
+func TestRedfishControllerReboots(t *testing.T) {
+	testCases := []struct {
+		name            string
+		resetTypes      []redfish.ResetType
+		action          func(*RedfishController) func(context.Context) error
+		expectedResets  []redfish.ResetType
+		expectedCrashes int
+	}{
+		{
+			name:           "power cycle",
+			action:         func(controller *RedfishController) func(context.Context) error { return controller.PowerCycle },
+			expectedResets: []redfish.ResetType{redfish.PowerCycleResetType},
+		},
+		{
+			name:           "power cycle fallback",
+			resetTypes:     []redfish.ResetType{redfish.OnResetType, redfish.ForceOffResetType},
+			action:         func(controller *RedfishController) func(context.Context) error { return controller.PowerCycle },
+			expectedResets: []redfish.ResetType{redfish.ForceOffResetType, redfish.OnResetType},
+		},
+		{
+			name:           "graceful reboot",
+			action:         func(controller *RedfishController) func(context.Context) error { return controller.GracefulReboot },
+			expectedResets: []redfish.ResetType{redfish.GracefulRestartResetType},
+		},
+		{
+			name:            "nmi",
+			action:          func(controller *RedfishController) func(context.Context) error { return controller.InjectNMI },
+			expectedResets:  []redfish.ResetType{redfish.NmiResetType},
+			expectedCrashes: 1,
+		},
+	}
+
+	for _, testCase := range testCases {
+		controller, simulator := newSimulatedController(t, testCase.resetTypes...)
+
+		ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+		bootID, err := controller.GetBootID(ctx)
+		assert.NoError(t, err, testCase.name)
+
+		err = RebootAndWait(ctx, controller, testCase.action(controller))
+		cancel()
+
+		assert.NoError(t, err, testCase.name)
+		assert.Equal(t, testCase.expectedResets, simulator.Resets(), testCase.name)
+		assert.Equal(t, testCase.expectedCrashes, simulator.Crashes(), testCase.name)
+
+		newBootID, err := controller.GetBootID(context.TODO())
+		assert.NoError(t, err, testCase.name)
+		assert.NotEqual(t, bootID, newBootID, testCase.name)
+	}
+}
+
+func TestRedfishControllerPowerOffOn(t *testing.T) {
+	controller, simulator := newSimulatedController(t)
+
+	bootID, err := controller.GetBootID(context.TODO())
+	assert.NoError(t, err)
+
+	err = controller.PowerOff(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, redfish.OffPowerState, simulator.PowerState())
+
+	_, err = controller.GetBootID(context.TODO())
+	assert.ErrorIs(t, err, ErrSimulatedNodeOff)
+
+	err = controller.InjectNMI(context.TODO())
+	assert.Error(t, err)
+
+	err = controller.PowerOn(context.TODO())
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.TODO(), 10*time.Second)
+	defer cancel()
+
+	err = controller.WaitReadyAfterReboot(ctx, bootID)
+	assert.NoError(t, err)
+	assert.Equal(t, []redfish.ResetType{redfish.ForceOffResetType, redfish.OnResetType}, simulator.Resets())
+}
+
+func TestRedfishControllerErrors(t *testing.T) {
+	controller, simulator := newSimulatedController(t, redfish.OnResetType, redfish.ForceOffResetType)
+
+	err := controller.InjectNMI(context.TODO())
+	assert.Error(t, err)
+
+	controller.BMC.WithRedfishUser(simulator.Username, "wrong")
+
+	err = controller.PowerOff(context.TODO())
+	assert.Error(t, err)
+	assert.Empty(t, simulator.Resets())
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	err = controller.PowerCycle(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+}
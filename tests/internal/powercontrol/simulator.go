@@ -0,0 +1,329 @@
+package powercontrol
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+
+	"github.com/stmcginnis/gofish/redfish"
+)
+
+const (
+	simulatorSessionsPath = "/redfish/v1/SessionService/Sessions"
+	simulatorSystemsPath  = "/redfish/v1/Systems"
+	simulatorSystemPath   = simulatorSystemsPath + "/1"
+	simulatorResetPath    = simulatorSystemPath + "/Actions/ComputerSystem.Reset"
+)
+
+// ErrSimulatedNodeOff is returned by the StatusFunc of RedfishSimulator while the simulated node is powered off, the
+// same way the API server fails to return a node that is down.
+var ErrSimulatedNodeOff = errors.New("simulated node is powered off")
+
+// RedfishSimulator is an in-process Redfish service for a single system, served over TLS on a local port. Reset
+// actions change the power state and boot ID of a simulated node, which Status reports the way ClusterStatus reports a
+// real one, so the reboot flows of RedfishController can be tested end to end without hardware.
+//
+// Reboots take effect immediately, but the node only becomes ready after Status has reported it as not ready
+// BootingPolls times. An NMI crashes the node, which then reboots as it would after kdump.
+type RedfishSimulator struct {
+	Username string
+	Password string
+	// BootingPolls is how many times Status reports the node as not ready after it boots.
+	BootingPolls int
+
+	server *httptest.Server
+
+	mutex        sync.Mutex
+	resetTypes   []redfish.ResetType
+	powerState   redfish.PowerState
+	bootID       string
+	bootingPolls int
+	resets       []redfish.ResetType
+	crashes      int
+	sessions     map[string]bool
+}
+
+// NewRedfishSimulator starts a RedfishSimulator for a node that is powered on and ready. The system advertises
+// resetTypes as its allowable reset types, or every type the simulator supports when none are given. Close must be
+// called to stop it.
+func NewRedfishSimulator(resetTypes ...redfish.ResetType) *RedfishSimulator {
+	if len(resetTypes) == 0 {
+		resetTypes = []redfish.ResetType{
+			redfish.OnResetType, redfish.ForceOffResetType, redfish.GracefulShutdownResetType,
+			redfish.GracefulRestartResetType, redfish.ForceRestartResetType, redfish.PowerCycleResetType,
+			redfish.NmiResetType,
+		}
+	}
+
+	simulator := &RedfishSimulator{
+		Username:     "admin",
+		Password:     "password",
+		BootingPolls: 1,
+		resetTypes:   resetTypes,
+		powerState:   redfish.OnPowerState,
+		bootID:       newSimulatorID(),
+		sessions:     make(map[string]bool),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /redfish/v1/{$}", simulator.handleServiceRoot)
+	mux.HandleFunc("POST "+simulatorSessionsPath, simulator.handleCreateSession)
+	mux.HandleFunc("DELETE "+simulatorSessionsPath+"/{id}", simulator.authenticated(simulator.handleDeleteSession))
+	mux.HandleFunc("GET "+simulatorSystemsPath, simulator.authenticated(simulator.handleSystems))
+	mux.HandleFunc("GET "+simulatorSystemPath, simulator.authenticated(simulator.handleSystem))
+	mux.HandleFunc("POST "+simulatorResetPath, simulator.authenticated(simulator.handleReset))
+
+	simulator.server = httptest.NewTLSServer(mux)
+
+	return simulator
+}
+
+// Host returns the address of the simulator, suitable for NewRedfishController.
+func (simulator *RedfishSimulator) Host() string {
+	return simulator.server.Listener.Addr().String()
+}
+
+// Close stops the simulator.
+func (simulator *RedfishSimulator) Close() {
+	simulator.server.Close()
+}
+
+// Status reports the boot ID and readiness of the simulated node. It returns ErrSimulatedNodeOff while the node is
+// powered off.
+func (simulator *RedfishSimulator) Status(ctx context.Context) (NodeStatus, error) {
+	if err := ctx.Err(); err != nil {
+		return NodeStatus{}, err
+	}
+
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	if simulator.powerState != redfish.OnPowerState {
+		return NodeStatus{}, ErrSimulatedNodeOff
+	}
+
+	if simulator.bootingPolls > 0 {
+		simulator.bootingPolls--
+
+		return NodeStatus{BootID: simulator.bootID}, nil
+	}
+
+	return NodeStatus{BootID: simulator.bootID, Ready: true}, nil
+}
+
+// PowerState returns the current power state of the simulated node.
+func (simulator *RedfishSimulator) PowerState() redfish.PowerState {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	return simulator.powerState
+}
+
+// Resets returns the reset types the simulator accepted, in order.
+func (simulator *RedfishSimulator) Resets() []redfish.ResetType {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	return slices.Clone(simulator.resets)
+}
+
+// Crashes returns how many times the simulated node crashed from an NMI.
+func (simulator *RedfishSimulator) Crashes() int {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	return simulator.crashes
+}
+
+func (simulator *RedfishSimulator) handleServiceRoot(writer http.ResponseWriter, _ *http.Request) {
+	writeSimulatorJSON(writer, http.StatusOK, map[string]any{
+		"@odata.id":      "/redfish/v1/",
+		"Id":             "RootService",
+		"RedfishVersion": "1.6.0",
+		"Systems":        map[string]string{"@odata.id": simulatorSystemsPath},
+		"Links": map[string]any{
+			"Sessions": map[string]string{"@odata.id": simulatorSessionsPath},
+		},
+	})
+}
+
+func (simulator *RedfishSimulator) handleCreateSession(writer http.ResponseWriter, request *http.Request) {
+	var credentials struct {
+		UserName string
+		Password string
+	}
+
+	err := json.NewDecoder(request.Body).Decode(&credentials)
+	if err != nil {
+		writeSimulatorError(writer, http.StatusBadRequest, "invalid session request: %v", err)
+
+		return
+	}
+
+	if credentials.UserName != simulator.Username || credentials.Password != simulator.Password {
+		writeSimulatorError(writer, http.StatusUnauthorized, "invalid credentials")
+
+		return
+	}
+
+	token := newSimulatorID()
+
+	simulator.mutex.Lock()
+	simulator.sessions[token] = true
+	simulator.mutex.Unlock()
+
+	location := simulatorSessionsPath + "/" + token
+
+	writer.Header().Set("X-Auth-Token", token)
+	writer.Header().Set("Location", location)
+	writeSimulatorJSON(writer, http.StatusCreated, map[string]string{"@odata.id": location, "Id": token})
+}
+
+func (simulator *RedfishSimulator) handleDeleteSession(writer http.ResponseWriter, request *http.Request) {
+	simulator.mutex.Lock()
+	delete(simulator.sessions, request.PathValue("id"))
+	simulator.mutex.Unlock()
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+func (simulator *RedfishSimulator) handleSystems(writer http.ResponseWriter, _ *http.Request) {
+	writeSimulatorJSON(writer, http.StatusOK, map[string]any{
+		"@odata.id":           simulatorSystemsPath,
+		"Members":             []map[string]string{{"@odata.id": simulatorSystemPath}},
+		"Members@odata.count": 1,
+	})
+}
+
+func (simulator *RedfishSimulator) handleSystem(writer http.ResponseWriter, _ *http.Request) {
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	writeSimulatorJSON(writer, http.StatusOK, map[string]any{
+		"@odata.id":  simulatorSystemPath,
+		"Id":         "1",
+		"PowerState": simulator.powerState,
+		"Actions": map[string]any{
+			"#ComputerSystem.Reset": map[string]any{
+				"target":                            simulatorResetPath,
+				"ResetType@Redfish.AllowableValues": simulator.resetTypes,
+			},
+		},
+	})
+}
+
+func (simulator *RedfishSimulator) handleReset(writer http.ResponseWriter, request *http.Request) {
+	var action struct {
+		ResetType redfish.ResetType
+	}
+
+	err := json.NewDecoder(request.Body).Decode(&action)
+	if err != nil {
+		writeSimulatorError(writer, http.StatusBadRequest, "invalid reset request: %v", err)
+
+		return
+	}
+
+	simulator.mutex.Lock()
+	defer simulator.mutex.Unlock()
+
+	if !slices.Contains(simulator.resetTypes, action.ResetType) {
+		writeSimulatorError(writer, http.StatusBadRequest, "reset type %s is not supported", action.ResetType)
+
+		return
+	}
+
+	err = simulator.reset(action.ResetType)
+	if err != nil {
+		writeSimulatorError(writer, http.StatusConflict, "%v", err)
+
+		return
+	}
+
+	simulator.resets = append(simulator.resets, action.ResetType)
+
+	writer.WriteHeader(http.StatusNoContent)
+}
+
+// reset applies resetType to the simulated node. The mutex must be held.
+func (simulator *RedfishSimulator) reset(resetType redfish.ResetType) error {
+	isOn := simulator.powerState == redfish.OnPowerState
+
+	switch resetType {
+	case redfish.OnResetType:
+		if !isOn {
+			simulator.boot()
+		}
+	case redfish.ForceOffResetType, redfish.GracefulShutdownResetType:
+		simulator.powerState = redfish.OffPowerState
+	case redfish.PowerCycleResetType:
+		simulator.boot()
+	case redfish.GracefulRestartResetType, redfish.ForceRestartResetType:
+		if !isOn {
+			return fmt.Errorf("cannot restart a system that is powered off")
+		}
+
+		simulator.boot()
+	case redfish.NmiResetType:
+		if !isOn {
+			return fmt.Errorf("cannot send an NMI to a system that is powered off")
+		}
+
+		simulator.crashes++
+		simulator.boot()
+	default:
+		return fmt.Errorf("reset type %s is not simulated", resetType)
+	}
+
+	return nil
+}
+
+// boot powers the simulated node on with a new boot ID. The mutex must be held.
+func (simulator *RedfishSimulator) boot() {
+	simulator.powerState = redfish.OnPowerState
+	simulator.bootID = newSimulatorID()
+	simulator.bootingPolls = simulator.BootingPolls
+}
+
+func (simulator *RedfishSimulator) authenticated(handler http.HandlerFunc) http.HandlerFunc {
+	return func(writer http.ResponseWriter, request *http.Request) {
+		simulator.mutex.Lock()
+		valid := simulator.sessions[request.Header.Get("X-Auth-Token")]
+		simulator.mutex.Unlock()
+
+		if !valid {
+			writeSimulatorError(writer, http.StatusUnauthorized, "missing or invalid session token")
+
+			return
+		}
+
+		handler(writer, request)
+	}
+}
+
+func writeSimulatorJSON(writer http.ResponseWriter, status int, body any) {
+	writer.Header().Set("Content-Type", "application/json")
+	writer.WriteHeader(status)
+
+	_ = json.NewEncoder(writer).Encode(body)
+}
+
+func writeSimulatorError(writer http.ResponseWriter, status int, format string, args ...any) {
+	writeSimulatorJSON(writer, status, map[string]any{
+		"error": map[string]string{"code": "Base.1.0.GeneralError", "message": fmt.Sprintf(format, args...)},
+	})
+}
+
+func newSimulatorID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+
+	return hex.EncodeToString(id)
+}
@@ -0,0 +1,73 @@
+package powercontrol
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/nodeexec"
+	"k8s.io/klog/v2"
+)
+
+// systemdRunDelay is how long the node waits before performing an action run through systemd-run, so the command
+// returns before the node goes down.
+const systemdRunDelay = "--on-active=5"
+
+// SystemctlController disrupts a node by running commands on it through a NodeExecutor. Since the node must be up to
+// run them, it cannot power the node on.
+type SystemctlController struct {
+	NodeWatcher
+
+	Executor nodeexec.NodeExecutor
+	NodeName string
+}
+
+// NewSystemctlController returns a SystemctlController running commands on nodeName through executor.
+func NewSystemctlController(executor nodeexec.NodeExecutor, nodeName string, status StatusFunc) *SystemctlController {
+	return &SystemctlController{NodeWatcher: NodeWatcher{Status: status}, Executor: executor, NodeName: nodeName}
+}
+
+// PowerOn always returns ErrUnsupported.
+func (controller *SystemctlController) PowerOn(context.Context) error {
+	return fmt.Errorf("cannot power on node %s through systemctl: %w", controller.NodeName, ErrUnsupported)
+}
+
+// PowerOff powers the node off immediately, without stopping services or unmounting file systems.
+func (controller *SystemctlController) PowerOff(ctx context.Context) error {
+	return controller.schedule(ctx, "systemctl", "poweroff", "--force", "--force")
+}
+
+// PowerCycle reboots the node immediately, without stopping services or unmounting file systems. It is the closest
+// the node can get to a power cycle by itself.
+func (controller *SystemctlController) PowerCycle(ctx context.Context) error {
+	return controller.schedule(ctx, "systemctl", "reboot", "--force", "--force")
+}
+
+// GracefulReboot reboots the node through systemd.
+func (controller *SystemctlController) GracefulReboot(ctx context.Context) error {
+	return controller.schedule(ctx, "systemctl", "reboot")
+}
+
+// InjectNMI crashes the kernel through sysrq, which triggers kdump the same way an NMI does when the kernel is
+// configured to panic on NMI.
+func (controller *SystemctlController) InjectNMI(ctx context.Context) error {
+	return controller.schedule(ctx, nodeexec.Shell("echo 1 > /proc/sys/kernel/sysrq && echo c > /proc/sysrq-trigger")...)
+}
+
+// schedule runs argv on the node through a transient systemd timer so the executor gets its result before the node
+// goes down.
+func (controller *SystemctlController) schedule(ctx context.Context, argv ...string) error {
+	if controller.Executor == nil {
+		return fmt.Errorf("cannot run systemctl on node %s without an executor", controller.NodeName)
+	}
+
+	argv = append([]string{"systemd-run", systemdRunDelay}, argv...)
+
+	klog.V(90).Infof("Scheduling %s on node %s", nodeexec.Quote(argv...), controller.NodeName)
+
+	_, err := nodeexec.Output(ctx, controller.Executor, controller.NodeName, argv...)
+	if err != nil {
+		return fmt.Errorf("failed to schedule %s on node %s: %w", nodeexec.Quote(argv...), controller.NodeName, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package powercontrol
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/nodeexec"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeExecutor records the commands it runs and returns err for each of them.
+type fakeExecutor struct {
+	argvs [][]string
+	err   error
+}
+
+func (executor *fakeExecutor) Execute(_ context.Context, _ string, argv ...string) (nodeexec.Result, error) {
+	executor.argvs = append(executor.argvs, argv)
+
+	return nodeexec.Result{}, executor.err
+}
+
+func (executor *fakeExecutor) Backend() string {
+	return "fake"
+}
+
+func TestSystemctlController(t *testing.T) {
+	testCases := []struct {
+		action       func(*SystemctlController) func(context.Context) error
+		expectedArgv []string
+	}{
+		{
+			action: func(controller *SystemctlController) func(context.Context) error { return controller.PowerOff },
+			expectedArgv: []string{
+				"systemd-run", "--on-active=5", "systemctl", "poweroff", "--force", "--force",
+			},
+		},
+		{
+			action: func(controller *SystemctlController) func(context.Context) error { return controller.PowerCycle },
+			expectedArgv: []string{
+				"systemd-run", "--on-active=5", "systemctl", "reboot", "--force", "--force",
+			},
+		},
+		{
+			action:       func(controller *SystemctlController) func(context.Context) error { return controller.GracefulReboot },
+			expectedArgv: []string{"systemd-run", "--on-active=5", "systemctl", "reboot"},
+		},
+		{
+			action: func(controller *SystemctlController) func(context.Context) error { return controller.InjectNMI },
+			expectedArgv: []string{
+				"systemd-run", "--on-active=5", "sh", "-c",
+				"echo 1 > /proc/sys/kernel/sysrq && echo c > /proc/sysrq-trigger",
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		executor := &fakeExecutor{}
+		controller := NewSystemctlController(executor, "worker-0", nil)
+
+		err := testCase.action(controller)(context.TODO())
+		assert.NoError(t, err)
+		assert.Equal(t, [][]string{testCase.expectedArgv}, executor.argvs)
+	}
+}
+
+func TestSystemctlControllerErrors(t *testing.T) {
+	controller := NewSystemctlController(&fakeExecutor{}, "worker-0", nil)
+
+	err := controller.PowerOn(context.TODO())
+	assert.ErrorIs(t, err, ErrUnsupported)
+
+	errExit := &nodeexec.ExitError{Node: "worker-0", Argv: []string{"systemd-run"}, ExitCode: 1}
+	controller.Executor = &fakeExecutor{err: errExit}
+
+	err = controller.GracefulReboot(context.TODO())
+	assert.ErrorIs(t, err, errExit)
+
+	controller.Executor = nil
+
+	err = controller.GracefulReboot(context.TODO())
+	assert.Error(t, err)
+}
+
+func TestIPMIControllerArgv(t *testing.T) {
+	controller := NewIPMIController("10.0.0.1", "root", "secret", nil)
+	assert.Equal(t,
+		[]string{"ipmitool", "-I", "lanplus", "-H", "10.0.0.1", "-U", "root", "-E", "chassis", "power", "cycle"},
+		controller.Argv("chassis", "power", "cycle"))
+
+	executor := &fakeExecutor{}
+	controller = NewInBandIPMIController(executor, "worker-0", nil)
+
+	err := controller.InjectNMI(context.TODO())
+	assert.NoError(t, err)
+	assert.Equal(t, [][]string{{"ipmitool", "chassis", "power", "diag"}}, executor.argvs)
+}
+
+func TestIPMIControllerGracefulRebootTimeout(t *testing.T) {
+	executor := &fakeExecutor{}
+	controller := NewInBandIPMIController(executor, "worker-0", nil)
+	controller.PollInterval = time.Millisecond
+	controller.PowerOffTimeout = 10 * time.Millisecond
+
+	// The fake executor never reports the node as off, so waiting for it times out without powering it on.
+	err := controller.GracefulReboot(context.TODO())
+	assert.Error(t, err)
+	assert.Equal(t, []string{"ipmitool", "chassis", "power", "soft"}, executor.argvs[0])
+	assert.NotContains(t, executor.argvs, []string{"ipmitool", "chassis", "power", "on"})
+}
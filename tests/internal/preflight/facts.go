@@ -0,0 +1,204 @@
+package preflight
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/infrastructure"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nodes"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/olm"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/schemes/olm/operators/v1alpha1"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/sriov"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+)
+
+const (
+	workerLabel        = "node-role.kubernetes.io/worker"
+	sriovNamespace     = "openshift-sriov-network-operator"
+	nvidiaGPUNodeLabel = "feature.node.kubernetes.io/pci-10de.present=true"
+	amdGPUNodeLabel    = "feature.node.kubernetes.io/pci-1002.present=true"
+)
+
+// Fact is a named fact about the cluster. Facts which could not be probed hold the reason instead of a value.
+type Fact struct {
+	Name  string
+	Value string
+}
+
+// Facts are the facts probed from a cluster, in the order they are printed.
+type Facts []Fact
+
+// Probe probes a single fact from the cluster.
+type Probe struct {
+	Name  string
+	Probe func(apiClient *clients.Settings) (string, error)
+}
+
+// ProbeFacts probes the topology, version, network type, nodes, operators and hardware of the cluster of apiClient.
+// Failing to probe a fact does not stop the others from being probed.
+func ProbeFacts(apiClient *clients.Settings) Facts {
+	return probeAll(apiClient, []Probe{
+		{Name: "Topology", Probe: probeTopology},
+		{Name: "OCP version", Probe: probeOCPVersion},
+		{Name: "Network type", Probe: probeNetworkType},
+		{Name: "Nodes", Probe: probeNodeCount("")},
+		{Name: "Worker nodes", Probe: probeNodeCount(workerLabel)},
+		{Name: "NVIDIA GPU nodes", Probe: probeNodeCount(nvidiaGPUNodeLabel)},
+		{Name: "AMD GPU nodes", Probe: probeNodeCount(amdGPUNodeLabel)},
+		{Name: "SR-IOV NICs", Probe: probeSriovNICs},
+		{Name: "Operators", Probe: probeOperators},
+	})
+}
+
+// probeAll runs probes against the cluster of apiClient in order. Facts which could not be probed hold the reason.
+func probeAll(apiClient *clients.Settings, probes []Probe) Facts {
+	var facts Facts
+
+	for _, probe := range probes {
+		value, err := probe.Probe(apiClient)
+		if err != nil {
+			klog.V(90).Infof("Failed to probe %s: %v", probe.Name, err)
+
+			value = fmt.Sprintf("unknown (%v)", err)
+		}
+
+		facts = append(facts, Fact{Name: probe.Name, Value: value})
+	}
+
+	return facts
+}
+
+// Write writes facts to writer as a table.
+func (facts Facts) Write(writer io.Writer) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(table, "FACT\tVALUE")
+	if err != nil {
+		return err
+	}
+
+	for _, fact := range facts {
+		_, err = fmt.Fprintf(table, "%s\t%s\n", fact.Name, fact.Value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return table.Flush()
+}
+
+func probeTopology(apiClient *clients.Settings) (string, error) {
+	infraConfig, err := infrastructure.Pull(apiClient)
+	if err != nil {
+		return "", err
+	}
+
+	return string(infraConfig.Object.Status.ControlPlaneTopology), nil
+}
+
+func probeOCPVersion(apiClient *clients.Settings) (string, error) {
+	clusterVersion, err := cluster.GetOCPClusterVersion(apiClient)
+	if err != nil {
+		return "", err
+	}
+
+	return clusterVersion.Object.Status.Desired.Version, nil
+}
+
+func probeNetworkType(apiClient *clients.Settings) (string, error) {
+	networkConfig, err := cluster.GetOCPNetworkConfig(apiClient)
+	if err != nil {
+		return "", err
+	}
+
+	return networkConfig.Object.Status.NetworkType, nil
+}
+
+func probeNodeCount(selector string) func(*clients.Settings) (string, error) {
+	return func(apiClient *clients.Settings) (string, error) {
+		nodeList, err := nodes.List(apiClient, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return "", err
+		}
+
+		return fmt.Sprint(len(nodeList)), nil
+	}
+}
+
+// probeSriovNICs returns the number of SR-IOV capable NICs of each PCI vendor across the nodes.
+func probeSriovNICs(apiClient *clients.Settings) (string, error) {
+	nodeStates, err := sriov.ListNetworkNodeState(apiClient, sriovNamespace)
+	if err != nil {
+		return "", err
+	}
+
+	vendors := make(map[string]int)
+
+	for _, nodeState := range nodeStates {
+		for _, nic := range nodeState.Objects.Status.Interfaces {
+			vendors[nic.Vendor]++
+		}
+	}
+
+	return formatCounts(vendors, "vendor"), nil
+}
+
+// probeOperators returns the names of the succeeded CSVs, which include the operator version. Operators installed in
+// all namespaces have a copy of their CSV in each namespace, so names are only listed once.
+func probeOperators(apiClient *clients.Settings) (string, error) {
+	csvs, err := olm.ListClusterServiceVersionInAllNamespaces(apiClient)
+	if err != nil {
+		return "", err
+	}
+
+	found := make(map[string]bool)
+
+	for _, csv := range csvs {
+		if csv.Object.Status.Phase == v1alpha1.CSVPhaseSucceeded {
+			found[csv.Object.Name] = true
+		}
+	}
+
+	var operators []string
+
+	for name := range found {
+		operators = append(operators, name)
+	}
+
+	sort.Strings(operators)
+
+	if len(operators) == 0 {
+		return "none", nil
+	}
+
+	return strings.Join(operators, " "), nil
+}
+
+// formatCounts formats counts as a sorted list of key counts, such as "vendor 15b3: 2, vendor 8086: 4".
+func formatCounts(counts map[string]int, prefix string) string {
+	if len(counts) == 0 {
+		return "none"
+	}
+
+	var keys []string
+
+	for key := range counts {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var formatted []string
+
+	for _, key := range keys {
+		formatted = append(formatted, fmt.Sprintf("%s %s: %d", prefix, key, counts[key]))
+	}
+
+	return strings.Join(formatted, ", ")
+}
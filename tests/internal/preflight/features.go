@@ -0,0 +1,49 @@
+package preflight
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/requires"
+)
+
+// Feature is what the suites of a feature need from the cluster and from their configuration to run. Features are
+// defined inside the tree of their suites, so they can use the suite config and the packages internal to the suites,
+// and are registered with the eco-preflight command.
+type Feature struct {
+	// Name is the name of the feature as used in ECO_TEST_FEATURES.
+	Name string
+	// Config is a pointer to the loaded config of the suites, or nil if it could not be loaded. Its fields tagged
+	// eco_required must be set for the feature to run.
+	Config       any
+	Requirements []requires.Requirement
+	// Facts are probed from the cluster and printed along with the general facts when the feature is checked.
+	Facts []Probe
+}
+
+// Spec returns the requirements of feature, starting with the required fields of its config, as a requires.Spec.
+func (feature Feature) Spec() requires.Spec {
+	requirements := append([]requires.Requirement{RequiredConfig(feature.Config)}, feature.Requirements...)
+
+	return requires.Spec{Name: feature.Name, Requirements: requirements}
+}
+
+// RequiredConfig requires the fields of cfg tagged eco_required to be set, as reported by config.MissingRequired. It
+// is never met when cfg is nil, since the config could not be loaded.
+func RequiredConfig(cfg any) requires.Requirement {
+	return requires.New("required config", func(_ *clients.Settings) (bool, string) {
+		value := reflect.ValueOf(cfg)
+		if cfg == nil || (value.Kind() == reflect.Ptr && value.IsNil()) {
+			return false, "failed to load the suite config"
+		}
+
+		missing := config.MissingRequired(cfg)
+		if len(missing) > 0 {
+			return false, "missing required config: " + strings.Join(missing, ", ")
+		}
+
+		return true, ""
+	})
+}
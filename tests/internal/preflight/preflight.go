@@ -0,0 +1,115 @@
+// Package preflight checks whether a cluster can run the suites of the selected features before a run starts. It
+// probes facts about the cluster, such as its topology, version, operators and hardware, and checks the requirements
+// and the required config of each feature, printing a GO or NO-GO verdict with the reason for each one. It is run by
+// the eco-preflight command, which checks every feature with RunFeature in a program inside the tree of its suites and
+// then combines their reports with Run.
+package preflight
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/requires"
+	"k8s.io/klog/v2"
+)
+
+// FeatureReport is the outcome of checking a single feature, as written by RunFeature and read by Run.
+type FeatureReport struct {
+	Facts  Facts
+	Result requires.Result
+}
+
+// RunFeature probes the facts of feature from the cluster of apiClient, checks its requirements against it and writes
+// the outcome as a FeatureReport in JSON to path.
+func RunFeature(apiClient *clients.Settings, feature Feature, path string) error {
+	if apiClient == nil {
+		return fmt.Errorf("cannot check feature %s: apiClient is nil", feature.Name)
+	}
+
+	klog.V(90).Infof("Checking pre-flight feature %s", feature.Name)
+
+	report := FeatureReport{
+		Facts:  probeAll(apiClient, feature.Facts),
+		Result: requires.Evaluate(apiClient, feature.Spec())[0],
+	}
+
+	content, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to marshal report of feature %s: %w", feature.Name, err)
+	}
+
+	err = os.WriteFile(path, content, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write report of feature %s: %w", feature.Name, err)
+	}
+
+	return nil
+}
+
+// Run probes the cluster of apiClient and writes the facts to writer, followed by the facts and the verdicts of the
+// features from the FeatureReports at reportPaths. It reports whether every feature can run.
+func Run(apiClient *clients.Settings, reportPaths []string, writer io.Writer) (bool, error) {
+	if apiClient == nil {
+		return false, fmt.Errorf("cannot run pre-flight checks: apiClient is nil")
+	}
+
+	if len(reportPaths) == 0 {
+		return false, fmt.Errorf("cannot run pre-flight checks: no features selected")
+	}
+
+	facts := ProbeFacts(apiClient)
+
+	var results []requires.Result
+
+	for _, reportPath := range reportPaths {
+		report, err := readFeatureReport(reportPath)
+		if err != nil {
+			return false, err
+		}
+
+		facts = append(facts, report.Facts...)
+		results = append(results, report.Result)
+	}
+
+	err := facts.Write(writer)
+	if err != nil {
+		return false, err
+	}
+
+	_, err = fmt.Fprintln(writer)
+	if err != nil {
+		return false, err
+	}
+
+	err = requires.WriteReport(writer, results)
+	if err != nil {
+		return false, err
+	}
+
+	for _, result := range results {
+		if !result.Met {
+			return false, nil
+		}
+	}
+
+	return true, nil
+}
+
+func readFeatureReport(path string) (FeatureReport, error) {
+	var report FeatureReport
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return report, fmt.Errorf("failed to read feature report %s: %w", path, err)
+	}
+
+	err = json.Unmarshal(content, &report)
+	if err != nil {
+		return report, fmt.Errorf("failed to unmarshal feature report %s: %w", path, err)
+	}
+
+	return report, nil
+}
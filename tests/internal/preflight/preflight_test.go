@@ -0,0 +1,191 @@
+package preflight
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/fakecluster"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/requires"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// testConfig is a suite config with a required field.
+type testConfig struct {
+	Image string `yaml:"image" envconfig:"ECO_TEST_PREFLIGHT_IMAGE" eco_required:"true"`
+}
+
+func TestRunFeature(t *testing.T) {
+	reportPath := filepath.Join(t.TempDir(), "ptp.json")
+	feature := Feature{
+		Name:         "ptp",
+		Config:       &testConfig{Image: "image"},
+		Requirements: []requires.Requirement{requires.OperatorVersion("openshift-ptp", "ptp-operator", "")},
+		Facts:        []Probe{{Name: "PTP nodes", Probe: probeNodeCount(workerLabel)}},
+	}
+
+	err := RunFeature(buildFakeCluster(t), feature, reportPath)
+	assert.NoError(t, err)
+
+	report, err := readFeatureReport(reportPath)
+	assert.NoError(t, err)
+	assert.Equal(t, FeatureReport{
+		Facts:  Facts{{Name: "PTP nodes", Value: "1"}},
+		Result: requires.Result{Spec: "ptp", Met: true},
+	}, report)
+
+	err = RunFeature(nil, feature, reportPath)
+	assert.Error(t, err)
+}
+
+func TestRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		features []Feature
+		ready    bool
+		verdicts []string
+	}{
+		{
+			name: "ptp",
+			features: []Feature{{
+				Name:         "ptp",
+				Config:       &testConfig{Image: "image"},
+				Requirements: []requires.Requirement{requires.OperatorVersion("openshift-ptp", "ptp-operator", "")},
+				Facts:        []Probe{{Name: "PTP nodes", Probe: probeNodeCount(workerLabel)}},
+			}},
+			ready:    true,
+			verdicts: []string{`ptp +GO`, `PTP nodes +1\n`},
+		},
+		{
+			name: "missing config",
+			features: []Feature{
+				{Name: "ptp", Config: &testConfig{Image: "image"}},
+				{Name: "sriov", Config: &testConfig{}},
+			},
+			verdicts: []string{`ptp +GO`, `sriov +NO-GO +missing required config: Image \(set yaml key image`},
+		},
+		{
+			name: "gpu",
+			features: []Feature{{
+				Name:         "nvidiagpu",
+				Config:       &testConfig{Image: "image"},
+				Requirements: []requires.Requirement{requires.OperatorVersion("openshift-nfd", "nfd", "")},
+			}},
+			verdicts: []string{`nvidiagpu +NO-GO +operator nfd is not installed in namespace openshift-nfd`},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			apiClient := buildFakeCluster(t)
+
+			var reportPaths []string
+
+			for _, feature := range testCase.features {
+				reportPath := filepath.Join(t.TempDir(), feature.Name+".json")
+
+				err := RunFeature(apiClient, feature, reportPath)
+				assert.NoError(t, err)
+
+				reportPaths = append(reportPaths, reportPath)
+			}
+
+			var output bytes.Buffer
+
+			ready, err := Run(apiClient, reportPaths, &output)
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.ready, ready)
+
+			assert.Contains(t, output.String(), "OCP version       4.20.0\n")
+			assert.Contains(t, output.String(), "SR-IOV NICs       vendor 8086: 1\n")
+			assert.Contains(t, output.String(), "Network type      unknown (network.config object cluster does not exist)\n")
+			assert.Contains(t, output.String(), "Operators         ptp-operator.v4.20.0 sriov-network-operator.v4.20.0\n")
+
+			for _, verdict := range testCase.verdicts {
+				assert.Regexp(t, verdict, output.String())
+			}
+		})
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	_, err := Run(nil, []string{"ptp.json"}, &bytes.Buffer{})
+	assert.Error(t, err)
+
+	_, err = Run(buildFakeCluster(t), nil, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "no features selected")
+
+	_, err = Run(buildFakeCluster(t), []string{filepath.Join(t.TempDir(), "missing.json")}, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "failed to read feature report")
+}
+
+func TestRequiredConfig(t *testing.T) {
+	var nilConfig *testConfig
+
+	testCases := []struct {
+		name            string
+		config          any
+		expectedMet     bool
+		expectedMessage string
+	}{
+		{name: "set", config: &testConfig{Image: "image"}, expectedMet: true},
+		{
+			name:            "missing",
+			config:          &testConfig{},
+			expectedMessage: "missing required config: Image (set yaml key image or env var ECO_TEST_PREFLIGHT_IMAGE)",
+		},
+		{name: "not loaded", config: nil, expectedMessage: "failed to load the suite config"},
+		{name: "typed nil", config: nilConfig, expectedMessage: "failed to load the suite config"},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			met, message := RequiredConfig(testCase.config).Check(&clients.Settings{})
+			assert.Equal(t, testCase.expectedMet, met)
+			assert.Equal(t, testCase.expectedMessage, message)
+		})
+	}
+}
+
+var (
+	infrastructureGVK = schema.FromAPIVersionAndKind("config.openshift.io/v1", "Infrastructure")
+	clusterVersionGVK = schema.FromAPIVersionAndKind("config.openshift.io/v1", "ClusterVersion")
+	nodeGVK           = schema.FromAPIVersionAndKind("v1", "Node")
+	nodeStateGVK      = schema.FromAPIVersionAndKind("sriovnetwork.openshift.io/v1", "SriovNetworkNodeState")
+	csvGVK            = schema.FromAPIVersionAndKind("operators.coreos.com/v1alpha1", "ClusterServiceVersion")
+)
+
+func buildFakeCluster(t *testing.T) *clients.Settings {
+	t.Helper()
+
+	apiClient, err := fakecluster.New(
+		fakecluster.Object(infrastructureGVK, "", "cluster", map[string]any{
+			"status": map[string]any{"controlPlaneTopology": "HighlyAvailable"},
+		}),
+		fakecluster.Object(clusterVersionGVK, "", "version", map[string]any{
+			"status": map[string]any{"desired": map[string]any{"version": "4.20.0"}},
+		}),
+		fakecluster.Object(nodeGVK, "", "worker-0", map[string]any{
+			"metadata": map[string]any{"labels": map[string]any{workerLabel: ""}},
+		}),
+		newCSV("openshift-ptp", "ptp-operator.v4.20.0"),
+		newCSV("openshift-sriov-network-operator", "sriov-network-operator.v4.20.0"),
+		fakecluster.Object(nodeStateGVK, sriovNamespace, "worker-0", map[string]any{
+			"status": map[string]any{"interfaces": []any{
+				map[string]any{"name": "ens1f0", "pciAddress": "0000:17:00.0", "vendor": "8086", "linkSpeed": "25000 Mb/s"},
+			}},
+		}),
+	)
+	assert.NoError(t, err)
+
+	return apiClient
+}
+
+func newCSV(namespace, name string) *unstructured.Unstructured {
+	return fakecluster.Object(csvGVK, namespace, name, map[string]any{
+		"status": map[string]any{"phase": "Succeeded"},
+	})
+}
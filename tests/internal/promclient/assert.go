@@ -0,0 +1,190 @@
+package promclient
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/klog/v2"
+)
+
+// DefaultPollInterval is the poll interval of assertions with a timeout but no poll interval.
+const DefaultPollInterval = 5 * time.Second
+
+// assertOptions holds the options of the assertions. It is unexported since the AssertOption functions should be
+// used to configure it.
+type assertOptions struct {
+	timeout        time.Duration
+	pollInterval   time.Duration
+	stableDuration time.Duration
+	startTime      time.Time
+}
+
+// AssertOption configures the timing of AssertQuery and AssertQueryFunc.
+type AssertOption func(*assertOptions)
+
+// VectorCheck checks the result of an instant query, returning an error describing why it does not hold.
+type VectorCheck func(model.Vector) error
+
+func noopAssertOption(*assertOptions) {}
+
+// AssertWithTimeout sets the timeout for the assertion. If the timeout is less than or equal to zero, it does nothing.
+// Similarly, the timeout cannot be set to less than the stable duration. This upholds the invariant that timeout =
+// max(timeout, stableDuration).
+func AssertWithTimeout(timeout time.Duration) AssertOption {
+	if timeout <= 0 {
+		return noopAssertOption
+	}
+
+	return func(options *assertOptions) {
+		if options.stableDuration > timeout {
+			return
+		}
+
+		options.timeout = timeout
+	}
+}
+
+// AssertWithPollInterval sets the poll interval for the assertion. If the poll interval is less than or equal to zero,
+// it does nothing. Note that if the poll interval is set to longer than the timeout, the assertion will only run once.
+func AssertWithPollInterval(pollInterval time.Duration) AssertOption {
+	if pollInterval <= 0 {
+		return noopAssertOption
+	}
+
+	return func(options *assertOptions) {
+		options.pollInterval = pollInterval
+	}
+}
+
+// AssertWithStableDuration sets the stable duration for the assertion. If the stable duration is less than or equal to
+// zero, it does nothing. If the stable duration is set to longer than the timeout, the timeout is updated to be the
+// stable duration. This upholds the invariant that timeout = max(timeout, stableDuration).
+func AssertWithStableDuration(stableDuration time.Duration) AssertOption {
+	if stableDuration <= 0 {
+		return noopAssertOption
+	}
+
+	return func(options *assertOptions) {
+		if options.timeout <= stableDuration {
+			options.timeout = stableDuration
+		}
+
+		options.stableDuration = stableDuration
+	}
+}
+
+// AssertWithStartTime sets the start time for the assertion. If the start time is zero or in the future, it does
+// nothing.
+func AssertWithStartTime(startTime time.Time) AssertOption {
+	if startTime.IsZero() || startTime.After(time.Now()) {
+		return noopAssertOption
+	}
+
+	return func(options *assertOptions) {
+		options.startTime = startTime
+	}
+}
+
+// AssertQuery asserts every sample returned by query has the expected value, after both are rounded to the nearest
+// integer. An empty result fails the assertion. See AssertQueryFunc for the timing of the assertion.
+//
+// SECURITY: The query is not sanitized. It should only be used with trusted queries.
+func AssertQuery(ctx context.Context,
+	client prometheusv1.API, query string, expected float64, options ...AssertOption) error {
+	return AssertQueryFunc(ctx, client, query, EqualsCheck(expected), options...)
+}
+
+// EqualsCheck returns a VectorCheck requiring a non-empty vector whose samples all round to the same integer as
+// expected.
+func EqualsCheck(expected float64) VectorCheck {
+	return func(vector model.Vector) error {
+		if len(vector) == 0 {
+			return fmt.Errorf("no samples returned")
+		}
+
+		for _, sample := range vector {
+			if sample == nil {
+				continue
+			}
+
+			if RoundValue(sample.Value) != RoundValue(model.SampleValue(expected)) {
+				return fmt.Errorf("expected %v, got %v for sample %s", expected, sample.Value, sample)
+			}
+		}
+
+		return nil
+	}
+}
+
+// AssertQueryFunc executes query and asserts check holds for its result. In the base case, the query is executed once
+// at the current time.
+//
+// Options can be provided to specify a timeout, poll interval, stable duration and start time. Timeout is equal to
+// max(timeout, stableDuration) if at least one of them is provided. The query is then executed at every poll
+// interval from the start time, which may be in the past since the metrics are stored by Prometheus, until the call
+// time plus timeout. The assertion succeeds once check holds and, if stableDuration is provided, has held for every
+// poll over the stable duration. A failed check resets the running stable duration.
+//
+// SECURITY: The query is not sanitized. It should only be used with trusted queries.
+func AssertQueryFunc(ctx context.Context,
+	client prometheusv1.API, query string, check VectorCheck, options ...AssertOption) error {
+	if client == nil {
+		return fmt.Errorf("cannot assert query with nil client")
+	}
+
+	opts := &assertOptions{pollInterval: DefaultPollInterval, startTime: time.Now()}
+
+	for _, option := range options {
+		option(opts)
+	}
+
+	// queryTime is the time each query is executed at, incremented by the poll interval. stableTime is the first
+	// time of the current run of successes, so the running stable duration is the time between the two.
+	queryTime := opts.startTime
+	stableTime := queryTime
+	lastTime := time.Now().Add(opts.timeout)
+
+	// The query runs when queryTime equals lastTime, which is what makes it run exactly once without a timeout.
+	for !queryTime.After(lastTime) {
+		select {
+		case <-time.After(time.Until(queryTime)):
+			err := assertQueryAtTime(ctx, client, query, check, queryTime)
+			if err == nil && queryTime.Sub(stableTime) >= opts.stableDuration {
+				return nil
+			}
+
+			queryTime = queryTime.Add(opts.pollInterval)
+
+			if err != nil {
+				klog.V(90).Infof("Query assert failed: %v", err)
+
+				stableTime = queryTime
+			}
+		case <-ctx.Done():
+			return fmt.Errorf("failed to assert query eventually: context finished: %w", ctx.Err())
+		}
+	}
+
+	return fmt.Errorf("failed to assert query eventually: timeout of %s exceeded", opts.timeout)
+}
+
+// assertQueryAtTime executes query at assertTime and applies check to the result.
+func assertQueryAtTime(
+	ctx context.Context, client prometheusv1.API, query string, check VectorCheck, assertTime time.Time) error {
+	vector, err := Query(ctx, client, query, assertTime)
+	if err != nil {
+		return fmt.Errorf("failed at time %s: %w", assertTime, err)
+	}
+
+	err = check(vector)
+	if err != nil {
+		return fmt.Errorf("failed at time %s: %w\nquery: %s", assertTime, err, query)
+	}
+
+	klog.V(90).Infof("Query assert passed at time %s: %s", assertTime, query)
+
+	return nil
+}
@@ -0,0 +1,147 @@
+package promclient
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPI returns the value for the time of each query from values, or an error when there is none.
+type fakeAPI struct {
+	prometheusv1.API
+
+	values  func(queryTime time.Time) (model.Vector, bool)
+	queries int
+}
+
+func (api *fakeAPI) Query(_ context.Context,
+	_ string, queryTime time.Time, _ ...prometheusv1.Option) (model.Value, prometheusv1.Warnings, error) {
+	api.queries++
+
+	vector, found := api.values(queryTime)
+	if !found {
+		return nil, nil, errors.New("no data")
+	}
+
+	return vector, prometheusv1.Warnings{"partial response"}, nil
+}
+
+func sample(value float64) model.Vector {
+	return model.Vector{{Metric: model.Metric{"node": "worker-0"}, Value: model.SampleValue(value)}}
+}
+
+func TestAssertQuery(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+
+	testCases := []struct {
+		name            string
+		values          func(queryTime time.Time) (model.Vector, bool)
+		options         []AssertOption
+		expectedError   bool
+		expectedQueries int
+	}{
+		{
+			name:            "single query passes",
+			values:          func(time.Time) (model.Vector, bool) { return sample(1.0000001), true },
+			expectedQueries: 1,
+		},
+		{
+			name:            "single query fails on value",
+			values:          func(time.Time) (model.Vector, bool) { return sample(2), true },
+			expectedError:   true,
+			expectedQueries: 1,
+		},
+		{
+			name:            "empty result fails",
+			values:          func(time.Time) (model.Vector, bool) { return model.Vector{}, true },
+			expectedError:   true,
+			expectedQueries: 1,
+		},
+		{
+			name: "stable after failures",
+			values: func(queryTime time.Time) (model.Vector, bool) {
+				if queryTime.Before(start.Add(20 * time.Second)) {
+					return sample(0), true
+				}
+
+				return sample(1), true
+			},
+			options: []AssertOption{
+				AssertWithStartTime(start), AssertWithPollInterval(10 * time.Second),
+				AssertWithStableDuration(20 * time.Second),
+			},
+			// Fails at 0s and 10s, then passes at 20s, 30s and 40s.
+			expectedQueries: 5,
+		},
+		{
+			name: "query errors reset stability",
+			values: func(queryTime time.Time) (model.Vector, bool) {
+				return sample(1), !queryTime.Equal(start.Add(10 * time.Second))
+			},
+			options: []AssertOption{
+				AssertWithStartTime(start), AssertWithPollInterval(10 * time.Second),
+				AssertWithStableDuration(10 * time.Second),
+			},
+			// Passes at 0s, errors at 10s, then passes at 20s and 30s.
+			expectedQueries: 4,
+		},
+	}
+
+	for _, testCase := range testCases {
+		api := &fakeAPI{values: testCase.values}
+
+		err := AssertQuery(context.TODO(), api, "up", 1, testCase.options...)
+		assert.Equal(t, testCase.expectedError, err != nil, testCase.name)
+		assert.Equal(t, testCase.expectedQueries, api.queries, testCase.name)
+	}
+}
+
+func TestAssertQueryContextDone(t *testing.T) {
+	api := &fakeAPI{values: func(time.Time) (model.Vector, bool) { return sample(0), true }}
+
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+
+	err := AssertQuery(ctx, api, "up", 1, AssertWithTimeout(time.Hour), AssertWithPollInterval(time.Hour))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestAssertOptions(t *testing.T) {
+	opts := &assertOptions{}
+
+	AssertWithStableDuration(time.Minute)(opts)
+	assert.Equal(t, time.Minute, opts.timeout)
+
+	AssertWithTimeout(time.Second)(opts)
+	assert.Equal(t, time.Minute, opts.timeout)
+
+	AssertWithTimeout(time.Hour)(opts)
+	assert.Equal(t, time.Hour, opts.timeout)
+
+	AssertWithStartTime(time.Now().Add(time.Hour))(opts)
+	assert.True(t, opts.startTime.IsZero())
+
+	AssertWithPollInterval(-time.Second)(opts)
+	assert.Zero(t, opts.pollInterval)
+}
+
+func TestQueryScalar(t *testing.T) {
+	api := &fakeAPI{values: func(time.Time) (model.Vector, bool) { return sample(42), true }}
+
+	value, err := QueryScalar(context.TODO(), api, "count(up)", time.Time{})
+	assert.NoError(t, err)
+	assert.Equal(t, float64(42), value)
+
+	api.values = func(time.Time) (model.Vector, bool) { return append(sample(1), sample(2)...), true }
+
+	_, err = QueryScalar(context.TODO(), api, "up", time.Time{})
+	assert.Error(t, err)
+
+	_, err = Query(context.TODO(), nil, "up", time.Time{})
+	assert.Error(t, err)
+}
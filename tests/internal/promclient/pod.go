@@ -0,0 +1,204 @@
+package promclient
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	prometheusapi "github.com/prometheus/client_golang/api"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/portforward"
+	"k8s.io/client-go/transport/spdy"
+)
+
+// defaultExecTimeout bounds the curl commands of the pod-exec transport when the request has no deadline.
+const defaultExecTimeout = 2 * time.Minute
+
+// newPortForwardAPI returns an API querying a Prometheus pod through a port-forward to a random local port. The
+// port-forward is stopped on close. Since it is bound to the pod, the client stops working if the pod is deleted.
+func newPortForwardAPI(apiClient *clients.Settings, closers *[]func() error) (prometheusv1.API, error) {
+	prometheusPod, err := findPrometheusPod(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	roundTripper, upgrader, err := spdy.RoundTripperFor(apiClient.Config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port-forward round tripper: %w", err)
+	}
+
+	url := apiClient.CoreV1Interface.RESTClient().
+		Post().
+		Namespace(MonitoringNamespace).
+		Resource("pods").
+		Name(prometheusPod.Object.Name).
+		SubResource("portforward").
+		URL()
+
+	stopChan := make(chan struct{})
+	readyChan := make(chan struct{})
+	errChan := make(chan error, 1)
+
+	forwarder, err := portforward.New(spdy.NewDialer(upgrader, &http.Client{Transport: roundTripper}, "POST", url),
+		[]string{fmt.Sprintf("0:%d", PrometheusLocalPort)}, stopChan, readyChan, io.Discard, io.Discard)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create port-forward to pod %s: %w", prometheusPod.Object.Name, err)
+	}
+
+	go func() {
+		errChan <- forwarder.ForwardPorts()
+	}()
+
+	*closers = append(*closers, func() error {
+		close(stopChan)
+
+		return nil
+	})
+
+	select {
+	case <-readyChan:
+	case err = <-errChan:
+		return nil, fmt.Errorf("failed to port-forward to pod %s: %w", prometheusPod.Object.Name, err)
+	case <-time.After(probeTimeout):
+		return nil, fmt.Errorf("timed out waiting for port-forward to pod %s", prometheusPod.Object.Name)
+	}
+
+	ports, err := forwarder.GetPorts()
+	if err != nil || len(ports) == 0 {
+		return nil, fmt.Errorf("failed to get local port forwarded to pod %s: %w", prometheusPod.Object.Name, err)
+	}
+
+	client, err := prometheusapi.NewClient(prometheusapi.Config{
+		Address: fmt.Sprintf("http://127.0.0.1:%d", ports[0].Local),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus client: %w", err)
+	}
+
+	return prometheusv1.NewAPI(client), nil
+}
+
+// newPodExecAPI returns an API querying Prometheus by executing curl in a Prometheus pod for each request. The pod is
+// looked up again for every request, so the client survives the pods being recreated.
+func newPodExecAPI(apiClient *clients.Settings, _ *[]func() error) (prometheusv1.API, error) {
+	client, err := prometheusapi.NewClient(prometheusapi.Config{
+		Address:      fmt.Sprintf("http://localhost:%d", PrometheusLocalPort),
+		RoundTripper: &podExecRoundTripper{apiClient: apiClient},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus client: %w", err)
+	}
+
+	return prometheusv1.NewAPI(client), nil
+}
+
+// podExecRoundTripper sends HTTP requests to the Prometheus listening inside a Prometheus pod using curl.
+type podExecRoundTripper struct {
+	apiClient *clients.Settings
+}
+
+// RoundTrip executes curl in a Prometheus pod with the method, content type and body of request and returns its
+// output as the response.
+func (roundTripper *podExecRoundTripper) RoundTrip(request *http.Request) (*http.Response, error) {
+	prometheusPod, err := findPrometheusPod(roundTripper.apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	var body []byte
+
+	if request.Body != nil {
+		body, err = io.ReadAll(request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read request body: %w", err)
+		}
+	}
+
+	timeout := defaultExecTimeout
+	if deadline, hasDeadline := request.Context().Deadline(); hasDeadline {
+		timeout = time.Until(deadline)
+	}
+
+	output, err := execrecorder.ExecCommandWithTimeout(roundTripper.apiClient, prometheusPod,
+		curlArgv(request, body), timeout, PrometheusContainer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute curl in pod %s: %w", prometheusPod.Object.Name, err)
+	}
+
+	statusCode, responseBody, err := parseCurlOutput(output.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", statusCode, http.StatusText(statusCode)),
+		StatusCode:    statusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewBufferString(responseBody)),
+		ContentLength: int64(len(responseBody)),
+		Request:       request,
+	}, nil
+}
+
+// curlArgv returns the curl command sending request with body to Prometheus inside its pod. The status code is
+// written on the last line of the output.
+func curlArgv(request *http.Request, body []byte) []string {
+	argv := []string{"curl", "--silent", "--show-error", "--request", request.Method, "--write-out", "\n%{http_code}"}
+
+	if contentType := request.Header.Get("Content-Type"); contentType != "" {
+		argv = append(argv, "--header", "Content-Type: "+contentType)
+	}
+
+	if len(body) > 0 {
+		argv = append(argv, "--data-raw", string(body))
+	}
+
+	return append(argv, fmt.Sprintf("http://localhost:%d%s", PrometheusLocalPort, request.URL.RequestURI()))
+}
+
+// parseCurlOutput splits the output of the command from curlArgv into the status code and body. Carriage returns
+// added by the TTY of the exec session are removed.
+func parseCurlOutput(output string) (int, string, error) {
+	output = strings.TrimRight(strings.ReplaceAll(output, "\r\n", "\n"), "\n")
+
+	index := strings.LastIndex(output, "\n")
+	if index < 0 {
+		return 0, "", fmt.Errorf("failed to find status code in curl output %q", output)
+	}
+
+	statusCode, err := strconv.Atoi(strings.TrimSpace(output[index+1:]))
+	if err != nil {
+		return 0, "", fmt.Errorf("failed to parse status code in curl output: %w", err)
+	}
+
+	return statusCode, output[:index], nil
+}
+
+// findPrometheusPod returns a running platform Prometheus pod.
+func findPrometheusPod(apiClient *clients.Settings) (*pod.Builder, error) {
+	podList, err := pod.List(apiClient, MonitoringNamespace, metav1.ListOptions{LabelSelector: PrometheusPodSelector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list prometheus pods: %w", err)
+	}
+
+	for _, prometheusPod := range podList {
+		if prometheusPod.Object.Status.Phase == corev1.PodRunning {
+			return prometheusPod, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no running prometheus pods matching %s in namespace %s",
+		PrometheusPodSelector, MonitoringNamespace)
+}
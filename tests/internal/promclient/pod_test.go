@@ -0,0 +1,67 @@
+package promclient
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCurlArgv(t *testing.T) {
+	request, err := http.NewRequest(http.MethodPost, "http://localhost:9090/api/v1/query?timeout=30s",
+		strings.NewReader(url.Values{"query": []string{`up{job="x"}`}}.Encode()))
+	assert.NoError(t, err)
+
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	assert.Equal(t, []string{
+		"curl", "--silent", "--show-error", "--request", "POST", "--write-out", "\n%{http_code}",
+		"--header", "Content-Type: application/x-www-form-urlencoded",
+		"--data-raw", "query=up%7Bjob%3D%22x%22%7D",
+		"http://localhost:9090/api/v1/query?timeout=30s",
+	}, curlArgv(request, []byte("query=up%7Bjob%3D%22x%22%7D")))
+}
+
+func TestParseCurlOutput(t *testing.T) {
+	testCases := []struct {
+		output         string
+		expectedStatus int
+		expectedBody   string
+		expectedError  bool
+	}{
+		{
+			output:         "{\"status\":\"success\"}\r\n200\r\n",
+			expectedStatus: 200,
+			expectedBody:   `{"status":"success"}`,
+		},
+		{
+			output:         "\n503",
+			expectedStatus: 503,
+			expectedBody:   "",
+		},
+		{
+			output:        "curl: (7) Failed to connect",
+			expectedError: true,
+		},
+		{
+			output:        "body\nnot-a-code",
+			expectedError: true,
+		},
+	}
+
+	for _, testCase := range testCases {
+		status, body, err := parseCurlOutput(testCase.output)
+
+		if testCase.expectedError {
+			assert.Error(t, err)
+
+			continue
+		}
+
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expectedStatus, status)
+		assert.Equal(t, testCase.expectedBody, body)
+	}
+}
@@ -0,0 +1,137 @@
+// Package promclient provides a typed Prometheus API client for the monitoring stack of any cluster, along with query
+// helpers and assertions polling a query until it holds for a stable duration. The client reaches Prometheus through
+// the Thanos Querier route, a port-forward to a Prometheus pod or by executing curl in a Prometheus pod, picking the
+// first transport that works unless one is requested.
+package promclient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"k8s.io/klog/v2"
+)
+
+// Transport is the way the client reaches Prometheus.
+type Transport string
+
+const (
+	// TransportAuto tries the route, port-forward and pod-exec transports in order and uses the first one that can
+	// run a query.
+	TransportAuto Transport = "auto"
+	// TransportRoute queries the Thanos Querier route with a service account token. It covers every Prometheus
+	// instance, including user workload monitoring, but needs the ingress of the cluster to be reachable.
+	TransportRoute Transport = "route"
+	// TransportPortForward queries a Prometheus pod through a port-forward from the API server. It only needs the
+	// API server to be reachable but only covers the platform Prometheus.
+	TransportPortForward Transport = "port-forward"
+	// TransportPodExec queries a Prometheus pod by executing curl in it. It is the slowest transport but works even
+	// when port-forwards are blocked.
+	TransportPodExec Transport = "pod-exec"
+)
+
+const (
+	// MonitoringNamespace is the namespace of the platform monitoring stack.
+	MonitoringNamespace = "openshift-monitoring"
+	// ThanosQuerierRouteName is the name of the Thanos Querier route in MonitoringNamespace.
+	ThanosQuerierRouteName = "thanos-querier"
+	// PrometheusPodSelector selects the platform Prometheus pods in MonitoringNamespace.
+	PrometheusPodSelector = "app.kubernetes.io/name=prometheus,prometheus=k8s"
+	// PrometheusContainer is the container of the Prometheus pods running Prometheus itself.
+	PrometheusContainer = "prometheus"
+	// PrometheusLocalPort is the port Prometheus listens on inside its pod, which does not require authentication.
+	PrometheusLocalPort = 9090
+
+	// probeTimeout bounds the query run to check a transport works.
+	probeTimeout = 30 * time.Second
+)
+
+// Client is a Prometheus API client for a cluster. It must be closed once no longer needed to release the
+// port-forward and the service account created for it, if any.
+type Client struct {
+	prometheusv1.API
+
+	// Transport is the transport used by the client, never TransportAuto.
+	Transport Transport
+
+	closers []func() error
+}
+
+// transportFactory creates the API for a single transport, appending the cleanup it needs to closers.
+type transportFactory func(apiClient *clients.Settings, closers *[]func() error) (prometheusv1.API, error)
+
+// New returns a Client for the cluster of apiClient using transport. When transport is TransportAuto, each transport
+// is tried in order and the first one able to run a query is used.
+func New(apiClient *clients.Settings, transport Transport) (*Client, error) {
+	if apiClient == nil {
+		return nil, fmt.Errorf("cannot create prometheus client with nil apiClient")
+	}
+
+	factories := map[Transport]transportFactory{
+		TransportRoute:       newRouteAPI,
+		TransportPortForward: newPortForwardAPI,
+		TransportPodExec:     newPodExecAPI,
+	}
+
+	candidates := []Transport{transport}
+
+	switch transport {
+	case TransportAuto, "":
+		candidates = []Transport{TransportRoute, TransportPortForward, TransportPodExec}
+	case TransportRoute, TransportPortForward, TransportPodExec:
+	default:
+		return nil, fmt.Errorf("unknown prometheus transport %q", transport)
+	}
+
+	var errs []error
+
+	for _, candidate := range candidates {
+		client := &Client{Transport: candidate}
+
+		api, err := factories[candidate](apiClient, &client.closers)
+		if err == nil {
+			client.API = api
+			err = client.probe()
+		}
+
+		if err == nil {
+			klog.V(90).Infof("Using %s transport for prometheus client", candidate)
+
+			return client, nil
+		}
+
+		klog.V(90).Infof("Prometheus transport %s is not usable: %v", candidate, err)
+
+		errs = append(errs, fmt.Errorf("%s: %w", candidate, err))
+
+		_ = client.Close()
+	}
+
+	return nil, fmt.Errorf("failed to create prometheus client: %w", errors.Join(errs...))
+}
+
+// Close releases the resources created for the client. It is safe to call more than once.
+func (client *Client) Close() error {
+	var errs []error
+
+	for index := len(client.closers) - 1; index >= 0; index-- {
+		errs = append(errs, client.closers[index]())
+	}
+
+	client.closers = nil
+
+	return errors.Join(errs...)
+}
+
+// probe checks the client can run a query.
+func (client *Client) probe() error {
+	ctx, cancel := context.WithTimeout(context.TODO(), probeTimeout)
+	defer cancel()
+
+	_, err := Query(ctx, client, "vector(1)", time.Time{})
+
+	return err
+}
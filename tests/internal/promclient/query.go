@@ -0,0 +1,94 @@
+package promclient
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/model"
+	"k8s.io/klog/v2"
+)
+
+// Query executes an instant query at queryTime, or at the current time when it is zero, and returns the resulting
+// vector. Warnings returned by Prometheus are logged.
+//
+// SECURITY: The query is not sanitized. It should only be used with trusted queries.
+func Query(ctx context.Context, client prometheusv1.API, query string, queryTime time.Time) (model.Vector, error) {
+	if client == nil {
+		return nil, fmt.Errorf("cannot execute query with nil client")
+	}
+
+	if queryTime.IsZero() {
+		queryTime = time.Now()
+	}
+
+	klog.V(90).Infof("Executing query at %s: %s", queryTime, query)
+
+	result, warnings, err := client.Query(ctx, query, queryTime)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query %s: %w", query, err)
+	}
+
+	logWarnings(warnings)
+
+	vector, isVector := result.(model.Vector)
+	if !isVector {
+		return nil, fmt.Errorf("unexpected result type %s for query %s", result.Type(), query)
+	}
+
+	return vector, nil
+}
+
+// QueryRange executes a range query and returns the resulting matrix. Warnings returned by Prometheus are logged.
+//
+// SECURITY: The query is not sanitized. It should only be used with trusted queries.
+func QueryRange(
+	ctx context.Context, client prometheusv1.API, query string, queryRange prometheusv1.Range) (model.Matrix, error) {
+	if client == nil {
+		return nil, fmt.Errorf("cannot execute query range with nil client")
+	}
+
+	klog.V(90).Infof("Executing query range from %s to %s: %s", queryRange.Start, queryRange.End, query)
+
+	result, warnings, err := client.QueryRange(ctx, query, queryRange)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute query range %s: %w", query, err)
+	}
+
+	logWarnings(warnings)
+
+	matrix, isMatrix := result.(model.Matrix)
+	if !isMatrix {
+		return nil, fmt.Errorf("unexpected result type %s for query range %s", result.Type(), query)
+	}
+
+	return matrix, nil
+}
+
+// QueryScalar executes an instant query expected to return a single sample and returns its value.
+func QueryScalar(ctx context.Context, client prometheusv1.API, query string, queryTime time.Time) (float64, error) {
+	vector, err := Query(ctx, client, query, queryTime)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(vector) != 1 {
+		return 0, fmt.Errorf("expected a single sample for query %s but got %d", query, len(vector))
+	}
+
+	return float64(vector[0].Value), nil
+}
+
+// RoundValue rounds the value of a sample to the nearest integer, guarding comparisons of integer metrics against
+// floating point precision.
+func RoundValue(value model.SampleValue) int64 {
+	return int64(math.Round(float64(value)))
+}
+
+func logWarnings(warnings prometheusv1.Warnings) {
+	for _, warning := range warnings {
+		klog.V(90).Infof("Query returned warning: %s", warning)
+	}
+}
@@ -0,0 +1,216 @@
+package promclient
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"time"
+
+	prometheusapi "github.com/prometheus/client_golang/api"
+	prometheusv1 "github.com/prometheus/client_golang/api/prometheus/v1"
+	"github.com/prometheus/common/config"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/rbac"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/route"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/secret"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/serviceaccount"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
+	rbacv1 "k8s.io/api/rbac/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// ServiceAccountNamePrefix is the prefix of the service accounts in MonitoringNamespace whose token is used for
+	// the route transport. Each client creates its own service account, named after the prefix, the ginkgo process and
+	// a random part, so clients in parallel processes or in the same process never share it.
+	ServiceAccountNamePrefix = "eco-promclient"
+	// clusterRoleBindingSuffix is appended to the name of the service account to get the name of its binding to the
+	// monitoring view role.
+	clusterRoleBindingSuffix = "-monitoring-view"
+	// MonitoringViewRole is the cluster role allowing to query the Thanos Querier.
+	MonitoringViewRole = "cluster-monitoring-view"
+	// ServiceAccountLabel is the label set on the service accounts of the route transport and their bindings. Its
+	// value identifies the ginkgo process which created them, so DeleteServiceAccounts can delete them by label,
+	// including those leaked by interrupted runs.
+	ServiceAccountLabel = "eco-gotests.io/promclient"
+
+	// routerCASecret is the secret in routerCANamespace holding the certificate of the default ingress router.
+	routerCASecret = "router-certs-default"
+	// routerCANamespace is the namespace of the default ingress router.
+	routerCANamespace = "openshift-ingress"
+	// tokenDuration is the requested validity of the service account token.
+	tokenDuration = 24 * time.Hour
+)
+
+// newRouteAPI returns an API querying the Thanos Querier route with the token of a service account allowed to view
+// the monitoring stack. The service account is created for the client and deleted on close.
+func newRouteAPI(apiClient *clients.Settings, closers *[]func() error) (prometheusv1.API, error) {
+	routeBuilder, err := route.Pull(apiClient, ThanosQuerierRouteName, MonitoringNamespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pull %s route: %w", ThanosQuerierRouteName, err)
+	}
+
+	if len(routeBuilder.Object.Status.Ingress) == 0 {
+		return nil, fmt.Errorf("route %s has no ingresses", ThanosQuerierRouteName)
+	}
+
+	caPool, err := routerCAPool(apiClient)
+	if err != nil {
+		return nil, err
+	}
+
+	saName := serviceAccountName()
+
+	*closers = append(*closers, func() error {
+		return deleteServiceAccount(apiClient, saName)
+	})
+
+	token, err := createServiceAccountToken(apiClient, saName)
+	if err != nil {
+		return nil, err
+	}
+
+	client, err := prometheusapi.NewClient(prometheusapi.Config{
+		Address: "https://" + routeBuilder.Object.Status.Ingress[0].Host,
+		RoundTripper: config.NewAuthorizationCredentialsRoundTripper(
+			"Bearer",
+			config.NewInlineSecret(token),
+			&http.Transport{TLSClientConfig: &tls.Config{RootCAs: caPool}},
+		),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prometheus client: %w", err)
+	}
+
+	return prometheusv1.NewAPI(client), nil
+}
+
+// serviceAccountName returns a new name for the service account of a client.
+func serviceAccountName() string {
+	return fmt.Sprintf("%s%s-%s", ServiceAccountNamePrefix, parallel.Suffix(), utilrand.String(5))
+}
+
+// serviceAccountLabels returns the labels of the service accounts and bindings created by the current ginkgo process.
+func serviceAccountLabels() map[string]string {
+	return map[string]string{ServiceAccountLabel: ServiceAccountNamePrefix + parallel.Suffix()}
+}
+
+// createServiceAccountToken creates the service account saName and its binding to MonitoringViewRole, if they do not
+// exist yet, and returns a token for it. Both are labelled with serviceAccountLabels.
+func createServiceAccountToken(apiClient *clients.Settings, saName string) (string, error) {
+	saBuilder := serviceaccount.NewBuilder(apiClient, saName, MonitoringNamespace)
+	saBuilder.Definition.Labels = serviceAccountLabels()
+
+	saBuilder, err := saBuilder.Create()
+	if err != nil {
+		return "", fmt.Errorf("failed to create service account %s: %w", saName, err)
+	}
+
+	subject := rbacv1.Subject{Kind: "ServiceAccount", Name: saName, Namespace: MonitoringNamespace}
+	crbName := saName + clusterRoleBindingSuffix
+
+	crbBuilder := rbac.NewClusterRoleBindingBuilder(apiClient, crbName, MonitoringViewRole, subject)
+	crbBuilder.Definition.Labels = serviceAccountLabels()
+
+	_, err = crbBuilder.Create()
+	if err != nil {
+		return "", fmt.Errorf("failed to create cluster role binding %s: %w", crbName, err)
+	}
+
+	token, err := saBuilder.CreateToken(tokenDuration)
+	if err != nil {
+		return "", fmt.Errorf("failed to create token for service account %s: %w", saName, err)
+	}
+
+	return token, nil
+}
+
+// deleteServiceAccount deletes the service account saName and its binding. It does not fail if they do not exist.
+func deleteServiceAccount(apiClient *clients.Settings, saName string) error {
+	crbName := saName + clusterRoleBindingSuffix
+
+	crbBuilder, err := rbac.PullClusterRoleBinding(apiClient, crbName)
+	if err == nil {
+		err = crbBuilder.Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete cluster role binding %s: %w", crbName, err)
+		}
+	}
+
+	saBuilder, err := serviceaccount.Pull(apiClient, saName, MonitoringNamespace)
+	if err == nil {
+		err = saBuilder.Delete()
+		if err != nil {
+			return fmt.Errorf("failed to delete service account %s: %w", saName, err)
+		}
+	}
+
+	return nil
+}
+
+// DeleteServiceAccounts deletes the service accounts and bindings created for the route transport by the current
+// ginkgo process, found by ServiceAccountLabel. Unlike closing the clients, this also deletes those left over by runs
+// which were interrupted before closing their clients.
+func DeleteServiceAccounts(apiClient *clients.Settings) error {
+	if apiClient == nil {
+		return fmt.Errorf("cannot delete prometheus service accounts with nil apiClient")
+	}
+
+	listOptions := metav1.ListOptions{LabelSelector: labels.SelectorFromSet(serviceAccountLabels()).String()}
+
+	crbList, err := apiClient.ClusterRoleBindings().List(context.TODO(), listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list prometheus cluster role bindings: %w", err)
+	}
+
+	for _, crb := range crbList.Items {
+		err = apiClient.ClusterRoleBindings().Delete(context.TODO(), crb.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete cluster role binding %s: %w", crb.Name, err)
+		}
+	}
+
+	saList, err := apiClient.ServiceAccounts(MonitoringNamespace).List(context.TODO(), listOptions)
+	if err != nil {
+		return fmt.Errorf("failed to list prometheus service accounts: %w", err)
+	}
+
+	for _, serviceAccount := range saList.Items {
+		err = apiClient.ServiceAccounts(MonitoringNamespace).Delete(
+			context.TODO(), serviceAccount.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete service account %s: %w", serviceAccount.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// routerCAPool returns the system CA pool with the certificate of the default ingress router added, so routes using
+// the default certificate are trusted. The system pool alone is returned when the router certificate is not found,
+// as is the case for clusters with a custom ingress certificate.
+func routerCAPool(apiClient *clients.Settings) (*x509.CertPool, error) {
+	caPool, err := x509.SystemCertPool()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get system CA pool: %w", err)
+	}
+
+	secretBuilder, err := secret.Pull(apiClient, routerCASecret, routerCANamespace)
+	if err != nil {
+		klog.V(90).Infof("Using system CA pool since the default router CA cannot be pulled: %v", err)
+
+		return caPool, nil
+	}
+
+	if !caPool.AppendCertsFromPEM(secretBuilder.Object.Data["tls.crt"]) {
+		return nil, fmt.Errorf("failed to append default router CA to pool")
+	}
+
+	return caPool, nil
+}
@@ -0,0 +1,53 @@
+package promclient
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	rbacv1 "k8s.io/api/rbac/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestServiceAccountName(t *testing.T) {
+	first := serviceAccountName()
+	second := serviceAccountName()
+
+	assert.True(t, strings.HasPrefix(first, ServiceAccountNamePrefix+"-"))
+	assert.Len(t, first, len(ServiceAccountNamePrefix)+6)
+	assert.NotEqual(t, first, second)
+}
+
+func TestDeleteServiceAccounts(t *testing.T) {
+	labelled := map[string]string{ServiceAccountLabel: ServiceAccountNamePrefix}
+	otherProcess := map[string]string{ServiceAccountLabel: ServiceAccountNamePrefix + "-p2"}
+
+	testClient := clients.GetTestClients(clients.TestClientParams{K8sMockObjects: []runtime.Object{
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name: "eco-promclient-leaked", Namespace: MonitoringNamespace, Labels: labelled}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{
+			Name: "eco-promclient-leaked" + clusterRoleBindingSuffix, Labels: labelled}},
+		&corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{
+			Name: "eco-promclient-p2-other", Namespace: MonitoringNamespace, Labels: otherProcess}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{
+			Name: "eco-promclient-p2-other" + clusterRoleBindingSuffix, Labels: otherProcess}},
+		&rbacv1.ClusterRoleBinding{ObjectMeta: metav1.ObjectMeta{Name: "unrelated"}},
+	}})
+
+	assert.Nil(t, DeleteServiceAccounts(testClient))
+
+	serviceAccounts, err := testClient.ServiceAccounts(MonitoringNamespace).List(context.TODO(), metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, serviceAccounts.Items, 1)
+	assert.Equal(t, "eco-promclient-p2-other", serviceAccounts.Items[0].Name)
+
+	bindings, err := testClient.ClusterRoleBindings().List(context.TODO(), metav1.ListOptions{})
+	assert.Nil(t, err)
+	assert.Len(t, bindings.Items, 2)
+
+	assert.NotNil(t, DeleteServiceAccounts(nil))
+}
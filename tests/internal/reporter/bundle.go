@@ -0,0 +1,456 @@
+package reporter
+
+import (
+	"archive/tar"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/openshift-kni/k8sreporter"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/klog/v2"
+)
+
+const (
+	// BundleExtension is the file extension of failure-dump bundles.
+	BundleExtension = ".tar.zst"
+	// ManifestFileName is the name of the manifest at the root of every bundle.
+	ManifestFileName = "manifest.json"
+
+	// reportXMLIDPrefix is the prefix of the label added by reportxml.ID for the default ID tag.
+	reportXMLIDPrefix = "test_id:"
+)
+
+// Transports used to run node commands recorded in the manifest.
+const (
+	TransportKubeClient = "kube-client"
+	TransportSSH        = "ssh"
+)
+
+// Manifest describes the failed spec and everything captured in its bundle.
+type Manifest struct {
+	SpecText        string        `json:"specText"`
+	Labels          []string      `json:"labels,omitempty"`
+	ReportXMLID     string        `json:"reportxmlID,omitempty"`
+	State           string        `json:"state"`
+	StartTime       time.Time     `json:"startTime"`
+	RunTime         string        `json:"runTime"`
+	FailureLocation string        `json:"failureLocation,omitempty"`
+	FailureMessage  string        `json:"failureMessage,omitempty"`
+	Clusters        []ClusterDump `json:"clusters,omitempty"`
+	NodeCommands    []NodeCommand `json:"nodeCommands,omitempty"`
+	Files           []string      `json:"files,omitempty"`
+}
+
+// ClusterDump describes the resources dumped from a single cluster.
+type ClusterDump struct {
+	// Kubeconfig is the path to the kubeconfig of the cluster. It is empty for the cluster from KUBECONFIG.
+	Kubeconfig string   `json:"kubeconfig"`
+	Namespaces []string `json:"namespaces,omitempty"`
+	CRDs       []string `json:"crds,omitempty"`
+}
+
+// NodeCommand describes a command run on cluster nodes whose output is stored in the bundle.
+type NodeCommand struct {
+	Command   string   `json:"command"`
+	Transport string   `json:"transport"`
+	Nodes     []string `json:"nodes,omitempty"`
+}
+
+// Bundle is the failure dump of a single spec. Reporters write into the directory returned by Dir and record what
+// they captured in the manifest. Close packages the directory into a tar.zst archive and adds it to the index, so
+// several reporters may open and close the same bundle in turn.
+type Bundle struct {
+	cfg      *config.GeneralConfig
+	suite    string
+	name     string
+	dumpDir  string
+	manifest Manifest
+}
+
+// OpenBundle opens the bundle of the spec in the failed tests dump location of testSuite. When the bundle already
+// exists, its contents and manifest are extracted so more data can be added. Dumping failed tests must be enabled in
+// cfg.
+func OpenBundle(cfg *config.GeneralConfig, testSuite string, report types.SpecReport) (*Bundle, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("cannot open bundle without general config")
+	}
+
+	dumpDir := cfg.GetDumpFailedTestReportLocation(testSuite)
+	if dumpDir == "" {
+		return nil, fmt.Errorf("cannot open bundle when dumping failed tests is disabled")
+	}
+
+	bundle := &Bundle{
+		cfg:     cfg,
+		suite:   testSuite,
+		name:    BundleName(report),
+		dumpDir: dumpDir,
+	}
+
+	err := os.MkdirAll(bundle.Dir(), 0755)
+	if err != nil {
+		return nil, err
+	}
+
+	err = extractArchive(bundle.Path(), bundle.Dir())
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to extract existing bundle %s: %w", bundle.Path(), err)
+	}
+
+	err = bundle.readManifest()
+	if err != nil {
+		return nil, err
+	}
+
+	bundle.setSpec(report)
+
+	return bundle, nil
+}
+
+// BundleName returns the name of the bundle of the spec, which is also the name of its directory while open. It
+// matches the directory name used by k8sreporter.
+func BundleName(report types.SpecReport) string {
+	return strings.NewReplacer("/", "-", " ", "_").Replace(report.FullText())
+}
+
+// Name returns the name of the bundle.
+func (bundle *Bundle) Name() string {
+	return bundle.name
+}
+
+// Dir returns the directory reporters write into while the bundle is open.
+func (bundle *Bundle) Dir() string {
+	return filepath.Join(bundle.dumpDir, bundle.name)
+}
+
+// Path returns the path of the bundle archive.
+func (bundle *Bundle) Path() string {
+	return filepath.Join(bundle.dumpDir, bundle.name+BundleExtension)
+}
+
+// Manifest returns a copy of the current manifest of the bundle.
+func (bundle *Bundle) Manifest() Manifest {
+	return bundle.manifest
+}
+
+// AddCluster records the namespaces and CRs dumped from the cluster specified by kubeconfig. Dumps of the same
+// cluster are merged.
+func (bundle *Bundle) AddCluster(kubeconfig string, namespaces map[string]string, cRDs []k8sreporter.CRData) {
+	var dump *ClusterDump
+
+	for index := range bundle.manifest.Clusters {
+		if bundle.manifest.Clusters[index].Kubeconfig == kubeconfig {
+			dump = &bundle.manifest.Clusters[index]
+		}
+	}
+
+	if dump == nil {
+		bundle.manifest.Clusters = append(bundle.manifest.Clusters, ClusterDump{Kubeconfig: kubeconfig})
+		dump = &bundle.manifest.Clusters[len(bundle.manifest.Clusters)-1]
+	}
+
+	for namespace := range namespaces {
+		dump.Namespaces = appendUnique(dump.Namespaces, namespace)
+	}
+
+	scheme := runtime.NewScheme()
+	if err := setReporterSchemes(scheme); err != nil {
+		scheme = nil
+	}
+
+	for _, crd := range cRDs {
+		dump.CRDs = appendUnique(dump.CRDs, describeCRData(scheme, crd))
+	}
+
+	sort.Strings(dump.Namespaces)
+	sort.Strings(dump.CRDs)
+}
+
+// AddNodeCommands records commands run on nodes through transport. Nodes may be empty when they are discovered by
+// the transport.
+func (bundle *Bundle) AddNodeCommands(transport string, commands []string, nodes []string) {
+	for _, command := range commands {
+		bundle.manifest.NodeCommands = append(bundle.manifest.NodeCommands, NodeCommand{
+			Command:   command,
+			Transport: transport,
+			Nodes:     slices.Clone(nodes),
+		})
+	}
+}
+
+// Close writes the manifest, packages the bundle directory into the bundle archive, removes the directory and adds
+// the bundle to the index of the reports directory. The bundle and the index are published to the artifact sink.
+func (bundle *Bundle) Close() error {
+	files, err := listFiles(bundle.Dir())
+	if err != nil {
+		return err
+	}
+
+	bundle.manifest.Files = files
+
+	manifest, err := json.MarshalIndent(bundle.manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(filepath.Join(bundle.Dir(), ManifestFileName), manifest, 0644)
+	if err != nil {
+		return err
+	}
+
+	err = writeArchive(bundle.Dir(), bundle.Path())
+	if err != nil {
+		return fmt.Errorf("failed to write bundle %s: %w", bundle.Path(), err)
+	}
+
+	err = os.RemoveAll(bundle.Dir())
+	if err != nil {
+		return err
+	}
+
+	// Failing to publish must not fail the suite since the artifacts are still available locally.
+	err = publishArtifact(context.TODO(), bundle.cfg, bundle.Path())
+	if err != nil {
+		klog.Errorf("Failed to publish bundle %s: %v", bundle.Path(), err)
+	}
+
+	return updateIndex(bundle.cfg.ReportsDirAbsPath, bundle.indexEntry(), func(indexPath string) {
+		err := publishArtifact(context.TODO(), bundle.cfg, indexPath)
+		if err != nil {
+			klog.Errorf("Failed to publish index %s: %v", indexPath, err)
+		}
+	})
+}
+
+func (bundle *Bundle) readManifest() error {
+	content, err := os.ReadFile(filepath.Join(bundle.Dir(), ManifestFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	err = json.Unmarshal(content, &bundle.manifest)
+	if err != nil {
+		return fmt.Errorf("failed to parse manifest of bundle %s: %w", bundle.name, err)
+	}
+
+	return nil
+}
+
+func (bundle *Bundle) setSpec(report types.SpecReport) {
+	bundle.manifest.SpecText = report.FullText()
+	bundle.manifest.Labels = report.Labels()
+	bundle.manifest.ReportXMLID = ReportXMLID(report.Labels())
+	bundle.manifest.State = report.State.String()
+	bundle.manifest.StartTime = report.StartTime
+	bundle.manifest.RunTime = report.RunTime.String()
+	bundle.manifest.FailureLocation = ""
+	bundle.manifest.FailureMessage = report.Failure.Message
+
+	if !report.Failure.IsZero() {
+		bundle.manifest.FailureLocation = report.Failure.Location.String()
+	}
+}
+
+func (bundle *Bundle) indexEntry() IndexEntry {
+	bundlePath, err := filepath.Rel(bundle.cfg.ReportsDirAbsPath, bundle.Path())
+	if err != nil {
+		bundlePath = bundle.Path()
+	}
+
+	return IndexEntry{
+		Bundle:        bundlePath,
+		SpecText:      bundle.manifest.SpecText,
+		ReportXMLID:   bundle.manifest.ReportXMLID,
+		Labels:        bundle.manifest.Labels,
+		State:         bundle.manifest.State,
+		JUnitReport:   bundle.cfg.GetJunitReportPath(bundle.suite),
+		TestRunReport: bundle.cfg.GetReportPath(),
+	}
+}
+
+// ReportXMLID returns the reportxml ID from the labels of a spec, or an empty string if it has none.
+func ReportXMLID(labels []string) string {
+	for _, label := range labels {
+		if id, found := strings.CutPrefix(label, reportXMLIDPrefix); found {
+			return id
+		}
+	}
+
+	return ""
+}
+
+// describeCRData returns the group, version and kind of the CR list as resolved by scheme, falling back to its Go
+// type when scheme is nil or does not know it.
+func describeCRData(scheme *runtime.Scheme, crd k8sreporter.CRData) string {
+	description := fmt.Sprintf("%T", crd.Cr)
+
+	if scheme != nil {
+		if gvks, _, err := scheme.ObjectKinds(crd.Cr); err == nil && len(gvks) > 0 {
+			description = gvks[0].String()
+		}
+	}
+
+	if crd.Namespace != nil {
+		description = fmt.Sprintf("%s in namespace %s", description, *crd.Namespace)
+	}
+
+	return description
+}
+
+func appendUnique(values []string, value string) []string {
+	if slices.Contains(values, value) {
+		return values
+	}
+
+	return append(values, value)
+}
+
+// listFiles returns the paths of all regular files in dir relative to it, excluding the manifest.
+func listFiles(dir string) ([]string, error) {
+	var files []string
+
+	err := filepath.WalkDir(dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil || !entry.Type().IsRegular() {
+			return err
+		}
+
+		relPath, err := filepath.Rel(dir, filePath)
+		if err != nil {
+			return err
+		}
+
+		if relPath != ManifestFileName {
+			files = append(files, filepath.ToSlash(relPath))
+		}
+
+		return nil
+	})
+
+	return files, err
+}
+
+// writeArchive packages all regular files in dir into a tar.zst archive at archivePath. The archive is written to a
+// temporary file first so an existing archive is only replaced once the new one is complete.
+func writeArchive(dir, archivePath string) error {
+	tempFile, err := os.CreateTemp(filepath.Dir(archivePath), filepath.Base(archivePath)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	encoder, err := zstd.NewWriter(tempFile)
+	if err != nil {
+		return err
+	}
+
+	tarWriter := tar.NewWriter(encoder)
+
+	err = tarWriter.AddFS(os.DirFS(dir))
+	if err != nil {
+		return err
+	}
+
+	err = tarWriter.Close()
+	if err != nil {
+		return err
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), archivePath)
+}
+
+// extractArchive extracts the regular files of the tar.zst archive at archivePath into dir. Entries which would be
+// written outside of dir are rejected.
+func extractArchive(archivePath, dir string) error {
+	archiveFile, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = archiveFile.Close()
+	}()
+
+	decoder, err := zstd.NewReader(archiveFile)
+	if err != nil {
+		return err
+	}
+
+	defer decoder.Close()
+
+	tarReader := tar.NewReader(decoder)
+
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return err
+		}
+
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+
+		if !filepath.IsLocal(header.Name) {
+			return fmt.Errorf("invalid path %s in archive", header.Name)
+		}
+
+		err = extractFile(tarReader, filepath.Join(dir, header.Name), header.FileInfo().Mode())
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func extractFile(reader io.Reader, filePath string, mode fs.FileMode) error {
+	err := os.MkdirAll(filepath.Dir(filePath), 0755)
+	if err != nil {
+		return err
+	}
+
+	outputFile, err := os.OpenFile(filePath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, mode.Perm())
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = outputFile.Close()
+	}()
+
+	_, err = io.Copy(outputFile, reader)
+
+	return err
+}
@@ -0,0 +1,137 @@
+package reporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"syscall"
+)
+
+// IndexFileName is the name of the index of all bundles at the root of the reports directory.
+const IndexFileName = "index.json"
+
+// Index ties the failure-dump bundles of a run to its junit and testrun XML reports.
+type Index struct {
+	JUnitReports   []string     `json:"junitReports,omitempty"`
+	TestRunReports []string     `json:"testrunReports,omitempty"`
+	Bundles        []IndexEntry `json:"bundles"`
+}
+
+// IndexEntry describes a single bundle in the index.
+type IndexEntry struct {
+	// Bundle is the path of the bundle archive relative to the reports directory.
+	Bundle      string   `json:"bundle"`
+	SpecText    string   `json:"specText"`
+	ReportXMLID string   `json:"reportxmlID,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	State       string   `json:"state"`
+	// JUnitReport is the path of the junit report containing the spec.
+	JUnitReport string `json:"junitReport,omitempty"`
+	// TestRunReport is the path of the reportxml testrun report containing the spec, if enabled.
+	TestRunReport string `json:"testrunReport,omitempty"`
+}
+
+// ReadIndex reads the index from the reports directory. A missing index is returned as empty.
+func ReadIndex(reportsDir string) (*Index, error) {
+	index := &Index{}
+
+	content, err := os.ReadFile(filepath.Join(reportsDir, IndexFileName))
+	if errors.Is(err, os.ErrNotExist) {
+		return index, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	err = json.Unmarshal(content, index)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse index in %s: %w", reportsDir, err)
+	}
+
+	return index, nil
+}
+
+// UpdateIndex adds entry to the index in the reports directory, replacing any entry for the same bundle. The index
+// is locked while it is updated since parallel ginkgo processes share the reports directory.
+func UpdateIndex(reportsDir string, entry IndexEntry) error {
+	return updateIndex(reportsDir, entry, nil)
+}
+
+// updateIndex is UpdateIndex with a function called with the path of the index while it is still locked, so the
+// index can be published without a newer version being overwritten by an older one.
+func updateIndex(reportsDir string, entry IndexEntry, afterWrite func(indexPath string)) error {
+	lockFile, err := os.OpenFile(filepath.Join(reportsDir, IndexFileName+".lock"), os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = lockFile.Close()
+	}()
+
+	err = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		return fmt.Errorf("failed to lock index in %s: %w", reportsDir, err)
+	}
+
+	defer func() {
+		_ = syscall.Flock(int(lockFile.Fd()), syscall.LOCK_UN)
+	}()
+
+	index, err := ReadIndex(reportsDir)
+	if err != nil {
+		return err
+	}
+
+	index.Bundles = slices.DeleteFunc(index.Bundles, func(existing IndexEntry) bool {
+		return existing.Bundle == entry.Bundle
+	})
+	index.Bundles = append(index.Bundles, entry)
+
+	sort.Slice(index.Bundles, func(i, j int) bool {
+		return index.Bundles[i].Bundle < index.Bundles[j].Bundle
+	})
+
+	if entry.JUnitReport != "" {
+		index.JUnitReports = appendUnique(index.JUnitReports, entry.JUnitReport)
+		sort.Strings(index.JUnitReports)
+	}
+
+	if entry.TestRunReport != "" {
+		index.TestRunReports = appendUnique(index.TestRunReports, entry.TestRunReport)
+		sort.Strings(index.TestRunReports)
+	}
+
+	err = writeIndex(reportsDir, index)
+	if err != nil {
+		return err
+	}
+
+	if afterWrite != nil {
+		afterWrite(filepath.Join(reportsDir, IndexFileName))
+	}
+
+	return nil
+}
+
+func writeIndex(reportsDir string, index *Index) error {
+	content, err := json.MarshalIndent(index, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	indexPath := filepath.Join(reportsDir, IndexFileName)
+	tempPath := indexPath + ".tmp"
+
+	err = os.WriteFile(tempPath, content, 0644)
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempPath, indexPath)
+}
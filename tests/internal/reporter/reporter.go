@@ -1,26 +1,26 @@
 package reporter
 
 import (
-	"errors"
 	"flag"
 	"fmt"
-	"io"
 	"os"
 	"path"
-	"strings"
+	"path/filepath"
 
 	"github.com/onsi/ginkgo/v2/types"
 	"github.com/openshift-kni/k8sreporter"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/klog/v2"
 )
 
-var (
-	pathToPodExecLogs = "/tmp/pod_exec_logs.log"
-	// generalCfg holds the configuration for reporter operations.
-	generalCfg *config.GeneralConfig
-)
+// ExecRecordsFileName is the name of the file in a failure-dump bundle holding the commands executed by the spec.
+const ExecRecordsFileName = "exec.jsonl"
+
+// generalCfg holds the configuration for reporter operations.
+var generalCfg *config.GeneralConfig
 
 // init initializes the reporter configuration.
 func init() {
@@ -74,8 +74,23 @@ func ReportIfFailed(
 }
 
 // ReportIfFailedOnCluster dumps the requested cluster CRs on the cluster specified by kubeconfig if TC is failed to the
-// given directory.
+// given directory. The dump is packaged into the failure-dump bundle of the spec together with a manifest.
 func ReportIfFailedOnCluster(
+	kubeconfig string,
+	report types.SpecReport,
+	testSuite string,
+	nSpaces map[string]string,
+	cRDs []k8sreporter.CRData) {
+	// The spec is reported at this point, so its exec records are attached to its report and cleared for the next
+	// spec, whether it failed or not.
+	defer execrecorder.Attach()
+
+	reportIfFailedOnCluster(kubeconfig, report, testSuite, nSpaces, cRDs)
+}
+
+// reportIfFailedOnCluster is ReportIfFailedOnCluster without attaching the exec records, so they can be written to the
+// bundle of every cluster before being attached.
+func reportIfFailedOnCluster(
 	kubeconfig string,
 	report types.SpecReport,
 	testSuite string,
@@ -100,70 +115,71 @@ func ReportIfFailedOnCluster(
 			klog.Fatalf("Failed to create log reporter due to %s", err)
 		}
 
-		tcReportFolderName := strings.ReplaceAll(report.FullText(), " ", "_")
+		bundle, err := OpenBundle(generalCfg, testSuite, report)
+		if err != nil {
+			klog.Fatalf("Failed to open failure-dump bundle due to %s", err)
+		}
 
 		// Workaround for the fact we are unable to pass a context to specify a logger for the client used by
 		// the reporter. Otherwise, we get megabytes of verbose logging.
 		_ = flag.Set("v", "0")
 
-		reporter.Dump(report.RunTime, tcReportFolderName)
+		reporter.Dump(report.RunTime, bundle.Name())
 
 		_ = flag.Set("v", generalCfg.VerboseLevel)
 
-		_, podExecLogsFName := path.Split(pathToPodExecLogs)
+		bundle.AddCluster(kubeconfig, nSpaces, cRDs)
 
-		err = moveFile(
-			pathToPodExecLogs, path.Join(generalCfg.ReportsDirAbsPath, tcReportFolderName, podExecLogsFName))
+		err = writeExecRecords(bundle, report)
 		if err != nil {
-			klog.Fatalf("Failed to move pod exec logs %s to report folder: %s", pathToPodExecLogs, err)
+			klog.Fatalf("Failed to write exec records to report folder: %s", err)
 		}
-	}
 
-	err := removeFile(pathToPodExecLogs)
-	if err != nil {
-		klog.Fatal(err.Error())
+		err = bundle.Close()
+		if err != nil {
+			klog.Fatalf("Failed to close failure-dump bundle %s due to %s", bundle.Path(), err)
+		}
 	}
 }
 
-func moveFile(sourcePath, destPath string) error {
-	_, err := os.Stat(sourcePath)
-	if errors.Is(err, os.ErrNotExist) {
-		return nil
-	}
+// ReportIfFailedOnClusters dumps the namespaces and CRs set for every cluster in the clusters registry if TC is failed.
+// Clusters without any namespace or CR to dump are skipped. Each cluster is dumped next to testSuite with its dump
+// prefix, such as hub_<suite file>, while clusters without one, like the cluster from KUBECONFIG, are dumped to
+// testSuite like ReportIfFailed.
+func ReportIfFailedOnClusters(report types.SpecReport, testSuite string) {
+	defer execrecorder.Attach()
 
-	inputFile, err := os.Open(sourcePath)
-	if err != nil {
-		return fmt.Errorf("couldn't open source file: %w", err)
+	if !types.SpecStateFailureStates.Is(report.State) {
+		return
 	}
 
-	defer func() {
-		_ = inputFile.Close()
-	}()
+	for _, cluster := range clusters.All() {
+		if !cluster.HasReporter() {
+			continue
+		}
 
-	outputFile, err := os.Create(destPath)
-	if err != nil {
-		return fmt.Errorf("couldn't open dest file: %w", err)
+		reportIfFailedOnCluster(cluster.Kubeconfig, report, clusterReportPath(testSuite, cluster),
+			cluster.NamespacesToDump, cluster.CRsToDump)
 	}
+}
 
-	defer func() {
-		_ = outputFile.Close()
-	}()
-
-	_, err = io.Copy(outputFile, inputFile)
-	if err != nil {
-		return fmt.Errorf("writing to output file failed: %w", err)
+// clusterReportPath returns the test suite path the failures of cluster are dumped to.
+func clusterReportPath(testSuite string, cluster *clusters.Cluster) string {
+	if cluster.DumpPrefix == "" {
+		return testSuite
 	}
 
-	return nil
+	suiteDir, suiteFile := path.Split(testSuite)
+
+	return fmt.Sprintf("%s%s_%s", suiteDir, cluster.DumpPrefix, suiteFile)
 }
 
-func removeFile(fPath string) error {
-	if _, err := os.Stat(fPath); err == nil {
-		err := os.Remove(fPath)
-		if err != nil {
-			return fmt.Errorf("failed to remove pod exec logs from %s: %w", fPath, err)
-		}
+// writeExecRecords writes the commands recorded during the spec of report into the bundle, if there are any.
+func writeExecRecords(bundle *Bundle, report types.SpecReport) error {
+	records := execrecorder.ForReport(report)
+	if records == "" {
+		return nil
 	}
 
-	return nil
+	return os.WriteFile(filepath.Join(bundle.Dir(), ExecRecordsFileName), []byte(records), 0644)
 }
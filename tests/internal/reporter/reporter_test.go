@@ -1 +1,223 @@
 package reporter
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/openshift-kni/k8sreporter"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestReportXMLID(t *testing.T) {
+	testCases := []struct {
+		labels     []string
+		expectedID string
+	}{
+		{
+			labels:     []string{"ptp", "12345", "test_id:12345"},
+			expectedID: "12345",
+		},
+		{
+			labels:     []string{"ptp"},
+			expectedID: "",
+		},
+		{
+			labels:     nil,
+			expectedID: "",
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedID, ReportXMLID(testCase.labels))
+	}
+}
+
+func TestBundle(t *testing.T) {
+	t.Cleanup(func() { SetSink(nil) })
+
+	reportsDir := t.TempDir()
+	cfg := &config.GeneralConfig{ReportsDirAbsPath: reportsDir, DumpFailedTests: true, EnableReport: true}
+	report := types.SpecReport{
+		ContainerHierarchyTexts: []string{"PTP"},
+		LeafNodeText:            "recovers a/b interface",
+		LeafNodeLabels:          []string{"test_id:12345"},
+		State:                   types.SpecStateFailed,
+		Failure: types.Failure{
+			Message:  "timed out",
+			Location: types.CodeLocation{FileName: "ptp.go", LineNumber: 7},
+		},
+	}
+	namespace := "openshift-ptp"
+
+	bundle, err := OpenBundle(cfg, "/suite/ptp_suite_test.go", report)
+	assert.Nil(t, err)
+	assert.Equal(t, "PTP_recovers_a-b_interface", bundle.Name())
+
+	err = os.WriteFile(filepath.Join(bundle.Dir(), "pods.log"), []byte("pod logs"), 0644)
+	assert.Nil(t, err)
+
+	bundle.AddCluster("", map[string]string{namespace: ""}, []k8sreporter.CRData{
+		{Cr: &corev1.ConfigMapList{}, Namespace: &namespace},
+	})
+
+	err = bundle.Close()
+	assert.Nil(t, err)
+	assert.NoDirExists(t, bundle.Dir())
+	assert.FileExists(t, bundle.Path())
+
+	bundle, err = OpenBundle(cfg, "/suite/ptp_suite_test.go", report)
+	assert.Nil(t, err)
+	assert.FileExists(t, filepath.Join(bundle.Dir(), "pods.log"))
+
+	bundle.AddCluster("", map[string]string{"default": ""}, nil)
+	bundle.AddNodeCommands(TransportSSH, []string{"uptime"}, []string{"worker-0"})
+
+	err = bundle.Close()
+	assert.Nil(t, err)
+
+	bundle, err = OpenBundle(cfg, "/suite/ptp_suite_test.go", report)
+	assert.Nil(t, err)
+
+	manifest := bundle.Manifest()
+	assert.Equal(t, "PTP recovers a/b interface", manifest.SpecText)
+	assert.Equal(t, "12345", manifest.ReportXMLID)
+	assert.Equal(t, "ptp.go:7", manifest.FailureLocation)
+	assert.Equal(t, "timed out", manifest.FailureMessage)
+	assert.Equal(t, []ClusterDump{{
+		Namespaces: []string{"default", namespace},
+		CRDs:       []string{"/v1, Kind=ConfigMapList in namespace openshift-ptp"},
+	}}, manifest.Clusters)
+	assert.Equal(t, []NodeCommand{{Command: "uptime", Transport: TransportSSH, Nodes: []string{"worker-0"}}},
+		manifest.NodeCommands)
+	assert.Equal(t, []string{"pods.log"}, manifest.Files)
+
+	err = bundle.Close()
+	assert.Nil(t, err)
+
+	index, err := ReadIndex(reportsDir)
+	assert.Nil(t, err)
+	assert.Equal(t, []IndexEntry{{
+		Bundle:        "failed_ptp_suite_test/PTP_recovers_a-b_interface.tar.zst",
+		SpecText:      "PTP recovers a/b interface",
+		ReportXMLID:   "12345",
+		Labels:        []string{"test_id:12345"},
+		State:         "failed",
+		JUnitReport:   filepath.Join(reportsDir, "ptp_suite_test_junit.xml"),
+		TestRunReport: filepath.Join(reportsDir, "report_testrun.xml"),
+	}}, index.Bundles)
+	assert.Equal(t, []string{filepath.Join(reportsDir, "ptp_suite_test_junit.xml")}, index.JUnitReports)
+}
+
+func TestOpenBundleDisabled(t *testing.T) {
+	_, err := OpenBundle(&config.GeneralConfig{ReportsDirAbsPath: t.TempDir()}, "suite_test.go", types.SpecReport{})
+	assert.NotNil(t, err)
+
+	_, err = OpenBundle(nil, "suite_test.go", types.SpecReport{})
+	assert.NotNil(t, err)
+}
+
+func TestClusterReportPath(t *testing.T) {
+	t.Cleanup(func() {
+		for _, role := range []clusters.Role{clusters.Hub, clusters.Spoke(2), clusters.Target, clusters.Seed} {
+			clusters.Unregister(role)
+		}
+	})
+
+	testCases := []struct {
+		cluster      *clusters.Cluster
+		expectedPath string
+	}{
+		{
+			cluster:      &clusters.Cluster{Role: clusters.Spoke(1)},
+			expectedPath: "/suite/talm_suite_test.go",
+		},
+		{
+			cluster:      clusters.Register(clusters.Hub, "/kubeconfigs/hub"),
+			expectedPath: "/suite/hub_talm_suite_test.go",
+		},
+		{
+			cluster:      clusters.Register(clusters.Spoke(2), "/kubeconfigs/spoke2"),
+			expectedPath: "/suite/spoke2_talm_suite_test.go",
+		},
+		{
+			cluster:      clusters.Register(clusters.Target, "/kubeconfigs/sno").SetDumpPrefix("spoke"),
+			expectedPath: "/suite/spoke_talm_suite_test.go",
+		},
+		{
+			cluster:      clusters.Register(clusters.Seed, "/kubeconfigs/seed").SetDumpPrefix(""),
+			expectedPath: "/suite/talm_suite_test.go",
+		},
+	}
+
+	for _, testCase := range testCases {
+		assert.Equal(t, testCase.expectedPath, clusterReportPath("/suite/talm_suite_test.go", testCase.cluster))
+	}
+}
+
+// failureRecorder is a ginkgo.GinkgoTestingT which records whether the suite failed, so a failing suite can be run
+// without failing the test.
+type failureRecorder struct {
+	failed bool
+}
+
+func (recorder *failureRecorder) Fail() {
+	recorder.failed = true
+}
+
+func TestExecRecordsOfFailedSpec(t *testing.T) {
+	cfg := &config.GeneralConfig{ReportsDirAbsPath: t.TempDir(), DumpFailedTests: true}
+	testSuite := "/suite/exec_suite_test.go"
+
+	var bundleErrors []error
+
+	ginkgo.Describe("Exec", func() {
+		ginkgo.JustAfterEach(func() {
+			report := ginkgo.CurrentSpecReport()
+
+			bundle, err := OpenBundle(cfg, testSuite, report)
+			if err != nil {
+				bundleErrors = append(bundleErrors, err)
+
+				return
+			}
+
+			bundleErrors = append(bundleErrors, writeExecRecords(bundle, report), bundle.Close())
+
+			execrecorder.Attach()
+		})
+
+		ginkgo.It("fails after executing a command", func() {
+			execrecorder.Start(execrecorder.Target{Node: "worker-0"}, "uptime").Finish("up 1 day", "warning", nil)
+			ginkgo.Fail("expected failure")
+		})
+	})
+
+	suiteConfig, reporterConfig := ginkgo.GinkgoConfiguration()
+	reporterConfig.Succinct = true
+	recorder := &failureRecorder{}
+
+	assert.False(t, ginkgo.RunSpecs(recorder, "Exec Records Suite", suiteConfig, reporterConfig))
+	assert.True(t, recorder.failed)
+
+	for _, err := range bundleErrors {
+		assert.Nil(t, err)
+	}
+
+	bundle, err := OpenBundle(cfg, testSuite, types.SpecReport{
+		LeafNodeText: "fails after executing a command", ContainerHierarchyTexts: []string{"Exec"},
+	})
+	assert.Nil(t, err)
+
+	records, err := os.ReadFile(filepath.Join(bundle.Dir(), ExecRecordsFileName))
+	assert.Nil(t, err)
+	assert.Contains(t, string(records), `"command":"uptime"`)
+	assert.Contains(t, string(records), `"stderr":"warning"`)
+	assert.Empty(t, execrecorder.Records())
+}
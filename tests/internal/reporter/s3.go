@@ -0,0 +1,233 @@
+package reporter
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	s3Algorithm       = "AWS4-HMAC-SHA256"
+	s3Service         = "s3"
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+	s3TimeFormat      = "20060102T150405Z"
+	s3DateFormat      = "20060102"
+	s3DefaultRegion   = "us-east-1"
+	// s3DefaultTimeout bounds each upload of the default client, including streaming the artifact.
+	s3DefaultTimeout = 10 * time.Minute
+)
+
+// S3Options configures an S3Sink.
+type S3Options struct {
+	// Endpoint is the URL of the S3-compatible service, such as https://s3.us-east-1.amazonaws.com or
+	// http://minio:9000. Buckets are addressed by path, which every S3-compatible service supports.
+	Endpoint        string
+	Region          string
+	Bucket          string
+	Prefix          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Client is the HTTP client used for uploads. When it is nil, a client timing out after 10 minutes is used.
+	Client *http.Client
+}
+
+// S3Sink uploads artifacts to a bucket of an S3-compatible service. Requests are signed with AWS signature version 4
+// and the payload is left unsigned, so artifacts are streamed from disk without being read twice.
+type S3Sink struct {
+	options  S3Options
+	endpoint *url.URL
+	now      func() time.Time
+}
+
+// NewS3Sink validates options and returns a new S3Sink.
+func NewS3Sink(options S3Options) (*S3Sink, error) {
+	if options.Endpoint == "" || options.Bucket == "" {
+		return nil, fmt.Errorf("the %s sink requires an endpoint and a bucket", SinkS3)
+	}
+
+	if options.AccessKeyID == "" || options.SecretAccessKey == "" {
+		return nil, fmt.Errorf("the %s sink requires an access key ID and a secret access key", SinkS3)
+	}
+
+	endpoint, err := url.Parse(options.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s endpoint %s: %w", SinkS3, options.Endpoint, err)
+	}
+
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid %s endpoint %s: scheme must be http or https", SinkS3, options.Endpoint)
+	}
+
+	if options.Region == "" {
+		options.Region = s3DefaultRegion
+	}
+
+	if options.Client == nil {
+		options.Client = &http.Client{Timeout: s3DefaultTimeout}
+	}
+
+	return &S3Sink{options: options, endpoint: endpoint, now: time.Now}, nil
+}
+
+// Put uploads body to the bucket of the sink as key, prefixed by the configured prefix.
+func (sink *S3Sink) Put(ctx context.Context, key string, body io.Reader, size int64) error {
+	objectPath := "/" + path.Join(sink.options.Bucket, sink.options.Prefix, key)
+	objectURL := *sink.endpoint
+	objectURL.Path = path.Join("/", sink.endpoint.Path, objectPath)
+	objectURL.RawPath = s3EscapePath(objectURL.Path)
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPut, objectURL.String(), body)
+	if err != nil {
+		return err
+	}
+
+	request.ContentLength = size
+
+	sink.sign(request, s3UnsignedPayload)
+
+	response, err := sink.options.Client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to upload %s to %s: %w", key, sink.options.Endpoint, err)
+	}
+
+	defer func() {
+		_ = response.Body.Close()
+	}()
+
+	if response.StatusCode/100 != 2 {
+		message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+
+		return fmt.Errorf("failed to upload %s to %s: %s: %s",
+			key, sink.options.Endpoint, response.Status, strings.TrimSpace(string(message)))
+	}
+
+	return nil
+}
+
+// sign adds the AWS signature version 4 headers to request. Only the host and x-amz- headers are signed.
+func (sink *S3Sink) sign(request *http.Request, payloadHash string) {
+	now := sink.now().UTC()
+
+	request.Header.Set("X-Amz-Date", now.Format(s3TimeFormat))
+	request.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	signedHeaders, canonicalHeaders := s3CanonicalHeaders(request)
+	canonicalRequest := strings.Join([]string{
+		request.Method,
+		s3EscapePath(request.URL.Path),
+		s3CanonicalQuery(request.URL.Query()),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{now.Format(s3DateFormat), sink.options.Region, s3Service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{s3Algorithm, now.Format(s3TimeFormat), scope, s3Hash(canonicalRequest)}, "\n")
+
+	signingKey := s3HMAC([]byte("AWS4"+sink.options.SecretAccessKey), now.Format(s3DateFormat))
+	signingKey = s3HMAC(signingKey, sink.options.Region)
+	signingKey = s3HMAC(signingKey, s3Service)
+	signingKey = s3HMAC(signingKey, "aws4_request")
+
+	request.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s3Algorithm, sink.options.AccessKeyID, scope, signedHeaders,
+		hex.EncodeToString(s3HMAC(signingKey, stringToSign))))
+}
+
+// s3CanonicalHeaders returns the signed header names and the canonical headers of request.
+func s3CanonicalHeaders(request *http.Request) (string, string) {
+	headers := map[string]string{"host": request.URL.Host}
+
+	for name, values := range request.Header {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "x-amz-") || name == "range" {
+			headers[name] = strings.Join(values, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+
+	for name := range headers {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var canonical strings.Builder
+
+	for _, name := range names {
+		canonical.WriteString(name + ":" + strings.TrimSpace(headers[name]) + "\n")
+	}
+
+	return strings.Join(names, ";"), canonical.String()
+}
+
+func s3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+
+	for key := range query {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	var pairs []string
+
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+
+		for _, value := range values {
+			pairs = append(pairs, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// s3EscapePath escapes an object path following the S3 rules, which keep slashes.
+func s3EscapePath(objectPath string) string {
+	return s3Escape(objectPath, false)
+}
+
+// s3Escape URI-encodes every byte except the unreserved characters. Slashes are encoded only when encodeSlash is
+// true.
+func s3Escape(value string, encodeSlash bool) string {
+	var escaped strings.Builder
+
+	for _, char := range []byte(value) {
+		switch {
+		case 'A' <= char && char <= 'Z', 'a' <= char && char <= 'z', '0' <= char && char <= '9',
+			char == '-', char == '_', char == '.', char == '~':
+			escaped.WriteByte(char)
+		case char == '/' && !encodeSlash:
+			escaped.WriteByte(char)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", char)
+		}
+	}
+
+	return escaped.String()
+}
+
+func s3Hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+
+	return hex.EncodeToString(sum[:])
+}
+
+func s3HMAC(key []byte, value string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(value))
+
+	return mac.Sum(nil)
+}
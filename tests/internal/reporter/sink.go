@@ -0,0 +1,289 @@
+package reporter
+
+import (
+	"archive/tar"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/onsi/ginkgo/v2/reporters"
+	"github.com/onsi/ginkgo/v2/types"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
+	"k8s.io/klog/v2"
+)
+
+// Names of the sinks which can be selected with the artifact_sink config field.
+const (
+	SinkLocal   = "local"
+	SinkTarball = "tarball"
+	SinkS3      = "s3"
+)
+
+const (
+	// tarTrailerSize is the size of the two zero blocks terminating a tar archive written by archive/tar.
+	tarTrailerSize = 2 * 512
+	// publishTimeout bounds publishing a single artifact, so a stalled sink cannot hang the suite while it reports.
+	publishTimeout = 10 * time.Minute
+)
+
+// Sink receives the artifacts of a run, such as the junit and testrun reports and the failure-dump bundles. Artifacts
+// are published as soon as they are written, so a sink receives them while the suite is still running.
+type Sink interface {
+	// Put stores size bytes read from body under key. Keys are slash-separated paths relative to the reports
+	// directory.
+	Put(ctx context.Context, key string, body io.Reader, size int64) error
+}
+
+// NewSink returns the sink selected by the artifact sink fields of cfg.
+func NewSink(cfg *config.GeneralConfig) (Sink, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("cannot create sink without general config")
+	}
+
+	switch cfg.ArtifactSink {
+	case "", SinkLocal:
+		dir := cfg.ArtifactSinkPath
+		if dir == "" {
+			dir = cfg.ReportsDirAbsPath
+		}
+
+		return &LocalSink{Dir: dir}, nil
+	case SinkTarball:
+		if cfg.ArtifactSinkPath == "" {
+			return nil, fmt.Errorf("the %s sink requires an artifact sink path", SinkTarball)
+		}
+
+		return &TarballSink{Path: cfg.ArtifactSinkPath}, nil
+	case SinkS3:
+		return NewS3Sink(S3Options{
+			Endpoint:        cfg.ArtifactS3Endpoint,
+			Region:          cfg.ArtifactS3Region,
+			Bucket:          cfg.ArtifactS3Bucket,
+			Prefix:          cfg.ArtifactS3Prefix,
+			AccessKeyID:     cfg.ArtifactS3AccessKeyID,
+			SecretAccessKey: cfg.ArtifactS3SecretAccessKey,
+		})
+	default:
+		return nil, fmt.Errorf("unknown artifact sink %s, must be one of %s, %s or %s",
+			cfg.ArtifactSink, SinkLocal, SinkTarball, SinkS3)
+	}
+}
+
+// LocalSink copies artifacts into a local directory. When Dir is the reports directory, artifacts are already in
+// place and nothing is copied.
+type LocalSink struct {
+	Dir string
+}
+
+// Put copies body to key inside the directory of the sink, replacing any existing file.
+func (sink *LocalSink) Put(ctx context.Context, key string, body io.Reader, size int64) error {
+	destPath := filepath.Join(sink.Dir, filepath.FromSlash(key))
+
+	err := os.MkdirAll(filepath.Dir(destPath), 0755)
+	if err != nil {
+		return err
+	}
+
+	tempFile, err := os.CreateTemp(filepath.Dir(destPath), filepath.Base(destPath)+".*")
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = tempFile.Close()
+		_ = os.Remove(tempFile.Name())
+	}()
+
+	_, err = io.CopyN(tempFile, body, size)
+	if err != nil {
+		return err
+	}
+
+	err = tempFile.Close()
+	if err != nil {
+		return err
+	}
+
+	return os.Rename(tempFile.Name(), destPath)
+}
+
+// TarballSink appends artifacts to an uncompressed tar archive. The archive is valid after every Put, so it can be
+// collected at any time, and it is locked while appending since parallel ginkgo processes share it. An artifact
+// published more than once appears once per Put; tar extracts the latest one.
+type TarballSink struct {
+	Path string
+}
+
+// Put appends body to the archive of the sink as key.
+func (sink *TarballSink) Put(ctx context.Context, key string, body io.Reader, size int64) error {
+	err := os.MkdirAll(filepath.Dir(sink.Path), 0755)
+	if err != nil {
+		return err
+	}
+
+	archiveFile, err := os.OpenFile(sink.Path, os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = archiveFile.Close()
+	}()
+
+	err = syscall.Flock(int(archiveFile.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		return fmt.Errorf("failed to lock tarball %s: %w", sink.Path, err)
+	}
+
+	defer func() {
+		_ = syscall.Flock(int(archiveFile.Fd()), syscall.LOCK_UN)
+	}()
+
+	fileInfo, err := archiveFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	// Overwrite the trailer of the existing archive so the new entry is appended before it.
+	if fileInfo.Size() >= tarTrailerSize {
+		_, err = archiveFile.Seek(-tarTrailerSize, io.SeekEnd)
+		if err != nil {
+			return err
+		}
+	}
+
+	tarWriter := tar.NewWriter(archiveFile)
+
+	err = tarWriter.WriteHeader(&tar.Header{
+		Name:    strings.TrimPrefix(key, "/"),
+		Mode:    0644,
+		Size:    size,
+		ModTime: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.CopyN(tarWriter, body, size)
+	if err != nil {
+		return err
+	}
+
+	return tarWriter.Close()
+}
+
+// artifactSink is the sink artifacts are published to. It is created from the config on first use unless set with
+// SetSink.
+var artifactSink Sink
+
+// SetSink allows overriding the sink selected by the configuration.
+func SetSink(sink Sink) {
+	artifactSink = sink
+}
+
+// PublishSuiteReports writes the junit report of testSuite and publishes it to the sink together with the reportxml
+// testrun report. It must be called from ReportAfterSuite after reportxml.Create, since ginkgo only writes the junit
+// report it was configured with after all ReportAfterSuite nodes have run.
+func PublishSuiteReports(report types.Report, testSuite string) {
+	if generalCfg == nil {
+		klog.V(100).Infof("No reporter configuration available, skipping publishing reports for: %s", testSuite)
+
+		return
+	}
+
+	sink, err := getSink(generalCfg)
+	if err != nil {
+		klog.Errorf("Failed to create artifact sink: %v", err)
+
+		return
+	}
+
+	if isInPlace(sink, generalCfg) {
+		return
+	}
+
+	junitPath := generalCfg.GetJunitReportPath(testSuite)
+
+	err = reporters.GenerateJUnitReport(report, junitPath)
+	if err != nil {
+		klog.Errorf("Failed to write junit report %s: %v", junitPath, err)
+	}
+
+	for _, reportPath := range []string{junitPath, generalCfg.GetReportPath()} {
+		// The testrun report is not written when it is disabled or the suite does not create it.
+		if _, err := os.Stat(reportPath); reportPath == "" || errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+
+		err = publishArtifact(context.TODO(), generalCfg, reportPath)
+		if err != nil {
+			klog.Errorf("Failed to publish report %s: %v", reportPath, err)
+		}
+	}
+}
+
+// publishArtifact publishes the file at filePath, which must be inside the reports directory of cfg, to the sink. It
+// gives up after publishTimeout.
+func publishArtifact(ctx context.Context, cfg *config.GeneralConfig, filePath string) error {
+	ctx, cancel := context.WithTimeout(ctx, publishTimeout)
+	defer cancel()
+
+	sink, err := getSink(cfg)
+	if err != nil {
+		return err
+	}
+
+	if isInPlace(sink, cfg) {
+		return nil
+	}
+
+	key, err := filepath.Rel(cfg.ReportsDirAbsPath, filePath)
+	if err != nil || !filepath.IsLocal(key) {
+		return fmt.Errorf("artifact %s is not inside reports directory %s", filePath, cfg.ReportsDirAbsPath)
+	}
+
+	artifactFile, err := os.Open(filePath)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		_ = artifactFile.Close()
+	}()
+
+	fileInfo, err := artifactFile.Stat()
+	if err != nil {
+		return err
+	}
+
+	return sink.Put(ctx, filepath.ToSlash(key), artifactFile, fileInfo.Size())
+}
+
+func getSink(cfg *config.GeneralConfig) (Sink, error) {
+	if artifactSink != nil {
+		return artifactSink, nil
+	}
+
+	sink, err := NewSink(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	artifactSink = sink
+
+	return sink, nil
+}
+
+// isInPlace returns true when sink is a local sink writing to the reports directory, so artifacts are already
+// published once written.
+func isInPlace(sink Sink, cfg *config.GeneralConfig) bool {
+	localSink, isLocal := sink.(*LocalSink)
+
+	return isLocal && filepath.Clean(localSink.Dir) == filepath.Clean(cfg.ReportsDirAbsPath)
+}
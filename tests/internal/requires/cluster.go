@@ -0,0 +1,162 @@
+package requires
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/infrastructure"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nodes"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/olm"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/schemes/olm/operators/v1alpha1"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/sriov"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/cluster"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Topology requires the control plane of the cluster to have the given topology.
+func Topology(topology configv1.TopologyMode) Requirement {
+	return New(fmt.Sprintf("%s topology", topology), func(apiClient *clients.Settings) (bool, string) {
+		infraConfig, err := infrastructure.Pull(apiClient)
+		if err != nil {
+			return false, fmt.Sprintf("failed to pull infrastructure configuration: %v", err)
+		}
+
+		if current := infraConfig.Object.Status.ControlPlaneTopology; current != topology {
+			return false, fmt.Sprintf("control plane topology is %s, not %s", current, topology)
+		}
+
+		return true, ""
+	})
+}
+
+// SNO requires the cluster to be a Single Node OpenShift cluster.
+func SNO() Requirement {
+	return Topology(configv1.SingleReplicaTopologyMode)
+}
+
+// OCPVersion requires the desired OCP version of the cluster to satisfy constraint, such as ">=4.16, <4.21".
+// Pre-release and build metadata of the cluster version are ignored, so nightly builds of 4.16.0 satisfy >=4.16.
+func OCPVersion(constraint string) Requirement {
+	return New("OCP version "+constraint, func(apiClient *clients.Settings) (bool, string) {
+		clusterVersion, err := cluster.GetOCPClusterVersion(apiClient)
+		if err != nil {
+			return false, fmt.Sprintf("failed to get clusterversion: %v", err)
+		}
+
+		return CheckVersion("OCP", clusterVersion.Object.Status.Desired.Version, constraint)
+	})
+}
+
+// NetworkType requires the cluster network type to be one of networkTypes, such as OVNKubernetes.
+func NetworkType(networkTypes ...string) Requirement {
+	description := "network type " + strings.Join(networkTypes, " or ")
+
+	return New(description, func(apiClient *clients.Settings) (bool, string) {
+		networkConfig, err := cluster.GetOCPNetworkConfig(apiClient)
+		if err != nil {
+			return false, fmt.Sprintf("failed to get cluster network config: %v", err)
+		}
+
+		if current := networkConfig.Object.Status.NetworkType; !slices.Contains(networkTypes, current) {
+			return false, fmt.Sprintf("network type is %s, not %s", current, strings.Join(networkTypes, " or "))
+		}
+
+		return true, ""
+	})
+}
+
+// OperatorVersion requires a succeeded CSV whose name starts with csvPrefix in namespace, with a version satisfying
+// constraint. An empty constraint only requires the operator to be installed.
+func OperatorVersion(namespace, csvPrefix, constraint string) Requirement {
+	description := fmt.Sprintf("operator %s in %s %s", csvPrefix, namespace, constraint)
+
+	return New(strings.TrimSpace(description), func(apiClient *clients.Settings) (bool, string) {
+		csvs, err := olm.ListClusterServiceVersionWithNamePattern(apiClient, csvPrefix, namespace)
+		if err != nil {
+			return false, fmt.Sprintf("failed to list CSVs in namespace %s: %v", namespace, err)
+		}
+
+		for _, csv := range csvs {
+			if !strings.HasPrefix(csv.Object.Name, csvPrefix) || csv.Object.Status.Phase != v1alpha1.CSVPhaseSucceeded {
+				continue
+			}
+
+			if constraint == "" {
+				return true, ""
+			}
+
+			return CheckVersion(csv.Object.Name, csv.Object.Spec.Version.String(), constraint)
+		}
+
+		return false, fmt.Sprintf("operator %s is not installed in namespace %s", csvPrefix, namespace)
+	})
+}
+
+// Nodes requires at least minimum nodes matching the label selector. An empty selector matches every node.
+func Nodes(selector string, minimum int) Requirement {
+	description := fmt.Sprintf("%d nodes", minimum)
+	if selector != "" {
+		description = fmt.Sprintf("%d nodes with %s", minimum, selector)
+	}
+
+	return New(description, func(apiClient *clients.Settings) (bool, string) {
+		nodeList, err := nodes.List(apiClient, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return false, fmt.Sprintf("failed to list nodes: %v", err)
+		}
+
+		if len(nodeList) < minimum {
+			return false, fmt.Sprintf("found %d nodes matching %q, need %d", len(nodeList), selector, minimum)
+		}
+
+		return true, ""
+	})
+}
+
+// NICVendor requires a node to have an SR-IOV capable NIC from one of the given PCI vendor IDs, such as 8086 for Intel,
+// according to the SriovNetworkNodeStates in the SR-IOV operator namespace.
+func NICVendor(sriovOperatorNamespace string, vendorIDs ...string) Requirement {
+	description := "NIC vendor " + strings.Join(vendorIDs, " or ")
+
+	return New(description, func(apiClient *clients.Settings) (bool, string) {
+		nodeStates, err := sriov.ListNetworkNodeState(apiClient, sriovOperatorNamespace)
+		if err != nil {
+			return false, fmt.Sprintf("failed to list SriovNetworkNodeStates: %v", err)
+		}
+
+		for _, nodeState := range nodeStates {
+			for _, nic := range nodeState.Objects.Status.Interfaces {
+				if slices.Contains(vendorIDs, nic.Vendor) {
+					return true, ""
+				}
+			}
+		}
+
+		return false, fmt.Sprintf("no node has an SR-IOV NIC from vendor %s", strings.Join(vendorIDs, " or "))
+	})
+}
+
+// CheckVersion reports whether the core of version satisfies constraint, such as ">=4.16", with a message naming what
+// was checked. Pre-release and build metadata of version are ignored.
+func CheckVersion(name, version, constraint string) (bool, string) {
+	constraints, err := semver.NewConstraint(constraint)
+	if err != nil {
+		return false, fmt.Sprintf("invalid version constraint %q: %v", constraint, err)
+	}
+
+	parsed, err := semver.NewVersion(strings.TrimPrefix(version, "v"))
+	if err != nil {
+		return false, fmt.Sprintf("failed to parse %s version %q: %v", name, version, err)
+	}
+
+	core := semver.New(parsed.Major(), parsed.Minor(), parsed.Patch(), "", "")
+	if !constraints.Check(core) {
+		return false, fmt.Sprintf("%s version %s does not satisfy %s", name, version, constraint)
+	}
+
+	return true, ""
+}
@@ -0,0 +1,166 @@
+// Package requires provides composable requirements a cluster must meet for specs to run. Requirements work on any
+// clients.Settings, so the same checks can skip specs on hub and spoke clusters and feed a pre-flight report of which
+// specs can run on a cluster before a run starts.
+package requires
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"k8s.io/klog/v2"
+)
+
+// Requirement is a condition a cluster must meet. Like the requirements of the assisted meets package, checking it
+// returns whether it is met and, if not, a message explaining why.
+type Requirement struct {
+	// Description is a short description of the requirement used in reports, such as "OCP version >=4.16".
+	Description string
+	check       func(apiClient *clients.Settings) (bool, string)
+}
+
+// New returns a Requirement with the given description that is met when check returns true.
+func New(description string, check func(apiClient *clients.Settings) (bool, string)) Requirement {
+	return Requirement{Description: description, check: check}
+}
+
+// Check reports whether the cluster of apiClient meets requirement. Requirements are never met without a client.
+func (requirement Requirement) Check(apiClient *clients.Settings) (bool, string) {
+	if apiClient == nil {
+		return false, fmt.Sprintf("%s: apiClient is nil", requirement.Description)
+	}
+
+	if requirement.check == nil {
+		return false, fmt.Sprintf("%s: requirement has no check", requirement.Description)
+	}
+
+	met, message := requirement.check(apiClient)
+
+	klog.V(90).Infof("Requirement %q met: %t %s", requirement.Description, met, message)
+
+	return met, message
+}
+
+// All returns a Requirement met when every one of requirements is met. Its message is the one of the first requirement
+// not met.
+func All(requirements ...Requirement) Requirement {
+	return New(joinDescriptions(requirements, " and "), func(apiClient *clients.Settings) (bool, string) {
+		return Check(apiClient, requirements...)
+	})
+}
+
+// Any returns a Requirement met when at least one of requirements is met. Its message lists why each one is not.
+func Any(requirements ...Requirement) Requirement {
+	return New(joinDescriptions(requirements, " or "), func(apiClient *clients.Settings) (bool, string) {
+		var messages []string
+
+		for _, requirement := range requirements {
+			met, message := requirement.Check(apiClient)
+			if met {
+				return true, ""
+			}
+
+			messages = append(messages, message)
+		}
+
+		return false, strings.Join(messages, "; ")
+	})
+}
+
+// Not returns a Requirement met when requirement is not.
+func Not(requirement Requirement) Requirement {
+	description := "not " + requirement.Description
+
+	return New(description, func(apiClient *clients.Settings) (bool, string) {
+		met, _ := requirement.Check(apiClient)
+		if met {
+			return false, fmt.Sprintf("requirement %q is not met", description)
+		}
+
+		return true, ""
+	})
+}
+
+// Check reports whether the cluster of apiClient meets all requirements, returning the message of the first one not
+// met. It has the same signature as the AllRequirements function of the assisted meets package.
+func Check(apiClient *clients.Settings, requirements ...Requirement) (bool, string) {
+	for _, requirement := range requirements {
+		met, message := requirement.Check(apiClient)
+		if !met {
+			return false, message
+		}
+	}
+
+	return true, ""
+}
+
+// SkipUnlessMet skips the current spec with the message of the first requirement the cluster of apiClient does not
+// meet. It must be called from a spec or a setup node.
+func SkipUnlessMet(apiClient *clients.Settings, requirements ...Requirement) {
+	if met, message := Check(apiClient, requirements...); !met {
+		ginkgo.Skip(message)
+	}
+}
+
+// Spec is a named set of requirements, such as those of a suite or a feature, used to build a pre-flight report.
+type Spec struct {
+	Name         string
+	Requirements []Requirement
+}
+
+// Result is whether the cluster meets the requirements of a Spec.
+type Result struct {
+	Spec   string
+	Met    bool
+	Reason string
+}
+
+// Evaluate checks the requirements of every spec against the cluster of apiClient and returns the results in the same
+// order.
+func Evaluate(apiClient *clients.Settings, specs ...Spec) []Result {
+	var results []Result
+
+	for _, spec := range specs {
+		met, reason := Check(apiClient, spec.Requirements...)
+		results = append(results, Result{Spec: spec.Name, Met: met, Reason: reason})
+	}
+
+	return results
+}
+
+// WriteReport writes results to writer as a table with a GO or NO-GO verdict and the reason for each spec.
+func WriteReport(writer io.Writer, results []Result) error {
+	table := tabwriter.NewWriter(writer, 0, 0, 2, ' ', 0)
+
+	_, err := fmt.Fprintln(table, "SPEC\tVERDICT\tREASON")
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		verdict := "GO"
+		if !result.Met {
+			verdict = "NO-GO"
+		}
+
+		_, err = fmt.Fprintf(table, "%s\t%s\t%s\n", result.Spec, verdict, result.Reason)
+		if err != nil {
+			return err
+		}
+	}
+
+	return table.Flush()
+}
+
+func joinDescriptions(requirements []Requirement, separator string) string {
+	var descriptions []string
+
+	for _, requirement := range requirements {
+		descriptions = append(descriptions, requirement.Description)
+	}
+
+	return "(" + strings.Join(descriptions, separator) + ")"
+}
@@ -0,0 +1,155 @@
+package requires
+
+import (
+	"bytes"
+	"testing"
+
+	configv1 "github.com/openshift/api/config/v1"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/fakecluster"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestRequirements(t *testing.T) {
+	apiClient := buildFakeCluster(t)
+
+	testCases := []struct {
+		name        string
+		requirement Requirement
+		met         bool
+		message     string
+	}{
+		{name: "sno", requirement: SNO(), met: true},
+		{
+			name:        "highly available",
+			requirement: Topology(configv1.HighlyAvailableTopologyMode),
+			message:     "control plane topology is SingleReplica, not HighlyAvailable",
+		},
+		{name: "version in range", requirement: OCPVersion(">=4.16, <4.21"), met: true},
+		{
+			name:        "version below",
+			requirement: OCPVersion(">=4.21"),
+			message:     "OCP version 4.20.0-rc.1 does not satisfy >=4.21",
+		},
+		{name: "invalid constraint", requirement: OCPVersion("four"), message: `invalid version constraint "four"`},
+		{name: "network type", requirement: NetworkType("OpenShiftSDN", "OVNKubernetes"), met: true},
+		{
+			name:        "other network type",
+			requirement: NetworkType("OpenShiftSDN"),
+			message:     "network type is OVNKubernetes, not OpenShiftSDN",
+		},
+		{name: "operator installed", requirement: OperatorVersion("openshift-ptp", "ptp-operator", ""), met: true},
+		{name: "operator version", requirement: OperatorVersion("openshift-ptp", "ptp-operator", ">=4.18"), met: true},
+		{
+			name:        "operator too old",
+			requirement: OperatorVersion("openshift-ptp", "ptp-operator", ">=4.21"),
+			message:     "does not satisfy >=4.21",
+		},
+		{
+			name:        "operator missing",
+			requirement: OperatorVersion("openshift-ptp", "sriov-network-operator", ""),
+			message:     "operator sriov-network-operator is not installed in namespace openshift-ptp",
+		},
+		{name: "nodes", requirement: Nodes("", 2), met: true},
+		{name: "labelled nodes", requirement: Nodes("ptp/slave", 1), met: true},
+		{name: "too few nodes", requirement: Nodes("ptp/slave", 2), message: `found 1 nodes matching "ptp/slave", need 2`},
+		{name: "nic vendor", requirement: NICVendor("openshift-sriov-network-operator", "15b3", "8086"), met: true},
+		{
+			name:        "other nic vendor",
+			requirement: NICVendor("openshift-sriov-network-operator", "14e4"),
+			message:     "no node has an SR-IOV NIC from vendor 14e4",
+		},
+		{name: "all", requirement: All(SNO(), Nodes("", 2)), met: true},
+		{name: "all unmet", requirement: All(SNO(), Nodes("", 3)), message: `found 2 nodes matching "", need 3`},
+		{name: "any", requirement: Any(NetworkType("OpenShiftSDN"), SNO()), met: true},
+		{
+			name:        "any unmet",
+			requirement: Any(NetworkType("OpenShiftSDN"), Nodes("", 3)),
+			message:     `network type is OVNKubernetes, not OpenShiftSDN; found 2 nodes matching "", need 3`,
+		},
+		{name: "not", requirement: Not(NetworkType("OpenShiftSDN")), met: true},
+		{name: "not unmet", requirement: Not(SNO()), message: `requirement "not SingleReplica topology" is not met`},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			met, message := testCase.requirement.Check(apiClient)
+			assert.Equal(t, testCase.met, met)
+
+			if testCase.met {
+				assert.Empty(t, message)
+			} else {
+				assert.Contains(t, message, testCase.message)
+			}
+		})
+	}
+}
+
+func TestCheckNilClient(t *testing.T) {
+	met, message := Check(nil, SNO())
+	assert.False(t, met)
+	assert.Equal(t, "SingleReplica topology: apiClient is nil", message)
+}
+
+func TestEvaluate(t *testing.T) {
+	apiClient := buildFakeCluster(t)
+
+	results := Evaluate(apiClient,
+		Spec{Name: "ptp", Requirements: []Requirement{OperatorVersion("openshift-ptp", "ptp-operator", "")}},
+		Spec{Name: "multinode", Requirements: []Requirement{Not(SNO())}})
+
+	assert.Equal(t, []Result{
+		{Spec: "ptp", Met: true},
+		{Spec: "multinode", Reason: `requirement "not SingleReplica topology" is not met`},
+	}, results)
+
+	var report bytes.Buffer
+
+	err := WriteReport(&report, results)
+	assert.NoError(t, err)
+	assert.Equal(t, "SPEC       VERDICT  REASON\n"+
+		"ptp        GO       \n"+
+		"multinode  NO-GO    requirement \"not SingleReplica topology\" is not met\n", report.String())
+}
+
+var (
+	infrastructureGVK = schema.FromAPIVersionAndKind("config.openshift.io/v1", "Infrastructure")
+	clusterVersionGVK = schema.FromAPIVersionAndKind("config.openshift.io/v1", "ClusterVersion")
+	networkGVK        = schema.FromAPIVersionAndKind("config.openshift.io/v1", "Network")
+	csvGVK            = schema.FromAPIVersionAndKind("operators.coreos.com/v1alpha1", "ClusterServiceVersion")
+	nodeGVK           = schema.FromAPIVersionAndKind("v1", "Node")
+	nodeStateGVK      = schema.FromAPIVersionAndKind("sriovnetwork.openshift.io/v1", "SriovNetworkNodeState")
+)
+
+func buildFakeCluster(t *testing.T) *clients.Settings {
+	t.Helper()
+
+	apiClient, err := fakecluster.New(
+		fakecluster.Object(infrastructureGVK, "", "cluster", map[string]any{
+			"status": map[string]any{"controlPlaneTopology": "SingleReplica"},
+		}),
+		fakecluster.Object(clusterVersionGVK, "", "version", map[string]any{
+			"status": map[string]any{"desired": map[string]any{"version": "4.20.0-rc.1"}},
+		}),
+		fakecluster.Object(networkGVK, "", "cluster", map[string]any{
+			"status": map[string]any{"networkType": "OVNKubernetes"},
+		}),
+		fakecluster.Object(csvGVK, "openshift-ptp", "ptp-operator.v4.20.0-202510101010", map[string]any{
+			"spec":   map[string]any{"version": "4.20.0-202510101010"},
+			"status": map[string]any{"phase": "Succeeded"},
+		}),
+		fakecluster.Object(nodeGVK, "", "master-0", map[string]any{}),
+		fakecluster.Object(nodeGVK, "", "worker-0", map[string]any{
+			"metadata": map[string]any{"name": "worker-0", "labels": map[string]any{"ptp/slave": ""}},
+		}),
+		fakecluster.Object(nodeStateGVK, "openshift-sriov-network-operator", "worker-0", map[string]any{
+			"status": map[string]any{"interfaces": []any{
+				map[string]any{"name": "ens1f0", "pciAddress": "0000:17:00.0", "vendor": "8086"},
+			}},
+		}),
+	)
+	assert.NoError(t, err)
+
+	return apiClient
+}
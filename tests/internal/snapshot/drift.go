@@ -0,0 +1,223 @@
+package snapshot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/klog/v2"
+)
+
+// Drift is how resources changed since a snapshot was taken.
+type Drift struct {
+	// Added are the objects created since the snapshot, usually leaked by a suite.
+	Added []*unstructured.Unstructured
+	// Removed are the objects of the snapshot which no longer exist.
+	Removed []*unstructured.Unstructured
+	// Modified are the objects of the snapshot whose content changed.
+	Modified []Modification
+}
+
+// Modification is an object whose content changed since the snapshot was taken.
+type Modification struct {
+	Saved   *unstructured.Unstructured
+	Current *unstructured.Unstructured
+	// Fields are the top-level fields which differ, such as spec or metadata.labels.
+	Fields []string
+}
+
+// Empty reports whether no resource drifted.
+func (drift *Drift) Empty() bool {
+	return len(drift.Added) == 0 && len(drift.Removed) == 0 && len(drift.Modified) == 0
+}
+
+// String returns one line per drifted object.
+func (drift *Drift) String() string {
+	var lines []string
+
+	for _, object := range drift.Added {
+		lines = append(lines, "added "+describe(object))
+	}
+
+	for _, object := range drift.Removed {
+		lines = append(lines, "removed "+describe(object))
+	}
+
+	for _, modification := range drift.Modified {
+		lines = append(lines, fmt.Sprintf("modified %s: %s",
+			describe(modification.Current), strings.Join(modification.Fields, ", ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// Restore reverts drift: added objects are deleted, removed objects are created again and modified objects get back
+// the content they had in the snapshot. Owned objects are only in drift when their Resource includes them, and are
+// created again with their owner references. It attempts every object and returns the errors joined.
+func Restore(apiClient *clients.Settings, drift *Drift) error {
+	if apiClient == nil {
+		return fmt.Errorf("cannot restore snapshot: apiClient is nil")
+	}
+
+	var errs []error
+
+	for _, object := range drift.Added {
+		klog.V(90).Infof("Deleting %s added since the snapshot", describe(object))
+
+		err := resourceClient(apiClient, object).Delete(context.TODO(), object.GetName(), metav1.DeleteOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to delete %s: %w", describe(object), err))
+		}
+	}
+
+	for _, object := range drift.Removed {
+		klog.V(90).Infof("Creating %s removed since the snapshot", describe(object))
+
+		_, err := resourceClient(apiClient, object).Create(context.TODO(), forCreate(object), metav1.CreateOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to create %s: %w", describe(object), err))
+		}
+	}
+
+	for _, modification := range drift.Modified {
+		klog.V(90).Infof("Restoring %s modified since the snapshot", describe(modification.Current))
+
+		_, err := resourceClient(apiClient, modification.Current).Update(
+			context.TODO(), restored(modification), metav1.UpdateOptions{})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to restore %s: %w", describe(modification.Current), err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Verify fails the current ginkgo node when the resources of snapshot drifted, such as in an AfterSuite. The drift is
+// added to the report of the node and, with restore, reverted before failing.
+func Verify(apiClient *clients.Settings, snapshot *Snapshot, restore bool) {
+	drift, err := snapshot.Diff(apiClient)
+	if err != nil {
+		ginkgo.Fail(fmt.Sprintf("failed to check cluster drift: %v", err))
+	}
+
+	if drift.Empty() {
+		return
+	}
+
+	ginkgo.AddReportEntry("cluster_drift", drift.String())
+
+	message := "cluster resources drifted from the snapshot:\n" + drift.String()
+
+	if restore {
+		err = Restore(apiClient, drift)
+		if err != nil {
+			ginkgo.Fail(fmt.Sprintf("%s\nfailed to restore them: %v", message, err))
+		}
+
+		message += "\nthey were restored"
+	}
+
+	ginkgo.Fail(message)
+}
+
+// compare returns the sorted top-level fields which differ between saved and current. Metadata other than the labels
+// and the status are ignored, since controllers update them continuously.
+func compare(saved, current *unstructured.Unstructured) []string {
+	var differences []string
+
+	for _, field := range contentFields(saved, current) {
+		if !reflect.DeepEqual(saved.Object[field], current.Object[field]) {
+			differences = append(differences, field)
+		}
+	}
+
+	if !reflect.DeepEqual(saved.GetLabels(), current.GetLabels()) {
+		differences = append(differences, "metadata.labels")
+	}
+
+	sort.Strings(differences)
+
+	return differences
+}
+
+// contentFields returns the top-level fields of either object holding their content.
+func contentFields(objects ...*unstructured.Unstructured) []string {
+	found := make(map[string]bool)
+
+	for _, object := range objects {
+		for field := range object.Object {
+			if field != "apiVersion" && field != "kind" && field != "metadata" && field != "status" {
+				found[field] = true
+			}
+		}
+	}
+
+	var fields []string
+
+	for field := range found {
+		fields = append(fields, field)
+	}
+
+	sort.Strings(fields)
+
+	return fields
+}
+
+// forCreate returns a copy of object without the fields set by the API server. The owner references are kept, so the
+// object is still garbage collected with its owner.
+func forCreate(object *unstructured.Unstructured) *unstructured.Unstructured {
+	created := &unstructured.Unstructured{Object: map[string]any{}}
+
+	for _, field := range contentFields(object) {
+		created.Object[field] = object.Object[field]
+	}
+
+	created.SetGroupVersionKind(object.GroupVersionKind())
+	created.SetNamespace(object.GetNamespace())
+	created.SetName(object.GetName())
+	created.SetLabels(object.GetLabels())
+	created.SetAnnotations(object.GetAnnotations())
+	created.SetOwnerReferences(object.GetOwnerReferences())
+
+	return created.DeepCopy()
+}
+
+// restored returns the current object of modification with the content of the saved one.
+func restored(modification Modification) *unstructured.Unstructured {
+	object := modification.Current.DeepCopy()
+
+	for _, field := range contentFields(object) {
+		delete(object.Object, field)
+	}
+
+	for _, field := range contentFields(modification.Saved) {
+		object.Object[field] = modification.Saved.DeepCopy().Object[field]
+	}
+
+	object.SetLabels(modification.Saved.GetLabels())
+
+	return object
+}
+
+func describe(object *unstructured.Unstructured) string {
+	if object.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", object.GetKind(), object.GetName())
+	}
+
+	return fmt.Sprintf("%s %s/%s", object.GetKind(), object.GetNamespace(), object.GetName())
+}
+
+func resourceClient(apiClient *clients.Settings, object *unstructured.Unstructured) dynamic.ResourceInterface {
+	gvr, _ := meta.UnsafeGuessKindToResource(object.GroupVersionKind())
+
+	return apiClient.Interface.Resource(gvr).Namespace(object.GetNamespace())
+}
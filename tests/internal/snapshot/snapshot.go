@@ -0,0 +1,154 @@
+// Package snapshot captures cluster-wide resources before a suite and detects the drift left behind after it, such as
+// leaked MachineConfigs or modified PtpConfigs. The drift can be reported as a failure of the suite and optionally
+// restored, generalizing the save and restore helpers of each suite to any resource.
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/klog/v2"
+)
+
+// Resource is a kind of resource captured by a snapshot. An empty Namespace captures the resources of every
+// namespace, or the cluster-scoped resources of the kind.
+type Resource struct {
+	GVK       schema.GroupVersionKind
+	Namespace string
+	// IncludeOwned captures the objects with owner references too. They are skipped by default, since their owner
+	// creates, updates and deletes them, such as the rendered MachineConfigs of a MachineConfigPool.
+	IncludeOwned bool
+}
+
+// Resources commonly mutated by suites.
+var (
+	MachineConfigs = Resource{GVK: schema.GroupVersionKind{
+		Group: "machineconfiguration.openshift.io", Version: "v1", Kind: "MachineConfig"}}
+	PerformanceProfiles = Resource{GVK: schema.GroupVersionKind{
+		Group: "performance.openshift.io", Version: "v2", Kind: "PerformanceProfile"}}
+	SriovNetworkNodePolicies = Resource{GVK: schema.GroupVersionKind{
+		Group: "sriovnetwork.openshift.io", Version: "v1", Kind: "SriovNetworkNodePolicy"}}
+	PtpConfigs = Resource{GVK: schema.GroupVersionKind{
+		Group: "ptp.openshift.io", Version: "v1", Kind: "PtpConfig"}}
+	// APIServers holds the TLS security profile of the API server.
+	APIServers = Resource{GVK: schema.GroupVersionKind{
+		Group: "config.openshift.io", Version: "v1", Kind: "APIServer"}}
+)
+
+// Snapshot is the state of a set of resources at a point in time.
+type Snapshot struct {
+	Resources []Resource
+	objects   map[string]*unstructured.Unstructured
+}
+
+// Take lists the given resources through the dynamic client of apiClient and returns their state.
+func Take(apiClient *clients.Settings, resources ...Resource) (*Snapshot, error) {
+	if apiClient == nil {
+		return nil, fmt.Errorf("cannot take snapshot: apiClient is nil")
+	}
+
+	klog.V(90).Infof("Taking snapshot of resources %v", resources)
+
+	objects, err := list(apiClient, resources)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Snapshot{Resources: resources, objects: objects}, nil
+}
+
+// Len returns the number of objects in the snapshot.
+func (snapshot *Snapshot) Len() int {
+	return len(snapshot.objects)
+}
+
+// Diff lists the resources of the snapshot again and returns how they drifted from it. Objects with owner references
+// are skipped unless their Resource includes them.
+func (snapshot *Snapshot) Diff(apiClient *clients.Settings) (*Drift, error) {
+	if apiClient == nil {
+		return nil, fmt.Errorf("cannot diff snapshot: apiClient is nil")
+	}
+
+	klog.V(90).Infof("Comparing resources %v with snapshot", snapshot.Resources)
+
+	current, err := list(apiClient, snapshot.Resources)
+	if err != nil {
+		return nil, err
+	}
+
+	drift := &Drift{}
+
+	for _, key := range sortedKeys(current) {
+		saved, found := snapshot.objects[key]
+		if !found {
+			drift.Added = append(drift.Added, current[key])
+
+			continue
+		}
+
+		if differences := compare(saved, current[key]); len(differences) > 0 {
+			drift.Modified = append(drift.Modified, Modification{
+				Saved: saved, Current: current[key], Fields: differences})
+		}
+	}
+
+	for _, key := range sortedKeys(snapshot.objects) {
+		if _, found := current[key]; !found {
+			drift.Removed = append(drift.Removed, snapshot.objects[key])
+		}
+	}
+
+	return drift, nil
+}
+
+// list returns the objects of resources keyed by objectKey, skipping owned objects unless the resource includes them.
+func list(apiClient *clients.Settings, resources []Resource) (map[string]*unstructured.Unstructured, error) {
+	objects := make(map[string]*unstructured.Unstructured)
+
+	for _, resource := range resources {
+		gvr, _ := meta.UnsafeGuessKindToResource(resource.GVK)
+
+		objectList, err := apiClient.Interface.Resource(gvr).Namespace(resource.Namespace).List(
+			context.TODO(), metav1.ListOptions{})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list %s: %w", gvr.String(), err)
+		}
+
+		for index := range objectList.Items {
+			object := &objectList.Items[index]
+			object.SetGroupVersionKind(resource.GVK)
+
+			if len(object.GetOwnerReferences()) > 0 && !resource.IncludeOwned {
+				klog.V(90).Infof("Skipping %s owned by %s", objectKey(object), object.GetOwnerReferences()[0].Name)
+
+				continue
+			}
+
+			objects[objectKey(object)] = object
+		}
+	}
+
+	return objects, nil
+}
+
+func objectKey(object *unstructured.Unstructured) string {
+	return fmt.Sprintf("%s/%s/%s", object.GroupVersionKind().GroupKind(), object.GetNamespace(), object.GetName())
+}
+
+func sortedKeys(objects map[string]*unstructured.Unstructured) []string {
+	keys := make([]string, 0, len(objects))
+
+	for key := range objects {
+		keys = append(keys, key)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
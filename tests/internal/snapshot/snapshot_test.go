@@ -0,0 +1,149 @@
+package snapshot
+
+import (
+	"context"
+	"testing"
+
+	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/fakecluster"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+)
+
+func TestDiffAndRestore(t *testing.T) {
+	apiClient := buildFakeCluster(t)
+
+	snapshot, err := Take(apiClient, MachineConfigs, PtpConfigs)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, snapshot.Len())
+
+	drift, err := snapshot.Diff(apiClient)
+	assert.NoError(t, err)
+	assert.True(t, drift.Empty())
+
+	leaked := fakecluster.Object(MachineConfigs.GVK, "", "99-worker-leaked", map[string]any{"spec": map[string]any{}})
+	_, err = dynamicClient(apiClient, MachineConfigs.GVK, "").Create(context.TODO(), leaked, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	ptpConfigs := dynamicClient(apiClient, PtpConfigs.GVK, "openshift-ptp")
+
+	err = ptpConfigs.Delete(context.TODO(), "boundary-clock", metav1.DeleteOptions{})
+	assert.NoError(t, err)
+
+	grandmaster, err := ptpConfigs.Get(context.TODO(), "grandmaster", metav1.GetOptions{})
+	assert.NoError(t, err)
+
+	grandmaster.Object["spec"] = map[string]any{"profile": []any{map[string]any{"name": "changed"}}}
+	grandmaster.SetLabels(map[string]string{"changed": "true"})
+	grandmaster.Object["status"] = map[string]any{"ignored": "true"}
+
+	_, err = ptpConfigs.Update(context.TODO(), grandmaster, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+
+	drift, err = snapshot.Diff(apiClient)
+	assert.NoError(t, err)
+	assert.False(t, drift.Empty())
+	assert.Equal(t, "added MachineConfig 99-worker-leaked\n"+
+		"removed PtpConfig openshift-ptp/boundary-clock\n"+
+		"modified PtpConfig openshift-ptp/grandmaster: metadata.labels, spec", drift.String())
+
+	err = Restore(apiClient, drift)
+	assert.NoError(t, err)
+
+	drift, err = snapshot.Diff(apiClient)
+	assert.NoError(t, err)
+	assert.True(t, drift.Empty(), drift.String())
+}
+
+func TestOwnedObjects(t *testing.T) {
+	apiClient := buildFakeCluster(t)
+	machineConfigs := dynamicClient(apiClient, MachineConfigs.GVK, "")
+
+	_, err := machineConfigs.Create(context.TODO(), newRenderedMachineConfig("rendered-worker-a"), metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	snapshot, err := Take(apiClient, MachineConfigs)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, snapshot.Len())
+
+	ownedSnapshot, err := Take(apiClient, Resource{GVK: MachineConfigs.GVK, IncludeOwned: true})
+	assert.NoError(t, err)
+	assert.Equal(t, 2, ownedSnapshot.Len())
+
+	_, err = machineConfigs.Create(context.TODO(), newRenderedMachineConfig("rendered-worker-b"), metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	err = machineConfigs.Delete(context.TODO(), "rendered-worker-a", metav1.DeleteOptions{})
+	assert.NoError(t, err)
+
+	drift, err := snapshot.Diff(apiClient)
+	assert.NoError(t, err)
+	assert.True(t, drift.Empty(), drift.String())
+
+	drift, err = ownedSnapshot.Diff(apiClient)
+	assert.NoError(t, err)
+	assert.Equal(t, "added MachineConfig rendered-worker-b\nremoved MachineConfig rendered-worker-a", drift.String())
+
+	err = Restore(apiClient, drift)
+	assert.NoError(t, err)
+
+	restored, err := machineConfigs.Get(context.TODO(), "rendered-worker-a", metav1.GetOptions{})
+	assert.NoError(t, err)
+	assert.Equal(t, "worker", restored.GetOwnerReferences()[0].Name)
+
+	drift, err = ownedSnapshot.Diff(apiClient)
+	assert.NoError(t, err)
+	assert.True(t, drift.Empty(), drift.String())
+}
+
+func TestNilClient(t *testing.T) {
+	_, err := Take(nil, PtpConfigs)
+	assert.Error(t, err)
+
+	err = Restore(nil, &Drift{})
+	assert.Error(t, err)
+}
+
+func buildFakeCluster(t *testing.T) *clients.Settings {
+	t.Helper()
+
+	apiClient, err := fakecluster.New(
+		fakecluster.Object(MachineConfigs.GVK, "", "99-worker-ssh", map[string]any{
+			"spec": map[string]any{"config": map[string]any{"ignition": map[string]any{"version": "3.2.0"}}},
+		}),
+		fakecluster.Object(PtpConfigs.GVK, "openshift-ptp", "grandmaster", map[string]any{
+			"spec": map[string]any{"profile": []any{map[string]any{"name": "gm"}}},
+		}),
+		fakecluster.Object(PtpConfigs.GVK, "openshift-ptp", "boundary-clock", map[string]any{
+			"metadata": map[string]any{"labels": map[string]any{"ptp": "bc"}},
+			"spec":     map[string]any{"profile": []any{map[string]any{"name": "bc"}}},
+		}),
+	)
+	assert.NoError(t, err)
+
+	return apiClient
+}
+
+func dynamicClient(
+	apiClient *clients.Settings, gvk schema.GroupVersionKind, namespace string) dynamic.ResourceInterface {
+	gvr, _ := meta.UnsafeGuessKindToResource(gvk)
+
+	return apiClient.Interface.Resource(gvr).Namespace(namespace)
+}
+
+// newRenderedMachineConfig returns a MachineConfig owned by the worker MachineConfigPool, like the ones it renders.
+func newRenderedMachineConfig(name string) *unstructured.Unstructured {
+	object := fakecluster.Object(MachineConfigs.GVK, "", name, map[string]any{"spec": map[string]any{}})
+	object.SetOwnerReferences([]metav1.OwnerReference{{
+		APIVersion: "machineconfiguration.openshift.io/v1",
+		Kind:       "MachineConfigPool",
+		Name:       "worker",
+		UID:        "00000000-0000-0000-0000-000000000001",
+	}})
+
+	return object
+}
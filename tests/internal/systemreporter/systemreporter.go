@@ -4,13 +4,14 @@ import (
 	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
 	re "regexp"
-	"strings"
 
 	"github.com/onsi/ginkgo/v2/types"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/cluster"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/internal/inittools"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 	"golang.org/x/crypto/ssh"
 	"k8s.io/klog/v2"
 )
@@ -37,40 +38,65 @@ func ReportIfFailedFromClient(
 			return
 		}
 
-		dumpDir := GeneralConfig.GetDumpFailedTestReportLocation(testSuite)
-
-		tcReportFolderName := strings.ReplaceAll(report.FullText(), " ", "_")
-
-		systemFolder := fmt.Sprintf("%s/%s/system", dumpDir, tcReportFolderName)
-
-		err := os.MkdirAll(systemFolder, 0755)
-		if err != nil {
-			klog.Errorf("failed creating dir for system info: %s", err)
-
+		bundle, systemFolder, ok := openSystemFolder(report, testSuite)
+		if !ok {
 			return
 		}
 
 		GatherInfoThroughKubeClient(commands, systemFolder, apiClient)
+		bundle.AddNodeCommands(reporter.TransportKubeClient, commands, nil)
+
+		closeBundle(bundle)
 	}
 }
 
 // ReportIfFailedFromNodeList dumps the requested command output from specified nodes through SSH if test case fails.
 func ReportIfFailedFromNodeList(report types.SpecReport, testSuite string, commands []string, nodes []string) {
 	if types.SpecStateFailureStates.Is(report.State) {
-		dumpDir := GeneralConfig.GetDumpFailedTestReportLocation(testSuite)
+		bundle, systemFolder, ok := openSystemFolder(report, testSuite)
+		if !ok {
+			return
+		}
 
-		tcReportFolderName := strings.ReplaceAll(report.FullText(), " ", "_")
+		GatherInfoThroughSSH(commands, systemFolder, GeneralConfig.SSHKeyPath, nodes)
+		bundle.AddNodeCommands(reporter.TransportSSH, commands, nodes)
 
-		systemFolder := fmt.Sprintf("%s/%s/system", dumpDir, tcReportFolderName)
+		closeBundle(bundle)
+	}
+}
 
-		err := os.MkdirAll(systemFolder, 0755)
-		if err != nil {
-			klog.Errorf("failed to create directory for storing system info %s", err)
+// openSystemFolder opens the failure-dump bundle of the spec and creates the folder for system info inside it. It
+// returns false when dumping failed tests is disabled or the folder cannot be created.
+func openSystemFolder(report types.SpecReport, testSuite string) (*reporter.Bundle, string, bool) {
+	if GeneralConfig.GetDumpFailedTestReportLocation(testSuite) == "" {
+		return nil, "", false
+	}
 
-			return
-		}
+	bundle, err := reporter.OpenBundle(GeneralConfig, testSuite, report)
+	if err != nil {
+		klog.Errorf("failed to open failure-dump bundle: %s", err)
 
-		GatherInfoThroughSSH(commands, systemFolder, GeneralConfig.SSHKeyPath, nodes)
+		return nil, "", false
+	}
+
+	systemFolder := filepath.Join(bundle.Dir(), "system")
+
+	err = os.MkdirAll(systemFolder, 0755)
+	if err != nil {
+		klog.Errorf("failed creating dir for system info: %s", err)
+
+		closeBundle(bundle)
+
+		return nil, "", false
+	}
+
+	return bundle, systemFolder, true
+}
+
+func closeBundle(bundle *reporter.Bundle) {
+	err := bundle.Close()
+	if err != nil {
+		klog.Errorf("failed to close failure-dump bundle %s: %s", bundle.Path(), err)
 	}
 }
 
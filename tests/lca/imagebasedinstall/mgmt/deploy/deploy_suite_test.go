@@ -36,6 +36,7 @@ var _ = BeforeSuite(func() {
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(
 		report, MGMTConfig.GetReportPath(), MGMTConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
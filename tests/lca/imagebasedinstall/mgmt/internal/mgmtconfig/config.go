@@ -53,7 +53,7 @@ type Cluster struct {
 			} `yaml:"network"`
 			BMC struct {
 				User       string `yaml:"user"`
-				Password   string `yaml:"pass"`
+				Password   string `yaml:"pass" eco_secret:"true"`
 				URLv4      string `yaml:"urlv4"`
 				URLv6      string `yaml:"urlv6"`
 				MACAddress string `yaml:"mac_address"`
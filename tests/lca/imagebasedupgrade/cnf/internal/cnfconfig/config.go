@@ -1,14 +1,13 @@
 package cnfconfig
 
 import (
-	"os"
 	"path/filepath"
 	"runtime"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/lca/imagebasedupgrade/cnf/internal/cnfparams"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/lca/imagebasedupgrade/internal/ibuconfig"
-	"gopkg.in/yaml.v2"
 	"k8s.io/klog/v2"
 )
 
@@ -50,19 +49,14 @@ func NewCNFConfig() *CNFConfig {
 	baseDir := filepath.Dir(filename)
 	configFile := filepath.Join(baseDir, PathToDefaultIbuCnfParamsFile)
 
-	err := readFile(&cnfConfig, configFile)
+	sources, err := config.LoadLayers(&cnfConfig, configFile, "")
 	if err != nil {
-		klog.V(cnfparams.CNFLogLevel).Infof("Error reading config file %s", configFile)
+		klog.V(cnfparams.CNFLogLevel).Infof("Error loading config layers from %s: %v", configFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&cnfConfig)
-	if err != nil {
-		klog.V(cnfparams.CNFLogLevel).Infof("Error reading environment variables")
-
-		return nil
-	}
+	cnfConfig.RecordSources(sources)
 
 	err = envconfig.Process("eco_lca_ibu_cnf_", &cnfConfig)
 	if err != nil {
@@ -71,26 +65,3 @@ func NewCNFConfig() *CNFConfig {
 
 	return &cnfConfig
 }
-
-func readFile(cnfConfig *CNFConfig, configFile string) error {
-	openedConfigFile, err := os.Open(configFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedConfigFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedConfigFile)
-
-	err = decoder.Decode(&cnfConfig)
-
-	return err
-}
-
-func readEnv(cnfConfig *CNFConfig) error {
-	err := envconfig.Process("", cnfConfig)
-
-	return err
-}
@@ -2,6 +2,7 @@ package cnfinittools
 
 import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/lca/imagebasedupgrade/cnf/internal/cnfconfig"
 )
 
@@ -26,4 +27,13 @@ func init() {
 	if CNFConfig.TargetSNOKubeConfig != "" {
 		TargetSNOAPIClient = clients.New(CNFConfig.TargetSNOKubeConfig)
 	}
+
+	if TargetHubAPIClient != nil {
+		clusters.RegisterClient(clusters.Hub, CNFConfig.TargetHubKubeConfig, TargetHubAPIClient)
+	}
+
+	// The target SNO is managed by the hub, so it is dumped as spoke_<suite file>.
+	if TargetSNOAPIClient != nil {
+		clusters.RegisterClient(clusters.Target, CNFConfig.TargetSNOKubeConfig, TargetSNOAPIClient).SetDumpPrefix("spoke")
+	}
 }
@@ -1,8 +1,6 @@
 package upgrade_test
 
 import (
-	"fmt"
-	"path"
 	"runtime"
 	"testing"
 
@@ -11,6 +9,7 @@ import (
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/lca/imagebasedupgrade/cnf/internal/cnfinittools"
 
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/reportxml"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/lca/imagebasedupgrade/cnf/upgrade-talm/internal/tsparams"
 	_ "github.com/rh-ecosystem-edge/eco-gotests/tests/lca/imagebasedupgrade/cnf/upgrade-talm/tests"
@@ -27,6 +26,9 @@ func TestUpgrade(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	clusters.SetReporter(clusters.Hub, tsparams.ReporterHubNamespacesToDump, tsparams.ReporterHubCRsToDump)
+	clusters.SetReporter(clusters.Target, tsparams.ReporterSpokeNamespacesToDump, tsparams.ReporterSpokeCRsToDump)
+
 	// should have top level check to skip all tests in case test env vars unavailable.
 	By("Checking if target hub cluster has valid apiClient")
 
@@ -44,31 +46,9 @@ var _ = BeforeSuite(func() {
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(
 		report, CNFConfig.GetReportPath(), CNFConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
-	var (
-		currentDir, currentFilename = path.Split(currentFile)
-		hubReportPath               = fmt.Sprintf("%shub_%s", currentDir, currentFilename)
-		spokeReportPath             = fmt.Sprintf("%sspoke_%s", currentDir, currentFilename)
-		report                      = CurrentSpecReport()
-	)
-
-	if TargetSNOAPIClient != nil {
-		reporter.ReportIfFailedOnCluster(
-			CNFConfig.TargetSNOKubeConfig,
-			report,
-			spokeReportPath,
-			tsparams.ReporterSpokeNamespacesToDump,
-			tsparams.ReporterSpokeCRsToDump)
-	}
-
-	if TargetHubAPIClient != nil {
-		reporter.ReportIfFailedOnCluster(
-			CNFConfig.TargetHubKubeConfig,
-			report,
-			hubReportPath,
-			tsparams.ReporterHubNamespacesToDump,
-			tsparams.ReporterHubCRsToDump)
-	}
+	reporter.ReportIfFailedOnClusters(CurrentSpecReport(), currentFile)
 })
@@ -85,6 +85,7 @@ var _ = AfterEach(func() {
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(
 		report, MGMTConfig.GetReportPath(), MGMTConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
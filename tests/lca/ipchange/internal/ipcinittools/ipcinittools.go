@@ -2,6 +2,7 @@ package ipcinittools
 
 import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/inittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/lca/ipchange/internal/ipcconfig"
 )
@@ -20,4 +21,9 @@ var (
 func init() {
 	IPCConfig = ipcconfig.NewIPCConfig()
 	APIClient = inittools.APIClient
+
+	// The target SNO is the only cluster of the suite, so it is dumped to the suite file itself.
+	if APIClient != nil {
+		clusters.RegisterClient(clusters.Target, APIClient.KubeconfigPath, APIClient).SetDumpPrefix("")
+	}
 }
@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/reportxml"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/lca/ipchange/internal/ipcinittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/lca/ipchange/internal/tsparams"
@@ -31,6 +32,8 @@ func TestIPChange(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	clusters.SetReporter(clusters.Target, tsparams.ReporterNamespacesToDump, tsparams.ReporterCRDsToDump)
+
 	By("Checking if API client is valid")
 
 	if APIClient == nil {
@@ -41,13 +44,9 @@ var _ = BeforeSuite(func() {
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(
 		report, IPCConfig.GetReportPath(), IPCConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
-	reporter.ReportIfFailedOnCluster(
-		APIClient.KubeconfigPath,
-		CurrentSpecReport(),
-		currentFile,
-		tsparams.ReporterNamespacesToDump,
-		tsparams.ReporterCRDsToDump)
+	reporter.ReportIfFailedOnClusters(CurrentSpecReport(), currentFile)
 })
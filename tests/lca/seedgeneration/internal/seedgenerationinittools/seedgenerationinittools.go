@@ -2,6 +2,7 @@ package seedgenerationinittools
 
 import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/lca/seedgeneration/internal/seedgenerationconfig"
 )
 
@@ -26,4 +27,8 @@ func init() {
 	}
 
 	TargetSNOAPIClient = SeedGenerationConfig.GetTargetSNOAPIClient()
+
+	// The target SNO is the only cluster of the suite, so it is dumped to the suite file itself.
+	clusters.RegisterClient(
+		clusters.Target, SeedGenerationConfig.TargetSNOKubeConfig, TargetSNOAPIClient).SetDumpPrefix("")
 }
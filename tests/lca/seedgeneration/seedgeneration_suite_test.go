@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/reportxml"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/clusters"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/reporter"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/lca/seedgeneration/internal/seedgenerationinittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/lca/seedgeneration/internal/tsparams"
@@ -31,6 +32,8 @@ func TestSeedGeneration(t *testing.T) {
 }
 
 var _ = BeforeSuite(func() {
+	clusters.SetReporter(clusters.Target, tsparams.ReporterNamespacesToDump, tsparams.ReporterCRDsToDump)
+
 	By("Checking if target sno cluster has valid apiClient")
 
 	if TargetSNOAPIClient == nil {
@@ -45,13 +48,9 @@ var _ = ReportAfterSuite("", func(report Report) {
 
 	reportxml.Create(
 		report, SeedGenerationConfig.GetReportPath(), SeedGenerationConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
 
 var _ = JustAfterEach(func() {
-	reporter.ReportIfFailedOnCluster(
-		SeedGenerationConfig.TargetSNOKubeConfig,
-		CurrentSpecReport(),
-		currentFile,
-		tsparams.ReporterNamespacesToDump,
-		tsparams.ReporterCRDsToDump)
+	reporter.ReportIfFailedOnClusters(CurrentSpecReport(), currentFile)
 })
@@ -2,13 +2,10 @@ package ocpconfig
 
 import (
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 
-	"github.com/kelseyhightower/envconfig"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -39,48 +36,14 @@ func NewOcpConfig() *OcpConfig {
 	baseDir := filepath.Dir(filename)
 	confFile := filepath.Join(baseDir, PathToDefaultOcpParamsFile)
 
-	err := readFile(&ocpConf, confFile)
+	sources, err := config.LoadLayers(&ocpConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&ocpConf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	ocpConf.RecordSources(sources)
 
 	return &ocpConf
 }
-
-func readFile(ocpConfig *OcpConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(&ocpConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(ocpConfig *OcpConfig) error {
-	err := envconfig.Process("", ocpConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
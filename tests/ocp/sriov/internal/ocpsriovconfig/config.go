@@ -2,19 +2,15 @@
 package ocpsriovconfig
 
 import (
-	"errors"
 	"fmt"
-	"io"
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 	"strconv"
 	"strings"
 
-	"github.com/kelseyhightower/envconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/ocp/internal/ocpconfig"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -46,7 +42,7 @@ type SriovOcpConfig struct {
 	DevicesEnv                  string         `envconfig:"ECO_OCP_SRIOV_DEVICES"`
 	VFNum                       int            `yaml:"vf_num" envconfig:"ECO_OCP_SRIOV_VF_NUM"`
 	SwitchUser                  string         `envconfig:"ECO_OCP_SRIOV_SWITCH_USER"`
-	SwitchPass                  string         `envconfig:"ECO_OCP_SRIOV_SWITCH_PASS"`
+	SwitchPass                  string         `envconfig:"ECO_OCP_SRIOV_SWITCH_PASS" eco_secret:"true"`
 	SwitchIP                    string         `envconfig:"ECO_OCP_SRIOV_SWITCH_IP"`
 	SwitchInterfacesEnv         string         `envconfig:"ECO_OCP_SRIOV_SWITCH_INTERFACES"`
 	VLAN                        string         `envconfig:"ECO_OCP_SRIOV_VLAN"`
@@ -76,19 +72,14 @@ func NewSriovOcpConfig() *SriovOcpConfig {
 	baseDir := filepath.Dir(filename)
 	confFile := filepath.Join(baseDir, PathToDefaultOcpSriovParamsFile)
 
-	err := readFile(&sriovOcpConf, confFile)
+	sources, err := config.LoadLayers(&sriovOcpConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s: %v", confFile, err)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&sriovOcpConf)
-	if err != nil {
-		log.Printf("Error to read environment variables: %v", err)
-
-		return nil
-	}
+	sriovOcpConf.RecordSources(sources)
 
 	return &sriovOcpConf
 }
@@ -261,32 +252,3 @@ func (sriovOcpConfig *SriovOcpConfig) GetSwitchInterfaces() ([]string, error) {
 
 	return envValue, nil
 }
-
-func readFile(sriovOcpConfig *SriovOcpConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(sriovOcpConfig)
-	if err != nil && !errors.Is(err, io.EOF) {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(sriovOcpConfig *SriovOcpConfig) error {
-	err := envconfig.Process("", sriovOcpConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
@@ -1,15 +1,13 @@
 // Package tsparams provides test suite parameters and constants for OCP SR-IOV tests.
 package tsparams
 
-import "time"
+import (
+	"time"
+
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/parallel"
+)
 
 const (
-	// TestNamespaceName sriov namespace where all test cases are performed.
-	TestNamespaceName = "sriov-tests"
-	// TestNamespaceName1 sriov namespace where all test cases are performed.
-	TestNamespaceName1 = "sriov-tests-1"
-	// TestNamespaceName2 sriov namespace where all test cases are performed.
-	TestNamespaceName2 = "sriov-tests-2"
 	// LabelSuite represents sriov label that can be used for test cases selection.
 	LabelSuite = "ocpsriov"
 	// LabelOcpSriovReinstallation represents an SR-IOV operator reinstallation label
@@ -97,3 +95,13 @@ const (
 	// TestResourceLabelValue is the label value for test-created resources.
 	TestResourceLabelValue = "ocp-sriov"
 )
+
+// Namespaces are suffixed per ginkgo process when running in parallel.
+var (
+	// TestNamespaceName sriov namespace where all test cases are performed.
+	TestNamespaceName = parallel.Namespace("sriov-tests")
+	// TestNamespaceName1 sriov namespace where all test cases are performed.
+	TestNamespaceName1 = parallel.Namespace("sriov-tests-1")
+	// TestNamespaceName2 sriov namespace where all test cases are performed.
+	TestNamespaceName2 = parallel.Namespace("sriov-tests-2")
+)
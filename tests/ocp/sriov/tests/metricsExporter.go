@@ -9,6 +9,7 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/netswitch"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/perfprofile"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/sriovoperator"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/ocp/sriov/internal/ocpsriovinittools"
@@ -595,15 +596,15 @@ func clearClientServerMacTableFromSwitch() {
 	switchCredentials, err := sriovocpenv.NewSwitchCredentials()
 	Expect(err).ToNot(HaveOccurred(), "Failed to get switch credentials")
 
-	jnpr, err := sriovocpenv.NewJunosSession(
+	jnpr, err := netswitch.NewJunos(
 		switchCredentials.SwitchIP, switchCredentials.User, switchCredentials.Password)
 	Expect(err).ToNot(HaveOccurred(), "Failed to create new Junos Session")
 
 	defer jnpr.Close()
 
-	_, err = jnpr.RunCommand(fmt.Sprintf("clear ethernet-switching table %s", tsparams.TestPodServerMAC))
+	err = jnpr.ClearMACAddress(tsparams.TestPodServerMAC)
 	Expect(err).ToNot(HaveOccurred(), fmt.Sprintf("Failed to clear mac table for %s", tsparams.TestPodServerMAC))
 
-	_, err = jnpr.RunCommand(fmt.Sprintf("clear ethernet-switching table %s", tsparams.TestPodClientMAC))
+	err = jnpr.ClearMACAddress(tsparams.TestPodClientMAC)
 	Expect(err).NotTo(HaveOccurred(), fmt.Sprintf("Failed to clear mac table for %s", tsparams.TestPodClientMAC))
 }
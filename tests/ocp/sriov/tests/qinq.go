@@ -16,6 +16,7 @@ import (
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nmstate"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/reportxml"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/cluster"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/netswitch"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/perfprofile"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/sriovoperator"
 	multus "gopkg.in/k8snetworkplumbingwg/multus-cni.v4/pkg/types"
@@ -958,7 +959,7 @@ func validateDot1Encapsulation(fileOutput, dot1X string) error {
 }
 
 func enableDot1ADonSwitchInterfaces(credentials *sriovocpenv.SwitchCredentials, switchInterfaces []string) error {
-	jnpr, err := sriovocpenv.NewJunosSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	if err != nil {
 		return err
 	}
@@ -976,7 +977,7 @@ func enableDot1ADonSwitchInterfaces(credentials *sriovocpenv.SwitchCredentials,
 				switchInterface),
 		}
 
-		err = jnpr.Config(commands)
+		err = jnpr.Config(commands...)
 		if err != nil {
 			return err
 		}
@@ -986,7 +987,7 @@ func enableDot1ADonSwitchInterfaces(credentials *sriovocpenv.SwitchCredentials,
 }
 
 func enableQinQTrunkOnSwitch(credentials *sriovocpenv.SwitchCredentials, switchInterfaces []string) error {
-	jnpr, err := sriovocpenv.NewJunosSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	if err != nil {
 		return err
 	}
@@ -1002,7 +1003,7 @@ func enableQinQTrunkOnSwitch(credentials *sriovocpenv.SwitchCredentials, switchI
 				switchInterface),
 		}
 
-		err = jnpr.Config(commands)
+		err = jnpr.Config(commands...)
 		if err != nil {
 			return err
 		}
@@ -1012,7 +1013,7 @@ func enableQinQTrunkOnSwitch(credentials *sriovocpenv.SwitchCredentials, switchI
 }
 
 func removeSwitchTPID(credentials *sriovocpenv.SwitchCredentials, switchInterfaces []string) error {
-	jnpr, err := sriovocpenv.NewJunosSession(credentials.SwitchIP, credentials.User, credentials.Password)
+	jnpr, err := netswitch.NewJunos(credentials.SwitchIP, credentials.User, credentials.Password)
 	if err != nil {
 		return err
 	}
@@ -1024,7 +1025,7 @@ func removeSwitchTPID(credentials *sriovocpenv.SwitchCredentials, switchInterfac
 				switchInterface),
 		}
 
-		err = jnpr.Config(commands)
+		err = jnpr.Config(commands...)
 		if err != nil {
 			return err
 		}
@@ -1034,7 +1035,7 @@ func removeSwitchTPID(credentials *sriovocpenv.SwitchCredentials, switchInterfac
 }
 
 func disableQinQOnSwitch(switchCredentials *sriovocpenv.SwitchCredentials, switchInterfaces []string) error {
-	jnpr, err := sriovocpenv.NewJunosSession(switchCredentials.SwitchIP, switchCredentials.User,
+	jnpr, err := netswitch.NewJunos(switchCredentials.SwitchIP, switchCredentials.User,
 		switchCredentials.Password)
 	if err != nil {
 		return err
@@ -1059,7 +1060,7 @@ func disableQinQOnSwitch(switchCredentials *sriovocpenv.SwitchCredentials, switc
 			fmt.Sprintf("set interfaces %s unit 0 family ethernet-switching vlan members %s", switchInterface, vlanName),
 		}
 
-		err = jnpr.Config(commands)
+		err = jnpr.Config(commands...)
 		if err != nil {
 			return err
 		}
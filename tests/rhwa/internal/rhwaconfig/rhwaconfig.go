@@ -2,13 +2,10 @@ package rhwaconfig
 
 import (
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 
-	"github.com/kelseyhightower/envconfig"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -33,48 +30,14 @@ func NewRHWAConfig() *RHWAConfig {
 	baseDir := filepath.Dir(filename)
 	confFile := filepath.Join(baseDir, PathToDefaultRhwaParamsFile)
 
-	err := readFile(&rhwaConf, confFile)
+	sources, err := config.LoadLayers(&rhwaConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&rhwaConf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	rhwaConf.RecordSources(sources)
 
 	return &rhwaConf
 }
-
-func readFile(rhwaConfig *RHWAConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(&rhwaConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(rhwaConfig *RHWAConfig) error {
-	err := envconfig.Process("", rhwaConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
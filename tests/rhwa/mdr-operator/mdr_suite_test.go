@@ -31,4 +31,5 @@ var _ = JustAfterEach(func() {
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(
 		report, RHWAConfig.GetReportPath(), RHWAConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })
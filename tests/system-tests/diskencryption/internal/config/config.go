@@ -26,7 +26,7 @@ type DiskEncrptionConfig struct {
 	// BMCClient provides access to the BMC. Nil when BMC configs are not provided.
 	Spoke1BMC   *bmc.BMC
 	BMCUsername string        `yaml:"BMCUsername" envconfig:"ECO_SYSTEM_TESTS_BMC_USERNAME"`
-	BMCPassword string        `yaml:"BMCPassword" envconfig:"ECO_SYSTEM_TESTS_BMC_PASSWORD"`
+	BMCPassword string        `yaml:"BMCPassword" envconfig:"ECO_SYSTEM_TESTS_BMC_PASSWORD" eco_secret:"true"`
 	BMCHosts    []string      `yaml:"BMCHosts" envconfig:"ECO_SYSTEM_TESTS_BMC_HOSTS"`
 	BMCTimeout  time.Duration `yaml:"BMCTimeout" envconfig:"ECO_SYSTEM_TESTS_BMC_TIMEOUT"`
 }
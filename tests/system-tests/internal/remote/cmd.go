@@ -9,6 +9,7 @@ import (
 
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/execrecorder"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/system-tests/internal/systemtestsinittools"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/fields"
@@ -31,7 +32,7 @@ func ExecuteOnNodeWithDebugPod(cmdToExec []string, nodeName string) (string, err
 
 	klog.V(90).Infof("Exec cmd %v on pod %s", cmdToExec, mcPodList[0].Definition.Name)
 
-	buf, err := mcPodList[0].ExecCommand(cmdToExec)
+	buf, err := execrecorder.ExecCommand(APIClient, mcPodList[0], cmdToExec)
 	if err != nil {
 		return "", fmt.Errorf("%w\n%s", err, buf.String())
 	}
@@ -53,7 +54,7 @@ func ExecuteOnNodeWithDebugPodWithTimeout(cmdToExec []string, nodeName string, t
 
 	klog.V(90).Infof("Exec cmd %v on pod %s", cmdToExec, mcPodList[0].Definition.Name)
 
-	buf, err := mcPodList[0].ExecCommandWithTimeout(cmdToExec, timeout)
+	buf, err := execrecorder.ExecCommandWithTimeout(APIClient, mcPodList[0], cmdToExec, timeout)
 	if err != nil {
 		klog.V(90).Infof("Failed to execute command on node %s: %v",
 			nodeName, err)
@@ -124,7 +125,7 @@ func ExecuteOnNodeWithPrivilegedDebugPod(apiClient *clients.Settings,
 		return "", err
 	}
 
-	buf, err := debugPod.ExecCommand(cmd)
+	buf, err := execrecorder.ExecCommand(apiClient, debugPod, cmd)
 	if err != nil {
 		return "", err
 	}
@@ -168,7 +169,11 @@ func ExecCmdOnHost(remoteHostname, remoteHostUsername, remoteHostPass, cmd strin
 	ss, _ := scpClient.NewSession()
 	defer ss.Close()
 
+	execution := execrecorder.Start(execrecorder.Target{Node: remoteHostname}, cmd)
+
 	out, err := ss.CombinedOutput(cmd)
+	execution.Finish(string(out), "", err)
+
 	if err != nil {
 		klog.V(100).Infof("Failed to run cmd %s on the host %s due to: %v",
 			cmd, remoteHostname, err)
@@ -2,13 +2,11 @@ package ipsecconfig
 
 import (
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 
-	"github.com/kelseyhightower/envconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/system-tests/internal/systemtestsconfig"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -35,7 +33,7 @@ type IpsecConfig struct {
 	NodePort            string `yaml:"node_port" envconfig:"ECO_IPSEC_NODE_PORT"`
 	NodePortIncrement   string `yaml:"node_port_increment" envconfig:"ECO_IPSEC_NODE_PORT_INCREMENT"`
 	SSHUser             string `yaml:"ssh_user" envconfig:"ECO_SSH_USER"`
-	SSHPrivateKey       string `yaml:"ssh_private_key" envconfig:"ECO_SSH_PRIVATE_KEY"`
+	SSHPrivateKey       string `yaml:"ssh_private_key" envconfig:"ECO_SSH_PRIVATE_KEY" eco_secret:"true"`
 	SSHPort             string `yaml:"ssh_port" envconfig:"ECO_SSH_PORT"`
 }
 
@@ -51,48 +49,14 @@ func NewIpsecConfig() *IpsecConfig {
 	baseDir := filepath.Dir(filename)
 	confFile := filepath.Join(baseDir, PathToDefaultIpsecParamsFile)
 
-	err := readFile(&ipsecConf, confFile)
+	sources, err := config.LoadLayers(&ipsecConf, confFile, "")
 	if err != nil {
-		log.Printf("Error reading config file %s", confFile)
+		log.Printf("Error loading config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&ipsecConf)
-	if err != nil {
-		log.Print("Error reading environment variables")
-
-		return nil
-	}
+	ipsecConf.RecordSources(sources)
 
 	return &ipsecConf
 }
-
-func readFile(ipsecConfig *IpsecConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(&ipsecConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(ipsecConfig *IpsecConfig) error {
-	err := envconfig.Process("", ipsecConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
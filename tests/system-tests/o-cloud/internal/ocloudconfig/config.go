@@ -2,15 +2,13 @@ package ocloudconfig
 
 import (
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 	"time"
 
-	"github.com/kelseyhightower/envconfig"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/bmc"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/system-tests/internal/systemtestsconfig"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -32,7 +30,7 @@ type OCloudConfig struct {
 	VirtualMediaID string `yaml:"virtual_media_id" envconfig:"ECO_OCLOUD_VIRTUAL_MEDIA_ID"`
 
 	// LocalRegistryAuth local registry auth information
-	LocalRegistryAuth string `yaml:"local_registry_auth" envconfig:"ECO_OCLOUD_LOCAL_REGISTRY_AUTH"`
+	LocalRegistryAuth string `yaml:"local_registry_auth" envconfig:"ECO_OCLOUD_LOCAL_REGISTRY_AUTH" eco_secret:"true"`
 	// SeedImage seed image
 	SeedImage string `yaml:"seed_image" envconfig:"ECO_OCLOUD_SEED_IMAGE"`
 	// SeedVersion seed version
@@ -44,7 +42,7 @@ type OCloudConfig struct {
 	// SSHKey ssh key
 	SSHKey string `yaml:"ssh_key" envconfig:"ECO_OCLOUD_SSH_KEY"`
 	// PullSecret pull secret
-	PullSecret string `yaml:"pull_secret" envconfig:"ECO_OCLOUD_PULL_SECRET"`
+	PullSecret string `yaml:"pull_secret" envconfig:"ECO_OCLOUD_PULL_SECRET" eco_secret:"true"`
 	// BaseImageName base image name
 	BaseImageName string `yaml:"base_image_name" envconfig:"ECO_OCLOUD_BASE_IMAGE_NAME"`
 	// InterfaceName interface name
@@ -64,7 +62,7 @@ type OCloudConfig struct {
 	// Spoke1BMCUsername BMC username for spoke 1
 	Spoke1BMCUsername string `yaml:"spoke1_bmc_username" envconfig:"ECO_OCLOUD_SPOKE1_BMC_USERNAME"`
 	// Spoke1BMCPassword BMC password for spoke 1
-	Spoke1BMCPassword string `yaml:"spoke1_bmc_password" envconfig:"ECO_OCLOUD_SPOKE1_BMC_PASSWORD"`
+	Spoke1BMCPassword string `yaml:"spoke1_bmc_password" envconfig:"ECO_OCLOUD_SPOKE1_BMC_PASSWORD" eco_secret:"true"`
 	// Spoke1BMCHost BMC IP address for spoke 1
 	Spoke1BMCHost string `yaml:"spoke1_bmc_host" envconfig:"ECO_OCLOUD_SPOKE1_BMC_HOST"`
 	// Spoke1BMCTimeout timeout for BMC for spoke 1
@@ -75,7 +73,7 @@ type OCloudConfig struct {
 	// Spoke2BMCUsername BMC username for spoke 2
 	Spoke2BMCUsername string `yaml:"spoke2_bmc_username" envconfig:"ECO_OCLOUD_SPOKE2_BMC_USERNAME"`
 	// Spoke2BMCPassword BMC password for spoke 2
-	Spoke2BMCPassword string `yaml:"spoke2_bmc_password" envconfig:"ECO_OCLOUD_SPOKE2_BMC_PASSWORD"`
+	Spoke2BMCPassword string `yaml:"spoke2_bmc_password" envconfig:"ECO_OCLOUD_SPOKE2_BMC_PASSWORD" eco_secret:"true"`
 	// Spoke2BMCHost BMC IP address for spoke 2
 	Spoke2BMCHost string `yaml:"spoke2_bmc_host" envconfig:"ECO_OCLOUD_SPOKE2_BMC_HOST"`
 	// Spoke2BMCTimeout timeout for BMC for spoke 2
@@ -155,19 +153,14 @@ func NewOCloudConfig() *OCloudConfig {
 	baseDir := filepath.Dir(filename)
 	confFile := filepath.Join(baseDir, PathToDefaultOCloudParamsFile)
 
-	err := readFile(&ocloudConf, confFile)
+	sources, err := config.LoadLayers(&ocloudConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&ocloudConf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	ocloudConf.RecordSources(sources)
 
 	ocloudConf.Spoke1BMC = bmc.New(ocloudConf.Spoke1BMCHost).
 		WithRedfishUser(ocloudConf.Spoke1BMCUsername, ocloudConf.Spoke1BMCPassword).
@@ -181,32 +174,3 @@ func NewOCloudConfig() *OCloudConfig {
 
 	return &ocloudConf
 }
-
-func readFile(ocloudConfig *OCloudConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(ocloudConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(ocloudConfig *OCloudConfig) error {
-	err := envconfig.Process("", ocloudConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
@@ -3,14 +3,12 @@ package randuconfig
 import (
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 	"strings"
 
-	"github.com/kelseyhightower/envconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/system-tests/internal/systemtestsconfig"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -99,7 +97,7 @@ type RanDuConfig struct {
 	PtpWpcSyncInterfaces       string      `yaml:"ptp_wpc_sync_interfaces" envconfig:"ECO_RANDU_PTP_WPC_SYNC_INTERFACES"`
 	PtpWpcPrimaryInterface     string      `yaml:"ptp_wpc_primary_interface" envconfig:"ECO_RANDU_PTP_WPC_PRIMARY_IFACE"`
 	RebootRecoveryTime         int         `yaml:"reboot_recovery_time" envconfig:"ECO_RANDU_RECOVERY_TIME"`
-	NodesCredentialsMap        NodesBMCMap `yaml:"randu_nodes_bmc_map" envconfig:"ECO_RANDU_NODES_CREDENTIALS_MAP"`
+	NodesCredentialsMap        NodesBMCMap `yaml:"randu_nodes_bmc_map" envconfig:"ECO_RANDU_NODES_CREDENTIALS_MAP" eco_secret:"true"` //nolint:lll
 	CertManager                struct {
 		DNSServer  string `yaml:"dns_server" envconfig:"ECO_RANDU_CERTMANAGER_DNS_SERVER"`
 		CertDomain string `yaml:"cert_domain" envconfig:"ECO_RANDU_CERTMANAGER_CERT_DOMAIN"`
@@ -122,48 +120,14 @@ func NewRanDuConfig() *RanDuConfig {
 	baseDir := filepath.Dir(filename)
 	confFile := filepath.Join(baseDir, PathToDefaultRanDuParamsFile)
 
-	err := readFile(&randuConf, confFile)
+	sources, err := config.LoadLayers(&randuConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&randuConf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	randuConf.RecordSources(sources)
 
 	return &randuConf
 }
-
-func readFile(randuConfig *RanDuConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(&randuConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(randuConfig *RanDuConfig) error {
-	err := envconfig.Process("", randuConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
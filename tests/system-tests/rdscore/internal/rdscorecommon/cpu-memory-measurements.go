@@ -2,19 +2,18 @@ package rdscorecommon
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/url"
 	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	. "github.com/onsi/gomega"
+	"github.com/prometheus/common/model"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/clients"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nodes"
 	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/nto"
-	"github.com/rh-ecosystem-edge/eco-goinfra/pkg/pod"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/promclient"
 	. "github.com/rh-ecosystem-edge/eco-gotests/tests/system-tests/rdscore/internal/rdscoreinittools"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/system-tests/rdscore/internal/rdscoreparams"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -24,56 +23,7 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 )
 
-// isPodReady checks if a pod is in Ready condition.
-func isPodReady(p *pod.Builder) bool {
-	if p.Object == nil || p.Object.Status.Conditions == nil {
-		return false
-	}
-
-	for _, condition := range p.Object.Status.Conditions {
-		if condition.Type == "Ready" && condition.Status == "True" {
-			return true
-		}
-	}
-
-	return false
-}
-
-// discoverPrometheusPod finds a ready Prometheus pod dynamically using label selector.
-// Returns the first ready pod found, or error if none available.
-func discoverPrometheusPod(apiClient *clients.Settings) (*pod.Builder, error) {
-	// List Prometheus pods using label selector
-	promPods, err := pod.List(
-		apiClient,
-		rdscoreparams.PrometheusNamespace,
-		metav1.ListOptions{LabelSelector: "app.kubernetes.io/name=prometheus"},
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list Prometheus pods: %w", err)
-	}
-
-	if len(promPods) == 0 {
-		return nil, fmt.Errorf("no Prometheus pods found in namespace %s", rdscoreparams.PrometheusNamespace)
-	}
-
-	// Find first ready pod
-	for _, p := range promPods {
-		if isPodReady(p) {
-			klog.V(rdscoreparams.RDSCoreLogLevel).Infof(
-				"Discovered ready Prometheus pod: %s", p.Definition.Name)
-
-			return p, nil
-		}
-	}
-
-	// No ready pod found - return first pod anyway (might become ready soon)
-	klog.V(rdscoreparams.RDSCoreLogLevel).Infof(
-		"No ready Prometheus pod found, using first available: %s", promPods[0].Definition.Name)
-
-	return promPods[0], nil
-}
-
-// ExecPromQuery executes a Prometheus query via curl in the Prometheus pod with retry logic.
+// ExecPromQuery executes a Prometheus query by running curl in a Prometheus pod, through promclient, with retry logic.
 // Uses exponential backoff (k8s wait.ExponentialBackoffWithContext) for retries.
 // Returns errors gracefully instead of panicking on timeout.
 func ExecPromQuery(apiClient *clients.Settings, query string) ([]rdscoreparams.PromMetric, error) {
@@ -94,38 +44,33 @@ func ExecPromQuery(apiClient *clients.Settings, query string) ([]rdscoreparams.P
 	ctx, cancel := context.WithTimeout(context.TODO(), rdscoreparams.PromQueryRetryTimeout)
 	defer cancel()
 
+	var promClient *promclient.Client
+
+	defer func() {
+		if promClient != nil {
+			_ = promClient.Close()
+		}
+	}()
+
 	// Execute with exponential backoff
 	err := wait.ExponentialBackoffWithContext(
 		ctx,
 		backoff,
 		func(ctx context.Context) (bool, error) {
-			// Discover ready Prometheus pod dynamically
-			promPod, err := discoverPrometheusPod(apiClient)
-			if err != nil {
-				klog.V(rdscoreparams.RDSCoreLogLevel).Infof(
-					"Retry: failed to discover Prometheus pod: %v", err)
+			if promClient == nil {
+				var err error
 
-				return false, nil // Retry
-			}
-
-			if !promPod.Exists() {
-				klog.V(rdscoreparams.RDSCoreLogLevel).Infof(
-					"Retry: Discovered Prometheus pod does not exist yet")
-
-				return false, nil // Retry
-			}
-
-			// URL encode the query
-			encodedQuery := url.QueryEscape(query)
+				// The pod-exec transport looks the Prometheus pod up again for every query
+				promClient, err = promclient.New(apiClient, promclient.TransportPodExec)
+				if err != nil {
+					klog.V(rdscoreparams.RDSCoreLogLevel).Infof(
+						"Retry: failed to create Prometheus client: %v", err)
 
-			// Execute curl command with timeout
-			cmd := []string{
-				"curl", "-s",
-				"--max-time", rdscoreparams.PromQueryCurlTimeout,
-				fmt.Sprintf("http://localhost:9090/api/v1/query?query=%s&timeout=25s", encodedQuery),
+					return false, nil // Retry
+				}
 			}
 
-			output, err := promPod.ExecCommand(cmd, rdscoreparams.PrometheusContainerName)
+			vector, err := promclient.Query(ctx, promClient, query, time.Time{})
 			if err != nil {
 				klog.V(rdscoreparams.RDSCoreLogLevel).Infof(
 					"Retry: failed to execute Prometheus query: %v", err)
@@ -133,23 +78,8 @@ func ExecPromQuery(apiClient *clients.Settings, query string) ([]rdscoreparams.P
 				return false, nil // Retry
 			}
 
-			var response rdscoreparams.PromQueryResponse
-			if err := json.Unmarshal(output.Bytes(), &response); err != nil {
-				klog.V(rdscoreparams.RDSCoreLogLevel).Infof(
-					"Retry: failed to parse Prometheus response: %v", err)
-
-				return false, nil // Retry
-			}
-
-			if response.Status != "success" {
-				klog.V(rdscoreparams.RDSCoreLogLevel).Infof(
-					"Retry: query failed with status %s: %s", response.Status, response.Error)
-
-				return false, nil // Retry
-			}
-
 			// Success - store result
-			metrics = response.Data.Result
+			metrics = promMetrics(vector)
 
 			return true, nil // Success, stop retrying
 		})
@@ -166,6 +96,27 @@ func ExecPromQuery(apiClient *clients.Settings, query string) ([]rdscoreparams.P
 	return metrics, nil
 }
 
+// promMetrics converts vector to metrics in the format of the Prometheus API response, so values are still
+// [timestamp, "value"] pairs.
+func promMetrics(vector model.Vector) []rdscoreparams.PromMetric {
+	metrics := make([]rdscoreparams.PromMetric, 0, len(vector))
+
+	for _, sample := range vector {
+		labels := make(map[string]string, len(sample.Metric))
+
+		for name, value := range sample.Metric {
+			labels[string(name)] = string(value)
+		}
+
+		metrics = append(metrics, rdscoreparams.PromMetric{
+			Metric: labels,
+			Value:  []interface{}{float64(sample.Timestamp) / 1000, sample.Value.String()},
+		})
+	}
+
+	return metrics
+}
+
 // ParseCPUValue parses CPU value from Prometheus result.
 func ParseCPUValue(value interface{}) (float64, error) {
 	strVal, ok := value.(string)
@@ -12,10 +12,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 
-	"github.com/kelseyhightower/envconfig"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
-
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -185,7 +182,7 @@ type CoreConfig struct {
 	IPVlanCMDataOne map[string]string `yaml:"rdscore_ipvlan_cm_data_one" envconfig:"ECO_SYSTEM_RDSCORE_IPVLAN_CM_DATA_ONE"`
 	//nolint:lll
 	StorageODFWorkloadImage string      `yaml:"rdscore_storage_storage_wlkd_image" envconfig:"ECO_RDSCORE_STORAGE_WLKD_IMAGE"`
-	NodesCredentialsMap     NodesBMCMap `yaml:"rdscore_nodes_bmc_map" envconfig:"ECO_RDSCORE_NODES_CREDENTIALS_MAP"`
+	NodesCredentialsMap     NodesBMCMap `yaml:"rdscore_nodes_bmc_map" envconfig:"ECO_RDSCORE_NODES_CREDENTIALS_MAP" eco_secret:"true"` //nolint:lll
 	WlkdSRIOVDeployOneImage string      `yaml:"rdscore_wlkd_sriov_one_image" envconfig:"ECO_RDSCORE_WLKD_SRIOV_ONE_IMG"`
 	WlkdSRIOVDeployTwoImage string      `yaml:"rdscore_wlkd_sriov_two_image" envconfig:"ECO_RDSCORE_WLKD_SRIOV_TWO_IMG"`
 	WlkdSRIOVDeploy3Image   string      `yaml:"rdscore_wlkd_sriov_3_image" envconfig:"ECO_RDSCORE_WLKD_SRIOV_3_IMG"`
@@ -242,7 +239,7 @@ type CoreConfig struct {
 
 	HypervisorHost string `yaml:"hypervisor_host" envconfig:"ECO_SYSTEM_TEST_HYPERVISOR_HOST"`
 	HypervisorUser string `yaml:"hypervisor_user" envconfig:"ECO_SYSTEM_TEST_HYPERVISOR_USER"`
-	HypervisorPass string `yaml:"hypervisor_pass" envconfig:"ECO_SYSTEM_TEST_HYPERVISOR_PASS"`
+	HypervisorPass string `yaml:"hypervisor_pass" envconfig:"ECO_SYSTEM_TEST_HYPERVISOR_PASS" eco_secret:"true"`
 	//nolint:lll
 	WlkdSRIOVConfigMapDataOne EnvMapString `yaml:"rdscore_wlkd_sriov_cm_data_one" envconfig:"ECO_RDSCORE_SRIOV_CM_DATA_ONE"`
 	//nolint:lll
@@ -641,50 +638,19 @@ func NewCoreConfig() *CoreConfig {
 
 	log.Printf("Open config file %s", confFile)
 
-	err := readFile(&rdsCoreConf, confFile)
+	sources, err := config.LoadLayers(&rdsCoreConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&rdsCoreConf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	rdsCoreConf.RecordSources(sources)
+	rdsCoreConf.setListOptions()
 
 	return &rdsCoreConf
 }
 
-func readFile(rdsConfig *CoreConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(&rdsConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(rdsConfig *CoreConfig) error {
-	err := envconfig.Process("", rdsConfig)
-	if err != nil {
-		return err
-	}
-
+func (rdsConfig *CoreConfig) setListOptions() {
 	rdsConfig.WorkerLabelListOption = metav1.ListOptions{LabelSelector: rdsConfig.WorkerLabel}
-
-	return nil
 }
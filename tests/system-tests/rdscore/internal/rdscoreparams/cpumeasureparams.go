@@ -37,11 +37,6 @@ const (
 	NodeDiscoveryRetryInterval = 3 * time.Second
 	// NodeDiscoveryRetryTimeout total timeout for node discovery operations.
 	NodeDiscoveryRetryTimeout = 20 * time.Second
-
-	// PromQueryCurlTimeout timeout in seconds for curl command.
-	// Set to 30s to exceed Prometheus query timeout (25s) while allowing multiple retries
-	// within PromQueryRetryTimeout of 120s.
-	PromQueryCurlTimeout = "30"
 )
 
 // Prometheus query templates (node name will be inserted via fmt.Sprintf).
@@ -219,20 +214,8 @@ type CPUAffinityInfo struct {
 	Source       string // Source of detection (e.g., "PerformanceProfile:name")
 }
 
-// PromQueryResponse represents Prometheus API response structure.
-type PromQueryResponse struct {
-	Status string        `json:"status"`
-	Data   PromQueryData `json:"data"`
-	Error  string        `json:"error,omitempty"`
-}
-
-// PromQueryData represents the data section of Prometheus response.
-type PromQueryData struct {
-	ResultType string       `json:"resultType"`
-	Result     []PromMetric `json:"result"`
-}
-
-// PromMetric represents an individual metric from Prometheus.
+// PromMetric represents an individual metric from Prometheus, with its value as a [timestamp, "value"] pair like in
+// the Prometheus API response.
 type PromMetric struct {
 	Metric map[string]string `json:"metric"`
 	Value  []interface{}     `json:"value"`
@@ -8,9 +8,8 @@ import (
 	"runtime"
 	"strings"
 
-	"github.com/kelseyhightower/envconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/system-tests/internal/systemtestsconfig"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -28,7 +27,7 @@ type SPKConfig struct {
 	IngressUDPIPv6URL string `yaml:"spk_ingress_udp_ipv6_url" envconfig:"ECO_SYSTEM_SPK_INGRESS_UDP_IPV6_URL"`
 	//nolint:lll
 	WorkloadDCIDeploymentName string      `yaml:"spk_dci_workload_deployment_name" envconfig:"ECO_SYSTEM_SPK_WORKLOAD_DCI_DEPLOYEMNT_NAME"`
-	NodesCredentialsMap       NodesBMCMap `yaml:"spk_nodes_bmc_map" envconfig:"ECO_SYSTEM_SPK_NODES_CREDENTIALS_MAP"`
+	NodesCredentialsMap       NodesBMCMap `yaml:"spk_nodes_bmc_map" envconfig:"ECO_SYSTEM_SPK_NODES_CREDENTIALS_MAP" eco_secret:"true"` //nolint:lll
 	//nolint:lll
 	WorkloadContainerImage string `yaml:"spk_workload_deployment_image" envconfig:"ECO_SYSTEM_SPK_WORKLOAD_DEPLOYMENT_IMAGE"`
 	BackendContainerImage  string `yaml:"spk_backend_deployment_image" envconfig:"ECO_SYSTEM_SPK_BACKEND_DEPLOYMENT_IMAGE"`
@@ -105,48 +104,14 @@ func NewSPKConfig() *SPKConfig {
 
 	log.Printf("Open config file %s", confFile)
 
-	err := readFile(&spkConf, confFile)
+	sources, err := config.LoadLayers(&spkConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&spkConf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	spkConf.RecordSources(sources)
 
 	return &spkConf
 }
-
-func readFile(spkConfig *SPKConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(&spkConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(spkConfig *SPKConfig) error {
-	err := envconfig.Process("", spkConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
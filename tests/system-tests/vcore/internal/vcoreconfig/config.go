@@ -3,15 +3,13 @@ package vcoreconfig
 import (
 	"fmt"
 	"log"
-	"os"
 	"path/filepath"
 	"runtime"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
-	"github.com/kelseyhightower/envconfig"
+	"github.com/rh-ecosystem-edge/eco-gotests/tests/internal/config"
 	"github.com/rh-ecosystem-edge/eco-gotests/tests/system-tests/internal/systemtestsconfig"
-	"gopkg.in/yaml.v2"
 )
 
 const (
@@ -28,11 +26,11 @@ type VCoreConfig struct {
 	VCoreCpMCPName              string `yaml:"vcore_cp_mcp" envconfig:"ECO_SYSTEM_VCORE_CP_MCP"`
 	Host                        string `yaml:"host" envconfig:"ECO_SYSTEM_VCORE_HOST"`
 	User                        string `yaml:"user" envconfig:"ECO_SYSTEM_VCORE_USER"`
-	Pass                        string `yaml:"pass" envconfig:"ECO_SYSTEM_VCORE_PASS"`
+	Pass                        string `yaml:"pass" envconfig:"ECO_SYSTEM_VCORE_PASS" eco_secret:"true"`
 	MirrorRegistryUser          string `yaml:"mirror_registry_user" envconfig:"ECO_SYSTEM_VCORE_MIRROR_REGISTRY_USER"`
-	MirrorRegistryPass          string `yaml:"mirror_registry_pass" envconfig:"ECO_SYSTEM_VCORE_MIRROR_REGISTRY_PASSWORD"`
+	MirrorRegistryPass          string `yaml:"mirror_registry_pass" envconfig:"ECO_SYSTEM_VCORE_MIRROR_REGISTRY_PASSWORD" eco_secret:"true"` //nolint:lll
 	CombinedPullSecretFile      string `yaml:"combined_pull_secret" envconfig:"ECO_SYSTEM_VCORE_COMBINED_PULL_SECRET"`
-	PrivateKey                  string `yaml:"private_key" envconfig:"ECO_SYSTEM_VCORE_PRIVATE_KEY"`
+	PrivateKey                  string `yaml:"private_key" envconfig:"ECO_SYSTEM_VCORE_PRIVATE_KEY" eco_secret:"true"`
 	RegistryRepository          string `yaml:"registry_repository" envconfig:"ECO_SYSTEM_VCORE_REGISTRY_REPOSITORY"`
 	CPUIsolated                 string `yaml:"cpu_isolated" envconfig:"ECO_SYSTEM_VCORE_CPU_ISOLATED"`
 	CPUReserved                 string `yaml:"cpu_reserved" envconfig:"ECO_SYSTEM_VCORE_CPU_RESERVED"`
@@ -62,49 +60,20 @@ func NewVCoreConfig() *VCoreConfig {
 	baseDir := filepath.Dir(filename)
 	confFile := filepath.Join(baseDir, PathToDefaultVCoreParamsFile)
 
-	err := readFile(&vcoreConf, confFile)
+	sources, err := config.LoadLayers(&vcoreConf, confFile, "")
 	if err != nil {
-		log.Printf("Error to read config file %s", confFile)
+		log.Printf("Error to load config layers from %s: %v", confFile, err)
 
 		return nil
 	}
 
-	err = readEnv(&vcoreConf)
-	if err != nil {
-		log.Print("Error to read environment variables")
-
-		return nil
-	}
+	vcoreConf.RecordSources(sources)
+	vcoreConf.setLabels()
 
 	return &vcoreConf
 }
 
-func readFile(vcoreConfig *VCoreConfig, cfgFile string) error {
-	openedCfgFile, err := os.Open(cfgFile)
-	if err != nil {
-		return err
-	}
-
-	defer func() {
-		_ = openedCfgFile.Close()
-	}()
-
-	decoder := yaml.NewDecoder(openedCfgFile)
-
-	err = decoder.Decode(&vcoreConfig)
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func readEnv(vcoreConfig *VCoreConfig) error {
-	err := envconfig.Process("", vcoreConfig)
-	if err != nil {
-		return err
-	}
-
+func (vcoreConfig *VCoreConfig) setLabels() {
 	vcoreConfig.OdfLabel = fmt.Sprintf("%s/%s", vcoreConfig.KubernetesRolePrefix, vcoreConfig.OdfMCPName)
 	vcoreConfig.VCorePpLabel = fmt.Sprintf("%s/%s", vcoreConfig.KubernetesRolePrefix, vcoreConfig.VCorePpMCPName)
 	vcoreConfig.VCoreCpLabel = fmt.Sprintf("%s/%s", vcoreConfig.KubernetesRolePrefix, vcoreConfig.VCoreCpMCPName)
@@ -116,6 +85,4 @@ func readEnv(vcoreConfig *VCoreConfig) error {
 	vcoreConfig.OdfLabelMap = map[string]string{vcoreConfig.OdfLabel: ""}
 	vcoreConfig.VCorePpLabelMap = map[string]string{vcoreConfig.VCorePpLabel: ""}
 	vcoreConfig.VCoreCpLabelMap = map[string]string{vcoreConfig.VCoreCpLabel: ""}
-
-	return nil
 }
@@ -75,4 +75,5 @@ var _ = JustAfterEach(func() {
 var _ = ReportAfterSuite("", func(report Report) {
 	reportxml.Create(
 		report, VCoreConfig.GetReportPath(), VCoreConfig.TCPrefix)
+	reporter.PublishSuiteReports(report, currentFile)
 })